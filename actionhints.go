@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/keys"
+	"changeme/internal/search"
+)
+
+// positionalBindings names the keybindings that apply to a result's first,
+// second and third action.
+var positionalBindings = []string{"activate", "secondary", "tertiary"}
+
+// maxHintActions keeps hints to a glanceable length.
+const maxHintActions = 3
+
+// applyShortcuts fills in each action's shortcut from the configured
+// keybindings and, when enabled, the result's hint.
+func applyShortcuts(results []search.Result, cfg config.Config) {
+	for i := range results {
+		r := &results[i]
+		for j := range r.Actions {
+			r.Actions[j].Shortcut = shortcutFor(r.Actions[j].ID, j, cfg.Keybindings)
+		}
+		if cfg.ShowActionHints && (i == 0 || !cfg.ActionHintsSelectedOnly) {
+			r.Hint = actionHint(*r)
+		}
+	}
+}
+
+func shortcutFor(actionID string, position int, bindings map[string]string) string {
+	if b, ok := bindings[actionID]; ok {
+		return b
+	}
+	if position < len(positionalBindings) {
+		return bindings[positionalBindings[position]]
+	}
+	return ""
+}
+
+// actionHint renders the shortcuts of r's actions, e.g. "↵ Open  ⌘↵ Reveal".
+func actionHint(r search.Result) string {
+	var parts []string
+	for _, a := range r.Actions {
+		if a.Shortcut == "" {
+			continue
+		}
+		parts = append(parts, keys.Format(a.Shortcut)+" "+a.Title)
+		if len(parts) == maxHintActions {
+			break
+		}
+	}
+	return strings.Join(parts, "  ")
+}
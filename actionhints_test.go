@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestActionHintRebound(t *testing.T) {
+	results := []search.Result{{
+		ID: "files:/tmp/a.txt",
+		Actions: []search.Action{
+			{ID: "open", Title: "Open"},
+			{ID: "reveal", Title: "Reveal"},
+			{ID: "copy-path", Title: "Copy Path"},
+		},
+	}, {
+		ID:      "files:/tmp/b.txt",
+		Actions: []search.Action{{ID: "open", Title: "Open"}},
+	}}
+	cfg := config.Default()
+	cfg.ShowActionHints = true
+	cfg.ActionHintsSelectedOnly = true
+	cfg.Keybindings = map[string]string{
+		"activate":  "ctrl+o",
+		"secondary": "cmd+enter",
+		"copy-path": "cmd+shift+c",
+	}
+	applyShortcuts(results, cfg)
+
+	if got, want := results[0].Hint, "⌃O Open  ⌘↵ Reveal  ⌘⇧C Copy Path"; got != want {
+		t.Errorf("hint = %q, want %q", got, want)
+	}
+	if got := results[0].Actions[0].Shortcut; got != "ctrl+o" {
+		t.Errorf("activate shortcut = %q, want ctrl+o", got)
+	}
+	if got := results[1].Hint; got != "" {
+		t.Errorf("hint on an unselected row = %q, want none", got)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// actionAirDrop opens the AirDrop panel with a file result. It is offered
+// on macOS file results and handled by GreetService.
+const actionAirDrop = "prism.airdrop"
+
+// airDropProviders are the providers whose results can be files to share.
+var airDropProviders = []string{"files", "finder", "grep", "downloads"}
+
+// airDroppable reports whether r is a file the AirDrop action applies to.
+// Folders and app bundles are left out.
+func airDroppable(r search.Result) bool {
+	if runtime.GOOS != "darwin" || !slices.Contains(airDropProviders, r.Provider) || r.Target == "" {
+		return false
+	}
+	info, err := os.Stat(r.Target)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// AirDrop opens the AirDrop panel with the file of a result from the last
+// search. The launcher hides first, so the panel is not left behind it.
+func (g *GreetService) AirDrop(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	return g.airDrop(r.Target)
+}
+
+// airDrop shares path through the platform once the launcher is hidden.
+func (g *GreetService) airDrop(path string) error {
+	if info, err := os.Stat(path); err != nil {
+		return err
+	} else if !info.Mode().IsRegular() {
+		return fmt.Errorf("airdrop: %s is not a file", path)
+	}
+	err := g.idle.Yield(func() error { return g.fallback.plat.AirDrop(context.Background(), path) }, false)
+	if errors.Is(err, platform.ErrUnsupported) {
+		return fmt.Errorf("airdrop: sharing is not available here")
+	}
+	return err
+}
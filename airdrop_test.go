@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// sharePlatform is a platform that records the files shared through
+// AirDrop, failing with err.
+type sharePlatform struct {
+	platform.Platform
+	shared []string
+	err    error
+}
+
+func (p *sharePlatform) AirDrop(ctx context.Context, path string) error {
+	p.shared = append(p.shared, path)
+	return p.err
+}
+
+func TestAirDrop(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(path, []byte("%PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := &testProvider{id: "files", results: []search.Result{
+		{ID: "files:" + path, Title: "report.pdf", Target: path},
+		{ID: "files:" + dir, Title: "reports folder", Target: dir},
+	}}
+	plat := &sharePlatform{}
+	g, _ := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 }, files)
+	g.fallback = noResults{plat: plat}
+	if _, err := g.engine.Search(context.Background(), "report"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Activate("files:"+path, actionAirDrop); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{path}; !slices.Equal(plat.shared, want) {
+		t.Errorf("shared %v, want %v", plat.shared, want)
+	}
+
+	// A folder is refused before the share hook is reached.
+	if err := g.Activate("files:"+dir, actionAirDrop); err == nil {
+		t.Error("sharing a folder succeeded")
+	}
+	if err := g.Activate("files:missing", actionAirDrop); err == nil {
+		t.Error("sharing an unknown result succeeded")
+	}
+	if len(plat.shared) != 1 {
+		t.Errorf("shared %v, want only the file", plat.shared)
+	}
+
+	// Without a sharing service, the error says so.
+	plat.err = platform.ErrUnsupported
+	err := g.Activate("files:"+path, actionAirDrop)
+	if err == nil || err.Error() != "airdrop: sharing is not available here" {
+		t.Errorf("sharing without AirDrop = %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "changeme/internal/config"
+
+// Bounds of the window corner radius, in points. Beyond the maximum the
+// corners start eating into the search field of the 50 point high window.
+const (
+	minCornerRadius = 0
+	maxCornerRadius = 24
+)
+
+// clampCornerRadius limits radius to the supported range.
+func clampCornerRadius(radius float64) float64 {
+	return min(max(radius, minCornerRadius), maxCornerRadius)
+}
+
+// SetWindowAppearance changes the window's corner radius and shadow, saving
+// them to the config. Radii outside 0–24 points are clamped. The change is
+// visible immediately on macOS, unless the adaptive DisplayAppearance gives
+// the current display its own look, and ignored elsewhere.
+func (g *GreetService) SetWindowAppearance(radius float64, shadow bool) error {
+	radius = clampCornerRadius(radius)
+	err := g.config.Update(func(c *config.Config) {
+		c.WindowCornerRadius, c.WindowShadow = radius, shadow
+	})
+	if err != nil {
+		return err
+	}
+	g.applyAppearance()
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+#import <Cocoa/Cocoa.h>
+
+static void prismSetWindowAppearance(void *handle, double radius, bool shadow) {
+	NSWindow *window = (NSWindow *)handle;
+	dispatch_async(dispatch_get_main_queue(), ^{
+		NSView *view = [window contentView];
+		[view setWantsLayer:YES];
+		view.layer.cornerRadius = radius;
+		view.layer.masksToBounds = YES;
+		[window setHasShadow:shadow];
+		[window invalidateShadow];
+	});
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// setWindowAppearance rounds the corners of the window's content view and
+// turns its shadow on or off. The shadow follows the rounded shape once it
+// is invalidated.
+func setWindowAppearance(w *application.WebviewWindow, radius float64, shadow bool) error {
+	handle, err := w.NativeWindowHandle()
+	if err != nil {
+		return err
+	}
+	C.prismSetWindowAppearance(unsafe.Pointer(handle), C.double(radius), C.bool(shadow))
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !darwin
+
+package main
+
+import (
+	"changeme/internal/platform"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// setWindowAppearance is only implemented on macOS; elsewhere the window
+// manager draws corners and shadows.
+func setWindowAppearance(w *application.WebviewWindow, radius float64, shadow bool) error {
+	return platform.ErrUnsupported
+}
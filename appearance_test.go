@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"changeme/internal/config"
+)
+
+func TestSetWindowAppearance(t *testing.T) {
+	tests := []struct {
+		radius float64
+		shadow bool
+		want   float64
+	}{
+		{12, true, 12},
+		{-4, false, 0},
+		{100, true, maxCornerRadius},
+		{math.Inf(1), false, maxCornerRadius},
+		{0, true, 0},
+	}
+	for _, tt := range tests {
+		g, _ := newTestService(t, nil)
+		applied := 0
+		g.applyAppearance = func() { applied++ }
+		if err := g.SetWindowAppearance(tt.radius, tt.shadow); err != nil {
+			t.Fatal(err)
+		}
+		reopened, err := config.Open(g.config.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range []config.Config{g.config.Get(), reopened.Get()} {
+			if c.WindowCornerRadius != tt.want || c.WindowShadow != tt.shadow {
+				t.Errorf("SetWindowAppearance(%v, %v) stored %v, %v; want %v, %v", tt.radius, tt.shadow, c.WindowCornerRadius, c.WindowShadow, tt.want, tt.shadow)
+			}
+		}
+		if applied != 1 {
+			t.Errorf("appearance applied %d times, want 1", applied)
+		}
+	}
+}
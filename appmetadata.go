@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+)
+
+// Actions copying details of an app result: its bundle identifier and
+// version, read from the bundle's Info.plist, or its path. They are handled
+// by GreetService.
+const (
+	actionCopyBundleID = "prism.copy-bundle-id"
+	actionCopyVersion  = "prism.copy-version"
+	actionCopyPath     = "prism.copy-path"
+)
+
+// errNoApps is returned when the apps provider is not registered.
+var errNoApps = errors.New("apps: provider not available")
+
+// appActions returns the copy actions for an app result: the path for any
+// app, and the bundle identifier and version for macOS bundles.
+func appActions(r search.Result) []search.Action {
+	if r.Provider != "apps" || r.Target == "" {
+		return nil
+	}
+	var actions []search.Action
+	if isAppBundle(r.Target) {
+		actions = append(actions,
+			search.Action{ID: actionCopyBundleID, Title: "Copy Bundle Identifier"},
+			search.Action{ID: actionCopyVersion, Title: "Copy Version"},
+		)
+	}
+	return append(actions, search.Action{ID: actionCopyPath, Title: "Copy Path"})
+}
+
+// AppMetadata returns the name, bundle identifier, version, minimum macOS
+// version and path of the app bundle at appPath.
+func (g *GreetService) AppMetadata(appPath string) (apps.Metadata, error) {
+	p, ok := g.engine.Provider("apps")
+	appsProvider, isApps := p.(*apps.Provider)
+	if !ok || !isApps {
+		return apps.Metadata{}, errNoApps
+	}
+	return appsProvider.Metadata(appPath)
+}
+
+// copyAppDetail copies the detail of an app result from the last search
+// that actionID names.
+func (g *GreetService) copyAppDetail(resultID, actionID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	text := r.Target
+	if actionID != actionCopyPath {
+		m, err := g.AppMetadata(r.Target)
+		if err != nil {
+			return err
+		}
+		text = m.BundleID
+		if actionID == actionCopyVersion {
+			text = m.Version
+		}
+	}
+	if text == "" {
+		return fmt.Errorf("apps: %s does not say", r.Target)
+	}
+	return g.out.Deliver(context.Background(), text, config.ClipboardActionCopy)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+)
+
+// copied is a clipboard output that records the text copied.
+type copied struct{ texts []string }
+
+func (c *copied) DefaultMode() string { return config.ClipboardActionCopy }
+
+func (c *copied) Deliver(ctx context.Context, text, mode string) error {
+	c.texts = append(c.texts, text)
+	return nil
+}
+
+func TestCopyAppDetail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Slack.app")
+	if err := os.MkdirAll(filepath.Join(path, "Contents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plist := `<plist><dict>
+	<key>CFBundleIdentifier</key><string>com.tinyspeck.slackmacgap</string>
+	<key>CFBundleShortVersionString</key><string>4.38.125</string>
+</dict></plist>`
+	if err := os.WriteFile(filepath.Join(path, "Contents", "Info.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plat := &launchPlatform{apps: []platform.App{{Name: "Slack", Path: path}}}
+	g, _ := newTestService(t, nil, apps.New(plat, fuzzy.Default()))
+	out := &copied{}
+	g.out = out
+
+	results, err := g.engine.Search(context.Background(), "slack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 {
+		t.Fatal("Slack not found")
+	}
+	r := results[0]
+	var offered []string
+	for _, a := range appActions(r) {
+		offered = append(offered, a.ID)
+	}
+	want := []string{actionCopyBundleID, actionCopyVersion, actionCopyPath}
+	if !slices.Equal(offered, want) {
+		t.Errorf("actions %v, want %v", offered, want)
+	}
+	for _, action := range want {
+		if err := g.copyAppDetail(r.ID, action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"com.tinyspeck.slackmacgap", "4.38.125", path}; !slices.Equal(out.texts, want) {
+		t.Errorf("copied %q, want %q", out.texts, want)
+	}
+
+	m, err := g.AppMetadata(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BundleID != "com.tinyspeck.slackmacgap" || m.Name != "Slack" {
+		t.Errorf("AppMetadata = %+v", m)
+	}
+	// An app that is not a bundle only offers its path.
+	if got := appActions(search.Result{Provider: "apps", Target: "/usr/share/applications/firefox.desktop"}); len(got) != 1 || got[0].ID != actionCopyPath {
+		t.Errorf("actions of a desktop entry = %+v, want only Copy Path", got)
+	}
+}
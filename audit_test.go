@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"changeme/internal/audit"
+	"changeme/internal/search"
+)
+
+// audited gives g an audit log and returns a function that closes it and
+// reads back its records.
+func audited(t *testing.T, g *GreetService) func() []audit.Record {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.Open(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.audit = l
+	return func() []audit.Record {
+		t.Helper()
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var out []audit.Record
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			var r audit.Record
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+}
+
+func TestActionAudited(t *testing.T) {
+	mail := appResult("Mail")
+	g, _ := newTestService(t, nil, &testProvider{id: "apps", results: []search.Result{mail}})
+	records := audited(t, g)
+
+	if _, err := g.engine.Search(context.Background(), "mail"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RunAction(mail.ID, "", false); err != nil {
+		t.Fatal(err)
+	}
+	got := records()
+	if len(got) != 1 {
+		t.Fatalf("audit records = %+v, want one", got)
+	}
+	if rec := got[0]; rec.Provider != "apps" || rec.ResultID != mail.ID || rec.Action != "open" || rec.Detail != mail.Target || rec.Error != "" {
+		t.Errorf("record = %+v", rec)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// idleHider hides the launcher window after a period without activity. The
+// countdown starts when the window is shown, restarts on every Touch and is
+// stopped when the window is hidden or pinned.
+type idleHider struct {
+	hide func()
+	// show brings the window back after Yield.
+	show func()
+
+	mu     sync.Mutex
+	after  time.Duration
+	timer  *time.Timer
+	pinned bool
+	// yielding is set from when Yield hides the window it will show again
+	// until the window is shown, and held once the hide was kept from
+	// ending the session; see holdSession and resumeSession.
+	yielding, held bool
+}
+
+func newIdleHider(hide, show func()) *idleHider {
+	return &idleHider{hide: hide, show: show}
+}
+
+// Start begins counting down from after; zero disables auto-hide until the
+// next Start.
+func (h *idleHider) Start(after time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.after = after
+	h.reset()
+}
+
+// Touch records activity, restarting the countdown.
+func (h *idleHider) Touch() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.timer != nil {
+		h.reset()
+	}
+}
+
+// Stop cancels the countdown, as when the window is hidden by hand.
+func (h *idleHider) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stop()
+}
+
+// SetPinned suspends auto-hide while the window is pinned and resumes it
+// when it is unpinned.
+func (h *idleHider) SetPinned(pinned bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pinned = pinned
+	if pinned {
+		h.stop()
+	} else {
+		h.reset()
+	}
+}
+
+// Hide hides the window now, as after running an action, unless it is
+// pinned.
+func (h *idleHider) Hide() {
+	h.mu.Lock()
+	pinned := h.pinned
+	h.stop()
+	h.mu.Unlock()
+	if !pinned {
+		h.hide()
+	}
+}
+
+// yieldSettle is how long Yield gives the app behind the window to take
+// focus once the window is hidden.
+const yieldSettle = 100 * time.Millisecond
+
+// Yield hides the window, even when pinned, so it has given up focus
+// before run runs. Afterwards the window is shown again when restore is
+// set or it was pinned. A window shown again keeps its session, such as
+// the selection and what providers cached, as if it had not been hidden.
+func (h *idleHider) Yield(run func() error, restore bool) error {
+	h.mu.Lock()
+	pinned := h.pinned
+	h.stop()
+	h.yielding = restore || pinned
+	h.mu.Unlock()
+	h.hide()
+	time.Sleep(yieldSettle)
+	err := run()
+	if restore || pinned {
+		h.show()
+	}
+	return err
+}
+
+// holdSession reports whether the window was hidden by a Yield that will
+// show it again, in which case the session is kept rather than ended.
+func (h *idleHider) holdSession() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.yielding {
+		h.held = true
+	}
+	return h.held
+}
+
+// resumeSession reports whether the window is shown again after a Yield
+// whose hide holdSession kept from ending the session, which then goes on
+// rather than starting anew. The auto-hide countdown restarts either way
+// it was shown after a Yield.
+func (h *idleHider) resumeSession() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	held := h.held
+	if h.yielding || held {
+		h.reset()
+	}
+	h.yielding, h.held = false, false
+	return held
+}
+
+// refocusDelay is how long Refocus waits for an app an action activated to
+// take focus, which happens after the action returns. It is well within
+// keepOpenGrace, so the focus loss does not hide the window first.
+const refocusDelay = 250 * time.Millisecond
+
+// Refocus shows the window and gives it focus again shortly, after the app
+// an action activated has come to the front.
+func (h *idleHider) Refocus() {
+	time.AfterFunc(refocusDelay, h.show)
+}
+
+// Pinned reports whether the window is pinned.
+func (h *idleHider) Pinned() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.pinned
+}
+
+func (h *idleHider) reset() {
+	h.stop()
+	if h.after <= 0 || h.pinned {
+		return
+	}
+	var t *time.Timer
+	t = time.AfterFunc(h.after, func() {
+		h.mu.Lock()
+		// A Touch that raced the timer replaced it; let the new one decide.
+		current := h.timer == t
+		if current {
+			h.timer = nil
+		}
+		h.mu.Unlock()
+		if current {
+			h.hide()
+		}
+	})
+	h.timer = t
+}
+
+func (h *idleHider) stop() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYieldKeepsSession(t *testing.T) {
+	var (
+		h            *idleHider
+		ends, begins int
+	)
+	end := func() {
+		if !h.holdSession() {
+			ends++
+		}
+	}
+	begin := func() {
+		if !h.resumeSession() {
+			begins++
+		}
+	}
+	h = newIdleHider(end, begin)
+
+	if err := h.Yield(func() error { return nil }, true); err != nil {
+		t.Fatal(err)
+	}
+	if ends != 0 || begins != 0 {
+		t.Errorf("after a restoring Yield: %d session ends, %d begins, want none", ends, begins)
+	}
+
+	// Without restore the window stays hidden, which ends the session.
+	if err := h.Yield(func() error { return nil }, false); err != nil {
+		t.Fatal(err)
+	}
+	if ends != 1 {
+		t.Errorf("after a Yield that does not restore: %d session ends, want 1", ends)
+	}
+
+	// Hiding and showing by hand ends and begins sessions as usual.
+	h.Hide()
+	h.show()
+	if ends != 2 || begins != 1 {
+		t.Errorf("after hiding and showing: %d session ends, %d begins, want 2 and 1", ends, begins)
+	}
+}
+
+func TestIdleHiderResetOnActivity(t *testing.T) {
+	hidden := make(chan time.Time, 1)
+	h := newIdleHider(func() { hidden <- time.Now() }, func() {})
+	const after = 60 * time.Millisecond
+
+	start := time.Now()
+	h.Start(after)
+	// Keep touching for well past the timeout; the window must stay up.
+	for range 6 {
+		time.Sleep(after / 3)
+		h.Touch()
+	}
+	select {
+	case <-hidden:
+		t.Fatal("hidden despite activity")
+	default:
+	}
+	touched := time.Now()
+	select {
+	case at := <-hidden:
+		if idle := at.Sub(touched); idle < after {
+			t.Errorf("hidden %v after the last activity, before the %v timeout", idle, after)
+		}
+		if at.Sub(start) < 2*after {
+			t.Errorf("hidden %v after starting; activity did not restart the countdown", at.Sub(start))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("never hidden once idle")
+	}
+}
+
+func TestIdleHiderPinned(t *testing.T) {
+	hidden := make(chan struct{}, 1)
+	h := newIdleHider(func() { hidden <- struct{}{} }, func() {})
+	h.Start(20 * time.Millisecond)
+	h.SetPinned(true)
+	select {
+	case <-hidden:
+		t.Fatal("pinned window hidden")
+	case <-time.After(60 * time.Millisecond):
+	}
+	h.SetPinned(false)
+	select {
+	case <-hidden:
+	case <-time.After(time.Second):
+		t.Fatal("unpinned window never hidden")
+	}
+}
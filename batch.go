@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// eventSelectionChanged carries a Selection whenever results are added to
+// or removed from the selection.
+const eventSelectionChanged = "selection:changed"
+
+// batchTrashConfirmID is the confirmation shown in place of the results
+// before trashing the selected files.
+const batchTrashConfirmID = "prism.trash.confirm-selection"
+
+// batchActions are the actions that can run on the whole selection, in the
+// order they are offered; see selectionActions.
+var batchActions = []search.Action{
+	{ID: "reveal", Title: "Reveal All"},
+	{ID: actionCopyPath, Title: "Copy Paths"},
+	{ID: actionTrash, Title: "Move All to Trash"},
+}
+
+// errEmptySelection is returned by RunActionOnSelection when nothing is
+// selected.
+var errEmptySelection = errors.New("selection: no results are selected")
+
+// Selection is the payload of eventSelectionChanged.
+type Selection struct {
+	IDs []string `json:"ids"`
+	// Actions are the batchActions that apply to every selected result.
+	Actions []search.Action `json:"actions"`
+}
+
+// batchApplies reports whether the batch action actionID applies to r.
+// Only results of files, folders and apps, which have paths, have any.
+func batchApplies(r search.Result, actionID string) bool {
+	if !slices.Contains([]string{"file", "folder", "app"}, r.Type) || !filepath.IsAbs(r.Target) {
+		return false
+	}
+	switch actionID {
+	case "reveal", actionCopyPath:
+		return true
+	case actionTrash:
+		// Applications need a confirmation of their own; see confirmTrash.
+		return r.Provider == "files" && trashable(r.Target) == nil && !isAppBundle(r.Target)
+	}
+	return false
+}
+
+// selectionActions returns the batchActions that apply to every one of
+// selected, so a mixed selection offers only what its results share.
+func selectionActions(selected []search.Result) []search.Action {
+	if len(selected) == 0 {
+		return nil
+	}
+	var actions []search.Action
+	for _, a := range batchActions {
+		if !slices.ContainsFunc(selected, func(r search.Result) bool { return !batchApplies(r, a.ID) }) {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// ToggleResultSelection adds the result with the given ID from the last
+// search to the selection, or removes it when already selected. Selected
+// results stay selected as the query changes, until the window is hidden
+// or an action runs on them.
+func (g *GreetService) ToggleResultSelection(id string) error {
+	r, known := g.engine.Result(id)
+	g.mu.Lock()
+	if i := slices.IndexFunc(g.selected, func(r search.Result) bool { return r.ID == id }); i >= 0 {
+		g.selected = slices.Delete(g.selected, i, i+1)
+	} else if known {
+		g.selected = append(g.selected, r)
+	} else {
+		g.mu.Unlock()
+		return search.ErrUnknownResult
+	}
+	g.mu.Unlock()
+	g.emitSelection()
+	return nil
+}
+
+// SelectedResults returns the IDs of the selected results, in the order
+// they were selected.
+func (g *GreetService) SelectedResults() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ids := make([]string, len(g.selected))
+	for i, r := range g.selected {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// SelectionActions returns the actions RunActionOnSelection can run on the
+// current selection.
+func (g *GreetService) SelectionActions() []search.Action {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return selectionActions(g.selected)
+}
+
+// ClearSelection empties the selection.
+func (g *GreetService) ClearSelection() {
+	g.mu.Lock()
+	empty := len(g.selected) == 0
+	g.selected = nil
+	g.mu.Unlock()
+	if !empty {
+		g.emitSelection()
+	}
+}
+
+func (g *GreetService) emitSelection() {
+	g.events.EmitEvent(eventSelectionChanged, Selection{IDs: g.SelectedResults(), Actions: g.SelectionActions()})
+}
+
+// RunActionOnSelection runs actionID, one of SelectionActions, on every
+// selected result. Copying paths copies them all at once, a line each.
+// Trashing first asks for confirmation in place of the results. Other
+// actions run on each result in turn; those that fail are reported
+// together once the rest have run. The selection is cleared afterwards.
+func (g *GreetService) RunActionOnSelection(actionID string) error {
+	g.mu.Lock()
+	selected := slices.Clone(g.selected)
+	g.mu.Unlock()
+	if len(selected) == 0 {
+		return errEmptySelection
+	}
+	if !slices.ContainsFunc(selectionActions(selected), func(a search.Action) bool { return a.ID == actionID }) {
+		return fmt.Errorf("selection: %q does not apply to every selected result", actionID)
+	}
+	if actionID == actionTrash {
+		g.confirmBatchTrash(len(selected))
+		return nil
+	}
+	if err := g.runBatch(selected, actionID); err != nil {
+		return err
+	}
+	g.idle.Hide()
+	return nil
+}
+
+// runBatch runs actionID on every one of selected, writing an audit record
+// for each, and clears the selection.
+func (g *GreetService) runBatch(selected []search.Result, actionID string) error {
+	g.forgetUndo()
+	defer g.ClearSelection()
+	ctx := context.Background()
+	if actionID == actionCopyPath {
+		paths := make([]string, len(selected))
+		for i, r := range selected {
+			paths[i] = r.Target
+		}
+		err := g.out.Deliver(ctx, strings.Join(paths, "\n"), config.ClipboardActionCopy)
+		for _, r := range selected {
+			g.auditAction(r, actionID, err)
+		}
+		return err
+	}
+	var errs []error
+	for _, r := range selected {
+		var err error
+		switch actionID {
+		case "reveal":
+			err = g.fallback.plat.Reveal(ctx, r.Target)
+			g.auditAction(r, actionID, err)
+		case actionTrash:
+			// trashResult writes the audit record itself.
+			err = g.trashResult(r)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Title, err))
+		}
+	}
+	if actionID == actionTrash {
+		// Undo puts back a single file, so it is not offered for part of
+		// a batch.
+		g.forgetUndo()
+	}
+	return errors.Join(errs...)
+}
+
+// confirmBatchTrash shows the confirmation for trashing the n selected
+// files in place of the results.
+func (g *GreetService) confirmBatchTrash(n int) {
+	r := search.Result{
+		ID:       batchTrashConfirmID,
+		Provider: "prism",
+		Type:     "confirmation",
+		Title:    fmt.Sprintf("Move %d items to the Trash?", n),
+		Actions:  []search.Action{{ID: actionTrash, Title: "Move All to Trash"}},
+	}
+	g.mu.Lock()
+	query := g.lastQuery
+	g.mu.Unlock()
+	g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{Query: query, Results: []search.Result{r}, Done: true})
+}
+
+// runBatchTrash trashes the selected files once confirmBatchTrash's
+// confirmation is accepted.
+func (g *GreetService) runBatchTrash() error {
+	g.mu.Lock()
+	selected := slices.Clone(g.selected)
+	g.mu.Unlock()
+	if len(selected) == 0 {
+		return errEmptySelection
+	}
+	return g.runBatch(selected, actionTrash)
+}
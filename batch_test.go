@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/audit"
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// batchPlatform is a trashPlatform that also records what it reveals,
+// failing for paths named "locked".
+type batchPlatform struct {
+	*trashPlatform
+	revealed []string
+}
+
+func (p *batchPlatform) Reveal(ctx context.Context, path string) error {
+	if filepath.Base(path) == "locked" {
+		return errors.New("permission denied")
+	}
+	p.revealed = append(p.revealed, path)
+	return nil
+}
+
+func actionIDsOf(actions []search.Action) []string {
+	var ids []string
+	for _, a := range actions {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+// auditedTargets returns the targets of records, which must all be of
+// actionID.
+func auditedTargets(records []audit.Record, actionID string) []string {
+	var out []string
+	for _, r := range records {
+		if r.Action != actionID || !strings.HasPrefix(r.ResultID, "files:") {
+			return append(out, "unexpected "+r.Action+" of "+r.ResultID)
+		}
+		out = append(out, r.Detail)
+	}
+	return out
+}
+
+// selectFiles searches for and selects the given files in dir, with an
+// app and a URL alongside them among the results.
+func selectFiles(t *testing.T, dir string, names ...string) (*GreetService, *batchPlatform, *copied) {
+	t.Helper()
+	var files []search.Result
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, search.Result{ID: "files:" + path, Type: "file", Title: "report " + name, Target: path})
+	}
+	others := []search.Result{
+		{ID: "apps:/Applications/Reports.app", Type: "app", Title: "Reports", Target: "/Applications/Reports.app"},
+		{ID: "web:reports", Type: "url", Title: "reports site", Target: "https://example.com/reports"},
+	}
+	plat := &batchPlatform{trashPlatform: &trashPlatform{dir: t.TempDir()}}
+	g, _ := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 },
+		&testProvider{id: "files", results: files},
+		&testProvider{id: "apps", results: others[:1]},
+		&testProvider{id: "web", results: others[1:]})
+	g.fallback = noResults{plat: plat}
+	out := &copied{}
+	g.out = out
+	if _, err := g.engine.Search(context.Background(), "report"); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range files {
+		if err := g.ToggleResultSelection(r.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g, plat, out
+}
+
+func TestRunActionOnSelection(t *testing.T) {
+	dir := t.TempDir()
+	g, _, out := selectFiles(t, dir, "a.txt", "b.txt", "c.txt")
+	records := audited(t, g)
+	if got, want := actionIDsOf(g.SelectionActions()), []string{"reveal", actionCopyPath, actionTrash}; !slices.Equal(got, want) {
+		t.Errorf("actions for three files = %v, want %v", got, want)
+	}
+	if err := g.RunActionOnSelection(actionCopyPath); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.txt")}, "\n")
+	if len(out.texts) != 1 || out.texts[0] != want {
+		t.Errorf("copied %q, want %q", out.texts, want)
+	}
+	if got := g.SelectedResults(); len(got) != 0 {
+		t.Errorf("selection after the action = %v, want it cleared", got)
+	}
+	if err := g.RunActionOnSelection(actionCopyPath); !errors.Is(err, errEmptySelection) {
+		t.Errorf("RunActionOnSelection with nothing selected = %v, want errEmptySelection", err)
+	}
+	if got, want := auditedTargets(records(), actionCopyPath), strings.Split(want, "\n"); !slices.Equal(got, want) {
+		t.Errorf("audited copying %v, want %v", got, want)
+	}
+}
+
+func TestSelectionActionsMixed(t *testing.T) {
+	g, _, _ := selectFiles(t, t.TempDir(), "a.txt")
+	tests := []struct {
+		toggle string
+		want   []string
+	}{
+		// An app has a path, but is not trashed with files.
+		{"apps:/Applications/Reports.app", []string{"reveal", actionCopyPath}},
+		// A URL has none of them.
+		{"web:reports", nil},
+		// Deselecting it brings them back.
+		{"web:reports", []string{"reveal", actionCopyPath}},
+	}
+	for _, tt := range tests {
+		if err := g.ToggleResultSelection(tt.toggle); err != nil {
+			t.Fatal(err)
+		}
+		if got := actionIDsOf(g.SelectionActions()); !slices.Equal(got, tt.want) {
+			t.Errorf("after toggling %s: actions %v, want %v", tt.toggle, got, tt.want)
+		}
+	}
+	if err := g.RunActionOnSelection(actionTrash); err == nil {
+		t.Error("trashing a selection with an app succeeded")
+	}
+	if got := len(g.SelectedResults()); got != 2 {
+		t.Errorf("%d selected after a refused action, want 2", got)
+	}
+	if err := g.ToggleResultSelection("files:/missing"); !errors.Is(err, search.ErrUnknownResult) {
+		t.Errorf("selecting an unknown result = %v, want ErrUnknownResult", err)
+	}
+}
+
+func TestRevealSelection(t *testing.T) {
+	dir := t.TempDir()
+	g, plat, _ := selectFiles(t, dir, "a.txt", "locked", "c.txt")
+	records := audited(t, g)
+	err := g.RunActionOnSelection("reveal")
+	if err == nil || !strings.Contains(err.Error(), "report locked: permission denied") {
+		t.Errorf("RunActionOnSelection(reveal) = %v, want the locked file's failure", err)
+	}
+	// The others are revealed regardless.
+	if want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "c.txt")}; !slices.Equal(plat.revealed, want) {
+		t.Errorf("revealed %v, want %v", plat.revealed, want)
+	}
+	// Every file is audited, the failure with its error.
+	got := records()
+	if want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "locked"), filepath.Join(dir, "c.txt")}; !slices.Equal(auditedTargets(got, "reveal"), want) {
+		t.Errorf("audited %+v, want a reveal of each of %v", got, want)
+	} else if got[0].Error != "" || got[1].Error != "permission denied" || got[2].Error != "" {
+		t.Errorf("audited errors %q, %q, %q; want only the locked file's", got[0].Error, got[1].Error, got[2].Error)
+	}
+}
+
+func TestTrashSelection(t *testing.T) {
+	dir := t.TempDir()
+	if err := trashable(dir); err != nil {
+		// On macOS temporary folders are under the protected /var.
+		t.Skip(err)
+	}
+	g, plat, _ := selectFiles(t, dir, "a.txt", "b.txt")
+	records := audited(t, g)
+	if err := g.RunActionOnSelection(actionTrash); err != nil {
+		t.Fatal(err)
+	}
+	if len(plat.trashed) != 0 {
+		t.Fatalf("trashed %v before confirming", plat.trashed)
+	}
+	if err := g.Activate(batchTrashConfirmID, actionTrash); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}; !slices.Equal(plat.trashed, want) {
+		t.Errorf("trashed %v, want %v", plat.trashed, want)
+	}
+	if got := g.SelectedResults(); len(got) != 0 {
+		t.Errorf("selection after trashing = %v, want it cleared", got)
+	}
+	if got, want := auditedTargets(records(), actionTrash), plat.trashed; !slices.Equal(got, want) {
+		t.Errorf("audited trashing %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/calc"
+	"changeme/internal/search"
+)
+
+// Suggestions made from the clipboard have IDs under clipSuggestPrefix and
+// are handled by GreetService rather than a provider. The smartactions
+// provider makes the rest, for URLs, paths and the like.
+const (
+	clipSuggestPrefix = "prism.clipboard:"
+	clipSuggestCalc   = clipSuggestPrefix + "calc"
+)
+
+// clipSuggestPrecision is the most fraction digits of a previewed answer,
+// matching the calculator's.
+const clipSuggestPrecision = 10
+
+// clipSuggestMaxLen is the longest clipboard text considered; anything
+// longer is prose or data rather than a sum.
+const clipSuggestMaxLen = 2048
+
+// clipboardSuggestion returns the suggestion for clipboard text that is an
+// expression: calculating it. It reports false for anything else.
+func clipboardSuggestion(text string, cfg config.Config) (search.Result, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > clipSuggestMaxLen || strings.ContainsAny(text, "\r\n") {
+		return search.Result{}, false
+	}
+	expr := strings.TrimSpace(strings.TrimPrefix(text, "="))
+	if !calc.IsExpression(expr) {
+		return search.Result{}, false
+	}
+	loc := configLocale(cfg)
+	v, err := calc.Evaluate(expr, loc)
+	if err != nil {
+		return search.Result{}, false
+	}
+	return search.Result{
+		ID:       clipSuggestCalc,
+		Provider: "prism",
+		Type:     "query",
+		Title:    "= " + expr,
+		Subtitle: fmt.Sprintf("%s · Calculate the clipboard", loc.FormatNumber(v, clipSuggestPrecision, true)),
+		Target:   "=" + expr,
+		Actions:  []search.Action{{ID: actionRepeatQuery, Title: "Calculate"}},
+	}, true
+}
+
+// clipboardSuggestions returns the suggestion for the current clipboard,
+// if SuggestClipboard is on and there is one.
+func (g *GreetService) clipboardSuggestions(cfg config.Config) []search.Result {
+	if !cfg.SuggestClipboard || g.clip == nil {
+		return nil
+	}
+	text, ok := g.clip.Text()
+	if !ok {
+		return nil
+	}
+	r, ok := clipboardSuggestion(text, cfg)
+	if !ok {
+		return nil
+	}
+	return []search.Result{r}
+}
+
+// runClipboardSuggestion puts the clipboard's expression in the query for
+// the calculator to answer.
+func (g *GreetService) runClipboardSuggestion(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok || resultID != clipSuggestCalc {
+		return search.ErrUnknownResult
+	}
+	g.events.EmitEvent(eventQuerySet, r.Target)
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/smartactions"
+)
+
+// clipboardText is a clipboard holding fixed text.
+type clipboardText string
+
+func (c clipboardText) Text() (string, bool) { return string(c), c != "" }
+
+func TestClipboardSuggestion(t *testing.T) {
+	tests := []struct {
+		clip       string
+		suggest    bool
+		wantAction string // of the first empty-state result, "" for none
+		wantTarget string
+	}{
+		{"https://go.dev/doc", true, smartactions.ActionOpen, "https://go.dev/doc"},
+		{"12*(3+4)", true, actionRepeatQuery, "=12*(3+4)"},
+		{"=2^10", true, actionRepeatQuery, "=2^10"},
+		{"just some words", true, "", ""},
+		{"https://go.dev/doc", false, "", ""},
+		{"12*(3+4)", false, "", ""},
+	}
+	for _, tt := range tests {
+		clip := clipboardText(tt.clip)
+		smart := smartactions.New(clip, nil, nil, nil)
+		smart.SetEnabled(tt.suggest)
+		g, _ := newTestService(t, func(c *config.Config) { c.SuggestClipboard = tt.suggest }, smart)
+		g.clip = clip
+
+		results, err := g.engine.Search(context.Background(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var action, target string
+		if len(results) > 0 && len(results[0].Actions) > 0 {
+			action, target = results[0].Actions[0].ID, results[0].Target
+		}
+		if action != tt.wantAction || target != tt.wantTarget {
+			t.Errorf("clipboard %q, suggest %v: first result %s %q, want %s %q", tt.clip, tt.suggest, action, target, tt.wantAction, tt.wantTarget)
+		}
+	}
+}
+
+func TestClipboardSuggestionCalc(t *testing.T) {
+	r, ok := clipboardSuggestion(" 1.5 + 2 ", config.Default())
+	if !ok || r.ID != clipSuggestCalc || r.Title != "= 1.5 + 2" || r.Subtitle != "3.5 · Calculate the clipboard" {
+		t.Errorf("clipboardSuggestion(1.5 + 2) = %+v, %v", r, ok)
+	}
+	for _, text := range []string{"", "hello", "1 +\n2", "1/0"} {
+		if r, ok := clipboardSuggestion(text, config.Default()); ok {
+			t.Errorf("clipboardSuggestion(%q) = %+v", text, r)
+		}
+	}
+}
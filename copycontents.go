@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// actionCopyContents copies the text of a file result to the clipboard. It
+// is offered on text files and handled by GreetService.
+const actionCopyContents = "prism.copy-contents"
+
+// maxCopyBytes bounds the files the Copy Contents action reads.
+const maxCopyBytes = 1 << 20
+
+// The errors CopyFileContents refuses a file with.
+var (
+	errFileTooLarge = errors.New("copy: file is too large to copy")
+	errBinaryFile   = errors.New("copy: file is not text")
+)
+
+// textExtensions are those of the files Copy Contents is offered on.
+var textExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".rst": true, ".log": true,
+	".csv": true, ".tsv": true, ".json": true, ".yaml": true, ".yml": true,
+	".toml": true, ".ini": true, ".cfg": true, ".conf": true, ".env": true,
+	".xml": true, ".html": true, ".htm": true, ".css": true, ".scss": true,
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".svelte": true, ".vue": true,
+	".go": true, ".py": true, ".rb": true, ".rs": true, ".java": true, ".kt": true,
+	".swift": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true, ".cs": true,
+	".php": true, ".lua": true, ".sql": true, ".sh": true, ".bash": true, ".zsh": true,
+	".fish": true, ".ps1": true, ".bat": true,
+}
+
+// copyableContents reports whether r is a text file the Copy Contents
+// action applies to, going by its extension.
+func copyableContents(r search.Result) bool {
+	return r.Type == "file" && filepath.IsAbs(r.Target) &&
+		textExtensions[strings.ToLower(filepath.Ext(r.Target))]
+}
+
+// CopyFileContents copies the text of the file at path to the clipboard.
+// Files over maxBytes are refused with errFileTooLarge, and those that are
+// not UTF-8 or UTF-16 text with errBinaryFile. A byte order mark is dropped.
+func (g *GreetService) CopyFileContents(path string, maxBytes int) error {
+	text, err := readText(path, maxBytes)
+	if err != nil {
+		return err
+	}
+	return g.out.Deliver(context.Background(), text, config.ClipboardActionCopy)
+}
+
+// copyContents runs actionCopyContents on a result from the last search.
+func (g *GreetService) copyContents(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	return g.CopyFileContents(r.Target, maxCopyBytes)
+}
+
+// readText returns the text of the file at path, of at most maxBytes.
+func readText(path string, maxBytes int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("copy: %s is a folder", path)
+	}
+	if info.Size() > int64(maxBytes) {
+		return "", fmt.Errorf("%w: %s", errFileTooLarge, path)
+	}
+	// The file may have grown since the Stat.
+	data, err := io.ReadAll(io.LimitReader(f, int64(maxBytes)+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxBytes {
+		return "", fmt.Errorf("%w: %s", errFileTooLarge, path)
+	}
+	text, ok := decodeText(data)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errBinaryFile, path)
+	}
+	return text, nil
+}
+
+// decodeText returns data as a string, decoding UTF-16 when it starts with
+// a UTF-16 byte order mark and UTF-8 otherwise. It reports false for data
+// that is not valid text or holds NUL bytes, as binary files do.
+func decodeText(data []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		if len(data)%2 != 0 {
+			return "", false
+		}
+		big := data[0] == 0xFE
+		units := make([]uint16, 0, len(data)/2-1)
+		for i := 2; i < len(data); i += 2 {
+			if big {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		data = []byte(string(utf16.Decode(units)))
+	default:
+		data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	}
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		return "", false
+	}
+	return string(data), true
+}
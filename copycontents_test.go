@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+func TestCopyFileContents(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	notes := write("notes.md", []byte("# Notes\nCafé ☕\n"))
+	bom := write("bom.txt", append([]byte{0xEF, 0xBB, 0xBF}, "with a BOM"...))
+	// "Hi é" in UTF-16, little- and big-endian.
+	utf16le := write("le.txt", []byte{0xFF, 0xFE, 'H', 0, 'i', 0, ' ', 0, 0xE9, 0})
+	utf16be := write("be.txt", []byte{0xFE, 0xFF, 0, 'H', 0, 'i', 0, ' ', 0, 0xE9})
+	binary := write("image.txt", []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0D})
+	latin1 := write("latin1.txt", []byte("caf\xe9"))
+	large := write("large.log", make([]byte, 101))
+
+	g, _ := newTestService(t, nil)
+	out := &copied{}
+	g.out = out
+	for _, path := range []string{notes, bom, utf16le, utf16be} {
+		if err := g.CopyFileContents(path, 100); err != nil {
+			t.Errorf("CopyFileContents(%s): %v", filepath.Base(path), err)
+		}
+	}
+	if want := []string{"# Notes\nCafé ☕\n", "with a BOM", "Hi é", "Hi é"}; !slices.Equal(out.texts, want) {
+		t.Errorf("copied %q, want %q", out.texts, want)
+	}
+
+	tests := []struct {
+		path string
+		want error
+	}{
+		{large, errFileTooLarge},
+		{binary, errBinaryFile},
+		{latin1, errBinaryFile},
+	}
+	for _, tt := range tests {
+		if err := g.CopyFileContents(tt.path, 100); !errors.Is(err, tt.want) {
+			t.Errorf("CopyFileContents(%s) = %v, want %v", filepath.Base(tt.path), err, tt.want)
+		}
+	}
+	for _, path := range []string{dir, filepath.Join(dir, "missing.txt")} {
+		if err := g.CopyFileContents(path, 100); err == nil {
+			t.Errorf("CopyFileContents(%s) succeeded", path)
+		}
+	}
+	if len(out.texts) != 4 {
+		t.Errorf("copied %q, want nothing from the refused files", out.texts)
+	}
+}
+
+func TestCopyableContents(t *testing.T) {
+	tests := []struct {
+		r    search.Result
+		want bool
+	}{
+		{search.Result{Type: "file", Target: "/home/sam/notes.md"}, true},
+		{search.Result{Type: "file", Target: "/home/sam/Main.GO"}, true},
+		{search.Result{Type: "file", Target: "/home/sam/photo.jpg"}, false},
+		{search.Result{Type: "folder", Target: "/home/sam/docs.txt"}, false},
+		{search.Result{Type: "file", Target: "notes.md"}, false},
+	}
+	for _, tt := range tests {
+		if got := copyableContents(tt.r); got != tt.want {
+			t.Errorf("copyableContents(%s %s) = %v, want %v", tt.r.Type, tt.r.Target, got, tt.want)
+		}
+	}
+}
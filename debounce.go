@@ -0,0 +1,28 @@
+package main
+
+import "time"
+
+// activation is a result and the action run on it, for debounced.
+type activation struct {
+	resultID, actionID string
+}
+
+// debounced reports whether running actionID on resultID at now repeats
+// the last activation, of the same action on the same result, within
+// window, as pressing enter twice does, and should be ignored. Otherwise
+// it is recorded as the last. Another action on the same result, such as
+// adding it to the favorites and then opening it, always runs. A window of
+// zero or less turns the guard off.
+func (g *GreetService) debounced(resultID, actionID string, now time.Time, window time.Duration) bool {
+	if window <= 0 {
+		return false
+	}
+	a := activation{resultID: resultID, actionID: actionID}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if a == g.lastActivated && now.Sub(g.lastActivatedAt) < window {
+		return true
+	}
+	g.lastActivated, g.lastActivatedAt = a, now
+	return false
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebounced(t *testing.T) {
+	g := &GreetService{}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := 300 * time.Millisecond
+	steps := []struct {
+		resultID, actionID string
+		after              time.Duration
+		want               bool
+	}{
+		{"files:/a", "open", 0, false},
+		{"files:/a", "open", 100 * time.Millisecond, true},
+		{"files:/a", "prism.favorite.add", 150 * time.Millisecond, false},
+		{"files:/a", "open", 200 * time.Millisecond, false},
+		{"files:/b", "open", 250 * time.Millisecond, false},
+		{"files:/b", "open", 600 * time.Millisecond, false},
+	}
+	for _, s := range steps {
+		if got := g.debounced(s.resultID, s.actionID, start.Add(s.after), window); got != s.want {
+			t.Errorf("debounced(%s, %s) at +%v = %v, want %v", s.resultID, s.actionID, s.after, got, s.want)
+		}
+	}
+	if g.debounced("files:/b", "open", start.Add(650*time.Millisecond), 0) {
+		t.Error("debounced with a zero window")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"sort"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// commonActions are offered on results of any type that they apply to.
+var commonActions = []string{actionAddFavorite, actionRemoveFavorite, actionCopyMarkdown, actionOpenTerminal, actionShowQR, actionCopySummary}
+
+// clipActions are the clipboard actions of text results.
+var clipActions = []string{config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain}
+
+// fileActions are the actions of file and folder results.
+var fileActions = []string{"open", "reveal", actionCopyContents, actionTrash, actionAirDrop}
+
+// typeActions lists, for each result type, the actions its results may
+// offer besides commonActions, for checking the configured DefaultActions.
+var typeActions = map[string][]string{
+	"app":       {"open", "reveal", "relaunch", actionCopyBundleID, actionCopyVersion, actionCopyPath, actionDock},
+	"archive":   {"extract-open", "mount-open", "reveal"},
+	"clipboard": clipActions,
+	"color":     append([]string{"query"}, clipActions...),
+	"command":   {"run"},
+	"compose":   {"start", "stop", "restart", "logs"},
+	"container": {"start", "stop", "restart", "logs"},
+	"file":      fileActions,
+	"folder":    fileActions,
+	"host":      {"connect"},
+	"layout":    {"apply"},
+	"mail":      {"compose", "open", "query"},
+	"menu":      {"click"},
+	"note":      {"open", "create"},
+	"plugin":    {"run", "approve"},
+	"process":   {"kill", "force-kill"},
+	"reminder":  {"open", "complete", "create"},
+	"repo":      {"open", "terminal", "remote"},
+	"service":   {"run"},
+	"setting":   {"set"},
+	"text":      append([]string{"regenerate"}, clipActions...),
+	"timer":     {"start", "cancel"},
+	"url":       {"open", "shorten"},
+	"wifi":      {"connect", "connect-password", "turn-on", "settings"},
+	"window":    {"focus", "reveal"},
+}
+
+// checkDefaultActions logs the configured default actions that no result of
+// their type offers. They are kept, but have no effect.
+func checkDefaultActions(defaults map[string]string) {
+	types := make([]string, 0, len(defaults))
+	for typ := range defaults {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		actionID := defaults[typ]
+		known, ok := typeActions[typ]
+		switch {
+		case !ok:
+			log.Printf("config: defaultActions: unknown result type %q", typ)
+		case !slices.Contains(known, actionID) && !slices.Contains(commonActions, actionID):
+			log.Printf("config: defaultActions: %s results have no action %q", typ, actionID)
+		}
+	}
+}
+
+// applyDefaultActions moves the action configured for each result's type
+// first, making it the one enter runs and the one hints show as such.
+// Results that do not offer it keep their own default.
+func applyDefaultActions(results []search.Result, cfg config.Config) {
+	for i := range results {
+		r := &results[i]
+		actionID, ok := cfg.DefaultActions[r.Type]
+		if !ok {
+			continue
+		}
+		j := slices.IndexFunc(r.Actions, func(a search.Action) bool { return a.ID == actionID })
+		if j <= 0 {
+			continue
+		}
+		a := r.Actions[j]
+		copy(r.Actions[1:j+1], r.Actions[:j])
+		r.Actions[0] = a
+	}
+}
+
+// defaultAction returns the action enter runs on r: the one configured for
+// its type when r offers it, and otherwise its first.
+func defaultAction(r search.Result, cfg config.Config) string {
+	if actionID, ok := cfg.DefaultActions[r.Type]; ok {
+		for _, a := range r.Actions {
+			if a.ID == actionID {
+				return actionID
+			}
+		}
+	}
+	return r.DefaultAction()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestDefaultActions(t *testing.T) {
+	fileActions := []search.Action{{ID: "open", Title: "Open"}, {ID: "reveal", Title: "Show in Folder"}}
+	files := &testProvider{id: "files", results: []search.Result{
+		{ID: "files:/tmp/report.pdf", Type: "file", Title: "report.pdf", Target: "/tmp/report.pdf", Actions: fileActions},
+		{ID: "files:/tmp/report.url", Type: "url", Title: "report.url", Target: "https://example.com", Actions: []search.Action{{ID: "open", Title: "Open"}}},
+	}}
+	g, _ := newTestService(t, func(c *config.Config) {
+		c.ActivationDebounceMs = 0
+		c.DefaultActions = map[string]string{"file": "reveal", "url": "reveal"}
+	}, files)
+
+	results := g.Search("report")
+	if len(results) != 2 {
+		t.Fatalf("Search = %v, want both reports", resultIDs(results))
+	}
+	// The action hints list the configured default first.
+	var first []string
+	for _, r := range results {
+		first = append(first, r.Actions[0].ID)
+	}
+	if want := []string{"reveal", "open"}; !slices.Equal(first, want) {
+		t.Errorf("first actions = %v, want %v", first, want)
+	}
+	if files.results[0].Actions[0].ID != "open" {
+		t.Error("reordering the actions changed the provider's result")
+	}
+
+	for _, id := range []string{"files:/tmp/report.pdf", "files:/tmp/report.url"} {
+		if err := g.Activate(id, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A url has no reveal action, so it keeps its own default.
+	if want := []string{"files:/tmp/report.pdf reveal", "files:/tmp/report.url open"}; !slices.Equal(files.ran(), want) {
+		t.Errorf("ran %v, want %v", files.ran(), want)
+	}
+}
+
+func TestCheckDefaultActions(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	checkDefaultActions(map[string]string{
+		"file":   "reveal",
+		"app":    actionAddFavorite,
+		"folder": "launch",
+		"widget": "open",
+	})
+	got := logs.String()
+	for _, want := range []string{`folder results have no action "launch"`, `unknown result type "widget"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log %q does not say %s", got, want)
+		}
+	}
+	if n := strings.Count(got, "\n"); n != 2 {
+		t.Errorf("logged %d lines, want 2:\n%s", n, got)
+	}
+}
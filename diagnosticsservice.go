@@ -0,0 +1,28 @@
+package main
+
+import "changeme/internal/lru"
+
+// Diagnostics is a snapshot of Prism's resource use, for the Diagnostics
+// report.
+type Diagnostics struct {
+	// Caches is how much of CacheMemoryBudgetMB the thumbnail cache uses.
+	Caches lru.Usage `json:"caches"`
+	// Displays are the connected displays and the window's look on each,
+	// for checking DisplayAppearance.
+	Displays []DisplayDiagnostics `json:"displays"`
+}
+
+// DiagnosticsService reports on Prism's resource use.
+type DiagnosticsService struct {
+	caches   *lru.Budget
+	displays func() []DisplayDiagnostics
+}
+
+func NewDiagnosticsService(caches *lru.Budget, displays func() []DisplayDiagnostics) *DiagnosticsService {
+	return &DiagnosticsService{caches: caches, displays: displays}
+}
+
+// Report returns the current Diagnostics.
+func (d *DiagnosticsService) Report() Diagnostics {
+	return Diagnostics{Caches: d.caches.Usage(), Displays: d.displays()}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"changeme/internal/config"
+)
+
+// eventWindowAppearance carries the WindowAppearance the window takes on
+// for the display it is on, so the frontend can scale its text.
+const eventWindowAppearance = "window:appearance"
+
+// highDensityScale is the lowest scale factor of a high-density display,
+// such as a Retina one; displays below it get the StandardDisplay look
+// under the adaptive policy.
+const highDensityScale = 1.5
+
+// Bounds of the font scale, beyond which the text no longer fits the
+// search field.
+const (
+	minFontScale = 0.75
+	maxFontScale = 1.5
+)
+
+// WindowAppearance is how the window looks on a display.
+type WindowAppearance struct {
+	FontScale    float64 `json:"fontScale"`
+	CornerRadius float64 `json:"cornerRadius"`
+	Shadow       bool    `json:"shadow"`
+}
+
+// DisplayDiagnostics is a display and the look the window takes on there,
+// for the Diagnostics report.
+type DisplayDiagnostics struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Scale   float64 `json:"scale"`
+	Primary bool    `json:"primary"`
+	// Current is set on the display the window is on.
+	Current    bool             `json:"current"`
+	Appearance WindowAppearance `json:"appearance"`
+}
+
+// displayAppearance returns how the window looks on a display with the
+// given scale factor, zero when not known, under c.DisplayAppearance.
+// Adapted corner radii are rounded to whole pixels of the display, so a
+// radius that falls between pixels at 1x is not drawn blurred.
+func displayAppearance(c config.Config, scale float64) WindowAppearance {
+	a := WindowAppearance{FontScale: 1, CornerRadius: c.WindowCornerRadius, Shadow: c.WindowShadow}
+	if c.DisplayAppearance != config.DisplayAppearanceAdaptive || scale <= 0 {
+		a.CornerRadius = clampCornerRadius(a.CornerRadius)
+		return a
+	}
+	if scale < highDensityScale {
+		sd := c.StandardDisplay
+		a = WindowAppearance{FontScale: sd.FontScale, CornerRadius: sd.CornerRadius, Shadow: sd.Shadow}
+		if a.FontScale == 0 {
+			a.FontScale = 1
+		}
+	}
+	a.FontScale = min(max(a.FontScale, minFontScale), maxFontScale)
+	a.CornerRadius = math.Round(clampCornerRadius(a.CornerRadius)*scale) / scale
+	return a
+}
+
+// applyDisplayAppearance restyles the window for the display it is on and
+// tells the frontend its font scale. It runs on start, whenever the window
+// is shown or changes display, and when displays are rearranged.
+func applyDisplayAppearance(c config.Config) {
+	var scale float64
+	if s, err := window.GetScreen(); err == nil && s != nil {
+		scale = float64(s.Scale)
+	}
+	a := displayAppearance(c, scale)
+	applyWindowAppearance(a.CornerRadius, a.Shadow)
+	appEvents{}.EmitEvent(eventWindowAppearance, a)
+}
+
+// displayDiagnostics lists the connected displays with the look the window
+// takes on each.
+func displayDiagnostics(c config.Config) []DisplayDiagnostics {
+	screens, err := application.Get().GetScreens()
+	if err != nil {
+		return nil
+	}
+	var current string
+	if s, err := window.GetScreen(); err == nil && s != nil {
+		current = s.ID
+	}
+	out := make([]DisplayDiagnostics, 0, len(screens))
+	for _, s := range screens {
+		out = append(out, DisplayDiagnostics{
+			ID:         s.ID,
+			Name:       s.Name,
+			Scale:      float64(s.Scale),
+			Primary:    s.IsPrimary,
+			Current:    s.ID == current,
+			Appearance: displayAppearance(c, float64(s.Scale)),
+		})
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"changeme/internal/config"
+)
+
+func TestDisplayAppearance(t *testing.T) {
+	adaptive := func(edit func(c *config.Config)) config.Config {
+		c := config.Default()
+		c.DisplayAppearance = config.DisplayAppearanceAdaptive
+		c.WindowCornerRadius, c.WindowShadow = 10, false
+		if edit != nil {
+			edit(&c)
+		}
+		return c
+	}
+	tests := []struct {
+		name  string
+		c     config.Config
+		scale float64
+		want  WindowAppearance
+	}{
+		// A Retina laptop keeps the window's own look, and an external
+		// monitor beside it takes the standard display's.
+		{"retina", adaptive(nil), 2, WindowAppearance{FontScale: 1, CornerRadius: 10, Shadow: false}},
+		{"external", adaptive(nil), 1, WindowAppearance{FontScale: 1.1, CornerRadius: 6, Shadow: true}},
+		// Radii land on whole pixels: 6.5 points is 8.125 pixels at 1.25x.
+		{"fractional scale", adaptive(func(c *config.Config) { c.StandardDisplay.CornerRadius = 6.5 }), 1.25, WindowAppearance{FontScale: 1.1, CornerRadius: 6.4, Shadow: true}},
+		{"radius clamped", adaptive(func(c *config.Config) { c.StandardDisplay.CornerRadius = 40 }), 1, WindowAppearance{FontScale: 1.1, CornerRadius: maxCornerRadius, Shadow: true}},
+		{"font scale clamped", adaptive(func(c *config.Config) { c.StandardDisplay.FontScale = 3 }), 1, WindowAppearance{FontScale: maxFontScale, CornerRadius: 6, Shadow: true}},
+		{"font scale unset", adaptive(func(c *config.Config) { c.StandardDisplay.FontScale = 0 }), 1, WindowAppearance{FontScale: 1, CornerRadius: 6, Shadow: true}},
+		{"unknown display", adaptive(nil), 0, WindowAppearance{FontScale: 1, CornerRadius: 10, Shadow: false}},
+		{"uniform", config.Default(), 1, WindowAppearance{FontScale: 1, CornerRadius: 8, Shadow: true}},
+	}
+	for _, tt := range tests {
+		if got := displayAppearance(tt.c, tt.scale); got != tt.want {
+			t.Errorf("%s: displayAppearance at %vx = %+v, want %+v", tt.name, tt.scale, got, tt.want)
+		}
+	}
+}
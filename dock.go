@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"changeme/internal/dock"
+	"changeme/internal/search"
+)
+
+// actionDock adds an app result to the Dock, or removes it when it is kept
+// there, after confirmation. It is offered on macOS app results and handled
+// by GreetService.
+const actionDock = "prism.dock"
+
+// The confirmations shown in place of results before editing the Dock.
+// Their IDs are the prefix followed by the app's path.
+const (
+	dockConfirmAddPrefix    = "prism.dock.confirm-add:"
+	dockConfirmRemovePrefix = "prism.dock.confirm-remove:"
+)
+
+// dockApps caches which apps are kept in the Dock, so the Dock action can
+// be labelled without reading the Dock's preferences for every search.
+type dockApps struct {
+	mu   sync.Mutex
+	apps map[string]bool
+}
+
+// refresh rereads the apps kept in the Dock.
+func (d *dockApps) refresh(ctx context.Context) {
+	paths, err := dock.Apps(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	apps := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		apps[p] = true
+	}
+	d.mu.Lock()
+	d.apps = apps
+	d.mu.Unlock()
+}
+
+// contains reports whether the app at path is kept in the Dock, as of the
+// last refresh.
+func (d *dockApps) contains(path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.apps[filepath.Clean(path)]
+}
+
+// set records that the app at path was added to or removed from the Dock.
+func (d *dockApps) set(path string, kept bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.apps == nil {
+		d.apps = make(map[string]bool)
+	}
+	d.apps[filepath.Clean(path)] = kept
+}
+
+// dockable reports whether r is an app the Dock action applies to.
+func dockable(r search.Result) bool {
+	return runtime.GOOS == "darwin" && r.Provider == "apps" && isAppBundle(r.Target)
+}
+
+// dockAction labels the Dock action for r by whether it is in the Dock.
+func (g *GreetService) dockAction(r search.Result) search.Action {
+	if g.dock.contains(r.Target) {
+		return search.Action{ID: actionDock, Title: "Remove from Dock"}
+	}
+	return search.Action{ID: actionDock, Title: "Add to Dock"}
+}
+
+// AddToDock adds the app bundle at appPath to the end of the Dock, which
+// restarts to show it.
+func (g *GreetService) AddToDock(appPath string) error {
+	if err := dock.Add(context.Background(), appPath); err != nil {
+		return err
+	}
+	g.dock.set(appPath, true)
+	return nil
+}
+
+// RemoveFromDock removes the app bundle at appPath from the Dock, which
+// restarts without it.
+func (g *GreetService) RemoveFromDock(appPath string) error {
+	if err := dock.Remove(context.Background(), appPath); err != nil {
+		return err
+	}
+	g.dock.set(appPath, false)
+	return nil
+}
+
+// isDockConfirmation reports whether resultID is one of confirmDock's.
+func isDockConfirmation(resultID string) bool {
+	return strings.HasPrefix(resultID, dockConfirmAddPrefix) || strings.HasPrefix(resultID, dockConfirmRemovePrefix)
+}
+
+// confirmDock shows the confirmation for adding path to the Dock, or for
+// removing it, in place of the results.
+func (g *GreetService) confirmDock(path string, remove bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	r := search.Result{
+		ID:       dockConfirmAddPrefix + path,
+		Provider: "prism",
+		Type:     "confirmation",
+		Title:    fmt.Sprintf("Add “%s” to the Dock?", name),
+		Subtitle: "The Dock restarts to show it",
+		Actions:  []search.Action{{ID: actionDock, Title: "Add to Dock"}},
+	}
+	if remove {
+		r.ID = dockConfirmRemovePrefix + path
+		r.Title = fmt.Sprintf("Remove “%s” from the Dock?", name)
+		r.Subtitle = "The Dock restarts without it"
+		r.Actions = []search.Action{{ID: actionDock, Title: "Remove from Dock"}}
+	}
+	g.mu.Lock()
+	query := g.lastQuery
+	g.mu.Unlock()
+	g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{Query: query, Results: []search.Result{r}, Done: true})
+}
+
+// runDock handles actionDock on an app result, asking for confirmation, and
+// on the confirmation, editing the Dock and showing the results again.
+func (g *GreetService) runDock(resultID string) error {
+	var err error
+	if path, ok := strings.CutPrefix(resultID, dockConfirmAddPrefix); ok {
+		err = g.AddToDock(path)
+	} else if path, ok := strings.CutPrefix(resultID, dockConfirmRemovePrefix); ok {
+		err = g.RemoveFromDock(path)
+	} else {
+		r, ok := g.engine.Result(resultID)
+		if !ok {
+			return search.ErrUnknownResult
+		}
+		if !dockable(r) {
+			return fmt.Errorf("dock: %q is not an application", r.Target)
+		}
+		// Read the Dock again in case it was edited since the session began.
+		g.dock.refresh(context.Background())
+		g.confirmDock(r.Target, g.dock.contains(r.Target))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	g.refreshResults()
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// emptyStateLimit is the number of results shown before anything is typed.
+const emptyStateLimit = 8
+
+// emptyState returns the results for a blank query: the offer to import app
+// usage until it is answered, a suggestion for what is on the clipboard, ongoing activity such as running timers, then results for
+// the configured mode. In frecency
+// mode favorites come first, in their pinned
+// order. Favorites and frecency entries that no longer resolve, such as
+// uninstalled apps, are skipped.
+func (g *GreetService) emptyState(ctx context.Context) []search.Result {
+	cfg := g.config.Get()
+	results := usageImportOffer(cfg)
+	results = append(results, g.clipboardSuggestions(cfg)...)
+	results = append(results, g.engine.Status(ctx)...)
+	return append(results, g.emptyStateMode(ctx)...)
+}
+
+func (g *GreetService) emptyStateMode(ctx context.Context) []search.Result {
+	cfg := g.config.Get()
+	switch cfg.EmptyState {
+	case config.EmptyStateFavorites:
+		return g.resolveAll(ctx, cfg.Favorites)
+	case config.EmptyStateRecent:
+		return g.engine.Recent(ctx, emptyStateLimit)
+	case config.EmptyStateRecentQueries:
+		if !cfg.QueryHistory {
+			return nil
+		}
+		return recentQueryResults(g.history.Recent(emptyStateLimit))
+	case config.EmptyStateFrecency:
+		// Ask for extra IDs so stale entries do not leave the list short.
+		ids := append([]string(nil), cfg.Favorites...)
+		for _, id := range g.frecency.Top(2*emptyStateLimit, time.Now()) {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		return g.resolveAll(ctx, ids)
+	}
+	return nil
+}
+
+// recentQueryResults turns queries into results whose default action runs
+// the query again.
+func recentQueryResults(queries []string) []search.Result {
+	results := make([]search.Result, 0, len(queries))
+	for _, q := range queries {
+		results = append(results, search.Result{
+			ID:       recentQueryPrefix + q,
+			Provider: "history",
+			Type:     "query",
+			Title:    q,
+			Subtitle: "Search again",
+			Actions:  []search.Action{{ID: actionRepeatQuery, Title: "Search Again"}},
+		})
+	}
+	return results
+}
+
+func (g *GreetService) resolveAll(ctx context.Context, ids []string) []search.Result {
+	var results []search.Result
+	for _, id := range ids {
+		if len(results) == emptyStateLimit {
+			break
+		}
+		if r, ok := g.engine.Resolve(ctx, id); ok {
+			results = append(results, r)
+		}
+	}
+	return results
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestEmptyStateModes(t *testing.T) {
+	mail, notes, safari := appResult("Mail"), appResult("Notes"), appResult("Safari")
+	clip := &recentProvider{testProvider{id: "clipboard", results: []search.Result{
+		{ID: "clipboard:2", Provider: "clipboard", Title: "newest"},
+		{ID: "clipboard:1", Provider: "clipboard", Title: "older"},
+	}}}
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		// Favorites first, in their order, then the most used; the stale
+		// favorite is skipped.
+		{config.EmptyStateFrecency, []string{notes.ID, mail.ID, safari.ID}},
+		{config.EmptyStateFavorites, []string{notes.ID, mail.ID}},
+		{config.EmptyStateRecent, []string{"clipboard:2", "clipboard:1"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			apps := &testProvider{id: "apps", results: []search.Result{mail, notes, safari}}
+			g, _ := newTestService(t, func(c *config.Config) {
+				c.EmptyState = tt.mode
+				c.Favorites = []string{notes.ID, "apps:/Applications/Gone.app", mail.ID}
+			}, apps, clip)
+			now := time.Now()
+			for _, id := range []string{safari.ID, safari.ID, mail.ID} {
+				if err := g.frecency.Record(id, now); err != nil {
+					t.Fatal(err)
+				}
+			}
+			results, err := g.engine.Search(context.Background(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resultIDs(results); !slices.Equal(got, tt.want) {
+				t.Errorf("empty state = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyStateRecentQueries(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		g, _ := newTestService(t, func(c *config.Config) {
+			c.EmptyState = config.EmptyStateRecentQueries
+			c.QueryHistory = enabled
+		})
+		for _, q := range []string{"mail", "notes", "safari", "mail"} {
+			if err := g.history.Add(q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		results, err := g.engine.Search(context.Background(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Title)
+			if r.DefaultAction() != actionRepeatQuery {
+				t.Errorf("%q runs %q, want %q", r.Title, r.DefaultAction(), actionRepeatQuery)
+			}
+		}
+		var want []string
+		if enabled {
+			want = []string{"mail", "safari", "notes"}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("history on %v: empty state = %v, want %v", enabled, got, want)
+		}
+	}
+}
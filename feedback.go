@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"log"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+)
+
+// Feedback reacts to activations and to queries that find nothing.
+type Feedback interface {
+	Activated()
+	NoResults()
+}
+
+// platformFeedback plays the sound and haptic enabled in the config. It
+// returns at once and plays them on another goroutine, so feedback never
+// delays an action.
+type platformFeedback struct {
+	plat   platform.Platform
+	config *config.Store
+}
+
+func (f platformFeedback) Activated() { f.play(platform.SoundActivate) }
+
+func (f platformFeedback) NoResults() { f.play(platform.SoundNoResults) }
+
+func (f platformFeedback) play(s platform.Sound) {
+	c := f.config.Get().Feedback
+	if !c.Sound && !c.Haptic {
+		return
+	}
+	go func() {
+		if c.Sound {
+			logFeedbackError(f.plat.PlaySound(s))
+		}
+		if c.Haptic {
+			logFeedbackError(f.plat.Haptic())
+		}
+	}()
+}
+
+// logFeedbackError logs err unless the platform simply lacks the feedback.
+func logFeedbackError(err error) {
+	if err != nil && !errors.Is(err, platform.ErrUnsupported) {
+		log.Println("feedback:", err)
+	}
+}
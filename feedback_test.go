@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+func TestActivationFeedback(t *testing.T) {
+	mail := appResult("Mail")
+	g, _ := newTestService(t, nil, &testProvider{id: "apps", results: []search.Result{mail}})
+	fb := g.feedback.(*feedbackCounter)
+	if _, err := g.engine.Search(context.Background(), "mail"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RunAction(mail.ID, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if n := fb.activated.Load(); n != 1 {
+		t.Errorf("Activated called %d times, want 1", n)
+	}
+}
+
+// cues is a platform that reports the feedback it plays; it has no other
+// methods.
+type cues struct {
+	platform.Platform
+	played chan string
+}
+
+func (c cues) PlaySound(s platform.Sound) error {
+	c.played <- "sound"
+	return nil
+}
+
+func (c cues) Haptic() error {
+	c.played <- "haptic"
+	return nil
+}
+
+func TestPlatformFeedback(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Feedback
+		want []string
+	}{
+		{"off", config.Feedback{}, nil},
+		{"sound", config.Feedback{Sound: true}, []string{"sound"}},
+		{"both", config.Feedback{Sound: true, Haptic: true}, []string{"sound", "haptic"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := config.Open(filepath.Join(t.TempDir(), "config.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Update(func(c *config.Config) { c.Feedback = tt.cfg }); err != nil {
+				t.Fatal(err)
+			}
+			plat := cues{played: make(chan string, 2)}
+			platformFeedback{plat: plat, config: store}.Activated()
+
+			var got []string
+			for range tt.want {
+				select {
+				case cue := <-plat.played:
+					got = append(got, cue)
+				case <-time.After(time.Second):
+				}
+			}
+			select {
+			case cue := <-plat.played:
+				got = append(got, cue)
+			case <-time.After(20 * time.Millisecond):
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("played %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
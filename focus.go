@@ -0,0 +1,31 @@
+package main
+
+import "time"
+
+// eventFocusInput asks the frontend to focus the search input.
+const eventFocusInput = "focus:input"
+
+// focusRetryDelay is how long after the window is shown the input is
+// focused a second time. The OS can activate the window after Show has
+// returned and take focus from the input the first request focused.
+const focusRetryDelay = 150 * time.Millisecond
+
+// showWindow shows and focuses the launcher and has the frontend focus its
+// input, at once and again after focusRetryDelay, so typing never goes
+// into a window whose input lost the race for focus.
+func showWindow(events Emitter) {
+	window.Show()
+	window.Focus()
+	focusInput(events, window.IsVisible)
+}
+
+// focusInput has the frontend focus its input now and again after
+// focusRetryDelay, unless the window was hidden by then.
+func focusInput(events Emitter, visible func() bool) {
+	events.EmitEvent(eventFocusInput)
+	time.AfterFunc(focusRetryDelay, func() {
+		if visible() {
+			events.EmitEvent(eventFocusInput)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFocusInput(t *testing.T) {
+	for _, visible := range []bool{true, false} {
+		events := &recorder{}
+		var shown atomic.Bool
+		shown.Store(true)
+		focusInput(events, shown.Load)
+		if got := events.emitted(); !slices.Equal(got, []string{eventFocusInput}) {
+			t.Errorf("events = %v, want the input focused at once", got)
+		}
+		shown.Store(visible)
+		time.Sleep(focusRetryDelay + 100*time.Millisecond)
+		want := []string{eventFocusInput}
+		if visible {
+			want = append(want, eventFocusInput)
+		}
+		// The request is repeated only while the window is still shown.
+		if got := events.emitted(); !slices.Equal(got, want) {
+			t.Errorf("visible %v: events after the retry = %v, want %v", visible, got, want)
+		}
+	}
+}
@@ -5,8 +5,11 @@ go 1.22.4
 toolchain go1.23.3
 
 require (
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/wailsapp/wails/v3 v3.0.0-alpha.7
 	golang.design/x/hotkey v0.4.1
+	golang.org/x/sys v0.20.0
 )
 
 require (
@@ -47,7 +50,6 @@ require (
 	golang.org/x/mod v0.17.0 // indirect
 	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/tools v0.21.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 )
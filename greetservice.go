@@ -1,7 +1,661 @@
 package main
 
-type GreetService struct{}
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"changeme/internal/audit"
+	"changeme/internal/config"
+	"changeme/internal/frecency"
+	"changeme/internal/history"
+	"changeme/internal/interactions"
+	"changeme/internal/platform"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+// actionAddFavorite and actionRemoveFavorite toggle a result's favorite
+// status. Every result offers whichever applies; they are handled by
+// GreetService rather than the result's provider.
+const (
+	actionAddFavorite    = "prism.favorite.add"
+	actionRemoveFavorite = "prism.favorite.remove"
+)
+
+// actionRepeatQuery runs a query from the recent-queries empty state again.
+// Its results have IDs of recentQueryPrefix followed by the query.
+const (
+	actionRepeatQuery = "prism.query.repeat"
+	recentQueryPrefix = "history:"
+)
+
+// queryTooLongID is the ID of the notice shown instead of results for a
+// query longer than the configured MaxQueryLength.
+const queryTooLongID = "prism.query.too-long"
+
+// eventQuerySet asks the frontend to replace the query with the string it
+// carries and search for it.
+const eventQuerySet = "query:set"
+
+// eventResultsUpdated carries a ResultsUpdate for results that arrive after
+// Search has returned.
+const eventResultsUpdated = "results:updated"
+
+// eventResultCount carries a ResultCount for each update of the results when
+// ResultCountBadge is set, including the first, which Search returns.
+const eventResultCount = "results:count"
+
+// eventResultsChange carries a ResultsChange just before each update of the
+// results, including the first, which Search returns.
+const eventResultsChange = "results:change"
+
+// syncBudget is how long Search waits for providers before returning what it
+// has; later results are delivered as events.
+const syncBudget = 30 * time.Millisecond
+
+// Emitter sends events to the frontend.
+type Emitter interface {
+	EmitEvent(name string, data ...any)
+}
+
+// ResultsUpdate is the payload of eventResultsUpdated.
+type ResultsUpdate struct {
+	Query   string          `json:"query"`
+	Results []search.Result `json:"results"`
+	Done    bool            `json:"done"`
+	// NoResults is set when the search is done and nothing matched;
+	// Results then holds the configured fallback, if any.
+	NoResults bool `json:"noResults,omitempty"`
+}
+
+// ResultCount is the payload of eventResultCount.
+type ResultCount struct {
+	Query string `json:"query"`
+	// Shown is how many results are listed, and Total how many matched
+	// before MaxResults applied. They grow as slower providers finish.
+	Shown int  `json:"shown"`
+	Total int  `json:"total"`
+	Done  bool `json:"done"`
+}
+
+// ResultsChange is the payload of eventResultsChange.
+type ResultsChange struct {
+	Query string `json:"query"`
+	// Change is one of the search.Change* kinds, comparing the results
+	// with those shown before, which may be for an earlier query.
+	Change string `json:"change"`
+}
+
+type GreetService struct {
+	engine   *search.Engine
+	config   *config.Store
+	frecency *frecency.Store
+	learned  *interactions.Store
+	events   Emitter
+	audit    *audit.Log
+	history  *history.Store
+	feedback Feedback
+	idle     *idleHider
+	fallback noResults
+	out      clipboard.Output
+	clip     clipboard.Clipboard
+	dock     *dockApps
+
+	// applyAppearance restyles the window from the config for the display
+	// it is on; see SetWindowAppearance.
+	applyAppearance func()
+
+	mu           sync.Mutex
+	frontmost    platform.App
+	cancelSearch context.CancelFunc
+	lastQuery    string
+	// lastResults are the latest results of the current search, before
+	// decorate, so they can be decorated again when a favorite changes.
+	lastResults []search.Result
+	// keepOpenUntil is when the hold of the latest keep-open action ends.
+	keepOpenUntil time.Time
+	// undo is the last action run, if it can be undone; see
+	// UndoLastAction.
+	undo undoable
+	// selected are the results chosen for a batch action; see
+	// ToggleResultSelection.
+	selected []search.Result
+	// selectionTimer and cancelPreview belong to the preview fetch of the
+	// latest selection; see OnSelectionChanged.
+	selectionTimer *time.Timer
+	cancelPreview  context.CancelFunc
+	// lastLaunch is what RepeatLast runs again.
+	lastLaunch *launch
+	// lastActivated and lastActivatedAt are the last result and action
+	// run and when, for ignoring an accidental second activation; see
+	// debounced.
+	lastActivated   activation
+	lastActivatedAt time.Time
+}
+
+func NewGreetService(engine *search.Engine, cfg *config.Store, fr *frecency.Store, learned *interactions.Store, events Emitter, auditLog *audit.Log, hist *history.Store, feedback Feedback, idle *idleHider, fallback noResults, out clipboard.Output, clip clipboard.Clipboard, applyAppearance func()) *GreetService {
+	g := &GreetService{
+		engine:          engine,
+		config:          cfg,
+		frecency:        fr,
+		learned:         learned,
+		events:          events,
+		audit:           auditLog,
+		history:         hist,
+		feedback:        feedback,
+		idle:            idle,
+		fallback:        fallback,
+		out:             out,
+		clip:            clip,
+		dock:            &dockApps{},
+		applyAppearance: applyAppearance,
+	}
+	engine.SetEmptyState(g.emptyState)
+	return g
+}
 
 func (g *GreetService) Greet(name string) string {
 	return "Hello " + name + "!"
 }
+
+// Search returns the results for query that are ready within a few
+// milliseconds, typically from fast providers such as apps. Results from
+// slower providers follow as eventResultsUpdated events carrying the full,
+// re-merged list, in which results already shown keep their place when
+// StableResults is set. Starting a new search cancels the previous one. Provider
+// failures are logged and do not hide results from providers that succeeded.
+func (g *GreetService) Search(query string) []search.Result {
+	g.idle.Touch()
+	ctx, cancel := context.WithCancel(context.Background())
+	g.mu.Lock()
+	if g.cancelSearch != nil {
+		g.cancelSearch()
+	}
+	g.cancelSearch = cancel
+	g.lastQuery = query
+	g.mu.Unlock()
+
+	cfg := g.config.Get()
+	if max := cfg.MaxQueryLength; max > 0 && len(query) > max && utf8.RuneCountInString(query) > max {
+		g.mu.Lock()
+		g.lastResults = nil
+		g.mu.Unlock()
+		return []search.Result{queryTooLong(max)}
+	}
+
+	var (
+		mu       sync.Mutex
+		latest   []search.Result
+		returned bool
+	)
+	ready := make(chan struct{})
+	go func() {
+		var shown []search.Result
+		err := g.engine.Stream(ctx, query, func(u search.Update) {
+			results := append([]search.Result(nil), u.Results...)
+			if cfg.StableResults {
+				results = search.Stabilize(shown, results, search.StableScoreDelta)
+				shown = results
+			}
+			change := ""
+			g.mu.Lock()
+			if ctx.Err() == nil {
+				change = search.Change(g.lastResults, results)
+				g.lastResults = slices.Clone(results)
+			}
+			g.mu.Unlock()
+			results = g.withUndo(results)
+			if change != "" {
+				g.events.EmitEvent(eventResultsChange, ResultsChange{Query: query, Change: change})
+			}
+			g.decorate(results, cfg)
+			if cfg.ResultCountBadge && ctx.Err() == nil {
+				g.events.EmitEvent(eventResultCount, ResultCount{Query: query, Shown: len(results), Total: max(u.Total, len(results)), Done: u.Done})
+			}
+			// Sticky results head every search, so on their own they
+			// still mean nothing matched.
+			none := u.Done && !slices.ContainsFunc(results, func(r search.Result) bool { return !r.Sticky }) && strings.TrimSpace(query) != ""
+			if none {
+				g.feedback.NoResults()
+				results = append(results, g.fallback.results(query, cfg)...)
+				applyShortcuts(results, cfg)
+				if ctx.Err() == nil {
+					g.events.EmitEvent(eventNoResults, ResultsUpdate{Query: query, Results: results, Done: true, NoResults: true})
+				}
+			}
+			mu.Lock()
+			latest = results
+			late := returned
+			mu.Unlock()
+			if late {
+				g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{Query: query, Results: results, Done: u.Done})
+			} else if u.Done {
+				close(ready)
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Println(err)
+		}
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(syncBudget):
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	returned = true
+	return latest
+}
+
+// queryTooLong is the notice shown for a query longer than max characters.
+// It has no actions, so activating it does nothing.
+func queryTooLong(max int) search.Result {
+	return search.Result{
+		ID:       queryTooLongID,
+		Type:     "notice",
+		Title:    "Query Too Long",
+		Subtitle: "Prism searches queries of up to " + strconv.Itoa(max) + " characters.",
+	}
+}
+
+// decorate adds the actions GreetService handles itself and the configured
+// shortcuts to results.
+func (g *GreetService) decorate(results []search.Result, cfg config.Config) {
+	for i, r := range results {
+		favorite := slices.Contains(cfg.Favorites, r.ID)
+		results[i].Favorite = favorite
+		// The provider's slice may have spare capacity shared with another
+		// copy of the result, so the actions are copied before appending.
+		actions := slices.Clip(r.Actions)
+		if favorite {
+			actions = append(actions, search.Action{ID: actionRemoveFavorite, Title: "Remove from Favorites"})
+		} else {
+			actions = append(actions, search.Action{ID: actionAddFavorite, Title: "Add to Favorites"})
+		}
+		if _, ok := markdownLink(r); ok {
+			actions = append(actions, search.Action{ID: actionCopyMarkdown, Title: "Copy as Markdown Link"})
+		}
+		if copyableContents(r) {
+			actions = append(actions, search.Action{ID: actionCopyContents, Title: "Copy Contents"})
+		}
+		if r.Provider == "files" && trashable(r.Target) == nil {
+			actions = append(actions, search.Action{ID: actionTrash, Title: "Move to Trash"})
+		}
+		actions = append(actions, appActions(r)...)
+		if dockable(r) {
+			actions = append(actions, g.dockAction(r))
+		}
+		if opensInTerminal(r) {
+			actions = append(actions, search.Action{ID: actionOpenTerminal, Title: "Open in Terminal"})
+		}
+		if airDroppable(r) {
+			actions = append(actions, search.Action{ID: actionAirDrop, Title: "Share via AirDrop", HideBeforeRun: true})
+		}
+		if _, ok := qrText(r); ok {
+			actions = append(actions, search.Action{ID: actionShowQR, Title: "Show QR Code"})
+		}
+		actions = append(actions, search.Action{ID: actionCopySummary, Title: "Copy Result Summary"})
+		results[i].Actions = actions
+	}
+	applyDefaultActions(results, cfg)
+	g.applySubtitleTemplates(results, cfg)
+	stripIcons(results, cfg)
+	applyShortcuts(results, cfg)
+}
+
+// refreshResults emits the current results again, decorated with the
+// current config, so a favorite toggled with an action shows its new state
+// without searching again.
+func (g *GreetService) refreshResults() {
+	g.mu.Lock()
+	query := g.lastQuery
+	results := slices.Clone(g.lastResults)
+	g.mu.Unlock()
+	if results == nil {
+		return
+	}
+	results = g.withUndo(results)
+	g.decorate(results, g.config.Get())
+	g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{Query: query, Results: results, Done: true})
+}
+
+// ActionHint returns the action hint for a result from the last search, so
+// the UI can show it for the selected row when hints are limited to it.
+func (g *GreetService) ActionHint(resultID string) string {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return ""
+	}
+	cfg := g.config.Get()
+	cfg.ShowActionHints, cfg.ActionHintsSelectedOnly = true, false
+	results := []search.Result{r}
+	g.decorate(results, cfg)
+	return results[0].Hint
+}
+
+// keepOpenGrace is how long after a keep-open action the window ignores
+// losing focus, since the focus change an action causes, such as launching
+// an app, is reported after the action has returned.
+const keepOpenGrace = time.Second
+
+// Activate runs actionID on a result from the last search and hides the
+// launcher, unless it is pinned. An empty actionID runs the result's default
+// action.
+func (g *GreetService) Activate(resultID, actionID string) error {
+	return g.RunAction(resultID, actionID, false)
+}
+
+// RunAction runs actionID on a result like Activate. With keepOpen the
+// launcher stays open afterwards, even if the action takes focus away, so
+// the user can go on from the same results. Unlike pinning, this applies
+// to the one action only.
+func (g *GreetService) RunAction(resultID, actionID string, keepOpen bool) error {
+	if g.debounced(resultID, actionID, time.Now(), time.Duration(g.config.Get().ActivationDebounceMs)*time.Millisecond) {
+		return nil
+	}
+	if keepOpen {
+		g.holdOpen()
+		defer g.holdOpen()
+	}
+	if resultID == undoID {
+		return g.UndoLastAction()
+	}
+	g.forgetUndo()
+	switch actionID {
+	case actionAddFavorite, actionRemoveFavorite:
+		var err error
+		if actionID == actionAddFavorite {
+			err = g.PinResult(resultID)
+		} else {
+			err = g.UnpinResult(resultID)
+		}
+		if err != nil {
+			return err
+		}
+		g.refreshResults()
+		return nil
+	}
+	if resultID == batchTrashConfirmID {
+		return g.runBatchTrash()
+	}
+	if actionID == actionTrash || isTrashConfirmation(resultID) {
+		return g.runTrash(resultID)
+	}
+	if actionID == actionDock || isDockConfirmation(resultID) {
+		return g.runDock(resultID)
+	}
+	if resultID == usageImportID {
+		return g.runUsageImport(actionID)
+	}
+	if actionID == actionAirDrop {
+		return g.AirDrop(resultID)
+	}
+	if actionID == actionShowQR {
+		return g.showQR(resultID)
+	}
+	var run func(resultID string) error
+	switch actionID {
+	case actionCopyMarkdown:
+		run = g.CopyMarkdownLink
+	case actionCopySummary:
+		run = func(resultID string) error { return g.CopyResultSummary(resultID, g.config.Get().ResultSummaryJSON) }
+	case actionCopyContents:
+		run = g.copyContents
+	case actionOpenTerminal:
+		run = g.OpenInTerminal
+	case actionCopyBundleID, actionCopyVersion, actionCopyPath:
+		run = func(resultID string) error { return g.copyAppDetail(resultID, actionID) }
+	}
+	if run != nil {
+		if err := run(resultID); err != nil {
+			return err
+		}
+		if !keepOpen {
+			g.idle.Hide()
+		}
+		return nil
+	}
+	if resultID == queryTooLongID || resultID == repeatFailedID {
+		return nil
+	}
+	if strings.HasPrefix(resultID, noResultsPrefix) {
+		return g.runFallback(resultID, keepOpen)
+	}
+	if strings.HasPrefix(resultID, clipSuggestPrefix) {
+		return g.runClipboardSuggestion(resultID)
+	}
+	if query, ok := strings.CutPrefix(resultID, recentQueryPrefix); ok {
+		g.events.EmitEvent(eventQuerySet, query)
+		return nil
+	}
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		// Activate reports it unknown; the audit log still names it.
+		r.ID = resultID
+	}
+	cfg := g.config.Get()
+	if actionID == "" {
+		actionID = defaultAction(r, cfg)
+	}
+	activate := func() error { return g.engine.Activate(context.Background(), resultID, actionID) }
+	var err error
+	if hideBeforeRun(r, actionID, cfg) {
+		// Yield shows the window again once the action has run when
+		// keepOpen is set; Refocus below covers apps that take focus
+		// later.
+		err = g.idle.Yield(activate, keepOpen)
+	} else {
+		err = activate()
+	}
+	if keepOpen && cfg.ReturnFocusAfterAction {
+		g.idle.Refocus()
+	}
+	again := errors.Is(err, search.ErrSearchAgain)
+	var handOff *search.QueryError
+	if again || errors.As(err, &handOff) {
+		err = nil
+	}
+	g.auditAction(r, actionID, err)
+	if err != nil {
+		return err
+	}
+	if again {
+		g.mu.Lock()
+		query := g.lastQuery
+		g.mu.Unlock()
+		g.events.EmitEvent(eventQuerySet, query)
+		return nil
+	}
+	if handOff != nil {
+		g.events.EmitEvent(eventQuerySet, handOff.Query)
+		return nil
+	}
+	g.feedback.Activated()
+	g.remember(r, actionID)
+	if err := g.frecency.Record(resultID, time.Now()); err != nil {
+		log.Println(err)
+	}
+	g.recordChoice(resultID)
+	g.recordQuery()
+	if !keepOpen {
+		g.idle.Hide()
+	}
+	return nil
+}
+
+// auditAction records actionID run on r in the audit log, with err when it
+// failed.
+func (g *GreetService) auditAction(r search.Result, actionID string, err error) {
+	rec := audit.Record{Provider: r.Provider, ResultID: r.ID, Action: actionID, Detail: r.Target}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	g.audit.Write(rec)
+}
+
+// runFallback activates a result shown because the last query matched
+// nothing. Fallback results are rebuilt from the query rather than kept.
+func (g *GreetService) runFallback(resultID string, keepOpen bool) error {
+	g.mu.Lock()
+	query := g.lastQuery
+	g.mu.Unlock()
+	for _, r := range g.fallback.results(query, g.config.Get()) {
+		if r.ID != resultID {
+			continue
+		}
+		if len(r.Actions) == 0 {
+			return nil
+		}
+		if err := g.fallback.activate(context.Background(), r); err != nil {
+			return err
+		}
+		g.feedback.Activated()
+		if !keepOpen {
+			g.idle.Hide()
+		}
+		return nil
+	}
+	return search.ErrUnknownResult
+}
+
+// holdOpen keeps the window from hiding on focus loss for keepOpenGrace.
+func (g *GreetService) holdOpen() {
+	g.mu.Lock()
+	g.keepOpenUntil = time.Now().Add(keepOpenGrace)
+	g.mu.Unlock()
+}
+
+// HoldsOpen reports whether the window should stay open when it loses
+// focus: while it is pinned and just after a keep-open action.
+func (g *GreetService) HoldsOpen() bool {
+	g.mu.Lock()
+	held := time.Now().Before(g.keepOpenUntil)
+	g.mu.Unlock()
+	return held || g.Pinned()
+}
+
+// recordQuery adds the query that led to an activation to the history,
+// unless history is turned off.
+func (g *GreetService) recordQuery() {
+	g.mu.Lock()
+	query := strings.TrimSpace(g.lastQuery)
+	g.mu.Unlock()
+	if query == "" || !g.config.Get().QueryHistory {
+		return
+	}
+	if err := g.history.Add(query); err != nil {
+		log.Println(err)
+	}
+}
+
+// ClearQueryHistory forgets every remembered query.
+func (g *GreetService) ClearQueryHistory() error {
+	return g.history.Clear()
+}
+
+// BeginSession starts a new search session, discarding per-session caches,
+// and starts the auto-hide countdown. A window shown again after Yield
+// goes on with its session instead.
+func (g *GreetService) BeginSession() {
+	if g.idle.resumeSession() {
+		return
+	}
+	g.engine.BeginSession()
+	if runtime.GOOS == "darwin" {
+		go g.dock.refresh(context.Background())
+	}
+	g.idle.Start(time.Duration(g.config.Get().AutoHideAfterMs) * time.Millisecond)
+}
+
+// EndSession stops the auto-hide countdown and clears the selection once
+// the window is hidden, unless Yield hid it and will show it again.
+func (g *GreetService) EndSession() {
+	if g.idle.holdSession() {
+		return
+	}
+	g.idle.Stop()
+	g.ClearSelection()
+}
+
+// Touch tells Prism the user is active in the window, such as moving the
+// selection, so that it does not auto-hide. Typing a query counts already.
+func (g *GreetService) Touch() {
+	g.idle.Touch()
+}
+
+// SetPinned pins the window open: it no longer hides when it loses focus or
+// after inactivity.
+func (g *GreetService) SetPinned(pinned bool) {
+	g.idle.SetPinned(pinned)
+}
+
+// Pinned reports whether the window is pinned open.
+func (g *GreetService) Pinned() bool {
+	return g.idle.Pinned()
+}
+
+// FrontmostApp returns the application that had focus when the launcher was
+// last shown.
+func (g *GreetService) FrontmostApp() platform.App {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.frontmost
+}
+
+// captureFrontmostApp records the focused application before Prism takes
+// focus, so actions can later target or return to it.
+func (g *GreetService) captureFrontmostApp(plat platform.Platform) {
+	app, err := plat.FrontmostApp(context.Background())
+	if err != nil {
+		app = platform.App{}
+	}
+	g.mu.Lock()
+	g.frontmost = app
+	g.mu.Unlock()
+}
+
+// PinResult adds a result to the end of the favorites.
+func (g *GreetService) PinResult(id string) error {
+	return g.config.Update(func(c *config.Config) {
+		if !slices.Contains(c.Favorites, id) {
+			c.Favorites = append(c.Favorites, id)
+		}
+	})
+}
+
+// UnpinResult removes a result from the favorites.
+func (g *GreetService) UnpinResult(id string) error {
+	return g.config.Update(func(c *config.Config) {
+		c.Favorites = slices.DeleteFunc(c.Favorites, func(f string) bool { return f == id })
+	})
+}
+
+// ReorderFavorites puts the favorites in the order given by ids. IDs that are
+// not favorites are ignored, and favorites missing from ids keep their
+// relative order after the ones listed, so a stale list from the UI never
+// drops a pin.
+func (g *GreetService) ReorderFavorites(ids []string) error {
+	return g.config.Update(func(c *config.Config) {
+		ordered := make([]string, 0, len(c.Favorites))
+		for _, id := range ids {
+			if slices.Contains(c.Favorites, id) && !slices.Contains(ordered, id) {
+				ordered = append(ordered, id)
+			}
+		}
+		for _, id := range c.Favorites {
+			if !slices.Contains(ordered, id) {
+				ordered = append(ordered, id)
+			}
+		}
+		c.Favorites = ordered
+	})
+}
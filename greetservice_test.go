@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/frecency"
+	"changeme/internal/history"
+	"changeme/internal/interactions"
+	"changeme/internal/search"
+)
+
+// testProvider serves a fixed set of results: those whose title contains
+// the query, and any of them by ID. It records the actions run on them.
+type testProvider struct {
+	id      string
+	results []search.Result
+
+	mu        sync.Mutex
+	activated []string
+}
+
+func (p *testProvider) ID() string { return p.id }
+
+func (p *testProvider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	var out []search.Result
+	for _, r := range p.results {
+		if strings.Contains(strings.ToLower(r.Title), strings.ToLower(query)) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (p *testProvider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	p.mu.Lock()
+	p.activated = append(p.activated, r.ID+" "+actionID)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *testProvider) Resolve(ctx context.Context, id string) (search.Result, bool) {
+	for _, r := range p.results {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return search.Result{}, false
+}
+
+// recentProvider is a testProvider that lists its results, in order, as
+// its history.
+type recentProvider struct {
+	testProvider
+}
+
+func (p *recentProvider) Recent(ctx context.Context, limit int) []search.Result {
+	return p.results[:min(limit, len(p.results))]
+}
+
+// ran returns the actions run so far, as "<result ID> <action ID>".
+func (p *testProvider) ran() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.activated)
+}
+
+// recorder is an Emitter that keeps the events emitted.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	data   [][]any
+}
+
+func (r *recorder) EmitEvent(name string, data ...any) {
+	r.mu.Lock()
+	r.events = append(r.events, name)
+	r.data = append(r.data, data)
+	r.mu.Unlock()
+}
+
+// emitted returns the names of the events emitted so far.
+func (r *recorder) emitted() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// feedbackCounter is a Feedback that counts its cues.
+type feedbackCounter struct {
+	activated, noResults atomic.Int32
+}
+
+func (f *feedbackCounter) Activated() { f.activated.Add(1) }
+func (f *feedbackCounter) NoResults() { f.noResults.Add(1) }
+
+// appResult returns an app result with the given name.
+func appResult(name string) search.Result {
+	path := "/Applications/" + name + ".app"
+	return search.Result{
+		ID:       "apps:" + path,
+		Provider: "apps",
+		Type:     "app",
+		Title:    name,
+		Target:   path,
+		Score:    1,
+		Actions:  []search.Action{{ID: "open", Title: "Open"}},
+	}
+}
+
+// newTestService returns a service searching providers, with its stores in
+// a temporary directory, after applying edit to the default config.
+func newTestService(t *testing.T, edit func(*config.Config), providers ...search.Provider) (*GreetService, *recorder) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg, err := config.Open(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Update(func(c *config.Config) {
+		c.UsageImport = config.UsageImportDeclined
+		c.SuggestClipboard = false
+		if edit != nil {
+			edit(c)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	fr, err := frecency.Open(filepath.Join(dir, "frecency.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	learned, err := interactions.Open(filepath.Join(dir, "interactions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, err := history.Open(filepath.Join(dir, "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := &recorder{}
+	idle := newIdleHider(func() {}, func() {})
+	g := NewGreetService(search.NewEngine(providers...), cfg, fr, learned, events, nil, hist, &feedbackCounter{}, idle, noResults{}, nil, nil, func() {})
+	return g, events
+}
+
+// resultIDs returns the IDs of results, in order.
+func resultIDs(results []search.Result) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func TestFavoritesRoundTrip(t *testing.T) {
+	g, _ := newTestService(t, nil)
+	for _, id := range []string{"apps:a", "apps:b", "apps:c", "apps:a"} {
+		if err := g.PinResult(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	steps := []struct {
+		name string
+		do   func() error
+		want []string
+	}{
+		{"pin", func() error { return nil }, []string{"apps:a", "apps:b", "apps:c"}},
+		{"reorder", func() error { return g.ReorderFavorites([]string{"apps:c", "apps:a", "apps:b"}) }, []string{"apps:c", "apps:a", "apps:b"}},
+		// A stale list from the UI neither drops nor adds pins.
+		{"partial reorder", func() error { return g.ReorderFavorites([]string{"apps:b", "apps:gone"}) }, []string{"apps:b", "apps:c", "apps:a"}},
+		{"unpin", func() error { return g.UnpinResult("apps:c") }, []string{"apps:b", "apps:a"}},
+	}
+	for _, s := range steps {
+		if err := s.do(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if got := g.config.Get().Favorites; !slices.Equal(got, s.want) {
+			t.Errorf("%s: favorites = %v, want %v", s.name, got, s.want)
+		}
+		// The change was saved, not just kept in memory.
+		reopened, err := config.Open(g.config.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reopened.Get().Favorites; !slices.Equal(got, s.want) {
+			t.Errorf("%s: saved favorites = %v, want %v", s.name, got, s.want)
+		}
+	}
+}
+
+func TestFavoriteActionToggles(t *testing.T) {
+	mail := appResult("Mail")
+	g, events := newTestService(t, nil, &testProvider{id: "apps", results: []search.Result{mail}})
+	results := g.Search("mail")
+	if len(results) != 1 || results[0].Favorite {
+		t.Fatalf("Search = %+v, want Mail, not a favorite", results)
+	}
+
+	for _, step := range []struct {
+		action, wantNext string
+		favorite         bool
+	}{
+		{actionAddFavorite, actionRemoveFavorite, true},
+		{actionRemoveFavorite, actionAddFavorite, false},
+	} {
+		if err := g.Activate(mail.ID, step.action); err != nil {
+			t.Fatal(err)
+		}
+		if got := slices.Contains(g.config.Get().Favorites, mail.ID); got != step.favorite {
+			t.Errorf("after %s: favorite in config = %v, want %v", step.action, got, step.favorite)
+		}
+		// The results are sent again with the new state, without a search.
+		events.mu.Lock()
+		last := events.data[len(events.data)-1][0].(ResultsUpdate)
+		name := events.events[len(events.events)-1]
+		events.mu.Unlock()
+		if name != eventResultsUpdated || len(last.Results) != 1 {
+			t.Fatalf("after %s: last event %s %+v, want the results updated", step.action, name, last)
+		}
+		r := last.Results[0]
+		if r.Favorite != step.favorite || !slices.ContainsFunc(r.Actions, func(a search.Action) bool { return a.ID == step.wantNext }) {
+			t.Errorf("after %s: result = %+v, want Favorite %v and a %s action", step.action, r, step.favorite, step.wantNext)
+		}
+	}
+}
+
+func TestSearchLongQuery(t *testing.T) {
+	apps := &searchCounter{id: "apps"}
+	g, _ := newTestService(t, nil, apps)
+	query := strings.Repeat("a", 4<<20)
+	start := time.Now()
+	results := g.Search(query)
+	if d := time.Since(start); d > syncBudget {
+		t.Errorf("Search took %v", d)
+	}
+	if len(results) != 1 || results[0].ID != queryTooLongID {
+		t.Errorf("Search = %+v, want the query-too-long notice", results)
+	}
+	if n := apps.searched.Load(); n != 0 {
+		t.Errorf("providers searched %d times, want none", n)
+	}
+	// The limit counts characters, not bytes.
+	if results := g.Search(strings.Repeat("é", 1000)); len(results) == 1 && results[0].ID == queryTooLongID {
+		t.Error("a query of 1000 characters was too long")
+	}
+}
+
+// searchCounter is a provider that counts its searches and finds nothing.
+type searchCounter struct {
+	id       string
+	searched atomic.Int32
+}
+
+func (f *searchCounter) ID() string { return f.id }
+
+func (f *searchCounter) Search(ctx context.Context, query string) ([]search.Result, error) {
+	f.searched.Add(1)
+	return nil, nil
+}
+
+func (f *searchCounter) Activate(ctx context.Context, r search.Result, actionID string) error {
+	return nil
+}
+
+func TestKeepOpen(t *testing.T) {
+	mail := appResult("Mail")
+	g, _ := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 }, &testProvider{id: "apps", results: []search.Result{mail}})
+	hides := 0
+	g.idle = newIdleHider(func() { hides++ }, func() {})
+	if _, err := g.engine.Search(context.Background(), "mail"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RunAction(mail.ID, "", true); err != nil {
+		t.Fatal(err)
+	}
+	if hides != 0 {
+		t.Errorf("keep-open action hid the window %d times", hides)
+	}
+	if !g.HoldsOpen() {
+		t.Error("window does not hold open just after a keep-open action")
+	}
+	if err := g.Activate(mail.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if hides != 1 {
+		t.Errorf("plain activation hid the window %d times, want 1", hides)
+	}
+}
+
+func TestResultsChangeEvent(t *testing.T) {
+	apps := &testProvider{id: "apps", results: []search.Result{appResult("Mail"), appResult("Maps"), appResult("Notes")}}
+	g, events := newTestService(t, nil, apps)
+	for _, q := range []string{"ma", "map", "no"} {
+		g.Search(q)
+	}
+	var got []string
+	events.mu.Lock()
+	for i, name := range events.events {
+		if name == eventResultsChange {
+			got = append(got, events.data[i][0].(ResultsChange).Change)
+		}
+	}
+	events.mu.Unlock()
+	if want := []string{search.ChangeReplace, search.ChangeRefine, search.ChangeReplace}; !slices.Equal(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+}
+
+// stepProvider is a testProvider that logs each activation with note.
+type stepProvider struct {
+	*testProvider
+	note func(string)
+}
+
+func (p stepProvider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	p.note("run")
+	return p.testProvider.Activate(ctx, r, actionID)
+}
+
+func TestReturnFocusAfterAction(t *testing.T) {
+	mail := appResult("Mail")
+	for _, returnFocus := range []bool{false, true} {
+		var mu sync.Mutex
+		var steps []string
+		note := func(step string) {
+			mu.Lock()
+			steps = append(steps, step)
+			mu.Unlock()
+		}
+		g, _ := newTestService(t, func(c *config.Config) {
+			c.ActivationDebounceMs = 0
+			c.HideBeforeRun = map[string]bool{"apps": true}
+			c.ReturnFocusAfterAction = returnFocus
+		}, stepProvider{&testProvider{id: "apps", results: []search.Result{mail}}, note})
+		g.idle = newIdleHider(func() { note("hide") }, func() { note("show") })
+		if _, err := g.engine.Search(context.Background(), "mail"); err != nil {
+			t.Fatal(err)
+		}
+
+		// The window hides before the action, shows again once it has run
+		// and, with returnFocus, takes focus back once the app has.
+		if err := g.RunAction(mail.ID, "", true); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"hide", "run", "show"}
+		if returnFocus {
+			want = append(want, "show")
+		}
+		time.Sleep(refocusDelay + 100*time.Millisecond)
+		mu.Lock()
+		got := slices.Clone(steps)
+		steps = nil
+		mu.Unlock()
+		if !slices.Equal(got, want) {
+			t.Errorf("return focus %v: keep-open steps = %v, want %v", returnFocus, got, want)
+		}
+
+		// An action that does not keep the launcher open hides it and does
+		// not take focus back.
+		if err := g.RunAction(mail.ID, "", false); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(refocusDelay + 100*time.Millisecond)
+		mu.Lock()
+		got = slices.Clone(steps)
+		mu.Unlock()
+		if want := []string{"hide", "run", "hide"}; !slices.Equal(got, want) {
+			t.Errorf("return focus %v: steps = %v, want %v", returnFocus, got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// hideBeforeRun reports whether the launcher hides before actionID runs on
+// r. The configured "provider:action" key decides first, then the
+// provider's key, then the action's own HideBeforeRun.
+func hideBeforeRun(r search.Result, actionID string, cfg config.Config) bool {
+	provider, hide := r.Provider, false
+	for _, a := range r.Actions {
+		if a.ID == actionID {
+			hide = a.HideBeforeRun
+			if a.Provider != "" {
+				provider = a.Provider
+			}
+			break
+		}
+	}
+	if v, ok := cfg.HideBeforeRun[provider+":"+actionID]; ok {
+		return v
+	}
+	if v, ok := cfg.HideBeforeRun[provider]; ok {
+		return v
+	}
+	return hide
+}
@@ -0,0 +1,15 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// showHideModifiers is held with space to toggle the launcher: Option+Space.
+var showHideModifiers = []hotkey.Modifier{hotkey.ModOption}
+
+// hotkeyModifiers maps the modifier names of configured shortcuts.
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":   hotkey.ModCtrl,
+	"shift":  hotkey.ModShift,
+	"alt":    hotkey.ModOption,
+	"option": hotkey.ModOption,
+	"cmd":    hotkey.ModCmd,
+}
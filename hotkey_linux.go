@@ -0,0 +1,17 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// showHideModifiers is held with space to toggle the launcher: Alt+Space.
+// X11 reports Alt as Mod1.
+var showHideModifiers = []hotkey.Modifier{hotkey.Mod1}
+
+// hotkeyModifiers maps the modifier names of configured shortcuts.
+// X11 reports Alt as Mod1 and Super as Mod4.
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.Mod1,
+	"super": hotkey.Mod4,
+	"cmd":   hotkey.Mod4,
+}
@@ -0,0 +1,15 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// showHideModifiers is held with space to toggle the launcher: Alt+Space.
+var showHideModifiers = []hotkey.Modifier{hotkey.ModAlt}
+
+// hotkeyModifiers maps the modifier names of configured shortcuts.
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.ModAlt,
+	"win":   hotkey.ModWin,
+	"cmd":   hotkey.ModWin,
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.design/x/hotkey"
+)
+
+// hotkeyKeys are the key names a configured global shortcut can end in.
+var hotkeyKeys = map[string]hotkey.Key{
+	"0":      hotkey.Key0,
+	"1":      hotkey.Key1,
+	"2":      hotkey.Key2,
+	"3":      hotkey.Key3,
+	"4":      hotkey.Key4,
+	"5":      hotkey.Key5,
+	"6":      hotkey.Key6,
+	"7":      hotkey.Key7,
+	"8":      hotkey.Key8,
+	"9":      hotkey.Key9,
+	"a":      hotkey.KeyA,
+	"b":      hotkey.KeyB,
+	"c":      hotkey.KeyC,
+	"d":      hotkey.KeyD,
+	"e":      hotkey.KeyE,
+	"f":      hotkey.KeyF,
+	"g":      hotkey.KeyG,
+	"h":      hotkey.KeyH,
+	"i":      hotkey.KeyI,
+	"j":      hotkey.KeyJ,
+	"k":      hotkey.KeyK,
+	"l":      hotkey.KeyL,
+	"m":      hotkey.KeyM,
+	"n":      hotkey.KeyN,
+	"o":      hotkey.KeyO,
+	"p":      hotkey.KeyP,
+	"q":      hotkey.KeyQ,
+	"r":      hotkey.KeyR,
+	"s":      hotkey.KeyS,
+	"t":      hotkey.KeyT,
+	"u":      hotkey.KeyU,
+	"v":      hotkey.KeyV,
+	"w":      hotkey.KeyW,
+	"x":      hotkey.KeyX,
+	"y":      hotkey.KeyY,
+	"z":      hotkey.KeyZ,
+	"f1":     hotkey.KeyF1,
+	"f2":     hotkey.KeyF2,
+	"f3":     hotkey.KeyF3,
+	"f4":     hotkey.KeyF4,
+	"f5":     hotkey.KeyF5,
+	"f6":     hotkey.KeyF6,
+	"f7":     hotkey.KeyF7,
+	"f8":     hotkey.KeyF8,
+	"f9":     hotkey.KeyF9,
+	"f10":    hotkey.KeyF10,
+	"f11":    hotkey.KeyF11,
+	"f12":    hotkey.KeyF12,
+	"f13":    hotkey.KeyF13,
+	"f14":    hotkey.KeyF14,
+	"f15":    hotkey.KeyF15,
+	"f16":    hotkey.KeyF16,
+	"f17":    hotkey.KeyF17,
+	"f18":    hotkey.KeyF18,
+	"f19":    hotkey.KeyF19,
+	"f20":    hotkey.KeyF20,
+	"space":  hotkey.KeySpace,
+	"return": hotkey.KeyReturn,
+	"enter":  hotkey.KeyReturn,
+	"tab":    hotkey.KeyTab,
+	"escape": hotkey.KeyEscape,
+	"delete": hotkey.KeyDelete,
+	"up":     hotkey.KeyUp,
+	"down":   hotkey.KeyDown,
+	"left":   hotkey.KeyLeft,
+	"right":  hotkey.KeyRight,
+}
+
+// parseHotkey reads a global shortcut such as "alt+shift+r": modifier names
+// from hotkeyModifiers followed by one key from hotkeyKeys, joined by "+".
+func parseHotkey(s string) ([]hotkey.Modifier, hotkey.Key, error) {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(s, " ", "")), "+")
+	key, ok := hotkeyKeys[parts[len(parts)-1]]
+	if !ok {
+		return nil, 0, fmt.Errorf("hotkey: unknown key in %q", s)
+	}
+	mods := make([]hotkey.Modifier, 0, len(parts)-1)
+	for _, name := range parts[:len(parts)-1] {
+		mod, ok := hotkeyModifiers[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("hotkey: unknown modifier %q in %q", name, s)
+		}
+		mods = append(mods, mod)
+	}
+	return mods, key, nil
+}
@@ -0,0 +1,137 @@
+// Package audit appends a record of every action run from the launcher to a
+// JSON-lines file, for machines where an administrator needs to know what
+// was launched or run.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// bufferSize is the number of records that may wait to be written. When the
+// writer falls this far behind, new records are dropped rather than
+// blocking the action that produced them.
+const bufferSize = 256
+
+// Record describes one action.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	ResultID string    `json:"resultId"`
+	Action   string    `json:"action"`
+	// Detail is provider-specific, such as the command line a shell
+	// provider ran.
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Log writes records in the background. A nil *Log discards everything, so
+// callers need not check whether auditing is enabled.
+type Log struct {
+	path     string
+	maxBytes int64
+	keep     int
+
+	records chan Record
+	done    chan struct{}
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// Open starts a log at path. When the file grows past maxBytes it is rotated
+// to path.1, path.2 and so on, keeping at most keep old files.
+func Open(path string, maxBytes int64, keep int) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	l := &Log{
+		path:     path,
+		maxBytes: maxBytes,
+		keep:     keep,
+		records:  make(chan Record, bufferSize),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l, nil
+}
+
+// Write queues r without blocking.
+func (l *Log) Write(r Record) {
+	if l == nil {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	select {
+	case l.records <- r:
+	default:
+		l.mu.Lock()
+		l.dropped++
+		l.mu.Unlock()
+	}
+}
+
+// Close flushes queued records and stops the writer.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	close(l.records)
+	<-l.done
+	return nil
+}
+
+func (l *Log) run() {
+	defer close(l.done)
+	for r := range l.records {
+		if err := l.append(r); err != nil {
+			log.Println("audit:", err)
+		}
+	}
+}
+
+func (l *Log) append(r Record) error {
+	l.mu.Lock()
+	dropped := l.dropped
+	l.dropped = 0
+	l.mu.Unlock()
+	if dropped > 0 {
+		r.Detail = fmt.Sprintf("%s (%d earlier records dropped)", r.Detail, dropped)
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(l.path); err == nil && l.maxBytes > 0 && fi.Size()+int64(len(line)) > l.maxBytes {
+		l.rotate()
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, and moves the
+// current file to path.1.
+func (l *Log) rotate() {
+	if l.keep <= 0 {
+		os.Remove(l.path)
+		return
+	}
+	os.Remove(fmt.Sprintf("%s.%d", l.path, l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	os.Rename(l.path, l.path+".1")
+}
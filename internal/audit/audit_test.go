@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// records reads the records in the log file at path.
+func records(t *testing.T, path string) []Record {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var out []Record
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "actions.jsonl")
+	l, err := Open(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Write(Record{Provider: "apps", ResultID: "apps:/Applications/Mail.app", Action: "open", Detail: "/Applications/Mail.app"})
+	l.Write(Record{Provider: "shell", ResultID: "shell:ls", Action: "run", Error: "exit status 1"})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := records(t, path)
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2", len(got))
+	}
+	if r := got[0]; r.Provider != "apps" || r.Action != "open" || r.Detail != "/Applications/Mail.app" || r.Time.IsZero() {
+		t.Errorf("first record = %+v", r)
+	}
+	if r := got[1]; r.ResultID != "shell:ls" || r.Error != "exit status 1" {
+		t.Errorf("second record = %+v", r)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "actions.jsonl")
+	l, err := Open(path, 200, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 6 {
+		l.Write(Record{Provider: "apps", ResultID: "apps:/Applications/Mail.app", Action: "open"})
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() > 200 {
+		t.Errorf("log not rotated: %v, %v", fi, err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("no rotated file: %v", err)
+	}
+	if _, err := os.Stat(path + ".2"); err == nil {
+		t.Errorf("kept more than 1 old file")
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	l.Write(Record{Action: "open"})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,682 @@
+// Package config loads and saves the user's Prism settings.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+)
+
+// Empty state modes, selecting what is shown before anything is typed.
+const (
+	EmptyStateFrecency  = "frecency"
+	EmptyStateFavorites = "favorites"
+	EmptyStateRecent    = "recent"
+	// EmptyStateRecentQueries lists the last distinct queries, so a search
+	// can be repeated with one keystroke.
+	EmptyStateRecentQueries = "recent-queries"
+)
+
+// No-results modes, selecting what is shown when a query matches nothing.
+const (
+	NoResultsNothing = "nothing"
+	// NoResultsWebSearch offers to search the web for the query with
+	// WebSearchURL.
+	NoResultsWebSearch = "web-search"
+	// NoResultsCreate offers to create a file named after the query in
+	// CreateDir.
+	NoResultsCreate = "create"
+	// NoResultsMessage shows NoResultsText.
+	NoResultsMessage = "message"
+)
+
+// FallbackWeb is the FallbackChain stage that offers a web search.
+const FallbackWeb = "web"
+
+// Window placements, selecting where the launcher opens.
+const (
+	WindowPlacementCenter = "center"
+	// WindowPlacementLastPosition opens the launcher where it was last
+	// dragged to, falling back to the center until it has been moved.
+	WindowPlacementLastPosition = "last-position"
+)
+
+// How apps sharing a name in different folders are listed.
+const (
+	// AppDuplicatesPreferSystem lists the system copy first.
+	AppDuplicatesPreferSystem = "prefer-system"
+	// AppDuplicatesShowAll lists the copies by how well they match.
+	AppDuplicatesShowAll = "show-all"
+)
+
+// How the window's look adapts to the display it is on; see
+// Config.DisplayAppearance.
+const (
+	// DisplayAppearanceUniform styles the window the same on every display.
+	DisplayAppearanceUniform = "uniform"
+	// DisplayAppearanceAdaptive uses Config.StandardDisplay on displays
+	// with a scale factor below 1.5, such as most external monitors.
+	DisplayAppearanceAdaptive = "adaptive"
+)
+
+// What a left click on the tray icon does. Right-clicking always opens the
+// menu.
+const (
+	TrayLeftClickMenu         = "menu"
+	TrayLeftClickToggleWindow = "toggleWindow"
+)
+
+// The built-in actions of tray menu items; see TrayItem.
+const (
+	// TrayActionShow shows or hides the launcher.
+	TrayActionShow = "show"
+	// TrayActionPause pauses or resumes background activity. Its label
+	// says which it will do, whatever the item's Label.
+	TrayActionPause = "pause"
+	// TrayActionUpdate opens the page of a newer version. The item is
+	// hidden until one is found.
+	TrayActionUpdate = "update"
+	// TrayActionSeparator is a line between items, which has no label.
+	TrayActionSeparator = "separator"
+	// TrayActionOpen, followed by a URL or an app or file path, opens it,
+	// as in "open:https://example.com".
+	TrayActionOpen = "open:"
+)
+
+// Layout densities for result rows.
+const (
+	// LayoutCompact shows one line of text per result, without icons.
+	LayoutCompact = "compact"
+	// LayoutComfortable shows a title and subtitle beside an icon.
+	LayoutComfortable = "comfortable"
+)
+
+// Clipboard actions, selecting what happens to text chosen from clipboard
+// history and similar providers.
+const (
+	ClipboardActionCopy  = "copy"
+	ClipboardActionPaste = "paste"
+	// ClipboardActionPastePlain pastes with HTML or RTF formatting removed.
+	ClipboardActionPastePlain = "paste-plain"
+)
+
+// What came of offering to import app usage from Spotlight. Until it is
+// one of these, the offer is shown before anything is typed.
+const (
+	UsageImportDone     = "done"
+	UsageImportDeclined = "declined"
+)
+
+// Audit configures the audit log of actions run from the launcher.
+type Audit struct {
+	Enabled bool `json:"enabled"`
+	// Path defaults to audit.log in the settings directory.
+	Path string `json:"path"`
+	// MaxSizeMB is the size at which the log is rotated.
+	MaxSizeMB int `json:"maxSizeMB"`
+	// Keep is the number of rotated files retained.
+	Keep int `json:"keep"`
+}
+
+// Performance is the budget for speculative work, such as prewarming the
+// top result before it is activated.
+type Performance struct {
+	// Prewarm lets providers prepare the top result ahead of activation.
+	Prewarm bool `json:"prewarm"`
+	// MaxConcurrentIO bounds the prewarms running at once.
+	MaxConcurrentIO int `json:"maxConcurrentIO"`
+	// PauseOnBattery skips prewarming while the machine runs on battery.
+	PauseOnBattery bool `json:"pauseOnBattery"`
+}
+
+// QueryRewrite replaces every match of the regular expression Pattern in a
+// query with Replacement, where $1 stands for the first submatch, before
+// the query is searched.
+type QueryRewrite struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// Quicklink opens a URL built from a keyword query, such as "jira PROJ-1".
+// In URL, {query} stands for everything typed after the keyword and {arg1},
+// {arg2} and so on for its words, each URL-encoded.
+type Quicklink struct {
+	Keyword string `json:"keyword"`
+	// Name is shown in the result; it defaults to the keyword.
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// DisplayAppearance is how the window looks on a kind of display.
+type DisplayAppearance struct {
+	// FontScale multiplies the size of the window's text.
+	FontScale    float64 `json:"fontScale"`
+	CornerRadius float64 `json:"cornerRadius"`
+	Shadow       bool    `json:"shadow"`
+}
+
+// TrayItem is an item of the tray menu. Action is one of the TrayAction*
+// values, or the ID of one of Prism's commands, such as "settings",
+// "rebuild-index" or "quit".
+type TrayItem struct {
+	Label  string `json:"label"`
+	Action string `json:"action"`
+}
+
+// LaunchLayout is a named set of things opened together, such as the apps
+// of a work day, by typing its name.
+type LaunchLayout struct {
+	Name string `json:"name"`
+	// Items are opened in order. Each is an app name, an app or file
+	// path, a URL, or a result ID such as "commands:settings".
+	Items []string `json:"items"`
+	// DelayMs waits between items so apps do not fight over focus; zero
+	// waits 300ms.
+	DelayMs int `json:"delayMs"`
+}
+
+// Feedback configures what happens when a result is activated or a query
+// finds nothing. Both are off by default.
+type Feedback struct {
+	// Sound plays a short system sound.
+	Sound bool `json:"sound"`
+	// Haptic taps the trackpad on hardware that supports it.
+	Haptic bool `json:"haptic"`
+}
+
+// Config is the on-disk settings file. Fields missing from the file keep
+// their default values.
+type Config struct {
+	// Prefixes maps a query prefix to the ID of the provider that handles
+	// queries starting with it exclusively.
+	Prefixes map[string]string `json:"prefixes"`
+	// EmptyState is one of the EmptyState* modes.
+	EmptyState string `json:"emptyState"`
+	// InstantFilter narrows the empty state results as the first
+	// keystrokes are typed, showing those that match at once while the
+	// providers are still searching.
+	InstantFilter bool `json:"instantFilter"`
+	// NoResults is one of the NoResults* modes. WebSearchURL has {query}
+	// replaced with the escaped query; CreateDir defaults to the Documents
+	// folder; NoResultsText defaults to "No Results".
+	NoResults     string `json:"noResults"`
+	WebSearchURL  string `json:"webSearchURL"`
+	CreateDir     string `json:"createDir"`
+	NoResultsText string `json:"noResultsText"`
+	// FallbackChain lists provider IDs tried one at a time, in order, once
+	// the other providers find nothing for a query, stopping at the first
+	// that does. FallbackWeb as a stage offers the web search; stages after
+	// it are never reached. A non-empty chain replaces NoResults, so a
+	// chain without FallbackWeb offers nothing when every stage is empty.
+	FallbackChain []string `json:"fallbackChain"`
+	// HideBeforeRun overrides, by "provider" or "provider:action" key,
+	// whether the launcher hides before an action runs so the app behind
+	// it has focus. Paste, menu, service and window switching actions
+	// hide by default.
+	HideBeforeRun map[string]bool `json:"hideBeforeRun"`
+	// ReturnFocusAfterAction brings the launcher back to the front after a
+	// keep-open action, once an app the action switched to has taken
+	// focus, so actions can be chained from the keyboard.
+	ReturnFocusAfterAction bool `json:"returnFocusAfterAction"`
+	// QueryRewrites are applied to every query in order, such as
+	// {"pattern": "^open\\s+", "replacement": ""} to ignore a leading
+	// "open". Invalid patterns are skipped with a warning.
+	QueryRewrites []QueryRewrite `json:"queryRewrites"`
+	// Quicklinks are keywords that open a URL built from the rest of the
+	// query.
+	Quicklinks []Quicklink `json:"quicklinks"`
+	// LaunchLayouts are found by name and open all their items.
+	LaunchLayouts []LaunchLayout `json:"launchLayouts"`
+	// Favorites are result IDs in the order they are shown.
+	Favorites []string `json:"favorites"`
+	// StickyResults are result IDs listed, in order, at the top of every
+	// search and of the empty state, except where a provider matched them
+	// already. Unlike favorites they show while a query is typed.
+	StickyResults []string `json:"stickyResults"`
+	// SubtitleTemplates replace the subtitle of a provider's results,
+	// keyed by provider ID, e.g. {"apps": "{bundleID} · {version}"}. Every
+	// result has {id}, {provider}, {type}, {title}, {subtitle}, {target}
+	// and {path}; apps add {name}, {bundleID}, {version} and {minimumOS}.
+	// Unknown fields render empty.
+	SubtitleTemplates map[string]string `json:"subtitleTemplates"`
+	// DefaultActions picks the action enter runs, keyed by result type,
+	// e.g. {"file": "reveal"} to show files in their folder rather than
+	// open them. Results that lack the action keep their own default.
+	DefaultActions map[string]string `json:"defaultActions"`
+	// Keybindings maps an action to the keys that run it. Keys are either an
+	// action ID or one of the positional names "activate", "secondary" and
+	// "tertiary" for a result's first three actions; an action ID binding
+	// wins over a positional one. "keepOpen" is the modifier that runs an
+	// action without hiding the launcher; see GreetService.RunAction.
+	Keybindings map[string]string `json:"keybindings"`
+	// LeaderSequences maps key sequences typed while the query is empty,
+	// such as "g s", to the ID of a Prism command, such as "settings".
+	// Keys are separated by spaces and each must follow the one before
+	// within LeaderTimeoutMs. A key that starts a sequence is held back
+	// until the sequence completes or breaks, then typed as usual.
+	LeaderSequences map[string]string `json:"leaderSequences"`
+	LeaderTimeoutMs int               `json:"leaderTimeoutMs"`
+	// ActivationDebounceMs ignores activating the same result again within
+	// this many milliseconds, so pressing enter twice does not launch
+	// twice. Zero turns it off.
+	ActivationDebounceMs int `json:"activationDebounceMs"`
+	// UsageImport records the one-time offer to seed frecency from the
+	// app usage Spotlight recorded: empty until answered, then one of the
+	// UsageImport* values. It applies only on macOS.
+	UsageImport string `json:"usageImport"`
+	// TerseAccessibilityLabels shortens what screen readers announce for
+	// a result to its title, type and position, leaving out the subtitle.
+	TerseAccessibilityLabels bool `json:"terseAccessibilityLabels"`
+	// SelectionPreview fetches more detail, such as a file's size, for the
+	// highlighted result as the selection moves.
+	SelectionPreview bool `json:"selectionPreview"`
+	// ShowActionHints adds a hint listing action shortcuts to results.
+	ShowActionHints bool `json:"showActionHints"`
+	// ActionHintsSelectedOnly limits hints to the first result, which is the
+	// selected one when results arrive.
+	ActionHintsSelectedOnly bool `json:"actionHintsSelectedOnly"`
+	// AppKeywords adds words an app is found by without showing them, such
+	// as "browser" for a browser, keyed by app name, bundle name or ID.
+	// They add to Prism's built-in synonyms.
+	AppKeywords map[string][]string `json:"appKeywords"`
+	// AppDuplicates is AppDuplicatesPreferSystem or AppDuplicatesShowAll,
+	// for apps installed both system-wide and in the home folder.
+	AppDuplicates string `json:"appDuplicates"`
+	// AppDirs are searched for applications in addition to the system
+	// application folders, e.g. folders on external drives.
+	AppDirs []index.Root `json:"appDirs"`
+	// FileDirs are indexed for file and folder search.
+	FileDirs []index.Root `json:"fileDirs"`
+	// Paused suspends background activity: clipboard polling, index and
+	// folder watching, update checks and network requests. Searching keeps
+	// working from what was last gathered. PauseTimers also holds back
+	// timer notifications until resuming; timers keep counting either way.
+	Paused      bool `json:"paused"`
+	PauseTimers bool `json:"pauseTimers"`
+	// DownloadsDir is the folder the downloads provider lists, by default
+	// ~/Downloads.
+	DownloadsDir string `json:"downloadsDir"`
+	// IndexArchives finds apps inside the zips and disk images in
+	// DownloadsDir, offering to extract or mount them and open the app.
+	// It applies only on macOS.
+	IndexArchives bool `json:"indexArchives"`
+	// MailSearch lists the messages in Mail's inbox whose subject matches
+	// text typed after "mail", besides composing. It needs Automation
+	// access to Mail and applies only on macOS.
+	MailSearch bool `json:"mailSearch"`
+	// RepoDirs are searched for Git repositories, each to its own depth.
+	RepoDirs []index.Root `json:"repoDirs"`
+	// IndexIgnore lists names such as "node_modules", and paths with a
+	// slash such as "~/Library", as filepath.Match patterns, that are
+	// skipped while indexing AppDirs, FileDirs and RepoDirs, and by content
+	// search and the downloads list.
+	IndexIgnore []string `json:"indexIgnore"`
+	// ShowHiddenFiles includes hidden files and folders, whose names start
+	// with a dot, in file search, content search and the downloads list.
+	// IndexIgnore still applies.
+	ShowHiddenFiles bool `json:"showHiddenFiles"`
+	// Fuzzy tunes match scoring; omitted fields keep their defaults.
+	Fuzzy fuzzy.Params `json:"fuzzy"`
+	// MinMatchScore drops fuzzy matches scoring less than this per query
+	// character on average, hiding ones made of scattered letters. The
+	// scale follows Fuzzy; 0 keeps every match.
+	MinMatchScore int `json:"minMatchScore"`
+	// DefaultClipboardAction is one of the ClipboardAction* values. It picks
+	// the default action of text results; the others remain available as
+	// secondary actions.
+	DefaultClipboardAction string `json:"defaultClipboardAction"`
+	// PlainPasteApps are app names or bundle IDs for which pasting plain
+	// text is the default action when the clipboard action is paste.
+	PlainPasteApps []string `json:"plainPasteApps"`
+	// ProviderTimeoutMs is how long a provider may take before its results
+	// are dropped for a query; ProviderTimeouts overrides it per provider ID.
+	ProviderTimeoutMs int            `json:"providerTimeoutMs"`
+	ProviderTimeouts  map[string]int `json:"providerTimeouts"`
+	Audit             Audit          `json:"audit"`
+	Performance       Performance    `json:"performance"`
+	// ProjectDirs are searched by file contents with the grep provider.
+	ProjectDirs []string `json:"projectDirs"`
+	// TodoMarkers are the words the todo provider lists comments by,
+	// matched as whole words and case-sensitively, in ProjectDirs or,
+	// when there are none, RepoDirs.
+	TodoMarkers []string `json:"todoMarkers"`
+	// Editor opens a file at a line, e.g. "code -g {file}:{line}". It is
+	// split on spaces and {file} and {line} are replaced in each argument.
+	// When empty, files open in their default application.
+	Editor   string   `json:"editor"`
+	Feedback Feedback `json:"feedback"`
+	// Pipeline lists the result transformers applied to merged results, in
+	// order. The built-ins are "frecency", which blends launch history into
+	// scores, "interactions", which boosts the results picked before for
+	// the same query, "dedup" and "truncate".
+	Pipeline []string `json:"pipeline"`
+	// FrecencyWeight scales the frecency transformer's bonus; zero turns it
+	// off.
+	FrecencyWeight float64 `json:"frecencyWeight"`
+	// LearnRanking records which result is picked for which query, in a
+	// file kept on this machine only, and boosts that result the next time
+	// a query like it is typed. Turning it off stops both.
+	LearnRanking bool `json:"learnRanking"`
+	// MaxResults is the number of results kept by the truncate transformer.
+	// ProviderLimits caps the results it keeps from each provider ID,
+	// before MaxResults applies to them all.
+	MaxResults     int            `json:"maxResults"`
+	ProviderLimits map[string]int `json:"providerLimits"`
+	// AutoHideAfterMs hides the launcher after this long without activity;
+	// zero disables it. A pinned window never hides on its own.
+	AutoHideAfterMs int `json:"autoHideAfterMs"`
+	// WindowCornerRadius rounds the launcher window's corners, in points,
+	// and WindowShadow draws a shadow behind it. Both apply on macOS only.
+	WindowCornerRadius float64 `json:"windowCornerRadius"`
+	WindowShadow       bool    `json:"windowShadow"`
+	// DisplayAppearance is one of the DisplayAppearance* policies. Under
+	// the adaptive one, the window takes StandardDisplay's look on
+	// standard-density displays and WindowCornerRadius and WindowShadow on
+	// high-density ones, updated as it moves between them.
+	DisplayAppearance string            `json:"displayAppearance"`
+	StandardDisplay   DisplayAppearance `json:"standardDisplay"`
+	// WindowWidth and WindowHeight size the launcher window in logical
+	// pixels. They are scaled for the display the window is shown on.
+	WindowWidth  int `json:"windowWidth"`
+	WindowHeight int `json:"windowHeight"`
+	// RememberWindowSize reopens the launcher at the size it last had,
+	// such as after growing with its results, rather than at WindowWidth
+	// and WindowHeight each launch.
+	RememberWindowSize bool `json:"rememberWindowSize"`
+	// TrayLeftClick is one of the TrayLeftClick* behaviors. Linux tray
+	// hosts always open the menu.
+	TrayLeftClick string `json:"trayLeftClick"`
+	// TrayMenu is the tray menu, item by item, top to bottom. It is read
+	// when Prism starts; items whose action is unknown are left out, and
+	// a Show Prism item is added at the top when none has the show action.
+	TrayMenu []TrayItem `json:"trayMenu"`
+	// LayoutDensity is LayoutCompact or LayoutComfortable.
+	LayoutDensity string `json:"layoutDensity"`
+	// WindowPlacement is one of the WindowPlacement* modes.
+	WindowPlacement string `json:"windowPlacement"`
+	// StableResults keeps results in place as slower providers add theirs
+	// to a query, appending late results instead of inserting them above.
+	StableResults bool `json:"stableResults"`
+	// ResultCountBadge shows in the input how many results matched, such
+	// as "9 of 42" when MaxResults cut the list short.
+	ResultCountBadge bool `json:"resultCountBadge"`
+	// AnimateResults animates results as they are appended, narrowed or
+	// replaced. It is ignored while macOS's Reduce Motion is on.
+	AnimateResults bool `json:"animateResults"`
+	// ResultSummaryJSON has Copy Result Summary copy a JSON object rather
+	// than lines of text.
+	ResultSummaryJSON bool `json:"resultSummaryJSON"`
+	// Tiebreak orders results with equal scores: "shorter" lists shorter
+	// titles first, as the closer matches, and "alphabetical" orders them
+	// by title. Either way, what is left tied is ordered by result ID, so
+	// the same query always lists the same results in the same order.
+	Tiebreak string `json:"tiebreak"`
+	// Terminal is the terminal SSH sessions, Docker logs and Open in
+	// Terminal use: an app name such as "Terminal" or "iTerm" on macOS, a
+	// terminal command on Linux and Windows, or a command template
+	// containing {cmd}, like "kitty -e {cmd}". Empty uses the system
+	// default.
+	Terminal string `json:"terminal"`
+	// TerminalCommand is the command Open in Terminal runs in the folder,
+	// split on spaces. Empty starts the user's shell.
+	TerminalCommand string `json:"terminalCommand"`
+	// Docker lists Docker containers and Compose projects after "docker ".
+	// It is off by default, as most people have no Docker to manage.
+	Docker bool `json:"docker"`
+	// SSHKnownHosts adds hosts from ~/.ssh/known_hosts to those in
+	// ~/.ssh/config.
+	SSHKnownHosts bool `json:"sshKnownHosts"`
+	// RespectFocusMode holds back Prism's notifications while a Focus or
+	// Do Not Disturb mode is on. CriticalTimers lets finished timers
+	// through anyway.
+	RespectFocusMode bool `json:"respectFocusMode"`
+	CriticalTimers   bool `json:"criticalTimers"`
+	// SuggestClipboard offers to open a URL or file path, or to calculate
+	// an expression, found on the clipboard when Prism opens blank. Turning
+	// it off keeps the clipboard from being read until asked for.
+	SuggestClipboard bool `json:"suggestClipboard"`
+	// SmartActions turns off, with false, the kinds of clipboard contents
+	// SuggestClipboard offers actions for: "url", "color", "email",
+	// "tracking" and "path". Kinds not listed are on.
+	SmartActions map[string]bool `json:"smartActions"`
+	// URLShortener is the URL that shortens a copied link, with {url}
+	// standing for the link and the short link as the whole response, such
+	// as "https://is.gd/create.php?format=simple&url={url}". Empty, the
+	// default, sends links nowhere and offers no Shorten action.
+	URLShortener string `json:"urlShortener"`
+	// Locale decides how the calculator and converters read and write
+	// numbers and dates, as a tag like "de-DE". Empty uses the system
+	// locale.
+	Locale string `json:"locale"`
+	// CalcAutoEval is how many operators a query made only of numbers and
+	// operators needs for the calculator to answer it without the "="
+	// prefix, e.g. 1 for "2+2". Zero or less answers only "=" queries.
+	CalcAutoEval int `json:"calcAutoEval"`
+	// CheckForUpdates has Prism read UpdateFeedURL every UpdateCheckHours
+	// and say when a newer version is out. It is off unless turned on, as
+	// each check tells the feed's host that Prism is running. Nothing is
+	// ever downloaded.
+	CheckForUpdates  bool   `json:"checkForUpdates"`
+	UpdateFeedURL    string `json:"updateFeedURL"`
+	UpdateCheckHours int    `json:"updateCheckHours"`
+	// CacheMemoryBudgetMB bounds the memory, in megabytes, that cached
+	// thumbnails take up together. Once it is reached, the least recently
+	// used are dropped. Zero or less uses 64.
+	CacheMemoryBudgetMB int `json:"cacheMemoryBudgetMB"`
+	// NetworkTimeoutMs bounds each request made by network providers.
+	NetworkTimeoutMs int `json:"networkTimeoutMs"`
+	// CurrencyEndpoint is the exchange-rate API, with {base} standing for
+	// CurrencyBase, the currency rates are fetched relative to. Empty uses
+	// a free API with USD as the base.
+	CurrencyEndpoint string `json:"currencyEndpoint"`
+	CurrencyBase     string `json:"currencyBase"`
+	// PasswordLength and PasswordPreset are used by "password" when the
+	// query names no length or preset. PasswordPresets adds named
+	// character sets to the built-in "alphanumeric", "symbols" and
+	// "pronounceable".
+	PasswordLength  int               `json:"passwordLength"`
+	PasswordPreset  string            `json:"passwordPreset"`
+	PasswordPresets map[string]string `json:"passwordPresets"`
+	// MaxQueryLength is the longest query, in characters, that is searched.
+	// A longer one, typically pasted by accident, shows a notice instead of
+	// running every provider on it. Zero turns the limit off.
+	MaxQueryLength int `json:"maxQueryLength"`
+	// RepeatHotkey is the global shortcut that runs the last launched
+	// result again without showing the launcher, such as "alt+shift+space".
+	// Modifiers are ctrl, shift, alt and cmd, which is the Windows or Super
+	// key off macOS. Empty turns it off.
+	RepeatHotkey string `json:"repeatHotkey"`
+	// ModeHotkeys maps a global shortcut, written like RepeatHotkey, to the
+	// ID of a provider with an entry in Prefixes. The shortcut opens the
+	// launcher with that provider's prefix typed, such as "/" for files.
+	ModeHotkeys map[string]string `json:"modeHotkeys"`
+	// ShowOnStartup shows the launcher as soon as Prism starts instead of
+	// waiting for the hotkey. It is skipped when Prism is started with
+	// --hidden, as a login item should be.
+	ShowOnStartup bool `json:"showOnStartup"`
+	// QueryHistory remembers the queries that led to an activation. Turning
+	// it off also hides the recent-queries empty state.
+	QueryHistory bool `json:"queryHistory"`
+}
+
+// Default returns the settings used when no config file exists.
+func Default() Config {
+	return Config{
+		Prefixes: map[string]string{
+			"=":         "calc",
+			"/":         "files",
+			"clip ":     "clipboard",
+			"docker ":   "docker",
+			"grep ":     "grep",
+			"note ":     "notes",
+			"port ":     "ports",
+			"reminder ": "reminders",
+			"todo ":     "todo",
+		},
+		EmptyState:    EmptyStateFrecency,
+		InstantFilter: true,
+		NoResults:     NoResultsWebSearch,
+		WebSearchURL:  "https://duckduckgo.com/?q={query}",
+		Keybindings: map[string]string{
+			"activate":              "enter",
+			"secondary":             "cmd+enter",
+			"tertiary":              "alt+enter",
+			"keepOpen":              "shift+enter",
+			"prism.favorite.add":    "cmd+d",
+			"prism.favorite.remove": "cmd+d",
+		},
+		IndexIgnore:   slices.Clone(index.DefaultIgnore),
+		Fuzzy:         fuzzy.DefaultParams(),
+		MinMatchScore: fuzzy.DefaultMinScore,
+
+		DefaultClipboardAction: ClipboardActionCopy,
+		ProviderTimeoutMs:      800,
+		ProviderTimeouts: map[string]int{
+			"finder": 2000,
+			"grep":   3000,
+			"todo":   3000,
+			"ports":  3000,
+			"docker": 5000,
+			"mail":   3000,
+			"menus":  3000,
+			// Plugins stop themselves after plugins.Timeout, 2 seconds.
+			"plugins": 2500,
+			// Notes and Reminders are listed once per session; Reminders
+			// are slow to script.
+			"notes":     5000,
+			"reminders": 8000,
+			// Scanning for Wi-Fi networks takes seconds; it happens once
+			// per session.
+			"wifi": 10000,
+		},
+		Audit:         Audit{MaxSizeMB: 10, Keep: 5},
+		Performance:   Performance{Prewarm: true, MaxConcurrentIO: 2, PauseOnBattery: true},
+		QueryHistory:  true,
+		StableResults: true,
+		Pipeline:      []string{"frecency", "interactions", "dedup", "truncate"},
+		MaxResults:    50,
+		ProviderLimits: map[string]int{
+			"clipboard": 3,
+			"files":     5,
+			"finder":    5,
+			"grep":      5,
+		},
+
+		FrecencyWeight:   16,
+		LearnRanking:     true,
+		RespectFocusMode: true,
+		SelectionPreview: true,
+		SuggestClipboard: true,
+		MaxQueryLength:   1000,
+		LeaderTimeoutMs:  1000,
+		RepeatHotkey:     "alt+shift+space",
+		PasswordLength:   20,
+		NetworkTimeoutMs: 3000,
+		UpdateCheckHours: 24,
+		UpdateFeedURL:    "https://api.github.com/repos/og-vikram/prism/releases/latest",
+		PasswordPreset:   "symbols",
+
+		ActivationDebounceMs: 300,
+
+		CalcAutoEval: 1,
+
+		TodoMarkers: []string{"TODO", "FIXME", "HACK"},
+
+		AnimateResults: true,
+		Tiebreak:       "shorter",
+
+		CacheMemoryBudgetMB: 64,
+
+		WindowCornerRadius: 8,
+		WindowShadow:       true,
+		WindowWidth:        600,
+		WindowHeight:       50,
+		WindowPlacement:    WindowPlacementCenter,
+		LayoutDensity:      LayoutComfortable,
+		AppDuplicates:      AppDuplicatesPreferSystem,
+		TrayLeftClick:      TrayLeftClickMenu,
+
+		TrayMenu: []TrayItem{
+			{Label: "Show Prism", Action: TrayActionShow},
+			{Label: "Update Available", Action: TrayActionUpdate},
+			{Label: "Pause Background Activity", Action: TrayActionPause},
+			{Action: TrayActionSeparator},
+			{Label: "Open Settings", Action: "settings"},
+			{Label: "Rebuild Index", Action: "rebuild-index"},
+			{Label: "Quit Prism", Action: "quit"},
+		},
+
+		DisplayAppearance: DisplayAppearanceUniform,
+		StandardDisplay:   DisplayAppearance{FontScale: 1.1, CornerRadius: 6, Shadow: true},
+	}
+}
+
+// Dir returns the directory Prism keeps its settings and data in.
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "Prism"), nil
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads the config file at path. A missing file yields Default.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a config written as JSON. Fields it lacks keep their
+// defaults, as for a config file written by an older version. On error it
+// returns Default.
+func Parse(data []byte) (Config, error) {
+	c := Default()
+	// Decoding an array into a slice overwrites its elements field by
+	// field, so a tray item given without a label would keep the label of
+	// the default item it lands on.
+	c.TrayMenu = nil
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Default(), err
+	}
+	if c.TrayMenu == nil {
+		c.TrayMenu = Default().TrayMenu
+	}
+	return c, nil
+}
+
+// Save writes c to path, replacing the file atomically.
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"changeme/internal/index"
+	"changeme/internal/quarantine"
+)
+
+// Store holds the live config and writes every change back to disk.
+type Store struct {
+	path string
+
+	mu  sync.Mutex
+	cfg Config
+}
+
+// Open loads the config at path into a Store. When the file cannot be read
+// the store starts from Default and the error is returned alongside it. A
+// file that is not valid JSON is quarantined first, so the next save does
+// not overwrite what was in it.
+func Open(path string) (*Store, error) {
+	cfg, err := Load(path)
+	if corrupt(err) {
+		err = fmt.Errorf("config: %w", quarantine.Move(path, err))
+	}
+	return &Store{path: path, cfg: cfg}, err
+}
+
+// corrupt reports whether err is Load failing to parse the file.
+func corrupt(err error) bool {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	return errors.As(err, &syntax) || errors.As(err, &typ)
+}
+
+// Get returns the current config. Callers must not modify its maps or
+// slices; use Update instead.
+func (s *Store) Get() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// Update applies fn to the config and saves the result.
+func (s *Store) Update(fn func(*Config)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := s.cfg.clone()
+	fn(&next)
+	if err := Save(s.path, next); err != nil {
+		return err
+	}
+	s.cfg = next
+	return nil
+}
+
+// Reload replaces the config with the file's current contents, picking up
+// edits made outside Prism. When the file cannot be read the config is left
+// unchanged; unlike Open, a file that does not parse is left in place, as it
+// is most likely still being edited.
+func (s *Store) Reload() error {
+	cfg, err := Load(s.path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+	return nil
+}
+
+// Replace swaps the whole config for c and saves it.
+func (s *Store) Replace(c Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := c.clone()
+	if err := Save(s.path, next); err != nil {
+		return err
+	}
+	s.cfg = next
+	return nil
+}
+
+// Path returns the file the store saves to.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// clone copies c deeply enough that Update never mutates a Config handed out
+// by Get.
+func (c Config) clone() Config {
+	out := c
+	out.Prefixes = make(map[string]string, len(c.Prefixes))
+	for k, v := range c.Prefixes {
+		out.Prefixes[k] = v
+	}
+	out.Favorites = append([]string(nil), c.Favorites...)
+	out.StickyResults = append([]string(nil), c.StickyResults...)
+	out.Keybindings = make(map[string]string, len(c.Keybindings))
+	for k, v := range c.Keybindings {
+		out.Keybindings[k] = v
+	}
+	out.ProviderTimeouts = make(map[string]int, len(c.ProviderTimeouts))
+	for k, v := range c.ProviderTimeouts {
+		out.ProviderTimeouts[k] = v
+	}
+	out.ProviderLimits = make(map[string]int, len(c.ProviderLimits))
+	for k, v := range c.ProviderLimits {
+		out.ProviderLimits[k] = v
+	}
+	if c.HideBeforeRun != nil {
+		out.HideBeforeRun = make(map[string]bool, len(c.HideBeforeRun))
+		for k, v := range c.HideBeforeRun {
+			out.HideBeforeRun[k] = v
+		}
+	}
+	if c.SubtitleTemplates != nil {
+		out.SubtitleTemplates = make(map[string]string, len(c.SubtitleTemplates))
+		for k, v := range c.SubtitleTemplates {
+			out.SubtitleTemplates[k] = v
+		}
+	}
+	if c.DefaultActions != nil {
+		out.DefaultActions = make(map[string]string, len(c.DefaultActions))
+		for k, v := range c.DefaultActions {
+			out.DefaultActions[k] = v
+		}
+	}
+	if c.PasswordPresets != nil {
+		out.PasswordPresets = make(map[string]string, len(c.PasswordPresets))
+		for k, v := range c.PasswordPresets {
+			out.PasswordPresets[k] = v
+		}
+	}
+	if c.LeaderSequences != nil {
+		out.LeaderSequences = make(map[string]string, len(c.LeaderSequences))
+		for k, v := range c.LeaderSequences {
+			out.LeaderSequences[k] = v
+		}
+	}
+	if c.ModeHotkeys != nil {
+		out.ModeHotkeys = make(map[string]string, len(c.ModeHotkeys))
+		for k, v := range c.ModeHotkeys {
+			out.ModeHotkeys[k] = v
+		}
+	}
+	if c.AppKeywords != nil {
+		out.AppKeywords = make(map[string][]string, len(c.AppKeywords))
+		for k, v := range c.AppKeywords {
+			out.AppKeywords[k] = append([]string(nil), v...)
+		}
+	}
+	out.Quicklinks = append([]Quicklink(nil), c.Quicklinks...)
+	if c.LaunchLayouts != nil {
+		out.LaunchLayouts = make([]LaunchLayout, len(c.LaunchLayouts))
+		for i, l := range c.LaunchLayouts {
+			l.Items = append([]string(nil), l.Items...)
+			out.LaunchLayouts[i] = l
+		}
+	}
+	out.AppDirs = append([]index.Root(nil), c.AppDirs...)
+	out.FileDirs = append([]index.Root(nil), c.FileDirs...)
+	out.RepoDirs = append([]index.Root(nil), c.RepoDirs...)
+	out.IndexIgnore = append([]string(nil), c.IndexIgnore...)
+	out.ProjectDirs = append([]string(nil), c.ProjectDirs...)
+	out.TodoMarkers = append([]string(nil), c.TodoMarkers...)
+	out.Pipeline = append([]string(nil), c.Pipeline...)
+	out.FallbackChain = append([]string(nil), c.FallbackChain...)
+	out.QueryRewrites = append([]QueryRewrite(nil), c.QueryRewrites...)
+	out.PlainPasteApps = append([]string(nil), c.PlainPasteApps...)
+	out.TrayMenu = append([]TrayItem(nil), c.TrayMenu...)
+	if c.SmartActions != nil {
+		out.SmartActions = make(map[string]bool, len(c.SmartActions))
+		for k, v := range c.SmartActions {
+			out.SmartActions[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"changeme/internal/index"
+	"changeme/internal/quarantine"
+)
+
+// fill gives every map and slice in v, a struct, one zero element, so that
+// sharing them is visible.
+func fill(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.Map:
+			m := reflect.MakeMap(f.Type())
+			m.SetMapIndex(reflect.Zero(f.Type().Key()), reflect.Zero(f.Type().Elem()))
+			f.Set(m)
+		case reflect.Slice:
+			f.Set(reflect.MakeSlice(f.Type(), 1, 1))
+		case reflect.Struct:
+			fill(f)
+		}
+	}
+}
+
+// shared returns the paths of the maps and slices a and b, structs of the
+// same type, have in common.
+func shared(a, b reflect.Value, path string) []string {
+	var out []string
+	for i := 0; i < a.NumField(); i++ {
+		fa, fb := a.Field(i), b.Field(i)
+		name := path + a.Type().Field(i).Name
+		switch fa.Kind() {
+		case reflect.Map, reflect.Slice:
+			if !fa.IsNil() && fa.Pointer() == fb.Pointer() {
+				out = append(out, name)
+			}
+		case reflect.Struct:
+			out = append(out, shared(fa, fb, name+".")...)
+		}
+	}
+	return out
+}
+
+func TestCloneSharesNothing(t *testing.T) {
+	var c Config
+	fill(reflect.ValueOf(&c).Elem())
+	clone := c.clone()
+	if s := shared(reflect.ValueOf(c), reflect.ValueOf(clone), ""); len(s) > 0 {
+		t.Errorf("clone shares %v with the original", s)
+	}
+}
+
+func TestOpenCorrupt(t *testing.T) {
+	for _, data := range []string{`{"maxResults": 8, "theme": "da`, `{"maxResults": "eight"}`} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Open(path)
+		if err == nil {
+			t.Errorf("Open(%q) succeeded", data)
+		}
+		if !reflect.DeepEqual(s.Get(), Default()) {
+			t.Errorf("Open(%q) did not start from the defaults", data)
+		}
+		if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+			t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+		}
+	}
+}
+
+func TestReloadKeepsUnparsableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update(func(c *Config) { c.MaxResults = 8 }); err != nil {
+		t.Fatal(err)
+	}
+	// The file is being edited by hand.
+	if err := os.WriteFile(path, []byte(`{"maxResults": 9,`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err == nil {
+		t.Error("Reload of an unparsable file succeeded")
+	}
+	if got := s.Get().MaxResults; got != 8 {
+		t.Errorf("MaxResults = %d after a failed reload, want 8", got)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the file being edited was moved: %v", err)
+	}
+}
+
+func TestLoadTrayMenu(t *testing.T) {
+	tests := []struct {
+		data string
+		want []TrayItem
+	}{
+		{`{}`, Default().TrayMenu},
+		// Items are read whole, not over the default items.
+		{`{"trayMenu": [{"label": "Show", "action": "show"}, {"action": "settings"}]}`,
+			[]TrayItem{{Label: "Show", Action: "show"}, {Action: "settings"}}},
+		{`{"trayMenu": []}`, []TrayItem{}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(c.TrayMenu, tt.want) {
+			t.Errorf("Load(%s).TrayMenu = %+v, want %+v", tt.data, c.TrayMenu, tt.want)
+		}
+	}
+}
+
+func TestLoadKeepsDefaultIgnore(t *testing.T) {
+	before := slices.Clone(index.DefaultIgnore)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"indexIgnore": ["foo"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"foo"}; !slices.Equal(c.IndexIgnore, want) {
+		t.Errorf("IndexIgnore = %v, want %v", c.IndexIgnore, want)
+	}
+	if !slices.Equal(index.DefaultIgnore, before) {
+		t.Errorf("loading a config changed index.DefaultIgnore to %v", index.DefaultIgnore)
+	}
+	if got := Default().IndexIgnore; !slices.Equal(got, before) {
+		t.Errorf("Default().IndexIgnore = %v, want %v", got, before)
+	}
+}
@@ -0,0 +1,100 @@
+// Package convert converts amounts between currencies with exchange rates
+// from a RateProvider.
+package convert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"changeme/internal/locale"
+)
+
+// ErrNoRates is returned by a RateProvider that has no rates yet, such as
+// before its first fetch has finished.
+var ErrNoRates = errors.New("convert: no exchange rates yet")
+
+// Rates are exchange rates relative to Base: one unit of Base buys
+// Rates[code] units of code.
+type Rates struct {
+	Base    string
+	Rates   map[string]float64
+	Fetched time.Time
+	// Stale is set when the rates could not be refreshed and are older
+	// than the provider's refresh interval.
+	Stale bool
+}
+
+// RateProvider supplies exchange rates. Rates must return quickly; a
+// provider that fetches rates does so in the background.
+type RateProvider interface {
+	Rates(ctx context.Context) (Rates, error)
+}
+
+// Query is a parsed conversion such as "100 usd to eur".
+type Query struct {
+	Amount   float64
+	From, To string
+}
+
+// Parse reads "<amount> <code> to <code>" or the same with "in", with
+// three-letter currency codes in any case. The amount may be glued to the
+// first code, as in "100usd in eur", and is written with loc's decimal and
+// thousands separators.
+func Parse(s string, loc locale.Locale) (Query, bool) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 4 {
+		fields = []string{fields[0] + fields[1], fields[2], fields[3]}
+	}
+	if len(fields) != 3 || (fields[1] != "to" && fields[1] != "in") {
+		return Query{}, false
+	}
+	first, to := fields[0], fields[2]
+	if len(first) <= 3 {
+		return Query{}, false
+	}
+	amount, from := first[:len(first)-3], first[len(first)-3:]
+	n, ok := loc.ParseNumber(amount)
+	if !ok || n < 0 || !isCode(from) || !isCode(to) {
+		return Query{}, false
+	}
+	return Query{Amount: n, From: strings.ToUpper(from), To: strings.ToUpper(to)}, true
+}
+
+func isCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Convert returns q.Amount of q.From in q.To.
+func Convert(q Query, r Rates) (float64, error) {
+	from, err := r.rate(q.From)
+	if err != nil {
+		return 0, err
+	}
+	to, err := r.rate(q.To)
+	if err != nil {
+		return 0, err
+	}
+	return q.Amount / from * to, nil
+}
+
+func (r Rates) rate(code string) (float64, error) {
+	if code == r.Base {
+		return 1, nil
+	}
+	v, ok := r.Rates[code]
+	if !ok || v <= 0 {
+		return 0, fmt.Errorf("convert: no rate for %s", code)
+	}
+	return v, nil
+}
@@ -0,0 +1,156 @@
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/network"
+)
+
+const (
+	// DefaultEndpoint is a free exchange-rate API that needs no key.
+	// {base} is replaced with the base currency.
+	DefaultEndpoint = "https://api.frankfurter.app/latest?from={base}"
+	// DefaultTTL is how long fetched rates are used before they are
+	// refreshed.
+	DefaultTTL = time.Hour
+	// retryInterval spaces out refreshes after one fails.
+	retryInterval = time.Minute
+)
+
+// HTTPRates fetches rates from a JSON API through the shared network
+// client, which also keeps them on disk. A call to Rates never waits for
+// the network: it returns the rates it has and, once they are older than
+// the TTL, refreshes them in the background for the next call.
+//
+// The API must answer with an object holding a "rates" map and the base
+// currency as "base" or "base_code", as frankfurter.app and
+// open.er-api.com do.
+type HTTPRates struct {
+	client *network.Client
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	endpoint   string
+	base       string
+	rates      Rates
+	loaded     bool // rates were read from the cache or fetched
+	refreshing bool
+	attempted  time.Time
+}
+
+// NewHTTPRates returns a rate provider fetching through client.
+func NewHTTPRates(client *network.Client) *HTTPRates {
+	return &HTTPRates{client: client, ttl: DefaultTTL, endpoint: DefaultEndpoint, base: "USD"}
+}
+
+// SetSource sets the API endpoint and base currency. Empty values keep the
+// defaults. Changing either discards the rates held in memory.
+func (h *HTTPRates) SetSource(endpoint, base string) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	base = strings.ToUpper(base)
+	if base == "" {
+		base = "USD"
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if endpoint != h.endpoint || base != h.base {
+		h.endpoint, h.base = endpoint, base
+		h.rates, h.loaded, h.attempted = Rates{}, false, time.Time{}
+	}
+}
+
+func (h *HTTPRates) Rates(ctx context.Context) (Rates, error) {
+	h.mu.Lock()
+	if !h.loaded {
+		// Rates cached by an earlier run are usable right away.
+		if r, err := h.fromCache(); err == nil {
+			h.rates = r
+		}
+		h.loaded = true
+	}
+	if time.Since(h.rates.Fetched) > h.ttl && time.Since(h.attempted) > retryInterval && !h.refreshing {
+		h.refreshing, h.attempted = true, time.Now()
+		go h.refresh(h.url())
+	}
+	r := h.rates
+	h.mu.Unlock()
+
+	if r.Rates == nil {
+		if !h.client.Online(ctx) {
+			return Rates{}, network.ErrOffline
+		}
+		return Rates{}, ErrNoRates
+	}
+	r.Stale = time.Since(r.Fetched) > h.ttl
+	return r, nil
+}
+
+// refresh fetches the rates from url and keeps them unless the source has
+// changed meanwhile. A failed fetch leaves the previous rates in place.
+func (h *HTTPRates) refresh(url string) {
+	resp, err := h.client.Get(context.Background(), cacheKey(url), url)
+	var r Rates
+	if err == nil {
+		r, err = decode(resp.Body)
+		r.Fetched = resp.Fetched
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.refreshing = false
+	if err != nil {
+		if !errors.Is(err, network.ErrOffline) {
+			log.Println(err)
+		}
+		return
+	}
+	if url == h.url() {
+		h.rates = r
+	}
+}
+
+func (h *HTTPRates) fromCache() (Rates, error) {
+	resp, ok := h.client.Cached(cacheKey(h.url()))
+	if !ok {
+		return Rates{}, ErrNoRates
+	}
+	r, err := decode(resp.Body)
+	r.Fetched = resp.Fetched
+	return r, err
+}
+
+// url returns the endpoint for the base currency. The caller holds h.mu.
+func (h *HTTPRates) url() string {
+	return strings.ReplaceAll(h.endpoint, "{base}", h.base)
+}
+
+func cacheKey(url string) string {
+	return "currency:" + url
+}
+
+// decode reads the rates from an API response.
+func decode(body []byte) (Rates, error) {
+	var v struct {
+		Base     string             `json:"base"`
+		BaseCode string             `json:"base_code"`
+		Rates    map[string]float64 `json:"rates"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return Rates{}, fmt.Errorf("convert: %w", err)
+	}
+	if v.Base == "" {
+		v.Base = v.BaseCode
+	}
+	if v.Base == "" || len(v.Rates) == 0 {
+		return Rates{}, errors.New("convert: response has no rates")
+	}
+	return Rates{Base: strings.ToUpper(v.Base), Rates: v.Rates}, nil
+}
@@ -0,0 +1,188 @@
+package convert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"changeme/internal/locale"
+	"changeme/internal/network"
+)
+
+// rateServer answers with EUR rates, or fails while failing is set.
+type rateServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	failing  atomic.Bool
+	base     atomic.Value // the from parameter of the last request
+}
+
+func newRateServer(t *testing.T) *rateServer {
+	s := &rateServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.base.Store(r.URL.Query().Get("from"))
+		if s.failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"base": "EUR", "rates": {"USD": 1.1, "GBP": 0.85}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T) *network.Client {
+	cache, err := network.OpenCache(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return network.New(cache)
+}
+
+// waitRates calls Rates until it returns rates.
+func waitRates(t *testing.T, h *HTTPRates) Rates {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r, err := h.Rates(context.Background())
+		if err == nil {
+			return r
+		}
+		if !errors.Is(err, ErrNoRates) || time.Now().After(deadline) {
+			t.Fatalf("Rates = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// waitRefreshed waits for a background refresh to finish.
+func waitRefreshed(h *HTTPRates) {
+	for {
+		h.mu.Lock()
+		done := !h.refreshing
+		h.mu.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHTTPRatesFetch(t *testing.T) {
+	srv := newRateServer(t)
+	h := NewHTTPRates(newTestClient(t))
+	h.SetSource(srv.URL+"/latest?from={base}", "eur")
+
+	// The first call does not wait for the fetch it starts.
+	if _, err := h.Rates(context.Background()); !errors.Is(err, ErrNoRates) {
+		t.Fatalf("first Rates = %v, want ErrNoRates", err)
+	}
+	r := waitRates(t, h)
+	if r.Base != "EUR" || r.Rates["USD"] != 1.1 || r.Stale {
+		t.Errorf("Rates = %+v", r)
+	}
+	if got := srv.base.Load(); got != "EUR" {
+		t.Errorf("fetched from=%v, want EUR", got)
+	}
+	// Fresh rates are not fetched again.
+	h.Rates(context.Background())
+	waitRefreshed(h)
+	if n := srv.requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
+
+func TestHTTPRatesCache(t *testing.T) {
+	srv := newRateServer(t)
+	client := newTestClient(t)
+	h := NewHTTPRates(client)
+	h.SetSource(srv.URL+"/latest?from={base}", "EUR")
+	want := waitRates(t, h)
+	srv.Close()
+
+	// A new provider, as after a restart, has the cached rates at once.
+	h = NewHTTPRates(client)
+	h.SetSource(srv.URL+"/latest?from={base}", "EUR")
+	r, err := h.Rates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rates["GBP"] != 0.85 || !r.Fetched.Equal(want.Fetched) {
+		t.Errorf("cached Rates = %+v, want %+v", r, want)
+	}
+
+	// Another base currency is not in the cache.
+	h.SetSource(srv.URL+"/latest?from={base}", "USD")
+	if _, err := h.Rates(context.Background()); err == nil {
+		t.Error("Rates for an uncached base succeeded")
+	}
+}
+
+func TestHTTPRatesStale(t *testing.T) {
+	srv := newRateServer(t)
+	h := NewHTTPRates(newTestClient(t))
+	h.SetSource(srv.URL+"/latest?from={base}", "EUR")
+	waitRates(t, h)
+
+	// The rates expire and the refresh fails.
+	srv.failing.Store(true)
+	h.mu.Lock()
+	h.ttl, h.attempted = time.Nanosecond, time.Time{}
+	h.mu.Unlock()
+	if _, err := h.Rates(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitRefreshed(h)
+	if n := srv.requests.Load(); n != 2 {
+		t.Errorf("%d requests, want a refresh", n)
+	}
+	r, err := h.Rates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rates["USD"] != 1.1 || !r.Stale {
+		t.Errorf("Rates after a failed refresh = %+v, want the old rates, stale", r)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	rates := Rates{Base: "EUR", Rates: map[string]float64{"USD": 1.25, "GBP": 0.5}}
+	tests := []struct {
+		query string
+		want  float64
+	}{
+		{"100 eur to usd", 125},
+		{"100usd in eur", 80},
+		{"10 USD to GBP", 4},
+		{"5 eur to eur", 5},
+	}
+	for _, tt := range tests {
+		q, ok := Parse(tt.query, locale.English)
+		if !ok {
+			t.Errorf("Parse(%q) failed", tt.query)
+			continue
+		}
+		if got, err := Convert(q, rates); err != nil || got != tt.want {
+			t.Errorf("Convert(%q) = %v, %v; want %v", tt.query, got, err, tt.want)
+		}
+	}
+	// Amounts are read in the configured locale.
+	if q, ok := Parse("1.000,5 eur to usd", locale.Lookup("de-DE")); !ok || q.Amount != 1000.5 {
+		t.Errorf("Parse(1.000,5 eur to usd) in German = %+v, %v; want 1000.5", q, ok)
+	}
+	q, _ := Parse("1 eur to jpy", locale.English)
+	if _, err := Convert(q, rates); err == nil {
+		t.Error("Convert to a currency without a rate succeeded")
+	}
+	for _, s := range []string{"100 euros to usd", "eur to usd", "-5 eur to usd", "100 eur usd"} {
+		if q, ok := Parse(s, locale.English); ok {
+			t.Errorf("Parse(%q) = %+v, want failure", s, q)
+		}
+	}
+}
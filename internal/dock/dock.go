@@ -0,0 +1,127 @@
+// Package dock reads and edits the apps kept in the macOS Dock, the same
+// way the defaults command line would: the Dock keeps them in the
+// persistent-apps array of its preferences, and rereads it when restarted.
+package dock
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// domain is the Dock's preferences domain.
+const domain = "com.apple.dock"
+
+// urlStringType is the _CFURLStringType of a tile whose _CFURLString is a
+// file URL.
+const urlStringType = 15
+
+// tileURL matches the file URL of each tile in `defaults read` output.
+var tileURL = regexp.MustCompile(`"_CFURLString" = "([^"]*)";`)
+
+// removeScript is JavaScript for Automation that drops every tile for the
+// file URL it is given from persistent-apps; defaults cannot remove one
+// element of an array.
+const removeScript = `ObjC.import("Foundation");
+function run(argv) {
+	var prefs = $.NSUserDefaults.alloc.initWithSuiteName("` + domain + `");
+	var apps = prefs.arrayForKey("persistent-apps").mutableCopy;
+	for (var i = apps.count - 1; i >= 0; i--) {
+		var u = apps.objectAtIndex(i).valueForKeyPath("tile-data.file-data._CFURLString");
+		if (u && u.js === argv[0]) apps.removeObjectAtIndex(i);
+	}
+	prefs.setObjectForKey(apps, "persistent-apps");
+	prefs.synchronize;
+}`
+
+// check refuses anything but an absolute path to an application bundle.
+func check(path string) error {
+	if !filepath.IsAbs(path) || !strings.EqualFold(filepath.Ext(path), ".app") {
+		return fmt.Errorf("dock: %q is not an application", path)
+	}
+	return nil
+}
+
+// fileURL is how tiles refer to the app at path: as a file URL with a
+// trailing slash, since a bundle is a folder.
+func fileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.Clean(path) + "/"}).String()
+}
+
+// Apps returns the paths of the apps kept in the Dock.
+func Apps(ctx context.Context) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "defaults", "read", domain, "persistent-apps").Output()
+	if err != nil {
+		return nil, fmt.Errorf("dock: reading the Dock: %w", err)
+	}
+	return parseApps(string(out)), nil
+}
+
+// parseApps returns the paths of the file tiles in the `defaults read`
+// output of persistent-apps.
+func parseApps(out string) []string {
+	var paths []string
+	for _, m := range tileURL.FindAllStringSubmatch(out, -1) {
+		if u, err := url.Parse(m[1]); err == nil && u.Scheme == "file" {
+			paths = append(paths, filepath.Clean(u.Path))
+		}
+	}
+	return paths
+}
+
+// AddCommand returns the defaults command line that appends the app at
+// path to the Dock.
+func AddCommand(path string) ([]string, error) {
+	if err := check(path); err != nil {
+		return nil, err
+	}
+	tile := fmt.Sprintf("<dict><key>tile-data</key><dict><key>file-data</key><dict>"+
+		"<key>_CFURLString</key><string>%s</string>"+
+		"<key>_CFURLStringType</key><integer>%d</integer>"+
+		"</dict></dict></dict>", html.EscapeString(fileURL(path)), urlStringType)
+	return []string{"defaults", "write", domain, "persistent-apps", "-array-add", tile}, nil
+}
+
+// RemoveCommand returns the command line that drops the app at path from
+// the Dock.
+func RemoveCommand(path string) ([]string, error) {
+	if err := check(path); err != nil {
+		return nil, err
+	}
+	return []string{"osascript", "-l", "JavaScript", "-e", removeScript, fileURL(path)}, nil
+}
+
+// Add appends the app at path to the Dock and restarts the Dock to show it.
+func Add(ctx context.Context, path string) error {
+	argv, err := AddCommand(path)
+	if err != nil {
+		return err
+	}
+	return edit(ctx, argv)
+}
+
+// Remove drops the app at path from the Dock and restarts the Dock.
+func Remove(ctx context.Context, path string) error {
+	argv, err := RemoveCommand(path)
+	if err != nil {
+		return err
+	}
+	return edit(ctx, argv)
+}
+
+// edit runs argv and then restarts the Dock, which launchd starts again
+// with the new preferences.
+func edit(ctx context.Context, argv []string) error {
+	if out, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("dock: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	if err := exec.CommandContext(ctx, "killall", "Dock").Run(); err != nil {
+		return fmt.Errorf("dock: restarting the Dock: %w", err)
+	}
+	return nil
+}
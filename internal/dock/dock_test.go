@@ -0,0 +1,87 @@
+package dock
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseApps(t *testing.T) {
+	out := `(
+        {
+        GUID = 1234;
+        "tile-data" =         {
+            "file-data" =             {
+                "_CFURLString" = "file:///System/Applications/Mail.app/";
+                "_CFURLStringType" = 15;
+            };
+            "file-label" = Mail;
+        };
+        "tile-type" = "file-tile";
+    },
+        {
+        "tile-data" =         {
+            "file-data" =             {
+                "_CFURLString" = "file:///Applications/Visual%20Studio%20Code.app/";
+                "_CFURLStringType" = 15;
+            };
+        };
+    },
+        {
+        "tile-data" =         {
+            "file-data" =             {
+                "_CFURLString" = "https://example.com/";
+                "_CFURLStringType" = 15;
+            };
+        };
+    }
+)`
+	want := []string{"/System/Applications/Mail.app", "/Applications/Visual Studio Code.app"}
+	if got := parseApps(out); !slices.Equal(got, want) {
+		t.Errorf("parseApps = %q, want %q", got, want)
+	}
+}
+
+func TestAddCommand(t *testing.T) {
+	got, err := AddCommand("/Applications/Visual Studio Code.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"defaults", "write", "com.apple.dock", "persistent-apps", "-array-add",
+		"<dict><key>tile-data</key><dict><key>file-data</key><dict>" +
+			"<key>_CFURLString</key><string>file:///Applications/Visual%20Studio%20Code.app/</string>" +
+			"<key>_CFURLStringType</key><integer>15</integer>" +
+			"</dict></dict></dict>"}
+	if !slices.Equal(got, want) {
+		t.Errorf("AddCommand =\n%q\nwant\n%q", got, want)
+	}
+	// The tile's URL is escaped for the property list.
+	got, err = AddCommand("/Applications/R&D.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tile := got[len(got)-1]; !strings.Contains(tile, "<string>file:///Applications/R&amp;D.app/</string>") {
+		t.Errorf("AddCommand of R&D.app tile = %s", tile)
+	}
+}
+
+func TestRemoveCommand(t *testing.T) {
+	got, err := RemoveCommand("/Applications/Slack.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"osascript", "-l", "JavaScript", "-e", removeScript, "file:///Applications/Slack.app/"}; !slices.Equal(got, want) {
+		t.Errorf("RemoveCommand = %q, want %q", got, want)
+	}
+}
+
+func TestRefusesNonApps(t *testing.T) {
+	for _, path := range []string{"/Users/me/notes.txt", "/Applications", "Slack.app", "", "/usr/bin/open"} {
+		if _, err := AddCommand(path); err == nil {
+			t.Errorf("AddCommand(%q) succeeded", path)
+		}
+		if _, err := RemoveCommand(path); err == nil {
+			t.Errorf("RemoveCommand(%q) succeeded", path)
+		}
+	}
+}
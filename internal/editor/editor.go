@@ -0,0 +1,64 @@
+// Package editor opens files and folders in the user's editor, as set by
+// config.Config.Editor, e.g. "code -g {file}:{line}".
+package editor
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrEmpty is returned for an editor command with no words.
+var ErrEmpty = errors.New("editor: empty editor command")
+
+// Open starts editor on path at line without waiting for it to exit.
+func Open(editor, path string, line int) error {
+	args, err := fileArgs(editor, path, line)
+	if err != nil {
+		return err
+	}
+	return start(args)
+}
+
+// OpenDir starts editor on dir without waiting for it to exit.
+func OpenDir(editor, dir string) error {
+	args, err := dirArgs(editor, dir)
+	if err != nil {
+		return err
+	}
+	return start(args)
+}
+
+// fileArgs splits editor on spaces and replaces {file} and {line} in each
+// of its arguments.
+func fileArgs(editor, path string, line int) ([]string, error) {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return nil, ErrEmpty
+	}
+	for i, a := range args {
+		a = strings.ReplaceAll(a, "{file}", path)
+		args[i] = strings.ReplaceAll(a, "{line}", strconv.Itoa(line))
+	}
+	return args, nil
+}
+
+// dirArgs is the first word of editor followed by dir. The rest of the
+// command names a file and line, which a folder has neither of.
+func dirArgs(editor, dir string) ([]string, error) {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return nil, ErrEmpty
+	}
+	return []string{args[0], dir}, nil
+}
+
+func start(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
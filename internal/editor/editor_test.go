@@ -0,0 +1,36 @@
+package editor
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"code -g {file}:{line}", []string{"code", "-g", "/src/my app/main.go:42"}},
+		{"  subl   {file}:{line} ", []string{"subl", "/src/my app/main.go:42"}},
+		{"vim +{line} {file}", []string{"vim", "+42", "/src/my app/main.go"}},
+		{"zed", []string{"zed"}},
+	}
+	for _, tt := range tests {
+		got, err := fileArgs(tt.editor, "/src/my app/main.go", 42)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("fileArgs(%q) = %q, %v, want %q", tt.editor, got, err, tt.want)
+		}
+	}
+	if got, err := dirArgs("code -g {file}:{line}", "/src/my app"); err != nil || !slices.Equal(got, []string{"code", "/src/my app"}) {
+		t.Errorf("dirArgs = %q, %v, want code on the folder", got, err)
+	}
+	for _, editor := range []string{"", "   "} {
+		if _, err := fileArgs(editor, "main.go", 1); !errors.Is(err, ErrEmpty) {
+			t.Errorf("fileArgs(%q) = %v, want ErrEmpty", editor, err)
+		}
+		if _, err := dirArgs(editor, "/src"); !errors.Is(err, ErrEmpty) {
+			t.Errorf("dirArgs(%q) = %v, want ErrEmpty", editor, err)
+		}
+	}
+}
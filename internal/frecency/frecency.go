@@ -0,0 +1,174 @@
+// Package frecency ranks result IDs by how often and how recently they were
+// activated.
+package frecency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+// HalfLife is how long it takes an activation to count for half as much.
+const HalfLife = 7 * 24 * time.Hour
+
+// Entry is the usage recorded for one result ID.
+type Entry struct {
+	// Count is the raw number of activations, unaffected by decay.
+	Count int `json:"count"`
+	// Last is when the result was last activated.
+	Last time.Time `json:"last"`
+	// Weight is the decayed activation count as of Last.
+	Weight float64 `json:"weight"`
+}
+
+// score returns the entry's weight decayed to now.
+func (e Entry) score(now time.Time) float64 {
+	elapsed := now.Sub(e.Last)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return e.Weight * math.Pow(0.5, float64(elapsed)/float64(HalfLife))
+}
+
+// Store keeps entries in memory and persists them to a JSON file.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// Open loads the store at path. A missing file yields an empty store, as
+// does a corrupt one, which is quarantined and reported in the error.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]Entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		s.entries = make(map[string]Entry)
+		return s, fmt.Errorf("frecency: %w", quarantine.Move(path, err))
+	}
+	return s, nil
+}
+
+// Record counts an activation of id at now and saves the store.
+func (s *Store) Record(id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entries[id]
+	e.Weight = e.score(now) + 1
+	e.Count++
+	e.Last = now
+	s.entries[id] = e
+	return s.save()
+}
+
+// Seed adds entries for the IDs that have none, for usage learned
+// elsewhere, and saves the store. Entries already recorded, from launches
+// made in Prism, are kept as they are. It returns how many were added.
+func (s *Store) Seed(entries map[string]Entry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := 0
+	for id, e := range entries {
+		if _, ok := s.entries[id]; ok {
+			continue
+		}
+		s.entries[id] = e
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.save()
+}
+
+// Score returns id's decayed frecency at now; unknown IDs score zero.
+func (s *Store) Score(id string, now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[id].score(now)
+}
+
+// Top returns up to n IDs with the highest frecency at now, best first.
+func (s *Store) Top(n int, now time.Time) []string {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.entries))
+	scores := make(map[string]float64, len(s.entries))
+	for id, e := range s.entries {
+		ids = append(ids, id)
+		scores[id] = e.score(now)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// Entries returns a copy of every recorded entry, keyed by result ID.
+func (s *Store) Entries() map[string]Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make(map[string]Entry, len(s.entries))
+	for id, e := range s.entries {
+		entries[id] = e
+	}
+	return entries
+}
+
+// Replace swaps every entry for entries and saves the store, for restoring
+// a backup.
+func (s *Store) Replace(entries map[string]Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]Entry, len(entries))
+	for id, e := range entries {
+		s.entries[id] = e
+	}
+	return s.save()
+}
+
+// Clear forgets every entry and saves the store.
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]Entry)
+	return s.save()
+}
+
+func (s *Store) save() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,87 @@
+package frecency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+func TestOpenCorrupt(t *testing.T) {
+	for _, data := range []string{`{"apps:/Applications/Mail.app": {"count": 3,`, "\x00\x01garbage"} {
+		path := filepath.Join(t.TempDir(), "frecency.json")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Open(path)
+		if err == nil {
+			t.Errorf("Open(%q) succeeded", data)
+		}
+		if len(s.Entries()) != 0 {
+			t.Errorf("Open(%q) has entries %v", data, s.Entries())
+		}
+		if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+			t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+		}
+		// The store starts over and saves again.
+		if err := s.Record("apps:/Applications/Notes.app", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		reopened, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := reopened.Entries()["apps:/Applications/Notes.app"]; !ok {
+			t.Errorf("reopened entries = %v", reopened.Entries())
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frecency.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mail, notes, safari := "apps:/Applications/Mail.app", "apps:/Applications/Notes.app", "apps:/Applications/Safari.app"
+	for range 2 {
+		if err := s.Record(mail, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	launched := s.Entries()[mail]
+
+	imported := map[string]Entry{
+		mail:   {Count: 500, Last: now.Add(time.Hour), Weight: 5},
+		notes:  {Count: 3, Last: now.Add(-time.Hour), Weight: 3},
+		safari: {Count: 40, Last: now.Add(-24 * time.Hour), Weight: 5},
+	}
+	added, err := s.Seed(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2 {
+		t.Errorf("Seed added %d, want 2", added)
+	}
+	// Seeding again adds nothing.
+	if added, err := s.Seed(imported); err != nil || added != 0 {
+		t.Errorf("Seed again = %d, %v, want 0", added, err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reopened.Entries()
+	if got := entries[mail]; !got.Last.Equal(launched.Last) || got.Count != launched.Count || got.Weight != launched.Weight {
+		t.Errorf("Mail = %+v, want the launches from Prism %+v", got, launched)
+	}
+	for _, id := range []string{notes, safari} {
+		if got, want := entries[id], imported[id]; !got.Last.Equal(want.Last) || got.Count != want.Count || got.Weight != want.Weight {
+			t.Errorf("%s = %+v, want the imported %+v", id, got, want)
+		}
+	}
+}
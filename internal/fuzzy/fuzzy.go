@@ -0,0 +1,175 @@
+// Package fuzzy implements the subsequence matcher used to rank results.
+package fuzzy
+
+import (
+	"sync/atomic"
+	"unicode"
+)
+
+// Params tunes how matches are scored. Bonuses are added and penalties, which
+// are negative, are added per rune, so raising a bonus or lowering a penalty
+// favours candidates with more of that property.
+type Params struct {
+	// MatchScore is added for every matched rune.
+	MatchScore int `json:"matchScore"`
+	// BoundaryBonus is added for a match at the start of a word or
+	// camelCase hump.
+	BoundaryBonus int `json:"boundaryBonus"`
+	// ConsecutiveBonus is added for a match immediately after the previous
+	// one.
+	ConsecutiveBonus int `json:"consecutiveBonus"`
+	// LeadingPenalty is added for each unmatched rune before the first
+	// match, down to MaxLeadingPenalty in total.
+	LeadingPenalty    int `json:"leadingPenalty"`
+	MaxLeadingPenalty int `json:"maxLeadingPenalty"`
+	// GapPenalty is added for each unmatched rune between two matches.
+	GapPenalty int `json:"gapPenalty"`
+	// PrefixBoost is added when the candidate starts with the whole pattern,
+	// ignoring case, so "mail" ranks Mail above Gmail by a wide margin.
+	PrefixBoost int `json:"prefixBoost"`
+}
+
+// DefaultParams returns the parameters Prism ships with.
+func DefaultParams() Params {
+	return Params{
+		MatchScore:        16,
+		BoundaryBonus:     8,
+		ConsecutiveBonus:  6,
+		LeadingPenalty:    -3,
+		MaxLeadingPenalty: -9,
+		GapPenalty:        -1,
+		PrefixBoost:       40,
+	}
+}
+
+// DefaultMinScore is the minimum average score per pattern rune Prism ships
+// with. With DefaultParams it drops matches whose runes are scattered far
+// apart mid-word, such as "xyz" in "proxy analyzer", and keeps abbreviations
+// such as "vsc" for Visual Studio Code.
+const DefaultMinScore = 14
+
+// Matcher scores candidates against a pattern.
+type Matcher struct {
+	p Params
+	// minScore is the lowest average score per pattern rune of a match;
+	// see SetMinScore.
+	minScore atomic.Int64
+}
+
+// New returns a matcher using params.
+func New(params Params) *Matcher {
+	return &Matcher{p: params}
+}
+
+// SetMinScore makes Match reject candidates scoring less than n per rune of
+// the pattern, on average, so the bar rises with the length of the query.
+// Zero or less accepts every match.
+func (m *Matcher) SetMinScore(n int) {
+	m.minScore.Store(int64(n))
+}
+
+// Default returns a matcher using DefaultParams.
+func Default() *Matcher {
+	return New(DefaultParams())
+}
+
+// Match reports whether the runes of pattern appear in candidate in order,
+// ignoring case, and how good the match is. Higher scores are better. An empty
+// pattern matches everything with a score of zero; otherwise matches below
+// the minimum score are rejected.
+//
+// PrefixBoost is sized to beat any subsequence match of the same pattern
+// but not heavy use: search results also gain a frecency bonus (see the
+// frecency transformer), so an app launched every day can still outrank a
+// rarely used one that the query happens to prefix.
+func (m *Matcher) Match(pattern, candidate string) (int, bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, true
+	}
+	c := []rune(candidate)
+	for i := range p {
+		p[i] = unicode.ToLower(p[i])
+	}
+	lower := make([]rune, len(c))
+	for i, r := range c {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	best, found := 0, false
+	for start := range lower {
+		if lower[start] != p[0] {
+			continue
+		}
+		score, ok := m.scoreFrom(p, c, lower, start)
+		if ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	if found && hasPrefix(lower, p) {
+		best += m.p.PrefixBoost
+	}
+	if min := m.minScore.Load(); found && min > 0 && int64(best) < min*int64(len(p)) {
+		return 0, false
+	}
+	return best, found
+}
+
+// scoreFrom greedily matches p against the candidate starting with p[0] at
+// position start.
+func (m *Matcher) scoreFrom(p, c, lower []rune, start int) (int, bool) {
+	score := max(m.p.LeadingPenalty*start, m.p.MaxLeadingPenalty)
+	prev := -1
+	j := start
+	for _, r := range p {
+		for j < len(lower) && lower[j] != r {
+			j++
+		}
+		if j == len(lower) {
+			return 0, false
+		}
+		score += m.p.MatchScore
+		if isBoundary(c, j) {
+			score += m.p.BoundaryBonus
+		}
+		if prev >= 0 {
+			if j == prev+1 {
+				score += m.p.ConsecutiveBonus
+			} else {
+				score += m.p.GapPenalty * (j - prev - 1)
+			}
+		}
+		prev = j
+		j++
+	}
+	return score, true
+}
+
+// hasPrefix reports whether s starts with prefix; both are lower-cased.
+func hasPrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isBoundary(c []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := c[i-1], c[i]
+	switch {
+	case unicode.IsSpace(prev), unicode.IsPunct(prev), unicode.IsSymbol(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package fuzzy
+
+import "testing"
+
+func score(t *testing.T, m *Matcher, pattern, candidate string) int {
+	t.Helper()
+	s, ok := m.Match(pattern, candidate)
+	if !ok {
+		t.Fatalf("Match(%q, %q) did not match", pattern, candidate)
+	}
+	return s
+}
+
+func TestParamsChangeRanking(t *testing.T) {
+	// "fb" matches "Foo Bar" at two word boundaries and "fbsd" consecutively.
+	const pattern, boundary, consecutive = "fb", "Foo Bar", "xfbsd"
+
+	def := Default()
+	if score(t, def, pattern, boundary) <= score(t, def, pattern, consecutive) {
+		t.Fatalf("with the defaults %q should rank above %q", boundary, consecutive)
+	}
+
+	p := DefaultParams()
+	p.BoundaryBonus = 0
+	p.ConsecutiveBonus = 30
+	m := New(p)
+	if score(t, m, pattern, boundary) >= score(t, m, pattern, consecutive) {
+		t.Errorf("favouring consecutive matches should rank %q above %q", consecutive, boundary)
+	}
+}
+
+func TestPrefixBoost(t *testing.T) {
+	m := Default()
+	mail := score(t, m, "mail", "Mail")
+	gmail := score(t, m, "mail", "Gmail")
+	if mail <= gmail {
+		t.Errorf("Mail scored %d, not above Gmail's %d", mail, gmail)
+	}
+	// Every name the query prefixes is boosted alike; the exact name then
+	// wins the tiebreak on length.
+	if mailbox := score(t, m, "mail", "Mailbox"); mailbox != mail {
+		t.Errorf("Mailbox scored %d, want Mail's %d", mailbox, mail)
+	}
+	if got := score(t, m, "MAIL", "mail"); got != mail {
+		t.Errorf("the boost should ignore case: %d != %d", got, mail)
+	}
+
+	p := DefaultParams()
+	p.PrefixBoost = 0
+	unboosted := score(t, New(p), "mail", "Mail")
+	if mail-unboosted != DefaultParams().PrefixBoost {
+		t.Errorf("prefix boost added %d, want %d", mail-unboosted, DefaultParams().PrefixBoost)
+	}
+	if unboosted := score(t, New(p), "mail", "Gmail"); unboosted != gmail {
+		t.Errorf("Gmail should not get the prefix boost: %d != %d", unboosted, gmail)
+	}
+}
+
+func TestMinScore(t *testing.T) {
+	tests := []struct {
+		pattern, candidate string
+		min                int
+		want               bool
+	}{
+		{"xyz", "proxy analyzer", 0, true},
+		{"xyz", "proxy analyzer", DefaultMinScore, false},
+		{"xyz", "proxy analyzer", 1, true},
+		{"vsc", "Visual Studio Code", DefaultMinScore, true},
+		{"mail", "Mail", DefaultMinScore, true},
+		{"", "anything", DefaultMinScore, true},
+	}
+	for _, tt := range tests {
+		m := Default()
+		m.SetMinScore(tt.min)
+		if _, ok := m.Match(tt.pattern, tt.candidate); ok != tt.want {
+			t.Errorf("Match(%q, %q) at min %d = %v, want %v", tt.pattern, tt.candidate, tt.min, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package history remembers the queries that led to an activation, so they
+// can be offered again.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"changeme/internal/quarantine"
+)
+
+// MaxQueries bounds the history; the oldest query is dropped first.
+const MaxQueries = 100
+
+// Store keeps distinct queries, most recent first, and persists them to a
+// JSON file.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// Open loads the store at path. A missing file yields an empty store, as
+// does a corrupt one, which is quarantined and reported in the error.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s.queries); err != nil {
+		s.queries = nil
+		return s, fmt.Errorf("history: %w", quarantine.Move(path, err))
+	}
+	return s, nil
+}
+
+// Add moves query to the front of the history and saves the store.
+func (s *Store) Add(query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = slices.DeleteFunc(s.queries, func(q string) bool { return q == query })
+	s.queries = slices.Insert(s.queries, 0, query)
+	if len(s.queries) > MaxQueries {
+		s.queries = s.queries[:MaxQueries]
+	}
+	return s.save()
+}
+
+// Recent returns up to n queries, most recent first.
+func (s *Store) Recent(n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.queries) {
+		n = len(s.queries)
+	}
+	return slices.Clone(s.queries[:n])
+}
+
+// Replace swaps the history for queries, most recent first, and saves the
+// store.
+func (s *Store) Replace(queries []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = slices.Clone(queries)
+	if len(s.queries) > MaxQueries {
+		s.queries = s.queries[:MaxQueries]
+	}
+	return s.save()
+}
+
+// Clear forgets every query.
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = nil
+	return s.save()
+}
+
+func (s *Store) save() error {
+	data, err := json.Marshal(s.queries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
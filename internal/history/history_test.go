@@ -0,0 +1,39 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/quarantine"
+)
+
+func TestOpenCorrupt(t *testing.T) {
+	for _, data := range []string{`["mail", "not`, `{"queries": true}`} {
+		path := filepath.Join(t.TempDir(), "history.json")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Open(path)
+		if err == nil {
+			t.Errorf("Open(%q) succeeded", data)
+		}
+		if got := s.Recent(MaxQueries); len(got) != 0 {
+			t.Errorf("Open(%q) has queries %v", data, got)
+		}
+		if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+			t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+		}
+		if err := s.Add("notes"); err != nil {
+			t.Fatal(err)
+		}
+		reopened, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reopened.Recent(MaxQueries); !slices.Equal(got, []string{"notes"}) {
+			t.Errorf("reopened queries = %v, want [notes]", got)
+		}
+	}
+}
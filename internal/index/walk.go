@@ -0,0 +1,98 @@
+// Package index walks and watches the directories Prism indexes.
+package index
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxDepth is used for roots that do not set a depth.
+const DefaultMaxDepth = 4
+
+// DefaultIgnore lists directories and files that are never indexed: names,
+// and ~/Library, which holds app data rather than the user's files.
+var DefaultIgnore = []string{"node_modules", ".git", ".DS_Store", "~/Library"}
+
+// Root is a directory to index.
+type Root struct {
+	Path string `json:"path"`
+	// MaxDepth is how many levels below Path are visited; entries directly
+	// inside Path are at depth 1. Zero means DefaultMaxDepth.
+	MaxDepth int `json:"maxDepth,omitempty"`
+}
+
+// Visit is called for each entry found by Walk. Returning false for a
+// directory stops Walk from descending into it, which is how bundles such as
+// .app directories are treated as single items.
+type Visit func(path string, d fs.DirEntry) (descend bool)
+
+// Walk visits the entries under root up to its depth limit, skipping any
+// entry that matches one of the ignore patterns; see Ignored. A leading
+// "~/" in the root is expanded. Unreadable directories,
+// including roots on unmounted volumes, are skipped silently.
+func Walk(root Root, ignore []string, visit Visit) {
+	base := Expand(root.Path)
+	depthLimit := root.MaxDepth
+	if depthLimit <= 0 {
+		depthLimit = DefaultMaxDepth
+	}
+	filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != base {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == base {
+			return nil
+		}
+		if Ignored(path, ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		depth := strings.Count(strings.TrimPrefix(path, base), string(filepath.Separator))
+		descend := visit(path, d)
+		if d.IsDir() && (!descend || depth >= depthLimit) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// Ignored reports whether the entry at path matches any of the patterns,
+// in filepath.Match syntax. A pattern with a slash, such as "~/Library",
+// matches the path, with a leading "~/" expanded; any other matches the
+// entry's name, such as "node_modules".
+func Ignored(path string, patterns []string) bool {
+	name := filepath.Base(path)
+	for _, p := range patterns {
+		target := name
+		if strings.Contains(p, "/") {
+			p, target = filepath.Clean(Expand(p)), path
+		}
+		if ok, _ := filepath.Match(p, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden reports whether name is that of a hidden file or folder, which
+// starts with a dot.
+func Hidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// Expand replaces a leading "~/" with the user's home directory.
+func Expand(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
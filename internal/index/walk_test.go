@@ -0,0 +1,121 @@
+package index
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// tree creates the given files, and their directories, under a temporary
+// directory and returns it.
+func tree(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// walked returns the paths Walk visits, relative to root and with forward
+// slashes.
+func walked(root Root, ignore []string) []string {
+	var out []string
+	Walk(root, ignore, func(path string, d fs.DirEntry) bool {
+		rel, _ := filepath.Rel(root.Path, path)
+		out = append(out, filepath.ToSlash(rel))
+		return true
+	})
+	slices.Sort(out)
+	return out
+}
+
+func TestWalkDepth(t *testing.T) {
+	root := tree(t, "a.txt", "one/b.txt", "one/two/c.txt", "one/two/three/d.txt")
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{1, []string{"a.txt", "one"}},
+		{2, []string{"a.txt", "one", "one/b.txt", "one/two"}},
+		{3, []string{"a.txt", "one", "one/b.txt", "one/two", "one/two/c.txt", "one/two/three"}},
+		{0, []string{"a.txt", "one", "one/b.txt", "one/two", "one/two/c.txt", "one/two/three", "one/two/three/d.txt"}},
+	}
+	for _, tt := range tests {
+		if got := walked(Root{Path: root, MaxDepth: tt.depth}, nil); !slices.Equal(got, tt.want) {
+			t.Errorf("depth %d: walked %v, want %v", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestWalkIgnore(t *testing.T) {
+	root := tree(t,
+		"app/main.go",
+		"app/node_modules/left-pad/index.js",
+		"app/.git/HEAD",
+		"notes.md",
+		"notes.tmp",
+		"build/out.bin",
+	)
+	ignore := []string{"node_modules", ".git", "*.tmp", filepath.Join(root, "build")}
+	want := []string{"app", "app/main.go", "notes.md"}
+	if got := walked(Root{Path: root}, ignore); !slices.Equal(got, want) {
+		t.Errorf("walked %v, want %v", got, want)
+	}
+}
+
+func TestWalkNoDescend(t *testing.T) {
+	root := tree(t, "Mail.app/Contents/Info.plist", "Notes.app/Contents/Info.plist")
+	var got []string
+	Walk(Root{Path: root}, nil, func(path string, d fs.DirEntry) bool {
+		got = append(got, filepath.Base(path))
+		return filepath.Ext(path) != ".app"
+	})
+	slices.Sort(got)
+	if want := []string{"Mail.app", "Notes.app"}; !slices.Equal(got, want) {
+		t.Errorf("walked %v, want %v", got, want)
+	}
+}
+
+func TestWalkMissingRoot(t *testing.T) {
+	if got := walked(Root{Path: filepath.Join(t.TempDir(), "unmounted")}, nil); len(got) != 0 {
+		t.Errorf("walked %v under a missing root", got)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	patterns := []string{"node_modules", "*.tmp", "~/Library"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/src/app/node_modules", true},
+		{"/src/app/notes.tmp", true},
+		{filepath.Join(home, "Library"), true},
+		{filepath.Join(home, "Documents", "Library"), false},
+		{"/src/app/main.go", false},
+	}
+	for _, tt := range tests {
+		if got := Ignored(tt.path, patterns); got != tt.want {
+			t.Errorf("Ignored(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	for _, name := range []string{".env", ".git"} {
+		if !Hidden(name) {
+			t.Errorf("Hidden(%s) = false", name)
+		}
+	}
+	if Hidden("notes.md") {
+		t.Error("Hidden(notes.md) = true")
+	}
+}
@@ -0,0 +1,114 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounce collapses bursts of file system events, such as an app being
+// copied into place, into a single change notification.
+const debounce = 500 * time.Millisecond
+
+// Watcher reports changes to a set of directories. A directory that does not
+// exist, such as a folder on an unmounted drive, is tracked by watching its
+// nearest existing ancestor, so mounting the drive is reported as a change.
+type Watcher struct {
+	fs       *fsnotify.Watcher
+	onChange func()
+
+	mu      sync.Mutex
+	dirs    []string
+	watched map[string]bool
+	pending *time.Timer
+}
+
+// NewWatcher returns a watcher that calls onChange after changes settle.
+func NewWatcher(onChange func()) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{fs: fsw, onChange: onChange, watched: make(map[string]bool)}, nil
+}
+
+// Watch replaces the set of watched directories.
+func (w *Watcher) Watch(dirs []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dirs = append([]string(nil), dirs...)
+	w.rewatch()
+}
+
+// rewatch points the underlying watches at each directory or, when it is
+// missing, its nearest existing ancestor. The caller holds w.mu.
+func (w *Watcher) rewatch() {
+	want := make(map[string]bool)
+	for _, d := range w.dirs {
+		want[existingAncestor(Expand(d))] = true
+	}
+	for d := range w.watched {
+		if !want[d] {
+			w.fs.Remove(d)
+			delete(w.watched, d)
+		}
+	}
+	for d := range want {
+		if !w.watched[d] && w.fs.Add(d) == nil {
+			w.watched[d] = true
+		}
+	}
+}
+
+// Run delivers events until ctx is cancelled, then closes the watcher.
+func (w *Watcher) Run(ctx context.Context) {
+	defer w.fs.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			w.changed()
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) changed() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending != nil {
+		w.pending.Stop()
+	}
+	w.pending = time.AfterFunc(debounce, func() {
+		// A root may have appeared or vanished; move the watches first so
+		// the next change is seen in the right place.
+		w.mu.Lock()
+		w.rewatch()
+		w.mu.Unlock()
+		w.onChange()
+	})
+}
+
+func existingAncestor(dir string) string {
+	for {
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,181 @@
+// Package interactions learns which result the user picks for a query, so
+// the same pick can rank higher the next time the query is typed.
+//
+// Only the normalized query, the chosen result ID and where it was shown
+// are kept, in a local file. Nothing is sent anywhere.
+package interactions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"changeme/internal/quarantine"
+)
+
+// MaxQueries is how many distinct queries are remembered. Past it, the
+// queries chosen from longest ago are forgotten first.
+const MaxQueries = 1000
+
+// minPrefix is the shortest query that borrows choices made for longer
+// queries it begins, so a single letter does not match everything.
+const minPrefix = 2
+
+// prefixShare is how much a choice made for a longer query counts towards
+// a query it begins with, which is usually that query still being typed.
+const prefixShare = 0.5
+
+// Choice is how often one result was picked for a query.
+type Choice struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+	// Position is where the result was listed the last time it was picked,
+	// 0 being the top.
+	Position int `json:"position"`
+}
+
+// Store keeps choices in memory and persists them to a JSON file.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	queries map[string]map[string]Choice
+}
+
+// Open loads the store at path. A missing file yields an empty store, as
+// does a corrupt one, which is quarantined and reported in the error.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, queries: make(map[string]map[string]Choice)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s.queries); err != nil {
+		s.queries = make(map[string]map[string]Choice)
+		return s, fmt.Errorf("interactions: %w", quarantine.Move(path, err))
+	}
+	return s, nil
+}
+
+// Normalize reduces query to the pattern choices are keyed by: lower case,
+// with runs of spaces collapsed.
+func Normalize(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// Record counts resultID, listed at position, as picked for query at now
+// and saves the store. Blank queries are not recorded.
+func (s *Store) Record(query, resultID string, position int, now time.Time) error {
+	q := Normalize(query)
+	if q == "" || resultID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	choices := s.queries[q]
+	if choices == nil {
+		choices = make(map[string]Choice)
+		s.queries[q] = choices
+	}
+	c := choices[resultID]
+	c.Count++
+	c.Last = now
+	c.Position = position
+	choices[resultID] = c
+	s.trim()
+	return s.save()
+}
+
+// Counts returns, for each result picked for a query like query, how many
+// times it was picked. Picks for query itself count fully; picks for a
+// longer query it begins count prefixShare each.
+func (s *Store) Counts(query string) map[string]float64 {
+	q := Normalize(query)
+	if q == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var counts map[string]float64
+	add := func(choices map[string]Choice, share float64) {
+		if counts == nil {
+			counts = make(map[string]float64)
+		}
+		for id, c := range choices {
+			counts[id] += share * float64(c.Count)
+		}
+	}
+	if choices, ok := s.queries[q]; ok {
+		add(choices, 1)
+	}
+	if utf8.RuneCountInString(q) >= minPrefix {
+		for past, choices := range s.queries {
+			if past != q && strings.HasPrefix(past, q) {
+				add(choices, prefixShare)
+			}
+		}
+	}
+	return counts
+}
+
+// Clear forgets every choice and saves the store.
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = make(map[string]map[string]Choice)
+	return s.save()
+}
+
+// trim forgets the queries picked from longest ago beyond MaxQueries.
+func (s *Store) trim() {
+	if len(s.queries) <= MaxQueries {
+		return
+	}
+	type query struct {
+		q    string
+		last time.Time
+	}
+	all := make([]query, 0, len(s.queries))
+	for q, choices := range s.queries {
+		var last time.Time
+		for _, c := range choices {
+			if c.Last.After(last) {
+				last = c.Last
+			}
+		}
+		all = append(all, query{q, last})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].last.Before(all[j].last) })
+	for _, old := range all[:len(all)-MaxQueries] {
+		delete(s.queries, old.q)
+	}
+}
+
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.queries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
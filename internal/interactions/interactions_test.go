@@ -0,0 +1,113 @@
+package interactions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+func TestCounts(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "interactions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, pick := range []struct{ query, id string }{
+		{"mail", "apps:mail"},
+		{"Mail ", "apps:mail"},
+		{"mail  app", "apps:mail"},
+		{"mailbox", "files:mailbox"},
+		{"m", "apps:music"},
+		{"  ", "apps:ignored"},
+	} {
+		if err := s.Record(pick.query, pick.id, 0, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		query string
+		want  map[string]float64
+	}{
+		// Longer queries beginning with the query count half.
+		{"MAIL", map[string]float64{"apps:mail": 2.5, "files:mailbox": 0.5}},
+		{"mail app", map[string]float64{"apps:mail": 1}},
+		{"ma", map[string]float64{"apps:mail": 1.5, "files:mailbox": 0.5}},
+		// A single letter borrows nothing from longer queries.
+		{"m", map[string]float64{"apps:music": 1}},
+		{"notes", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := s.Counts(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Counts(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestPersistAndClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interactions.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Record("mail", "apps:mail", 2, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Counts("mail"); got["apps:mail"] != 1 {
+		t.Errorf("after reopening, Counts(mail) = %v", got)
+	}
+	if err := reopened.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if reopened, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Counts("mail"); got != nil {
+		t.Errorf("after Clear, Counts(mail) = %v", got)
+	}
+}
+
+func TestOpenCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interactions.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(path)
+	if err == nil {
+		t.Error("Open of a corrupt store succeeded")
+	}
+	if s == nil || s.Counts("mail") != nil {
+		t.Errorf("Open of a corrupt store = %v, want an empty store", s)
+	}
+	if _, err := os.Stat(path + quarantine.Suffix); err != nil {
+		t.Errorf("corrupt store was not moved aside: %v", err)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	s, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := range MaxQueries + 10 {
+		if err := s.Record(fmt.Sprint("query ", i), "apps:mail", 0, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(s.queries); n != MaxQueries {
+		t.Errorf("%d queries kept, want %d", n, MaxQueries)
+	}
+	if s.Counts("query 0") != nil || s.Counts(fmt.Sprint("query ", MaxQueries+9)) == nil {
+		t.Error("trimmed the wrong queries")
+	}
+}
@@ -0,0 +1,43 @@
+// Package keys formats the key bindings users write in the config, such as
+// "cmd+enter", for display, and recognizes multi-key sequences like "g s".
+package keys
+
+import "strings"
+
+var symbols = map[string]string{
+	"cmd":       "⌘",
+	"command":   "⌘",
+	"super":     "⌘",
+	"ctrl":      "⌃",
+	"control":   "⌃",
+	"alt":       "⌥",
+	"option":    "⌥",
+	"shift":     "⇧",
+	"enter":     "↵",
+	"return":    "↵",
+	"tab":       "⇥",
+	"escape":    "⎋",
+	"esc":       "⎋",
+	"backspace": "⌫",
+	"delete":    "⌦",
+	"space":     "␣",
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+}
+
+// Format renders a binding like "cmd+shift+enter" as "⌘⇧↵". Keys without a
+// symbol are upper-cased, so "cmd+k" becomes "⌘K".
+func Format(binding string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(binding, "+") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if sym, ok := symbols[part]; ok {
+			b.WriteString(sym)
+		} else {
+			b.WriteString(strings.ToUpper(part))
+		}
+	}
+	return b.String()
+}
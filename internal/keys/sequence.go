@@ -0,0 +1,106 @@
+package keys
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSequenceTimeout is how long a Sequencer waits for the next key of
+// a sequence when no timeout is configured.
+const DefaultSequenceTimeout = time.Second
+
+// Press is what a Sequencer made of a key.
+type Press struct {
+	// Command is set when the key completed a sequence.
+	Command string `json:"command,omitempty"`
+	// Consumed is set when the key belongs to a sequence and should not be
+	// handled any other way.
+	Consumed bool `json:"consumed"`
+	// Replay lists keys held back as the possible start of a sequence that
+	// did not complete. They should be handled as if typed now, ahead of
+	// the key itself when it is not consumed.
+	Replay []string `json:"replay,omitempty"`
+}
+
+// Sequencer recognizes multi-key sequences such as "g s", each key pressed
+// within the timeout of the one before.
+type Sequencer struct {
+	mu        sync.Mutex
+	sequences map[string]string
+	timeout   time.Duration
+	pending   []string
+	last      time.Time
+}
+
+// NewSequencer returns a Sequencer with no sequences.
+func NewSequencer() *Sequencer {
+	return &Sequencer{timeout: DefaultSequenceTimeout}
+}
+
+// Set replaces the sequences, which map keys separated by spaces to a
+// command, and the timeout between keys. A timeout of zero or less uses
+// DefaultSequenceTimeout. Any sequence in progress is dropped.
+func (s *Sequencer) Set(sequences map[string]string, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultSequenceTimeout
+	}
+	normalized := make(map[string]string, len(sequences))
+	for seq, cmd := range sequences {
+		if keys := strings.Fields(strings.ToLower(seq)); len(keys) > 0 && cmd != "" {
+			normalized[strings.Join(keys, " ")] = cmd
+		}
+	}
+	s.mu.Lock()
+	s.sequences, s.timeout, s.pending = normalized, timeout, nil
+	s.mu.Unlock()
+}
+
+// Press feeds key, pressed at now, to the sequencer.
+func (s *Sequencer) Press(key string, now time.Time) Press {
+	key = strings.ToLower(strings.TrimSpace(key))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var p Press
+	if len(s.pending) > 0 && now.Sub(s.last) > s.timeout {
+		p.Replay, s.pending = s.pending, nil
+	}
+	if s.advance(append(s.pending, key), now, &p) {
+		return p
+	}
+	// The key broke the sequence in progress; it may still start another.
+	if len(s.pending) > 0 {
+		p.Replay = append(p.Replay, s.pending...)
+		s.pending = nil
+		s.advance([]string{key}, now, &p)
+	}
+	return p
+}
+
+// advance tries seq as the keys pressed so far and reports whether it is
+// or begins a sequence. The caller holds s.mu.
+func (s *Sequencer) advance(seq []string, now time.Time, p *Press) bool {
+	joined := strings.Join(seq, " ")
+	if cmd, ok := s.sequences[joined]; ok {
+		s.pending = nil
+		p.Command, p.Consumed = cmd, true
+		return true
+	}
+	for full := range s.sequences {
+		if strings.HasPrefix(full, joined+" ") {
+			s.pending, s.last = seq, now
+			p.Consumed = true
+			return true
+		}
+	}
+	return false
+}
+
+// Reset drops the sequence in progress and returns the keys it held back.
+func (s *Sequencer) Reset() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = nil
+	return pending
+}
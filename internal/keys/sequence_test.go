@@ -0,0 +1,92 @@
+package keys
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSequencer(t *testing.T) {
+	type step struct {
+		key   string
+		after time.Duration // since the previous key
+		want  Press
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"sequence", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"S", 100 * time.Millisecond, Press{Command: "open-settings", Consumed: true}},
+		}},
+		{"longer sequence", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"r", 100 * time.Millisecond, Press{Consumed: true}},
+			{"i", 100 * time.Millisecond, Press{Command: "rebuild-index", Consumed: true}},
+		}},
+		{"single key", []step{
+			{"?", 0, Press{Command: "help", Consumed: true}},
+		}},
+		{"not a sequence", []step{
+			{"x", 0, Press{}},
+		}},
+		// A key that continues no sequence gives back the keys held.
+		{"broken", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"x", 100 * time.Millisecond, Press{Replay: []string{"g"}}},
+		}},
+		// ...and may start another itself.
+		{"broken into another", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"g", 100 * time.Millisecond, Press{Consumed: true, Replay: []string{"g"}}},
+			{"s", 100 * time.Millisecond, Press{Command: "open-settings", Consumed: true}},
+		}},
+		{"timeout", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"s", 2 * time.Second, Press{Replay: []string{"g"}}},
+		}},
+		{"timeout then restart", []step{
+			{"g", 0, Press{Consumed: true}},
+			{"g", 2 * time.Second, Press{Consumed: true, Replay: []string{"g"}}},
+			{"s", 100 * time.Millisecond, Press{Command: "open-settings", Consumed: true}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSequencer()
+			s.Set(map[string]string{
+				"g s":    "open-settings",
+				"G  R I": "rebuild-index",
+				"?":      "help",
+				"q":      "", // no command; ignored
+			}, time.Second)
+			now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+			for i, st := range tt.steps {
+				now = now.Add(st.after)
+				if got := s.Press(st.key, now); !reflect.DeepEqual(got, st.want) {
+					t.Errorf("key %d %q: Press = %+v, want %+v", i, st.key, got, st.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSequencerReset(t *testing.T) {
+	s := NewSequencer()
+	s.Set(map[string]string{"g r i": "rebuild-index"}, 0)
+	now := time.Now()
+	s.Press("g", now)
+	s.Press("r", now)
+	if got := s.Reset(); !reflect.DeepEqual(got, []string{"g", "r"}) {
+		t.Errorf("Reset = %q, want [g r]", got)
+	}
+	if p := s.Press("i", now); p.Command != "" || p.Consumed {
+		t.Errorf("Press(i) after Reset = %+v, want nothing", p)
+	}
+	// The default timeout applies when none is set.
+	s.Press("g", now)
+	if p := s.Press("r", now.Add(DefaultSequenceTimeout/2)); !p.Consumed {
+		t.Errorf("Press(r) within the default timeout = %+v", p)
+	}
+}
@@ -0,0 +1,83 @@
+package locale
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDate reads a numeric date such as "25/12/2025", "12-25-25" or
+// "2025.12.25" in the locale's order of day, month and year, in the
+// location of now. All three parts are required, since "10/2" is more
+// likely a division. The day and month have one or two digits and the year
+// two or four, so "8/4/2" is not a date; a two-digit year is in this
+// century. A four-digit first part is always read as the year, whatever
+// the locale, since no other order starts with one.
+func (l Locale) ParseDate(s string, now time.Time) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	sep := strings.IndexAny(s, "/.-")
+	if sep <= 0 {
+		return time.Time{}, false
+	}
+	parts := strings.Split(s, s[sep:sep+1])
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	n := make([]int, len(parts))
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 || len(p) > 4 {
+			return time.Time{}, false
+		}
+		n[i] = v
+	}
+	order := l.Dates
+	if len(parts[0]) == 4 {
+		order = YearMonthDay
+	}
+	yearPart, dayMonth := 2, []int{0, 1}
+	if order == YearMonthDay {
+		yearPart, dayMonth = 0, []int{1, 2}
+	}
+	if n := len(parts[yearPart]); n != 2 && n != 4 {
+		return time.Time{}, false
+	}
+	for _, i := range dayMonth {
+		if len(parts[i]) > 2 {
+			return time.Time{}, false
+		}
+	}
+	var year, month, day int
+	switch {
+	case order == YearMonthDay:
+		year, month, day = n[0], n[1], n[2]
+	case order == DayMonthYear:
+		year, month, day = n[2], n[1], n[0]
+	default:
+		year, month, day = n[2], n[0], n[1]
+	}
+	if year < 100 {
+		year += 2000
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, now.Location())
+	// time.Date normalizes out-of-range values; a date that moved was not
+	// a real one.
+	if t.Day() != day || int(t.Month()) != month || t.Year() != year {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// FormatDate writes t as a long date with the weekday, in the locale's
+// order: "Thursday, December 25, 2025" in the United States,
+// "Thursday, 25 December 2025" in Britain and "2025-12-25, Thursday"
+// where the year comes first.
+func (l Locale) FormatDate(t time.Time) string {
+	switch l.Dates {
+	case DayMonthYear:
+		return t.Format("Monday, 2 January 2006")
+	case YearMonthDay:
+		return t.Format("2006-01-02, Monday")
+	}
+	return t.Format("Monday, January 2, 2006")
+}
@@ -0,0 +1,36 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	british := Lookup("en-GB")
+	tests := []struct {
+		loc  Locale
+		s    string
+		want string // "" when s is not a date
+	}{
+		{English, "12/25/2025", "2025-12-25"},
+		{English, "12-25-25", "2025-12-25"},
+		{English, "2025.12.25", "2025-12-25"},
+		{british, "25/12/2025", "2025-12-25"},
+		{English, "10-2-5", ""},
+		{English, "8/4/2", ""},
+		{English, "8/4/202", ""},
+		{English, "123/4/2025", ""},
+		{English, "2/30/2025", ""},
+		{English, "10/2", ""},
+	}
+	for _, tt := range tests {
+		got, ok := tt.loc.ParseDate(tt.s, now)
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("%s: ParseDate(%q) = %v, want no date", tt.loc.Tag, tt.s, got)
+		case tt.want != "" && (!ok || got.Format("2006-01-02") != tt.want):
+			t.Errorf("%s: ParseDate(%q) = %v, %v, want %s", tt.loc.Tag, tt.s, got, ok, tt.want)
+		}
+	}
+}
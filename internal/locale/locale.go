@@ -0,0 +1,192 @@
+// Package locale reads and writes numbers and dates the way a locale does:
+// "1.234,5" in German is "1,234.5" in English, and 03/04/2025 is the 3rd
+// of April in Britain but March 4th in the United States.
+package locale
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DateOrder is the order of day, month and year in a numeric date.
+type DateOrder int
+
+const (
+	MonthDayYear DateOrder = iota
+	DayMonthYear
+	YearMonthDay
+)
+
+// Locale holds the conventions Prism needs from a locale.
+type Locale struct {
+	// Tag is the BCP 47 language tag, such as "de-DE".
+	Tag string
+	// Decimal separates the fraction; Group separates thousands.
+	Decimal, Group rune
+	Dates          DateOrder
+}
+
+// English is the locale used when none is configured or detected.
+var English = Locale{Tag: "en-US", Decimal: '.', Group: ',', Dates: MonthDayYear}
+
+// Conventions by language, with overrides for regions that differ from it.
+var (
+	languages = map[string]Locale{
+		"en": {Decimal: '.', Group: ',', Dates: DayMonthYear},
+		"de": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"es": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"it": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"nl": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"pt": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"da": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"tr": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"id": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+		"fr": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"ru": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"pl": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"cs": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"sv": {Decimal: ',', Group: ' ', Dates: YearMonthDay},
+		"fi": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"nb": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"uk": {Decimal: ',', Group: ' ', Dates: DayMonthYear},
+		"ja": {Decimal: '.', Group: ',', Dates: YearMonthDay},
+		"zh": {Decimal: '.', Group: ',', Dates: YearMonthDay},
+		"ko": {Decimal: '.', Group: ',', Dates: YearMonthDay},
+	}
+	regions = map[string]Locale{
+		"en-US": English,
+		"en-CA": {Decimal: '.', Group: ',', Dates: YearMonthDay},
+		"en-PH": {Decimal: '.', Group: ',', Dates: MonthDayYear},
+		"de-CH": {Decimal: '.', Group: '\'', Dates: DayMonthYear},
+		"es-MX": {Decimal: '.', Group: ',', Dates: DayMonthYear},
+		"pt-BR": {Decimal: ',', Group: '.', Dates: DayMonthYear},
+	}
+)
+
+// Lookup returns the conventions for tag, which may be written as a BCP 47
+// tag like "de-DE" or a POSIX locale like "de_DE.UTF-8". Unknown languages
+// get English conventions.
+func Lookup(tag string) Locale {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag = strings.ReplaceAll(tag, "_", "-")
+	lang, region, _ := strings.Cut(tag, "-")
+	lang = strings.ToLower(lang)
+	tag = lang
+	if region != "" {
+		tag += "-" + strings.ToUpper(region)
+	}
+	l, ok := regions[tag]
+	if !ok {
+		if l, ok = languages[lang]; !ok {
+			return English
+		}
+	}
+	l.Tag = tag
+	return l
+}
+
+// System returns the locale named by the environment, checking LC_ALL,
+// LC_NUMERIC and LANG in that order as POSIX does. Apps started from the
+// macOS Finder usually have none of them set and get English.
+func System() Locale {
+	for _, v := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if tag := os.Getenv(v); tag != "" && tag != "C" && tag != "POSIX" {
+			return Lookup(tag)
+		}
+	}
+	return English
+}
+
+// isGroup reports whether r separates thousands in l. Where the locale uses
+// a space, a no-break or narrow no-break space counts too.
+func (l Locale) isGroup(r rune) bool {
+	if l.Group == ' ' {
+		return r == ' ' || r == '\u00a0' || r == '\u202f'
+	}
+	return r == l.Group
+}
+
+// ParseNumber reads a number written in the locale's style. Thousands
+// separators are optional but, when present, must group the integer part
+// in threes, so "1,5" is not a number in English and "1.000" is one
+// thousand in German but one in English.
+func (l Locale) ParseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	intPart, frac, hasFrac := strings.Cut(s, string(l.Decimal))
+	neg := strings.HasPrefix(intPart, "-")
+	intPart = strings.TrimPrefix(intPart, "-")
+	var digits strings.Builder
+	group := -1 // digits since the last separator; -1 before the first
+	count := 0
+	for _, r := range intPart {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+			count++
+			if group >= 0 {
+				group++
+			}
+		case l.isGroup(r):
+			if group == -1 && (count == 0 || count > 3) || group >= 0 && group != 3 {
+				return 0, false
+			}
+			group = 0
+		default:
+			return 0, false
+		}
+	}
+	if group >= 0 && group != 3 {
+		return 0, false
+	}
+	for _, r := range frac {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	if count == 0 && frac == "" {
+		return 0, false
+	}
+	num := digits.String()
+	if num == "" {
+		num = "0"
+	}
+	if hasFrac {
+		num += "." + frac
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+// FormatNumber writes v in the locale's style with up to decimals fraction
+// digits, dropping trailing zeros, and with thousands separators when
+// grouped is set.
+func (l Locale) FormatNumber(v float64, decimals int, grouped bool) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, frac, _ := strings.Cut(s, ".")
+	frac = strings.TrimRight(frac, "0")
+	var b strings.Builder
+	if neg && (strings.Trim(intPart, "0") != "" || frac != "") {
+		b.WriteByte('-')
+	}
+	for i, r := range intPart {
+		if grouped && i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteRune(l.Group)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteRune(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+// Package lru keeps caches within a memory budget they share. Once the
+// entries of all the caches on a Budget add up to more than its limit, the
+// least recently used entries are evicted, whichever cache holds them, so a
+// busy cache can use the room an idle one is not.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultLimit is the budget, in bytes, used when none is set.
+const DefaultLimit = 64 << 20
+
+// Budget is the memory shared by a set of caches.
+type Budget struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+	order *list.List // of *entry, most recently used first
+}
+
+// entry is one cached value, charged to the budget at size bytes.
+type entry struct {
+	size  int64
+	key   any
+	value any
+	// remove deletes the entry from the map of the cache holding it.
+	remove func(key any)
+}
+
+// Usage is how much of a budget is in use.
+type Usage struct {
+	Used    int64 `json:"used"`
+	Limit   int64 `json:"limit"`
+	Entries int   `json:"entries"`
+}
+
+// NewBudget returns a budget of limit bytes; zero or less uses DefaultLimit.
+func NewBudget(limit int64) *Budget {
+	b := &Budget{order: list.New()}
+	b.SetLimit(limit)
+	return b
+}
+
+// SetLimit changes the budget to limit bytes, evicting entries at once if
+// they no longer fit. Zero or less restores DefaultLimit.
+func (b *Budget) SetLimit(limit int64) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	b.mu.Lock()
+	b.limit = limit
+	b.evict()
+	b.mu.Unlock()
+}
+
+// Usage returns how much of the budget the caches on it use.
+func (b *Budget) Usage() Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Usage{Used: b.used, Limit: b.limit, Entries: b.order.Len()}
+}
+
+// evict drops least recently used entries until the rest fit the limit.
+// b.mu must be held.
+func (b *Budget) evict() {
+	for b.used > b.limit {
+		el := b.order.Back()
+		b.drop(el)
+	}
+}
+
+// drop removes el from the budget and from its cache. b.mu must be held.
+func (b *Budget) drop(el *list.Element) {
+	e := b.order.Remove(el).(*entry)
+	b.used -= e.size
+	e.remove(e.key)
+}
+
+// Cache maps keys to values whose memory is charged to a Budget.
+type Cache[K comparable, V any] struct {
+	budget *Budget
+	size   func(V) int64
+	// items is guarded by budget.mu, as evicting from any cache on the
+	// budget changes it.
+	items map[K]*list.Element
+}
+
+// New returns an empty cache on budget, charging each value size(value)
+// bytes.
+func New[K comparable, V any](budget *Budget, size func(V) int64) *Cache[K, V] {
+	return &Cache[K, V]{budget: budget, size: size, items: make(map[K]*list.Element)}
+}
+
+// Get returns the value cached for key and marks it recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.budget.mu.Lock()
+	defer c.budget.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.budget.order.MoveToFront(el)
+	return el.Value.(*entry).value.(V), true
+}
+
+// Put caches value for key, replacing what was there, and evicts the least
+// recently used entries of the budget's caches that no longer fit. A value
+// larger than the whole budget is not kept.
+func (c *Cache[K, V]) Put(key K, value V) {
+	b := c.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		b.drop(el)
+	}
+	size := c.size(value)
+	if size > b.limit {
+		return
+	}
+	c.items[key] = b.order.PushFront(&entry{size: size, key: key, value: value, remove: c.remove})
+	b.used += size
+	b.evict()
+}
+
+// remove deletes key from the cache's map. budget.mu must be held.
+func (c *Cache[K, V]) remove(key any) {
+	delete(c.items, key.(K))
+}
@@ -0,0 +1,71 @@
+package lru
+
+import "testing"
+
+func size(s string) int64 { return int64(len(s)) }
+
+func TestEvict(t *testing.T) {
+	b := NewBudget(10)
+	icons := New[string, string](b, size)
+	thumbs := New[string, string](b, size)
+	icons.Put("a", "aaaa")
+	thumbs.Put("b", "bbb")
+	icons.Put("c", "cc")
+	// "a" is read, so "b" is now the least recently used.
+	if v, ok := icons.Get("a"); !ok || v != "aaaa" {
+		t.Fatalf(`Get("a") = %q, %v`, v, ok)
+	}
+	thumbs.Put("d", "dddd")
+
+	if _, ok := thumbs.Get("b"); ok {
+		t.Error(`"b" survived, want it evicted as the oldest`)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := icons.Get(key); !ok {
+			t.Errorf("%q was evicted, want it kept", key)
+		}
+	}
+	if _, ok := thumbs.Get("d"); !ok {
+		t.Error(`"d" was evicted, want the newest entry kept`)
+	}
+	if got, want := b.Usage(), (Usage{Used: 10, Limit: 10, Entries: 3}); got != want {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestPut(t *testing.T) {
+	b := NewBudget(10)
+	c := New[string, string](b, size)
+	c.Put("a", "aaaa")
+	c.Put("a", "aaaaaa")
+	if got, want := b.Usage(), (Usage{Used: 6, Limit: 10, Entries: 1}); got != want {
+		t.Errorf("Usage after replacing = %+v, want %+v", got, want)
+	}
+	// What could never fit is not kept, and evicts nothing.
+	c.Put("big", "bbbbbbbbbbb")
+	if _, ok := c.Get("big"); ok {
+		t.Error("a value larger than the budget was kept")
+	}
+	if v, _ := c.Get("a"); v != "aaaaaa" {
+		t.Errorf(`Get("a") = %q, want the replaced value`, v)
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	b := NewBudget(10)
+	c := New[string, string](b, size)
+	c.Put("a", "aaa")
+	c.Put("b", "bbb")
+	c.Put("c", "ccc")
+	b.SetLimit(5)
+	if _, ok := c.Get("c"); !ok {
+		t.Error(`"c" was evicted, want the newest entry kept`)
+	}
+	if got, want := b.Usage(), (Usage{Used: 3, Limit: 5, Entries: 1}); got != want {
+		t.Errorf("Usage after lowering the limit = %+v, want %+v", got, want)
+	}
+	b.SetLimit(0)
+	if got := b.Usage().Limit; got != DefaultLimit {
+		t.Errorf("Limit after SetLimit(0) = %d, want DefaultLimit", got)
+	}
+}
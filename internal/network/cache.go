@@ -0,0 +1,96 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+// maxEntries bounds the cache; the oldest response is dropped first.
+const maxEntries = 100
+
+type entry struct {
+	Body    []byte    `json:"body"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// Cache keeps the last successful response for each request key and
+// persists them to a JSON file, so they survive a restart while offline.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// OpenCache loads the cache at path. A missing file yields an empty cache,
+// as does a corrupt one, which is quarantined and reported in the error.
+func OpenCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]entry)
+		return c, fmt.Errorf("network: %w", quarantine.Move(path, err))
+	}
+	return c, nil
+}
+
+// Get returns the cached body for key and when it was fetched.
+func (c *Cache) Get(key string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e.Body, e.Fetched, ok
+}
+
+// Put stores body for key and saves the cache. Save errors are logged; the
+// cache still serves the entry until Prism quits.
+func (c *Cache) Put(key string, body []byte, fetched time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{Body: body, Fetched: fetched}
+	for len(c.entries) > maxEntries {
+		oldest := ""
+		for k, e := range c.entries {
+			if oldest == "" || e.Fetched.Before(c.entries[oldest].Fetched) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	if err := c.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+func (c *Cache) save() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
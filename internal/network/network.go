@@ -0,0 +1,183 @@
+// Package network is the HTTP client shared by providers that fetch data,
+// such as currency rates. It bounds every request with a timeout, stops
+// trying for a while once the network is found to be down, and keeps the
+// last successful response for each request so providers stay useful
+// offline.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a request when no timeout is configured.
+	DefaultTimeout = 3 * time.Second
+	// recheckInterval is how long the network is assumed to be down after
+	// a request fails to connect.
+	recheckInterval = 30 * time.Second
+	// probeTimeout bounds the connectivity check once recheckInterval has
+	// passed.
+	probeTimeout = 500 * time.Millisecond
+	// maxBody bounds a response body.
+	maxBody = 4 << 20
+)
+
+// ErrOffline is returned when the network is unreachable and no cached
+// response exists.
+var ErrOffline = errors.New("network: offline")
+
+// ErrPaused is returned while requests are paused and no cached response
+// exists.
+var ErrPaused = errors.New("network: paused")
+
+// Response is the body of a successful request, fresh or from the cache.
+type Response struct {
+	Body    []byte
+	Fetched time.Time
+	// Stale is set when the network was unreachable and Body is the last
+	// response cached for the request.
+	Stale bool
+}
+
+// Client makes requests for providers and caches their responses.
+type Client struct {
+	cache *Cache
+
+	mu           sync.Mutex
+	http         *http.Client
+	offlineUntil time.Time
+	failedHost   string
+	paused       bool
+}
+
+// New returns a client that caches responses in cache.
+func New(cache *Cache) *Client {
+	return &Client{cache: cache, http: &http.Client{Timeout: DefaultTimeout}}
+}
+
+// SetTimeout bounds each request. Zero or less restores DefaultTimeout.
+func (c *Client) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTimeout
+	}
+	c.mu.Lock()
+	c.http = &http.Client{Timeout: d}
+	c.mu.Unlock()
+}
+
+// SetPaused stops or resumes requests. While paused, Get answers from the
+// cache only, as it does offline.
+func (c *Client) SetPaused(paused bool) {
+	c.mu.Lock()
+	c.paused = paused
+	c.mu.Unlock()
+}
+
+// Online reports whether requests are worth attempting. After a request
+// fails to connect the answer is no, without any I/O, for a short while;
+// then a quick connection to the host that failed decides.
+func (c *Client) Online(ctx context.Context) bool {
+	c.mu.Lock()
+	until, host := c.offlineUntil, c.failedHost
+	c.mu.Unlock()
+	if host == "" {
+		return true
+	}
+	if time.Now().Before(until) {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(host, "443"))
+	if err != nil {
+		c.markOffline(host)
+		return false
+	}
+	conn.Close()
+	c.markOnline()
+	return true
+}
+
+// Get fetches rawURL, caching the body under key. When the network is
+// unreachable, or requests are paused, it returns the cached body marked
+// stale, or ErrOffline or ErrPaused if there is none. HTTP errors are returned as they are, without falling
+// back to the cache.
+func (c *Client) Get(ctx context.Context, key, rawURL string) (Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Response{}, err
+	}
+	c.mu.Lock()
+	paused := c.paused
+	c.mu.Unlock()
+	if paused {
+		return c.cached(key, ErrPaused)
+	}
+	if !c.Online(ctx) {
+		return c.cached(key, ErrOffline)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Response{}, err
+	}
+	c.mu.Lock()
+	client := c.http
+	c.mu.Unlock()
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Response{}, ctx.Err()
+		}
+		c.markOffline(u.Hostname())
+		return c.cached(key, fmt.Errorf("%w: %v", ErrOffline, err))
+	}
+	defer resp.Body.Close()
+	c.markOnline()
+	if resp.StatusCode != http.StatusOK {
+		return Response{}, fmt.Errorf("network: %s: %s", u.Hostname(), resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return Response{}, err
+	}
+	now := time.Now()
+	c.cache.Put(key, body, now)
+	return Response{Body: body, Fetched: now}, nil
+}
+
+// Cached returns the response last cached for key without making a
+// request.
+func (c *Client) Cached(key string) (Response, bool) {
+	body, fetched, ok := c.cache.Get(key)
+	return Response{Body: body, Fetched: fetched}, ok
+}
+
+// cached returns the cached response for key marked stale, or err when
+// nothing is cached.
+func (c *Client) cached(key string, err error) (Response, error) {
+	body, fetched, ok := c.cache.Get(key)
+	if !ok {
+		return Response{}, err
+	}
+	return Response{Body: body, Fetched: fetched, Stale: true}, nil
+}
+
+func (c *Client) markOffline(host string) {
+	c.mu.Lock()
+	c.offlineUntil, c.failedHost = time.Now().Add(recheckInterval), host
+	c.mu.Unlock()
+}
+
+func (c *Client) markOnline() {
+	c.mu.Lock()
+	c.offlineUntil, c.failedHost = time.Time{}, ""
+	c.mu.Unlock()
+}
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	cache, err := OpenCache(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(cache)
+}
+
+func TestGetTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	c.SetTimeout(50 * time.Millisecond)
+	start := time.Now()
+	_, err := c.Get(context.Background(), "slow", srv.URL)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Get took %v with a 50ms timeout", d)
+	}
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Get = %v, want ErrOffline", err)
+	}
+	// The host is now taken to be down, so the next request answers at
+	// once.
+	start = time.Now()
+	if _, err := c.Get(context.Background(), "slow", srv.URL); !errors.Is(err, ErrOffline) {
+		t.Errorf("second Get = %v, want ErrOffline", err)
+	}
+	if d := time.Since(start); d > 10*time.Millisecond {
+		t.Errorf("second Get took %v", d)
+	}
+}
+
+func TestGetOfflineCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"usd": 1}`))
+	}))
+	c := newTestClient(t)
+	fresh, err := c.Get(context.Background(), "rates", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fresh.Body) != `{"usd": 1}` || fresh.Stale {
+		t.Fatalf("Get = %+v, want the fresh body", fresh)
+	}
+
+	srv.Close()
+	stale, err := c.Get(context.Background(), "rates", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stale.Body) != `{"usd": 1}` || !stale.Stale || !stale.Fetched.Equal(fresh.Fetched) {
+		t.Errorf("offline Get = %+v, want the cached body, stale, fetched %v", stale, fresh.Fetched)
+	}
+	if _, err := c.Get(context.Background(), "other", srv.URL); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline Get of an uncached key = %v, want ErrOffline", err)
+	}
+
+	// The cache survives a restart.
+	reopened, err := OpenCache(c.cache.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _, ok := reopened.Get("rates"); !ok || string(body) != `{"usd": 1}` {
+		t.Errorf("reopened cache = %q, %v", body, ok)
+	}
+}
+
+func TestGetPaused(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	c := newTestClient(t)
+	c.SetPaused(true)
+	if _, err := c.Get(context.Background(), "k", srv.URL); !errors.Is(err, ErrPaused) {
+		t.Errorf("paused Get = %v, want ErrPaused", err)
+	}
+	c.SetPaused(false)
+	if _, err := c.Get(context.Background(), "k", srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	c.SetPaused(true)
+	if r, err := c.Get(context.Background(), "k", srv.URL); err != nil || !r.Stale {
+		t.Errorf("paused Get = %+v, %v; want the cached body", r, err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+}
+
+func TestGetHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := newTestClient(t)
+	c.cache.Put("k", []byte("old"), time.Now())
+	_, err := c.Get(context.Background(), "k", srv.URL)
+	if err == nil || errors.Is(err, ErrOffline) {
+		t.Errorf("Get = %v, want the HTTP error", err)
+	}
+	if !c.Online(context.Background()) {
+		t.Error("an HTTP error marked the network offline")
+	}
+}
+
+func TestOpenCacheCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := OpenCache(path)
+	if err == nil {
+		t.Error("OpenCache of a corrupt file succeeded")
+	}
+	if _, _, ok := c.Get("k"); ok {
+		t.Error("corrupt cache has entries")
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("corrupt cache was not moved aside")
+	}
+}
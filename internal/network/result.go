@@ -0,0 +1,25 @@
+package network
+
+import (
+	"time"
+
+	"changeme/internal/search"
+)
+
+// OfflineResult is what a network provider shows instead of hanging when
+// the network is down and it has nothing cached. It has no actions.
+func OfflineResult(providerID string) search.Result {
+	return search.Result{
+		ID:       providerID + ":offline",
+		Provider: providerID,
+		Type:     "notice",
+		Title:    "You're Offline",
+		Subtitle: "Connect to the internet to get results here.",
+	}
+}
+
+// StaleNote marks a subtitle built from a cached response, for example
+// "(offline, as of Jan 2 15:04)".
+func StaleNote(fetched time.Time) string {
+	return "(offline, as of " + fetched.Format("Jan 2 15:04") + ")"
+}
@@ -0,0 +1,78 @@
+// Package osascript runs AppleScript through the osascript binary.
+package osascript
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"changeme/internal/search"
+)
+
+// errNotAuthorized is the AppleScript error number returned when the user has
+// not granted Automation access to the scripted application.
+const errNotAuthorized = "-1743"
+
+// errNoAccessibility is the System Events error number returned when Prism
+// may not send keystrokes or inspect UI elements because it lacks
+// Accessibility access.
+const errNoAccessibility = "1002"
+
+// errNoAssistiveAccess is returned instead of errNoAccessibility when a
+// script reads UI elements, such as windows, without Accessibility access.
+const errNoAssistiveAccess = "-1719"
+
+// Run executes script and returns its trimmed standard output. When the
+// script is denied Automation access to target, or Accessibility access, the
+// error is a *search.PermissionError.
+func Run(ctx context.Context, target, script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, errNotAuthorized) {
+			return "", &search.PermissionError{Permission: "Automation", Target: target, Err: err}
+		}
+		if strings.Contains(msg, "("+errNoAccessibility+")") || strings.Contains(msg, "("+errNoAssistiveAccess+")") {
+			return "", &search.PermissionError{Permission: "Accessibility", Err: err}
+		}
+		if msg != "" {
+			return "", fmt.Errorf("osascript: %s: %w", msg, err)
+		}
+		return "", fmt.Errorf("osascript: %w", err)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// ISODate is the format of AppleScript's «class isot» dates, which are in
+// local time.
+const ISODate = "2006-01-02T15:04:05"
+
+// Quote returns s as an AppleScript string literal.
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// FindProcess returns System Events script lines setting p to the process
+// of the app at path, or the one called name. Processes are matched by
+// bundle path first, since an app's process name can differ from its
+// bundle name, as "Code" does for Visual Studio Code.
+func FindProcess(path, name string) string {
+	return `	set p to missing value
+	repeat with candidate in (every application process whose background only is false)
+		try
+			set candidatePath to POSIX path of (application file of candidate)
+			if candidatePath is ` + Quote(path) + ` or candidatePath is ` + Quote(path+"/") + ` then
+				set p to contents of candidate
+				exit repeat
+			end if
+		end try
+	end repeat
+	if p is missing value then set p to first application process whose name is ` + Quote(name)
+}
@@ -0,0 +1,89 @@
+// Package paste delivers text chosen in the launcher, either by copying it to
+// the clipboard or by pasting it into the app that was frontmost before the
+// launcher opened.
+package paste
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+)
+
+// settle is how long the previous app is given to take focus before paste
+// is pressed.
+const settle = 150 * time.Millisecond
+
+// Clipboard is the system clipboard.
+type Clipboard interface {
+	SetText(text string) bool
+}
+
+// Target is the part of the platform used to paste.
+type Target interface {
+	Activate(ctx context.Context, app platform.App) error
+	Paste(ctx context.Context) error
+}
+
+// Output implements both delivery modes. All fields are required.
+type Output struct {
+	Clipboard Clipboard
+	Target    Target
+	// Frontmost returns the app captured when the launcher was shown.
+	Frontmost func() platform.App
+	// Notify tells the user that a paste fell back to copying.
+	Notify func(title, body string) error
+	// Mode returns the configured config.ClipboardAction* value.
+	Mode func() string
+	// PlainApps returns the apps that are pasted into as plain text by
+	// default.
+	PlainApps func() []string
+}
+
+var errClipboard = errors.New("paste: could not set clipboard text")
+
+// DefaultMode returns the configured mode, defaulting to copy. Paste mode
+// becomes plain paste when the frontmost app is one of the PlainApps.
+func (o *Output) DefaultMode() string {
+	switch o.Mode() {
+	case config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		app := o.Frontmost()
+		for _, name := range o.PlainApps() {
+			if name != "" && (name == app.Name || name == app.ID) {
+				return config.ClipboardActionPastePlain
+			}
+		}
+		return config.ClipboardActionPaste
+	}
+	return config.ClipboardActionCopy
+}
+
+// Deliver puts text on the clipboard and, in either paste mode, pastes it
+// into the previously frontmost app. Formatting is removed by the caller for
+// plain paste. When pasting is not possible, typically because
+// Accessibility access was not granted, the text stays on the clipboard and
+// the user is told so.
+func (o *Output) Deliver(ctx context.Context, text, mode string) error {
+	if !o.Clipboard.SetText(text) {
+		return errClipboard
+	}
+	if mode != config.ClipboardActionPaste && mode != config.ClipboardActionPastePlain {
+		return nil
+	}
+	if app := o.Frontmost(); app.Path != "" || app.ID != "" {
+		if err := o.Target.Activate(ctx, app); err != nil {
+			log.Println("paste:", err)
+		}
+		time.Sleep(settle)
+	}
+	if err := o.Target.Paste(ctx); err != nil {
+		log.Println("paste:", err)
+		if err := o.Notify("Copied to Clipboard", "Prism could not paste; grant Accessibility access to paste automatically."); err != nil {
+			log.Println("paste:", err)
+		}
+	}
+	return nil
+}
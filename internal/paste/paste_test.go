@@ -0,0 +1,123 @@
+package paste
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+)
+
+type clipboard struct{ text string }
+
+func (c *clipboard) SetText(text string) bool {
+	c.text = text
+	return true
+}
+
+// target pastes successfully unless err is set, as without Accessibility
+// access.
+type target struct {
+	err       error
+	activated []string
+	pasted    int
+}
+
+func (t *target) Activate(ctx context.Context, app platform.App) error {
+	t.activated = append(t.activated, app.Name)
+	return nil
+}
+
+func (t *target) Paste(ctx context.Context) error {
+	t.pasted++
+	return t.err
+}
+
+func TestDeliver(t *testing.T) {
+	denied := errors.New("not allowed to send keystrokes")
+	tests := []struct {
+		name     string
+		mode     string
+		pasteErr error
+		pasted   bool
+		notified bool
+	}{
+		{"copy", config.ClipboardActionCopy, nil, false, false},
+		{"copy without permission", config.ClipboardActionCopy, denied, false, false},
+		{"paste", config.ClipboardActionPaste, nil, true, false},
+		{"plain paste", config.ClipboardActionPastePlain, nil, true, false},
+		{"paste without permission", config.ClipboardActionPaste, denied, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip, tgt := &clipboard{}, &target{err: tt.pasteErr}
+			var notes []string
+			o := &Output{
+				Clipboard: clip,
+				Target:    tgt,
+				Frontmost: func() platform.App { return platform.App{} },
+				Notify: func(title, body string) error {
+					notes = append(notes, title)
+					return nil
+				},
+				Mode:      func() string { return tt.mode },
+				PlainApps: func() []string { return nil },
+			}
+			if err := o.Deliver(context.Background(), "hello", o.DefaultMode()); err != nil {
+				t.Fatal(err)
+			}
+			if clip.text != "hello" {
+				t.Errorf("clipboard = %q, want the text copied in every mode", clip.text)
+			}
+			if got := tgt.pasted > 0; got != tt.pasted {
+				t.Errorf("pasted = %v, want %v", got, tt.pasted)
+			}
+			if got := len(notes) > 0; got != tt.notified {
+				t.Errorf("notified = %v (%v), want %v", got, notes, tt.notified)
+			}
+		})
+	}
+}
+
+func TestDefaultMode(t *testing.T) {
+	tests := []struct {
+		mode      string
+		frontmost platform.App
+		want      string
+	}{
+		{config.ClipboardActionCopy, platform.App{Name: "Terminal"}, config.ClipboardActionCopy},
+		{"", platform.App{}, config.ClipboardActionCopy},
+		{config.ClipboardActionPaste, platform.App{Name: "Mail"}, config.ClipboardActionPaste},
+		{config.ClipboardActionPaste, platform.App{Name: "Terminal"}, config.ClipboardActionPastePlain},
+		{config.ClipboardActionPaste, platform.App{ID: "com.apple.Terminal"}, config.ClipboardActionPastePlain},
+	}
+	for _, tt := range tests {
+		o := &Output{
+			Frontmost: func() platform.App { return tt.frontmost },
+			Mode:      func() string { return tt.mode },
+			PlainApps: func() []string { return []string{"Terminal", "com.apple.Terminal", ""} },
+		}
+		if got := o.DefaultMode(); got != tt.want {
+			t.Errorf("DefaultMode(%q, %+v) = %q, want %q", tt.mode, tt.frontmost, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverActivatesFrontmost(t *testing.T) {
+	tgt := &target{}
+	o := &Output{
+		Clipboard: &clipboard{},
+		Target:    tgt,
+		Frontmost: func() platform.App { return platform.App{Name: "Mail", Path: "/Applications/Mail.app"} },
+		Notify:    func(title, body string) error { return nil },
+		Mode:      func() string { return config.ClipboardActionPaste },
+		PlainApps: func() []string { return nil },
+	}
+	if err := o.Deliver(context.Background(), "hello", config.ClipboardActionPaste); err != nil {
+		t.Fatal(err)
+	}
+	if len(tgt.activated) != 1 || tgt.activated[0] != "Mail" || tgt.pasted != 1 {
+		t.Errorf("activated %v and pasted %d times, want Mail then one paste", tgt.activated, tgt.pasted)
+	}
+}
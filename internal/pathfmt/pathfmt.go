@@ -0,0 +1,68 @@
+// Package pathfmt shortens file paths for display.
+package pathfmt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SubtitleLen is the longest path shown as a result subtitle, in runes,
+// which fits the launcher's default width.
+const SubtitleLen = 60
+
+const ellipsis = "…"
+
+// Truncate shortens path to at most max runes by replacing folders in the
+// middle with "…", keeping its root and as many leading folders, and its
+// file name and as many trailing folders, as fit, as in
+// "/Users/me/…/project/file.go". Folders nearest the file are kept first.
+// Paths that already fit are returned unchanged. When not even the root
+// and file name fit, the end of the path is kept after a leading "…".
+func Truncate(path string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(path) <= max {
+		return path
+	}
+	sep := "/"
+	if !strings.Contains(path, "/") && strings.Contains(path, `\`) {
+		sep = `\`
+	}
+	parts := strings.Split(path, sep)
+	if len(parts) < 3 {
+		return truncateStart(path, max)
+	}
+	// A Unix root shows as an empty first part, a Windows one as "C:".
+	head, tail := parts[:1], parts[len(parts)-1:]
+	width := func(head, tail []string) int {
+		return utf8.RuneCountInString(strings.Join(head, sep)) + utf8.RuneCountInString(sep+ellipsis+sep) + utf8.RuneCountInString(strings.Join(tail, sep))
+	}
+	if width(head, tail) > max {
+		return truncateStart(path, max)
+	}
+	for i, j := 1, len(parts)-2; i <= j; {
+		grown := false
+		if next := parts[j:]; width(head, next) <= max {
+			tail, j, grown = next, j-1, true
+		}
+		if i <= j {
+			if next := parts[:i+1]; width(next, tail) <= max {
+				head, i, grown = next, i+1, true
+			}
+		}
+		if !grown {
+			break
+		}
+	}
+	if len(head)+len(tail) == len(parts) {
+		return path
+	}
+	return strings.Join(head, sep) + sep + ellipsis + sep + strings.Join(tail, sep)
+}
+
+// truncateStart keeps the last max-1 runes of s after "…".
+func truncateStart(s string, max int) string {
+	runes := []rune(s)
+	if max <= 1 {
+		return ellipsis
+	}
+	return ellipsis + string(runes[len(runes)-(max-1):])
+}
@@ -0,0 +1,42 @@
+package pathfmt
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		path string
+		max  int
+		want string
+	}{
+		{"/Users/me/project/file.go", 60, "/Users/me/project/file.go"},
+		{"/Users/me/project/file.go", 25, "/Users/me/project/file.go"},
+		{"/Users/me/project/file.go", 0, "/Users/me/project/file.go"},
+		{"/Users/me/Documents/work/project/file.go", 30, "/Users/…/work/project/file.go"},
+		{"/Users/me/Documents/work/project/file.go", 20, "/…/project/file.go"},
+		{"/Users/me/Documents/work/project/file.go", 10, "/…/file.go"},
+		// Not even the root and file name fit.
+		{"/Users/me/Documents/work/project/file.go", 8, "…file.go"},
+		{`C:\Users\me\Documents\work\file.go`, 24, `C:\Users\…\work\file.go`},
+		// Runes are counted, not bytes.
+		{"/home/josé/文档/项目/报告.txt", 23, "/home/josé/文档/项目/报告.txt"},
+		{"/home/josé/文档/项目/报告.txt", 22, "/home/…/文档/项目/报告.txt"},
+		{"/home/josé/文档/项目/报告.txt", 16, "/…/文档/项目/报告.txt"},
+		{"/home/josé/文档/项目/报告.txt", 9, "/…/报告.txt"},
+		{"/home/josé/文档/项目/报告.txt", 4, "…txt"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.path, tt.max)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.path, tt.max, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); tt.max > 0 && n > tt.max {
+			t.Errorf("Truncate(%q, %d) is %d runes long", tt.path, tt.max, n)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d) = %q split a rune", tt.path, tt.max, got)
+		}
+	}
+}
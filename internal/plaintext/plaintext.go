@@ -0,0 +1,212 @@
+// Package plaintext strips formatting from HTML and RTF clipboard contents.
+package plaintext
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Strip returns the plain text of s when it is an HTML fragment or an RTF
+// document, and reports whether it was either.
+func Strip(s string) (string, bool) {
+	t := strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(t, `{\rtf`):
+		return FromRTF(t), true
+	case looksLikeHTML(t):
+		return FromHTML(t), true
+	}
+	return s, false
+}
+
+var (
+	htmlTag       = regexp.MustCompile(`(?s)<[a-zA-Z!/][^>]*>`)
+	htmlHidden    = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)\s*>`)
+	htmlComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreak     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote|pre)\s*>`)
+	htmlCell      = regexp.MustCompile(`(?i)</t[dh]\s*>`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+	trailingBlank = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// looksLikeHTML reports whether s starts with a tag and contains a closing
+// tag, which plain text rarely does.
+func looksLikeHTML(s string) bool {
+	return strings.HasPrefix(s, "<") && htmlTag.MatchString(s) && strings.Contains(s, "</")
+}
+
+// FromHTML drops tags, scripts, styles and comments, turns block ends and
+// line breaks into newlines and decodes entities.
+func FromHTML(s string) string {
+	s = htmlComment.ReplaceAllString(s, "")
+	s = htmlHidden.ReplaceAllString(s, "")
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlCell.ReplaceAllString(s, "\t")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, " ", " ")
+	return tidy(s)
+}
+
+// rtfDestinations are groups holding document metadata rather than text.
+var rtfDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "header": true, "footer": true, "listtable": true,
+	"listoverridetable": true, "expandedcolortbl": true,
+}
+
+// FromRTF keeps the text of an RTF document. It handles paragraphs, tabs,
+// escaped characters, \'hh bytes (read as Windows-1252's Latin-1 subset)
+// and \u Unicode escapes, and skips metadata groups.
+func FromRTF(s string) string {
+	var (
+		b     strings.Builder
+		depth int
+		skip  = -1 // depth at which a skipped group started
+		uc    = 1  // fallback characters following a \u escape
+		drop  int  // fallback characters still to drop
+	)
+	emit := func(r rune) {
+		if skip >= 0 {
+			return
+		}
+		if drop > 0 {
+			drop--
+			return
+		}
+		b.WriteRune(r)
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '{':
+			depth++
+			i++
+			continue
+		case '}':
+			if depth == skip {
+				skip = -1
+			}
+			depth--
+			i++
+			continue
+		case '\r', '\n':
+			i++
+			continue
+		case '\\':
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			emit(r)
+			i += size
+			continue
+		}
+
+		// A control symbol or word.
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '\\', '{', '}':
+			emit(rune(s[i]))
+			i++
+			continue
+		case '\'':
+			if i+3 <= len(s) {
+				if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+					emit(rune(v))
+				}
+			}
+			i += 3
+			continue
+		case '*':
+			if skip < 0 {
+				skip = depth
+			}
+			i++
+			continue
+		case '~':
+			emit(' ')
+			i++
+			continue
+		case '\n', '\r':
+			emit('\n')
+			i++
+			continue
+		}
+		start := i
+		for i < len(s) && isLetter(s[i]) {
+			i++
+		}
+		word := s[start:i]
+		numStart := i
+		if i < len(s) && (s[i] == '-' || isDigit(s[i])) {
+			i++
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+		num, hasNum := 0, i > numStart
+		if hasNum {
+			num, _ = strconv.Atoi(s[numStart:i])
+		}
+		if i < len(s) && s[i] == ' ' {
+			i++
+		}
+		switch {
+		case word == "":
+			i++
+		case rtfDestinations[word]:
+			if skip < 0 {
+				skip = depth
+			}
+		case word == "par" || word == "line" || word == "sect" || word == "page":
+			emit('\n')
+		case word == "tab" || word == "cell":
+			emit('\t')
+		case word == "row":
+			emit('\n')
+		case word == "uc" && hasNum:
+			uc = num
+		case word == "u" && hasNum:
+			if num < 0 {
+				num += 65536
+			}
+			emit(rune(num))
+			if skip < 0 {
+				drop = uc
+			}
+		case word == "emdash":
+			emit('—')
+		case word == "endash":
+			emit('–')
+		case word == "bullet":
+			emit('•')
+		case word == "lquote":
+			emit('‘')
+		case word == "rquote":
+			emit('’')
+		case word == "ldblquote":
+			emit('“')
+		case word == "rdblquote":
+			emit('”')
+		}
+	}
+	return tidy(b.String())
+}
+
+// tidy trims trailing spaces on each line, collapses runs of blank lines and
+// trims the ends.
+func tidy(s string) string {
+	s = trailingBlank.ReplaceAllString(s, "\n")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
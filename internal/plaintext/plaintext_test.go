@@ -0,0 +1,51 @@
+package plaintext
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+		formatted      bool
+	}{
+		{
+			name:      "rtf",
+			in:        `{\rtf1\ansi\deff0{\fonttbl{\f0 Helvetica;}}{\colortbl;\red255\green0\blue0;}\f0\fs24 Hello \b bold\b0  world\par Caf\'e9 \u8364? 5\tab x\par}`,
+			want:      "Hello bold world\nCafé € 5\tx",
+			formatted: true,
+		},
+		{
+			name:      "rtf escapes and skipped groups",
+			in:        `{\rtf1{\*\generator Word;}{\info{\title Secret}}Braces \{ok\} and \\ slash\ldblquote q\rdblquote}`,
+			want:      `Braces {ok} and \ slash“q”`,
+			formatted: true,
+		},
+		{
+			name:      "html",
+			in:        "<html><head><title>t</title><style>p{}</style></head><body><!-- c --><p>Fish &amp; chips</p>\n<p>line<br>break</p><script>alert(1)</script></body></html>",
+			want:      "Fish & chips\n line\nbreak",
+			formatted: true,
+		},
+		{
+			name:      "html table",
+			in:        "<table><tr><td>a</td><td>b</td></tr><tr><td>c</td><td>d</td></tr></table>",
+			want:      "a\tb\nc\td",
+			formatted: true,
+		},
+		{
+			name: "plain text",
+			in:   "1 < 2 and 3 > 2",
+			want: "1 < 2 and 3 > 2",
+		},
+		{
+			name: "a lone tag is not html",
+			in:   "<b> is bold",
+			want: "<b> is bold",
+		},
+	}
+	for _, tt := range tests {
+		got, formatted := Strip(tt.in)
+		if got != tt.want || formatted != tt.formatted {
+			t.Errorf("%s: Strip = %q, %v; want %q, %v", tt.name, got, formatted, tt.want, tt.formatted)
+		}
+	}
+}
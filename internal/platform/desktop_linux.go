@@ -0,0 +1,148 @@
+package platform
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// applicationDirs returns the XDG application directories in precedence
+// order: entries in earlier directories shadow those with the same desktop
+// file ID in later ones.
+func applicationDirs() []string {
+	var dirs []string
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "applications"))
+	}
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, d := range filepath.SplitList(dataDirs) {
+		dirs = append(dirs, filepath.Join(d, "applications"))
+	}
+	return dirs
+}
+
+func desktopApplications() []App {
+	var apps []App
+	seen := make(map[string]bool)
+	for _, root := range applicationDirs() {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".desktop") {
+				return nil
+			}
+			// The desktop file ID is the path below the applications
+			// directory with slashes replaced by dashes.
+			rel, _ := filepath.Rel(root, path)
+			id := strings.ReplaceAll(rel, string(filepath.Separator), "-")
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+			if app, ok := parseDesktopFile(path); ok {
+				app.ID = id
+				apps = append(apps, app)
+			}
+			return nil
+		})
+	}
+	return apps
+}
+
+// parseDesktopFile reads the [Desktop Entry] group of a desktop file and
+// reports whether it describes a launchable, visible application.
+func parseDesktopFile(path string) (App, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return App{}, false
+	}
+	defer f.Close()
+
+	entry := make(map[string]string)
+	inEntry := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			entry[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	if entry["Type"] != "Application" || entry["NoDisplay"] == "true" || entry["Hidden"] == "true" {
+		return App{}, false
+	}
+	if entry["Name"] == "" || entry["Exec"] == "" {
+		return App{}, false
+	}
+	return App{
+		Name:     entry["Name"],
+		Path:     path,
+		Exec:     entry["Exec"],
+		Terminal: entry["Terminal"] == "true",
+	}, true
+}
+
+// splitExec splits a desktop entry Exec value into arguments, honouring
+// double quotes and dropping field codes such as %f and %U, which Prism never
+// has values for.
+func splitExec(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inQuote bool
+		hasArg  bool
+	)
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inQuote && r == '\\' && i+1 < len(runes):
+			i++
+			cur.WriteRune(runes[i])
+		case r == '"':
+			inQuote = !inQuote
+			hasArg = true
+		case !inQuote && (r == ' ' || r == '\t'):
+			if hasArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				hasArg = false
+			}
+		case r == '%' && i+1 < len(runes):
+			i++
+			if runes[i] == '%' {
+				cur.WriteRune('%')
+				hasArg = true
+			}
+		default:
+			cur.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quote in Exec")
+	}
+	if hasArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,29 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// loginEnvironment runs the user's shell as a login shell to print its
+// environment, NUL-separated so values may span lines.
+func loginEnvironment(ctx context.Context) ([]string, error) {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	out, err := exec.CommandContext(ctx, shell, "-l", "-c", "env -0").Output()
+	if err != nil {
+		return nil, fmt.Errorf("platform: reading login environment: %w", err)
+	}
+	var env []string
+	for _, kv := range strings.Split(string(out), "\x00") {
+		if strings.Contains(kv, "=") {
+			env = append(env, kv)
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,45 @@
+//go:build cgo
+
+package platform
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#include <stdlib.h>
+#import <AppKit/AppKit.h>
+
+static void prismPlaySound(const char *name) {
+	@autoreleasepool {
+		NSString *soundName = [NSString stringWithUTF8String:name];
+		dispatch_async(dispatch_get_main_queue(), ^{
+			[[NSSound soundNamed:soundName] play];
+		});
+	}
+}
+
+static void prismHaptic(void) {
+	dispatch_async(dispatch_get_main_queue(), ^{
+		[[NSHapticFeedbackManager defaultPerformer]
+			performFeedbackPattern:NSHapticFeedbackPatternGeneric
+			performanceTime:NSHapticFeedbackPerformanceTimeNow];
+	});
+}
+*/
+import "C"
+
+import "unsafe"
+
+// PlaySound plays the sound with NSSound on the main thread.
+func (darwin) PlaySound(s Sound) error {
+	name := C.CString(darwinSounds[s])
+	defer C.free(unsafe.Pointer(name))
+	C.prismPlaySound(name)
+	return nil
+}
+
+// Haptic performs the generic haptic pattern. Hardware without haptics
+// ignores it.
+func (darwin) Haptic() error {
+	C.prismHaptic()
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build darwin && !cgo
+
+package platform
+
+import "os/exec"
+
+// PlaySound plays the sound file with afplay when Prism is built without
+// cgo and so cannot use NSSound.
+func (darwin) PlaySound(s Sound) error {
+	cmd := exec.Command("afplay", "/System/Library/Sounds/"+darwinSounds[s]+".aiff")
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func (darwin) Haptic() error {
+	return ErrUnsupported
+}
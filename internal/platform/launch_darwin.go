@@ -0,0 +1,75 @@
+//go:build cgo
+
+package platform
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#include <stdlib.h>
+#include <string.h>
+#import <AppKit/AppKit.h>
+
+// prismLaunchApp opens the app bundle at path with NSWorkspace, waiting up
+// to timeout seconds for it to launch. It returns NULL on success or an
+// error message for the caller to free.
+static char *prismLaunchApp(const char *path, double timeout) {
+	@autoreleasepool {
+		NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+		if (@available(macOS 10.15, *)) {
+			NSWorkspaceOpenConfiguration *config = [NSWorkspaceOpenConfiguration configuration];
+			config.activates = YES;
+			dispatch_semaphore_t done = dispatch_semaphore_create(0);
+			__block char *message = NULL;
+			[[NSWorkspace sharedWorkspace] openApplicationAtURL:url
+				configuration:config
+				completionHandler:^(NSRunningApplication *app, NSError *error) {
+					if (error != nil) {
+						message = strdup(error.localizedDescription.UTF8String);
+					}
+					dispatch_semaphore_signal(done);
+				}];
+			if (dispatch_semaphore_wait(done, dispatch_time(DISPATCH_TIME_NOW, (int64_t)(timeout * NSEC_PER_SEC))) != 0) {
+				return strdup("timed out waiting for the app to launch");
+			}
+			return message;
+		}
+		NSError *error = nil;
+		if (![[NSWorkspace sharedWorkspace] launchApplicationAtURL:url options:NSWorkspaceLaunchDefault configuration:@{} error:&error]) {
+			return strdup(error.localizedDescription.UTF8String);
+		}
+		return NULL;
+	}
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// workspaceLaunchTimeout is how long workspaceLaunch waits for an app to
+// launch when ctx has no deadline.
+const workspaceLaunchTimeout = 5 * time.Second
+
+// workspaceLaunch launches the app bundle at path in process through
+// NSWorkspace, saving the fork and exec of open and its own round trip to
+// Launch Services.
+func workspaceLaunch(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timeout := workspaceLaunchTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if msg := C.prismLaunchApp(cpath, C.double(timeout.Seconds())); msg != nil {
+		defer C.free(unsafe.Pointer(msg))
+		return errors.New("platform: launching " + path + ": " + C.GoString(msg))
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build darwin && !cgo
+
+package platform
+
+import "context"
+
+// workspaceLaunch needs cgo for NSWorkspace; without it Launch uses open.
+func workspaceLaunch(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,76 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+// stubLaunchers replaces launchers for the rest of the test.
+func stubLaunchers(t testing.TB, l ...appLauncher) {
+	old := launchers
+	launchers = l
+	t.Cleanup(func() { launchers = old })
+}
+
+func TestLaunchFallsBack(t *testing.T) {
+	var tried []string
+	launcher := func(name string, err error) appLauncher {
+		return func(ctx context.Context, path string) error {
+			tried = append(tried, name)
+			return err
+		}
+	}
+	broken := errors.New("broken")
+	tests := []struct {
+		launchers []appLauncher
+		tried     []string
+		err       error
+	}{
+		{[]appLauncher{launcher("workspace", nil), launcher("open", nil)}, []string{"workspace"}, nil},
+		{[]appLauncher{launcher("workspace", broken), launcher("open", nil)}, []string{"workspace", "open"}, nil},
+		{[]appLauncher{launcher("workspace", ErrUnsupported), launcher("open", nil)}, []string{"workspace", "open"}, nil},
+		{[]appLauncher{launcher("workspace", ErrUnsupported), launcher("open", broken)}, []string{"workspace", "open"}, broken},
+		{[]appLauncher{launcher("workspace", ErrUnsupported)}, []string{"workspace"}, ErrUnsupported},
+	}
+	for i, tt := range tests {
+		tried = nil
+		stubLaunchers(t, tt.launchers...)
+		err := darwin{}.Launch(context.Background(), App{Name: "Mail", Path: "/System/Applications/Mail.app"})
+		if !slices.Equal(tried, tt.tried) {
+			t.Errorf("case %d: tried %v, want %v", i, tried, tt.tried)
+		}
+		if (tt.err == nil) != (err == nil) || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("case %d: Launch = %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// BenchmarkLaunch compares launching an already running app through
+// NSWorkspace and through open. PRISM_BENCH_APP names the app bundle, such
+// as /System/Applications/Calculator.app; it is skipped without one.
+func BenchmarkLaunch(b *testing.B) {
+	path := os.Getenv("PRISM_BENCH_APP")
+	if path == "" {
+		b.Skip("PRISM_BENCH_APP is not set")
+	}
+	for _, bm := range []struct {
+		name   string
+		launch appLauncher
+	}{
+		{"workspace", workspaceLaunch},
+		{"open", openLaunch},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			stubLaunchers(b, bm.launch)
+			app := App{Path: path}
+			for range b.N {
+				if err := (darwin{}).Launch(context.Background(), app); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
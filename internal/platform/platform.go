@@ -0,0 +1,134 @@
+// Package platform hides the operating-system specific parts of Prism:
+// discovering and launching applications, opening files and URLs, tracking
+// the frontmost application and placing the launcher window.
+package platform
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math"
+)
+
+// ErrUnsupported is returned by operations the current platform or session
+// (for example a Wayland compositor) cannot perform.
+var ErrUnsupported = errors.New("platform: not supported")
+
+// App is an installed or running application.
+type App struct {
+	Name string `json:"name"`
+	// Path is the bundle, desktop entry or shortcut the app was found at.
+	Path string `json:"path"`
+	// ID is the bundle identifier, desktop file ID or window ID, when known.
+	ID string `json:"id,omitempty"`
+	// Exec is the command line from a Linux desktop entry.
+	Exec string `json:"-"`
+	// Terminal reports that the app must run inside a terminal emulator.
+	Terminal bool `json:"-"`
+}
+
+// Window is the part of the launcher window the platform positions.
+type Window interface {
+	Center()
+	Size() (width, height int)
+	SetSize(width, height int)
+	SetPosition(x, y int)
+	NativeWindowHandle() (uintptr, error)
+}
+
+// ScaleSize converts a size in logical pixels to device pixels for a display
+// with the given backing scale factor, such as 2 for Retina or 1.5 for a
+// Windows display at 150%. Non-positive scales count as 1.
+func ScaleSize(width, height int, scale float64) (int, int) {
+	if scale <= 0 {
+		scale = 1
+	}
+	return int(math.Round(float64(width) * scale)), int(math.Round(float64(height) * scale))
+}
+
+// Sound is a feedback sound played by PlaySound.
+type Sound int
+
+const (
+	// SoundActivate confirms that a result was activated.
+	SoundActivate Sound = iota
+	// SoundNoResults signals that a query matched nothing.
+	SoundNoResults
+)
+
+// Platform is implemented once per operating system.
+type Platform interface {
+	// Applications lists the installed applications.
+	Applications() ([]App, error)
+	// ApplicationDirs returns the directories Applications reads, so they
+	// can be watched for installs and removals.
+	ApplicationDirs() []string
+	// AppAt reports whether the entry at path is an application, for
+	// indexing apps in user-configured folders.
+	AppAt(path string, d fs.DirEntry) (App, bool)
+	// Launch starts or activates app.
+	Launch(ctx context.Context, app App) error
+	// Open opens a file, folder or URL with its default handler.
+	Open(ctx context.Context, target string) error
+	// RunInTerminal runs a command line in a new terminal window. terminal is
+	// the name of a terminal app, empty for the system default, or a command
+	// template containing {cmd}.
+	RunInTerminal(ctx context.Context, terminal string, args []string) error
+	// OpenTerminal opens terminal, as for RunInTerminal, with dir as its
+	// working folder, running args there or the user's shell when args is
+	// empty.
+	OpenTerminal(ctx context.Context, terminal, dir string, args []string) error
+	// Reveal shows path selected in the system file manager.
+	Reveal(ctx context.Context, path string) error
+	// Trash moves path to the Trash or Recycle Bin, from where it can be
+	// restored.
+	Trash(ctx context.Context, path string) error
+	// RestoreFromTrash moves the item Trash last moved from path back
+	// there. It fails with ErrNotInTrash once the item has been emptied
+	// from the Trash, and when something else is at path by then.
+	RestoreFromTrash(ctx context.Context, path string) error
+	// CanRestoreFromTrash reports whether RestoreFromTrash is supported, so
+	// undoing a move to the Trash is only offered where it works.
+	CanRestoreFromTrash() bool
+	// AirDrop opens the system's AirDrop sharing panel with the file at
+	// path, returning once it is shown. It fails when AirDrop is turned
+	// off or cannot share the file.
+	AirDrop(ctx context.Context, path string) error
+	// Environment returns the user's environment as "NAME=value" entries:
+	// that of a login shell where there is one, which has what the user's
+	// profile exports even when Prism was started without it.
+	Environment(ctx context.Context) ([]string, error)
+	// OnBattery reports whether the machine is running on battery power.
+	// Machines without a battery report false.
+	OnBattery(ctx context.Context) (bool, error)
+	// FrontmostApp returns the application that currently has focus. It is
+	// captured just before the launcher is shown.
+	FrontmostApp(ctx context.Context) (App, error)
+	// RunningApps lists the regular applications that are running, for
+	// noticing when one quits.
+	RunningApps(ctx context.Context) ([]App, error)
+	// Activate gives focus back to an app returned by FrontmostApp.
+	Activate(ctx context.Context, app App) error
+	// Paste sends the system paste shortcut to the focused application.
+	Paste(ctx context.Context) error
+	// PlaceWindow sizes and positions the launcher window before it is
+	// shown. width and height are in logical pixels and are scaled for the
+	// display the window is shown on.
+	PlaceWindow(w Window, width, height int)
+	// Notify shows a system notification.
+	Notify(ctx context.Context, title, body string) error
+	// FocusActive reports whether a Focus, Do Not Disturb or similar mode
+	// is currently silencing notifications.
+	FocusActive(ctx context.Context) (bool, error)
+	// PlaySound starts a short system sound and returns without waiting for
+	// it to finish.
+	PlaySound(s Sound) error
+	// Haptic performs a light tap on hardware that supports it, such as a
+	// Force Touch trackpad.
+	Haptic() error
+}
+
+// Current returns the implementation for the running operating system.
+func Current() Platform {
+	return newPlatform()
+}
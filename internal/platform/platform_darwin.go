@@ -0,0 +1,309 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"changeme/internal/osascript"
+)
+
+type darwin struct{}
+
+// darwinSounds are names from /System/Library/Sounds.
+var darwinSounds = map[Sound]string{
+	SoundActivate:  "Tink",
+	SoundNoResults: "Funk",
+}
+
+func newPlatform() Platform {
+	return darwin{}
+}
+
+// applicationDirs returns the folders scanned for .app bundles.
+func applicationDirs() []string {
+	dirs := []string{
+		"/Applications",
+		"/System/Applications",
+		"/System/Library/CoreServices/Applications",
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "Applications"))
+	}
+	return dirs
+}
+
+func (darwin) Applications() ([]App, error) {
+	var apps []App
+	for _, root := range applicationDirs() {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if strings.HasSuffix(d.Name(), ".app") {
+				apps = append(apps, App{Name: strings.TrimSuffix(d.Name(), ".app"), Path: path})
+				return filepath.SkipDir
+			}
+			// Apps are never nested deeper than one folder, e.g. Utilities.
+			if strings.Count(strings.TrimPrefix(path, root), string(filepath.Separator)) >= 2 {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+	return apps, nil
+}
+
+func (darwin) ApplicationDirs() []string {
+	return applicationDirs()
+}
+
+func (darwin) AppAt(path string, d fs.DirEntry) (App, bool) {
+	if !d.IsDir() || !strings.HasSuffix(d.Name(), ".app") {
+		return App{}, false
+	}
+	return App{Name: strings.TrimSuffix(d.Name(), ".app"), Path: path}, true
+}
+
+// appLauncher launches the app bundle at path.
+type appLauncher func(ctx context.Context, path string) error
+
+// launchers are tried in order by Launch until one succeeds. NSWorkspace
+// launches in process, skipping the 50-100ms of starting open for every
+// launch; open remains the fallback for when it fails or Prism is built
+// without cgo. Tests and benchmarks can swap the list to stub or compare
+// the paths.
+var launchers = []appLauncher{workspaceLaunch, openLaunch}
+
+func (darwin) Launch(ctx context.Context, app App) error {
+	var errs []error
+	for _, launch := range launchers {
+		err := launch(ctx, app.Path)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return ErrUnsupported
+	}
+	return errors.Join(errs...)
+}
+
+// openLaunch launches the app at path with open -a.
+func openLaunch(ctx context.Context, path string) error {
+	return exec.CommandContext(ctx, "open", "-a", path).Run()
+}
+
+func (darwin) Open(ctx context.Context, target string) error {
+	return exec.CommandContext(ctx, "open", target).Run()
+}
+
+// RunInTerminal scripts Terminal or iTerm to open a window running args;
+// other terminal apps need a {cmd} template.
+func (darwin) RunInTerminal(ctx context.Context, terminal string, args []string) error {
+	if isTerminalTemplate(terminal) {
+		return runTerminalTemplate(terminal, "", args)
+	}
+	return runScripted(ctx, terminal, shellJoin(args))
+}
+
+// OpenTerminal opens Terminal at dir with open -a when there is nothing to
+// run, and otherwise scripts Terminal or iTerm to change to dir and run
+// args. Other terminal apps are opened at dir, or need a {cmd} template to
+// run a command.
+func (darwin) OpenTerminal(ctx context.Context, terminal, dir string, args []string) error {
+	if isTerminalTemplate(terminal) {
+		return runTerminalTemplate(terminal, dir, shellOr(args))
+	}
+	app := terminal
+	if app == "" {
+		app = "Terminal"
+	}
+	if len(args) == 0 {
+		return exec.CommandContext(ctx, "open", "-a", app, dir).Run()
+	}
+	return runScripted(ctx, terminal, "cd "+shellJoin([]string{dir})+" && "+shellJoin(args))
+}
+
+// runScripted has Terminal or iTerm open a window running the shell command
+// line command.
+func runScripted(ctx context.Context, terminal, command string) error {
+	command = osascript.Quote(command)
+	var app, script string
+	switch strings.ToLower(terminal) {
+	case "", "terminal":
+		app = "Terminal"
+		script = `tell application "Terminal"
+	do script ` + command + `
+	activate
+end tell`
+	case "iterm", "iterm2":
+		app = "iTerm"
+		script = `tell application "iTerm"
+	create window with default profile command ` + command + `
+	activate
+end tell`
+	default:
+		return fmt.Errorf("platform: cannot script terminal %q; use a command template with {cmd}", terminal)
+	}
+	_, err := osascript.Run(ctx, app, script)
+	return err
+}
+
+func (darwin) Reveal(ctx context.Context, path string) error {
+	return exec.CommandContext(ctx, "open", "-R", path).Run()
+}
+
+// Environment asks a login shell, since apps started from the Dock or
+// Finder do not inherit what the user's shell profile exports.
+func (darwin) Environment(ctx context.Context) ([]string, error) {
+	return loginEnvironment(ctx)
+}
+
+// OnBattery reads the power source pmset reports drawing from.
+func (darwin) OnBattery(ctx context.Context) (bool, error) {
+	out, err := exec.CommandContext(ctx, "pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(out), "'Battery Power'"), nil
+}
+
+func (darwin) AirDrop(ctx context.Context, path string) error {
+	return airDrop(ctx, path)
+}
+
+// Trash has Finder move path to the Trash, so it can be put back from
+// there, and remembers where it went for RestoreFromTrash.
+func (darwin) Trash(ctx context.Context, path string) error {
+	inTrash, err := osascript.Run(ctx, "Finder", fmt.Sprintf(trashScript, osascript.Quote(path)))
+	if err != nil {
+		return err
+	}
+	if inTrash != "" {
+		rememberTrashed(path, strings.TrimSuffix(inTrash, "/"))
+	}
+	return nil
+}
+
+// trashScript moves the item at the POSIX path in %s to the Trash and
+// prints where it is now.
+const trashScript = `tell application "Finder"
+	set t to delete (POSIX file %s as alias)
+end tell
+return POSIX path of (t as alias)`
+
+// RestoreFromTrash moves the item back from where Finder put it, which is
+// only known for items trashed since Prism started.
+func (darwin) RestoreFromTrash(ctx context.Context, path string) error {
+	inTrash, ok := takeTrashed(path)
+	if !ok {
+		return ErrNotInTrash
+	}
+	return putBack(inTrash, path)
+}
+
+func (darwin) CanRestoreFromTrash() bool { return true }
+
+// FrontmostApp asks for the frontmost application's path with "path to",
+// which unlike System Events does not require Automation permission.
+func (darwin) FrontmostApp(ctx context.Context) (App, error) {
+	out, err := osascript.Run(ctx, "", `POSIX path of (path to frontmost application)`)
+	if err != nil {
+		return App{}, err
+	}
+	path := strings.TrimSuffix(out, "/")
+	return App{Name: strings.TrimSuffix(filepath.Base(path), ".app"), Path: path}, nil
+}
+
+// RunningApps derives app bundles from the executable paths ps reports,
+// which unlike asking System Events needs no permission. Helpers nested in
+// other bundles, such as browser renderers, map to their outermost app, and
+// processes outside a bundle are skipped.
+func (darwin) RunningApps(ctx context.Context) ([]App, error) {
+	out, err := exec.CommandContext(ctx, "ps", "-axo", "comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var apps []App
+	for _, line := range strings.Split(string(out), "\n") {
+		i := strings.Index(line, ".app/")
+		if i < 0 {
+			continue
+		}
+		path := line[:i+len(".app")]
+		if seen[path] || strings.HasPrefix(path, "/System/Library/") {
+			continue
+		}
+		seen[path] = true
+		apps = append(apps, App{Name: strings.TrimSuffix(filepath.Base(path), ".app"), Path: path})
+	}
+	return apps, nil
+}
+
+func (darwin) Activate(ctx context.Context, app App) error {
+	return exec.CommandContext(ctx, "open", "-a", app.Path).Run()
+}
+
+// Paste presses Cmd+V through System Events, which needs Accessibility
+// access; without it the error is a *search.PermissionError.
+func (darwin) Paste(ctx context.Context) error {
+	_, err := osascript.Run(ctx, "System Events", `tell application "System Events" to keystroke "v" using command down`)
+	return err
+}
+
+// PlaceWindow needs no scaling: AppKit sizes windows in points, which the
+// backing scale factor of each display already maps to pixels.
+func (darwin) PlaceWindow(w Window, width, height int) {
+	w.SetSize(width, height)
+	w.Center()
+}
+
+func (darwin) Notify(ctx context.Context, title, body string) error {
+	_, err := osascript.Run(ctx, "", "display notification "+osascript.Quote(body)+" with title "+osascript.Quote(title))
+	return err
+}
+
+// FocusActive reads the assertions the Focus daemon keeps for modes turned
+// on by hand or by another device. There is no public API for the Focus
+// state; modes started by a schedule are not listed there.
+func (darwin) FocusActive(ctx context.Context) (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(filepath.Join(home, "Library", "DoNotDisturb", "DB", "Assertions.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var assertions struct {
+		Data []struct {
+			StoreAssertionRecords []json.RawMessage `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &assertions); err != nil {
+		return false, err
+	}
+	for _, d := range assertions.Data {
+		if len(d.StoreAssertionRecords) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
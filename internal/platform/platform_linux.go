@@ -0,0 +1,257 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type linux struct{}
+
+func newPlatform() Platform {
+	return linux{}
+}
+
+func (linux) Applications() ([]App, error) {
+	return desktopApplications(), nil
+}
+
+func (linux) ApplicationDirs() []string {
+	return applicationDirs()
+}
+
+func (linux) AppAt(path string, d fs.DirEntry) (App, bool) {
+	if d.IsDir() || !strings.HasSuffix(d.Name(), ".desktop") {
+		return App{}, false
+	}
+	app, ok := parseDesktopFile(path)
+	app.ID = d.Name()
+	return app, ok
+}
+
+// Launch runs the desktop entry's Exec line detached from Prism, wrapping it
+// in a terminal emulator when the entry asks for one.
+func (linux) Launch(ctx context.Context, app App) error {
+	args, err := splitExec(app.Exec)
+	if err != nil {
+		return fmt.Errorf("platform: %s: %w", app.Path, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("platform: %s has no Exec line", app.Path)
+	}
+	if app.Terminal {
+		args = append([]string{"x-terminal-emulator", "-e"}, args...)
+	}
+	return startDetached(args[0], args[1:]...)
+}
+
+// RunInTerminal starts terminal, or x-terminal-emulator by default, with
+// -e, which xterm-compatible terminals and the Debian alternative accept.
+func (linux) RunInTerminal(ctx context.Context, terminal string, args []string) error {
+	if isTerminalTemplate(terminal) {
+		return runTerminalTemplate(terminal, "", args)
+	}
+	if terminal == "" {
+		terminal = "x-terminal-emulator"
+	}
+	return startDetached(terminal, append([]string{"-e"}, args...)...)
+}
+
+// OpenTerminal starts terminal in dir, as RunInTerminal would, without -e
+// when there is nothing to run so the terminal starts its shell.
+func (linux) OpenTerminal(ctx context.Context, terminal, dir string, args []string) error {
+	if isTerminalTemplate(terminal) {
+		return runTerminalTemplate(terminal, dir, shellOr(args))
+	}
+	if terminal == "" {
+		terminal = "x-terminal-emulator"
+	}
+	if len(args) == 0 {
+		return startDetachedIn(dir, terminal)
+	}
+	return startDetachedIn(dir, terminal, append([]string{"-e"}, args...)...)
+}
+
+func (linux) Open(ctx context.Context, target string) error {
+	return startDetached("xdg-open", target)
+}
+
+// Reveal asks the file manager to select path over the freedesktop
+// FileManager1 D-Bus interface, falling back to opening its folder.
+func (linux) Reveal(ctx context.Context, path string) error {
+	uri := "file://" + path
+	err := exec.CommandContext(ctx, "dbus-send", "--session", "--dest=org.freedesktop.FileManager1",
+		"--type=method_call", "/org/freedesktop/FileManager1", "org.freedesktop.FileManager1.ShowItems",
+		"array:string:"+uri, "string:").Run()
+	if err != nil {
+		return startDetached("xdg-open", filepath.Dir(path))
+	}
+	return nil
+}
+
+// Environment asks a login shell, since apps started from the desktop's
+// launcher do not inherit what the user's shell profile exports.
+func (linux) Environment(ctx context.Context) ([]string, error) {
+	return loginEnvironment(ctx)
+}
+
+// OnBattery reads the power supplies the kernel lists: the machine is on
+// battery when it has one and no mains or USB supply is online.
+func (linux) OnBattery(ctx context.Context) (bool, error) {
+	dirs, err := filepath.Glob("/sys/class/power_supply/*")
+	if err != nil {
+		return false, err
+	}
+	battery := false
+	for _, dir := range dirs {
+		kind, err := os.ReadFile(filepath.Join(dir, "type"))
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(string(kind)) {
+		case "Battery":
+			battery = true
+		case "Mains", "USB":
+			if online, err := os.ReadFile(filepath.Join(dir, "online")); err == nil && strings.TrimSpace(string(online)) == "1" {
+				return false, nil
+			}
+		}
+	}
+	return battery, nil
+}
+
+// AirDrop is macOS only.
+func (linux) AirDrop(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+// Trash uses gio, which follows the freedesktop.org Trash specification
+// that file managers restore from.
+func (linux) Trash(ctx context.Context, path string) error {
+	if out, err := exec.CommandContext(ctx, "gio", "trash", "--", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("platform: gio trash: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (linux) RestoreFromTrash(ctx context.Context, path string) error {
+	return restoreFromTrash(filepath.Clean(path))
+}
+
+func (linux) CanRestoreFromTrash() bool { return true }
+
+// FrontmostApp reads the active window from the X server. Wayland does not let
+// clients inspect other windows, so it reports ErrUnsupported there.
+func (linux) FrontmostApp(ctx context.Context) (App, error) {
+	if isWayland() {
+		return App{}, ErrUnsupported
+	}
+	out, err := exec.CommandContext(ctx, "xprop", "-root", "_NET_ACTIVE_WINDOW").Output()
+	if err != nil {
+		return App{}, err
+	}
+	id := xpropWindowID.FindString(string(out))
+	if id == "" {
+		return App{}, fmt.Errorf("platform: no active window")
+	}
+	out, err = exec.CommandContext(ctx, "xprop", "-id", id, "WM_CLASS").Output()
+	if err != nil {
+		return App{}, err
+	}
+	name := id
+	if m := xpropClass.FindStringSubmatch(string(out)); m != nil {
+		name = m[1]
+	}
+	return App{Name: name, ID: id}, nil
+}
+
+func (linux) Activate(ctx context.Context, app App) error {
+	if isWayland() || app.ID == "" {
+		return ErrUnsupported
+	}
+	if _, err := exec.LookPath("xdotool"); err == nil {
+		return exec.CommandContext(ctx, "xdotool", "windowactivate", app.ID).Run()
+	}
+	return exec.CommandContext(ctx, "wmctrl", "-ia", app.ID).Run()
+}
+
+// Paste presses Ctrl+V with xdotool on X11 or wtype on Wayland, reporting
+// ErrUnsupported when neither tool is installed.
+func (linux) Paste(ctx context.Context) error {
+	name, args := "xdotool", []string{"key", "--clearmodifiers", "ctrl+v"}
+	if isWayland() {
+		name, args = "wtype", []string{"-M", "ctrl", "v", "-m", "ctrl"}
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ErrUnsupported
+	}
+	return exec.CommandContext(ctx, name, args...).Run()
+}
+
+// PlaceWindow centers the window on X11. Wayland compositors decide where
+// new windows go and ignore client positioning, so there it is only sized.
+// GTK sizes windows in logical pixels and scales them itself.
+func (linux) PlaceWindow(w Window, width, height int) {
+	w.SetSize(width, height)
+	if isWayland() {
+		return
+	}
+	w.Center()
+}
+
+func (linux) Notify(ctx context.Context, title, body string) error {
+	return exec.CommandContext(ctx, "notify-send", "--app-name=Prism", title, body).Run()
+}
+
+// FocusActive reads GNOME's Do Not Disturb switch, which turns banners off.
+// Other desktops report it as unsupported.
+func (linux) FocusActive(ctx context.Context) (bool, error) {
+	out, err := exec.CommandContext(ctx, "gsettings", "get", "org.gnome.desktop.notifications", "show-banners").Output()
+	if err != nil {
+		return false, ErrUnsupported
+	}
+	return strings.TrimSpace(string(out)) == "false", nil
+}
+
+// linuxSounds are freedesktop sound theme names, played by
+// canberra-gtk-play when it is installed.
+var linuxSounds = map[Sound]string{
+	SoundActivate:  "button-pressed",
+	SoundNoResults: "dialog-warning",
+}
+
+func (linux) PlaySound(s Sound) error {
+	cmd := exec.Command("canberra-gtk-play", "--id="+linuxSounds[s])
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func (linux) Haptic() error {
+	return ErrUnsupported
+}
+
+var (
+	xpropWindowID = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	xpropClass    = regexp.MustCompile(`"([^"]*)"\s*$`)
+)
+
+// isWayland reports whether the session is a native Wayland session. GTK
+// forced onto XWayland with GDK_BACKEND=x11 behaves like X11.
+func isWayland() bool {
+	if os.Getenv("GDK_BACKEND") == "x11" {
+		return false
+	}
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland"
+}
+
+func (linux) RunningApps(ctx context.Context) ([]App, error) {
+	return nil, ErrUnsupported
+}
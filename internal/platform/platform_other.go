@@ -0,0 +1,105 @@
+//go:build !darwin && !linux && !windows
+
+package platform
+
+import (
+	"context"
+	"io/fs"
+)
+
+type unsupported struct{}
+
+func newPlatform() Platform {
+	return unsupported{}
+}
+
+func (unsupported) Applications() ([]App, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) ApplicationDirs() []string {
+	return nil
+}
+
+func (unsupported) AppAt(path string, d fs.DirEntry) (App, bool) {
+	return App{}, false
+}
+
+func (unsupported) Launch(ctx context.Context, app App) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Open(ctx context.Context, target string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) RunInTerminal(ctx context.Context, terminal string, args []string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Reveal(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) OpenTerminal(ctx context.Context, terminal, dir string, args []string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Environment(ctx context.Context) ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) OnBattery(ctx context.Context) (bool, error) {
+	return false, ErrUnsupported
+}
+
+func (unsupported) AirDrop(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Trash(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) RestoreFromTrash(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) CanRestoreFromTrash() bool { return false }
+
+func (unsupported) FrontmostApp(ctx context.Context) (App, error) {
+	return App{}, ErrUnsupported
+}
+
+func (unsupported) Activate(ctx context.Context, app App) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Paste(ctx context.Context) error {
+	return ErrUnsupported
+}
+
+func (unsupported) PlaceWindow(w Window, width, height int) {
+	w.SetSize(width, height)
+	w.Center()
+}
+
+func (unsupported) Notify(ctx context.Context, title, body string) error {
+	return ErrUnsupported
+}
+
+func (unsupported) FocusActive(ctx context.Context) (bool, error) {
+	return false, ErrUnsupported
+}
+
+func (unsupported) PlaySound(s Sound) error {
+	return ErrUnsupported
+}
+
+func (unsupported) Haptic() error {
+	return ErrUnsupported
+}
+
+func (unsupported) RunningApps(ctx context.Context) ([]App, error) {
+	return nil, ErrUnsupported
+}
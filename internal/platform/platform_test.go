@@ -0,0 +1,23 @@
+package platform
+
+import "testing"
+
+func TestScaleSize(t *testing.T) {
+	tests := []struct {
+		scale        float64
+		wantW, wantH int
+	}{
+		{1, 600, 50},
+		{2, 1200, 100},
+		{1.25, 750, 63}, // 62.5 rounds away from zero
+		{1.5, 900, 75},
+		{1.75, 1050, 88},
+		{0, 600, 50},
+		{-2, 600, 50},
+	}
+	for _, tt := range tests {
+		if w, h := ScaleSize(600, 50, tt.scale); w != tt.wantW || h != tt.wantH {
+			t.Errorf("ScaleSize(600, 50, %v) = %d, %d; want %d, %d", tt.scale, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
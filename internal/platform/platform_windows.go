@@ -0,0 +1,419 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                  = windows.NewLazySystemDLL("user32.dll")
+	procGetCursorPos        = user32.NewProc("GetCursorPos")
+	procMonitorFromPoint    = user32.NewProc("MonitorFromPoint")
+	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+	procKeybdEvent          = user32.NewProc("keybd_event")
+	procMessageBeep         = user32.NewProc("MessageBeep")
+	procSetWindowPos        = user32.NewProc("SetWindowPos")
+
+	shcore               = windows.NewLazySystemDLL("shcore.dll")
+	procGetDpiForMonitor = shcore.NewProc("GetDpiForMonitor")
+
+	kernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+
+	shell32                          = windows.NewLazySystemDLL("shell32.dll")
+	procSHQueryUserNotificationState = shell32.NewProc("SHQueryUserNotificationState")
+)
+
+const (
+	monitorDefaultToNearest = 2
+	mdtEffectiveDPI         = 0
+	defaultDPI              = 96
+	swpNoZOrder             = 0x4
+	swpNoActivate           = 0x10
+
+	vkControl      = 0x11
+	vkV            = 0x56
+	keyeventfKeyUp = 0x2
+
+	mbOK          = 0x0
+	mbIconWarning = 0x30
+
+	qunsBusy             = 2
+	qunsPresentationMode = 4
+	qunsQuietTime        = 6
+)
+
+type point struct{ X, Y int32 }
+
+type rect struct{ Left, Top, Right, Bottom int32 }
+
+type monitorInfo struct {
+	Size    uint32
+	Monitor rect
+	Work    rect
+	Flags   uint32
+}
+
+type windowsPlatform struct{}
+
+func newPlatform() Platform {
+	return windowsPlatform{}
+}
+
+// startMenuDirs returns the per-user and all-users Start Menu program folders.
+func startMenuDirs() []string {
+	var dirs []string
+	for _, env := range []string{"APPDATA", "ProgramData"} {
+		if root := os.Getenv(env); root != "" {
+			dirs = append(dirs, filepath.Join(root, "Microsoft", "Windows", "Start Menu", "Programs"))
+		}
+	}
+	return dirs
+}
+
+// Applications indexes Start Menu shortcuts that point at executables, then
+// adds packaged (UWP) apps reported by Get-StartApps that no shortcut covers.
+func (windowsPlatform) Applications() ([]App, error) {
+	var apps []App
+	seen := make(map[string]bool)
+	for _, root := range startMenuDirs() {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".lnk") {
+				return nil
+			}
+			name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+			if strings.Contains(strings.ToLower(name), "uninstall") {
+				return nil
+			}
+			sc, err := readShortcut(path)
+			if err != nil || !strings.EqualFold(filepath.Ext(sc.Target), ".exe") {
+				return nil
+			}
+			key := strings.ToLower(sc.Target + " " + sc.Arguments)
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+			seen[strings.ToLower(name)] = true
+			apps = append(apps, App{Name: name, Path: sc.Target, ID: path})
+			return nil
+		})
+	}
+	for _, app := range packagedApps() {
+		if !seen[strings.ToLower(app.Name)] {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
+
+func (windowsPlatform) ApplicationDirs() []string {
+	return startMenuDirs()
+}
+
+// AppAt accepts shortcuts to executables and executables themselves.
+func (windowsPlatform) AppAt(path string, d fs.DirEntry) (App, bool) {
+	if d.IsDir() {
+		return App{}, false
+	}
+	name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".exe":
+		return App{Name: name, Path: path}, true
+	case ".lnk":
+		sc, err := readShortcut(path)
+		if err != nil || !strings.EqualFold(filepath.Ext(sc.Target), ".exe") {
+			return App{}, false
+		}
+		return App{Name: name, Path: sc.Target, ID: path}, true
+	}
+	return App{}, false
+}
+
+// packagedApps lists UWP apps, whose AppUserModelIDs contain a "!". Failures
+// are ignored since PowerShell may be unavailable or restricted.
+func packagedApps() []App {
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+		"Get-StartApps | ConvertTo-Json -Compress").Output()
+	if err != nil {
+		return nil
+	}
+	var entries []struct {
+		Name  string
+		AppID string
+	}
+	if err := json.Unmarshal(out, &entries); err != nil {
+		return nil
+	}
+	var apps []App
+	for _, e := range entries {
+		if strings.Contains(e.AppID, "!") {
+			apps = append(apps, App{Name: e.Name, Path: `shell:AppsFolder\` + e.AppID, ID: e.AppID})
+		}
+	}
+	return apps
+}
+
+// Launch opens the app's shortcut when it has one so its arguments and
+// working directory apply; otherwise it opens Path directly.
+func (p windowsPlatform) Launch(ctx context.Context, app App) error {
+	if strings.EqualFold(filepath.Ext(app.ID), ".lnk") {
+		return shellExecute(app.ID)
+	}
+	return shellExecute(app.Path)
+}
+
+// RunInTerminal runs args in Windows Terminal when terminal is "wt", or in
+// a new console window through start by default.
+func (windowsPlatform) RunInTerminal(ctx context.Context, terminal string, args []string) error {
+	if isTerminalTemplate(terminal) {
+		return runTerminalTemplate(terminal, "", args)
+	}
+	if terminal == "" {
+		return startDetached("cmd", append([]string{"/c", "start", ""}, args...)...)
+	}
+	return startDetached(terminal, args...)
+}
+
+// OpenTerminal opens a console in dir, or Windows Terminal with -d when
+// terminal is "wt", running args or a command prompt.
+func (windowsPlatform) OpenTerminal(ctx context.Context, terminal, dir string, args []string) error {
+	if len(args) == 0 {
+		args = []string{"cmd"}
+	}
+	switch {
+	case isTerminalTemplate(terminal):
+		return runTerminalTemplate(terminal, dir, args)
+	case terminal == "":
+		return startDetachedIn(dir, "cmd", append([]string{"/c", "start", ""}, args...)...)
+	case strings.EqualFold(terminal, "wt"):
+		return startDetached("wt", append([]string{"-d", dir}, args...)...)
+	}
+	return startDetachedIn(dir, terminal, args...)
+}
+
+func (windowsPlatform) Open(ctx context.Context, target string) error {
+	return shellExecute(target)
+}
+
+// Reveal starts Explorer without waiting, since it exits with status 1 even
+// when it succeeds.
+func (windowsPlatform) Reveal(ctx context.Context, path string) error {
+	return exec.Command("explorer", "/select,"+path).Start()
+}
+
+// trashScript sends the path in PRISM_TRASH to the Recycle Bin through the
+// Visual Basic file system API, which unlike Remove-Item does not delete it
+// for good. The path is passed in the environment to avoid quoting it.
+const trashScript = `Add-Type -AssemblyName Microsoft.VisualBasic
+$p = $env:PRISM_TRASH
+if (Test-Path -LiteralPath $p -PathType Container) {
+  [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteDirectory($p, 'OnlyErrorDialogs', 'SendToRecycleBin')
+} else {
+  [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteFile($p, 'OnlyErrorDialogs', 'SendToRecycleBin')
+}`
+
+// Environment returns Prism's own environment, which Windows builds from
+// the user's settings at logon; there is no login shell to ask.
+func (windowsPlatform) Environment(ctx context.Context) ([]string, error) {
+	return os.Environ(), nil
+}
+
+// systemPowerStatus is SYSTEM_POWER_STATUS.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// acOffline is the ACLineStatus of a machine running on battery; 1 is on
+// AC power and 255 unknown.
+const acOffline = 0
+
+func (windowsPlatform) OnBattery(ctx context.Context) (bool, error) {
+	var status systemPowerStatus
+	if ok, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); ok == 0 {
+		return false, fmt.Errorf("platform: GetSystemPowerStatus: %w", err)
+	}
+	return status.ACLineStatus == acOffline, nil
+}
+
+// AirDrop is macOS only.
+func (windowsPlatform) AirDrop(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (windowsPlatform) Trash(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", trashScript)
+	cmd.Env = append(os.Environ(), "PRISM_TRASH="+path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("platform: recycle: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RestoreFromTrash is unsupported, as the Recycle Bin can only be browsed
+// through the shell's folder objects.
+func (windowsPlatform) RestoreFromTrash(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
+
+func (windowsPlatform) CanRestoreFromTrash() bool { return false }
+
+func (windowsPlatform) FrontmostApp(ctx context.Context) (App, error) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return App{}, fmt.Errorf("platform: no foreground window")
+	}
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &pid); err != nil {
+		return App{}, err
+	}
+	proc, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return App{}, err
+	}
+	defer windows.CloseHandle(proc)
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(proc, 0, &buf[0], &size); err != nil {
+		return App{}, err
+	}
+	path := windows.UTF16ToString(buf[:size])
+	return App{
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path: path,
+		ID:   strconv.FormatUint(uint64(hwnd), 16),
+	}, nil
+}
+
+func (windowsPlatform) Activate(ctx context.Context, app App) error {
+	hwnd, err := strconv.ParseUint(app.ID, 16, 64)
+	if err != nil {
+		return ErrUnsupported
+	}
+	procSetForegroundWindow.Call(uintptr(hwnd))
+	return nil
+}
+
+// Paste presses Ctrl+V.
+func (windowsPlatform) Paste(ctx context.Context) error {
+	procKeybdEvent.Call(vkControl, 0, 0, 0)
+	procKeybdEvent.Call(vkV, 0, 0, 0)
+	procKeybdEvent.Call(vkV, 0, keyeventfKeyUp, 0)
+	procKeybdEvent.Call(vkControl, 0, keyeventfKeyUp, 0)
+	return nil
+}
+
+// PlaceWindow centers the window in the work area of the monitor under the
+// mouse cursor, which is where the user is looking when they press the
+// hotkey. The window is sized for that monitor's DPI, since it keeps the
+// size it was scaled to when it is moved between monitors.
+func (windowsPlatform) PlaceWindow(w Window, width, height int) {
+	var pt point
+	if ok, _, _ := procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt))); ok == 0 {
+		w.SetSize(width, height)
+		w.Center()
+		return
+	}
+	mon, _, _ := procMonitorFromPoint.Call(uintptr(*(*uint64)(unsafe.Pointer(&pt))), monitorDefaultToNearest)
+	info := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if ok, _, _ := procGetMonitorInfoW.Call(mon, uintptr(unsafe.Pointer(&info))); ok == 0 {
+		w.SetSize(width, height)
+		w.Center()
+		return
+	}
+	var dpiX, dpiY uint32
+	scale := 1.0
+	if hr, _, _ := procGetDpiForMonitor.Call(mon, mdtEffectiveDPI, uintptr(unsafe.Pointer(&dpiX)), uintptr(unsafe.Pointer(&dpiY))); hr == 0 && dpiX > 0 {
+		scale = float64(dpiX) / defaultDPI
+	}
+	pw, ph := ScaleSize(width, height, scale)
+	x := int(info.Work.Left) + (int(info.Work.Right-info.Work.Left)-pw)/2
+	y := int(info.Work.Top) + (int(info.Work.Bottom-info.Work.Top)-ph)/2
+
+	hwnd, err := w.NativeWindowHandle()
+	if err != nil {
+		w.SetSize(width, height)
+		w.SetPosition(x, y)
+		return
+	}
+	procSetWindowPos.Call(hwnd, 0, uintptr(x), uintptr(y), uintptr(pw), uintptr(ph), swpNoZOrder|swpNoActivate)
+}
+
+// toastScript shows a toast through the WinRT notification API, which
+// PowerShell can reach without any extra modules.
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode(%s)) > $null
+$x.Item(1).AppendChild($t.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('Prism').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+func (windowsPlatform) Notify(ctx context.Context, title, body string) error {
+	script := fmt.Sprintf(toastScript, psQuote(title), psQuote(body))
+	return exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script).Run()
+}
+
+// FocusActive asks the shell whether the user should not be disturbed, as
+// during Focus Assist quiet hours, a presentation or a full-screen game.
+func (windowsPlatform) FocusActive(ctx context.Context) (bool, error) {
+	var state uint32
+	if hr, _, _ := procSHQueryUserNotificationState.Call(uintptr(unsafe.Pointer(&state))); hr != 0 {
+		return false, fmt.Errorf("platform: SHQueryUserNotificationState failed: 0x%x", hr)
+	}
+	// Busy, full-screen Direct3D and presentation mode are consecutive.
+	return state >= qunsBusy && state <= qunsPresentationMode || state == qunsQuietTime, nil
+}
+
+// PlaySound plays the system sound for a message box type; MessageBeep
+// queues the sound and returns immediately.
+func (windowsPlatform) PlaySound(s Sound) error {
+	typ := uintptr(mbOK)
+	if s == SoundNoResults {
+		typ = mbIconWarning
+	}
+	if ok, _, err := procMessageBeep.Call(typ); ok == 0 {
+		return err
+	}
+	return nil
+}
+
+func (windowsPlatform) Haptic() error {
+	return ErrUnsupported
+}
+
+// psQuote returns s as a single-quoted PowerShell string literal.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func shellExecute(target string) error {
+	verb, err := windows.UTF16PtrFromString("open")
+	if err != nil {
+		return err
+	}
+	file, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	return windows.ShellExecute(0, verb, file, nil, nil, windows.SW_SHOWNORMAL)
+}
+
+func (windowsPlatform) RunningApps(ctx context.Context) ([]App, error) {
+	return nil, ErrUnsupported
+}
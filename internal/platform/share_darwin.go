@@ -0,0 +1,61 @@
+//go:build cgo
+
+package platform
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework AppKit
+#include <stdlib.h>
+#include <string.h>
+#import <AppKit/AppKit.h>
+
+// prismAirDrop shows the AirDrop panel for the file at path on the main
+// thread, which AppKit requires. It returns NULL once the panel is shown
+// or an error message for the caller to free.
+static char *prismAirDrop(const char *path) {
+	NSURL *url = [NSURL fileURLWithPath:[NSString stringWithUTF8String:path]];
+	__block char *message = NULL;
+	void (^share)(void) = ^{
+		@autoreleasepool {
+			NSSharingService *service = [NSSharingService sharingServiceNamed:NSSharingServiceNameSendViaAirDrop];
+			NSArray *items = @[url];
+			if (service == nil) {
+				message = strdup("AirDrop is not available on this Mac");
+			} else if (![service canPerformWithItems:items]) {
+				message = strdup("AirDrop cannot share this file; check that Wi-Fi and Bluetooth are on");
+			} else {
+				[NSApp activateIgnoringOtherApps:YES];
+				[service performWithItems:items];
+			}
+		}
+	};
+	if ([NSThread isMainThread]) {
+		share();
+	} else {
+		dispatch_sync(dispatch_get_main_queue(), share);
+	}
+	return message;
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"unsafe"
+)
+
+// airDrop opens the AirDrop panel through NSSharingService, which has no
+// command line equivalent.
+func airDrop(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if msg := C.prismAirDrop(cpath); msg != nil {
+		defer C.free(unsafe.Pointer(msg))
+		return errors.New("platform: " + C.GoString(msg))
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build darwin && !cgo
+
+package platform
+
+import "context"
+
+// airDrop needs cgo for NSSharingService.
+func airDrop(ctx context.Context, path string) error {
+	return ErrUnsupported
+}
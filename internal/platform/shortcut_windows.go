@@ -0,0 +1,142 @@
+package platform
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"unicode/utf16"
+)
+
+// Shell Link (.lnk) layout, from [MS-SHLLINK].
+const (
+	lnkHeaderSize = 0x4c
+
+	lnkHasTargetIDList = 1 << 0
+	lnkHasLinkInfo     = 1 << 1
+	lnkHasName         = 1 << 2
+	lnkHasRelativePath = 1 << 3
+	lnkHasWorkingDir   = 1 << 4
+	lnkHasArguments    = 1 << 5
+	lnkIsUnicode       = 1 << 7
+
+	// lnkInfoHeaderSize is the size of the LinkInfo fields up to and
+	// including CommonPathSuffixOffset.
+	lnkInfoHeaderSize = 0x1c
+
+	lnkInfoVolumeIDAndLocalBasePath = 1 << 0
+)
+
+var errBadShortcut = errors.New("platform: malformed shortcut")
+
+// shortcut is the part of a Shell Link Prism needs.
+type shortcut struct {
+	Target    string
+	Arguments string
+	WorkDir   string
+}
+
+func readShortcut(path string) (shortcut, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return shortcut{}, err
+	}
+	return parseShortcut(data)
+}
+
+func parseShortcut(b []byte) (shortcut, error) {
+	if len(b) < lnkHeaderSize || binary.LittleEndian.Uint32(b) != lnkHeaderSize {
+		return shortcut{}, errBadShortcut
+	}
+	flags := binary.LittleEndian.Uint32(b[0x14:])
+	off := lnkHeaderSize
+
+	if flags&lnkHasTargetIDList != 0 {
+		if off+2 > len(b) {
+			return shortcut{}, errBadShortcut
+		}
+		off += 2 + int(binary.LittleEndian.Uint16(b[off:]))
+	}
+
+	var s shortcut
+	if flags&lnkHasLinkInfo != 0 {
+		if off+lnkInfoHeaderSize > len(b) {
+			return shortcut{}, errBadShortcut
+		}
+		info := b[off:]
+		size := int(binary.LittleEndian.Uint32(info))
+		if size < lnkInfoHeaderSize || size > len(info) {
+			return shortcut{}, errBadShortcut
+		}
+		info = info[:size]
+		if binary.LittleEndian.Uint32(info[8:])&lnkInfoVolumeIDAndLocalBasePath != 0 {
+			base, ok := cString(info, binary.LittleEndian.Uint32(info[0x10:]))
+			if !ok {
+				return shortcut{}, errBadShortcut
+			}
+			suffix, ok := cString(info, binary.LittleEndian.Uint32(info[0x18:]))
+			if !ok {
+				return shortcut{}, errBadShortcut
+			}
+			s.Target = base + suffix
+		}
+		off += size
+	}
+
+	unicode := flags&lnkIsUnicode != 0
+	next := func() (string, bool) {
+		if off+2 > len(b) {
+			return "", false
+		}
+		n := int(binary.LittleEndian.Uint16(b[off:]))
+		off += 2
+		if !unicode {
+			if off+n > len(b) {
+				return "", false
+			}
+			str := string(b[off : off+n])
+			off += n
+			return str, true
+		}
+		if off+2*n > len(b) {
+			return "", false
+		}
+		u := make([]uint16, n)
+		for i := range u {
+			u[i] = binary.LittleEndian.Uint16(b[off+2*i:])
+		}
+		off += 2 * n
+		return string(utf16.Decode(u)), true
+	}
+	for _, f := range []uint32{lnkHasName, lnkHasRelativePath, lnkHasWorkingDir, lnkHasArguments} {
+		if flags&f == 0 {
+			continue
+		}
+		str, ok := next()
+		if !ok {
+			return shortcut{}, errBadShortcut
+		}
+		switch f {
+		case lnkHasWorkingDir:
+			s.WorkDir = str
+		case lnkHasArguments:
+			s.Arguments = str
+		}
+	}
+	return s, nil
+}
+
+// cString returns the NUL-terminated ANSI string at off in b, reporting
+// false when off is outside b or the string is not terminated within it.
+func cString(b []byte, off uint32) (string, bool) {
+	if uint64(off) >= uint64(len(b)) {
+		return "", false
+	}
+	end := int(off)
+	for end < len(b) && b[end] != 0 {
+		end++
+	}
+	if end == len(b) {
+		return "", false
+	}
+	return string(b[off:end]), true
+}
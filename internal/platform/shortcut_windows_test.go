@@ -0,0 +1,93 @@
+package platform
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode/utf16"
+)
+
+// lnk builds a Unicode Shell Link with a LinkInfo locating target as base
+// and suffix, and the given working directory and arguments.
+func lnk(base, suffix, workDir, args string) []byte {
+	b := make([]byte, lnkHeaderSize)
+	binary.LittleEndian.PutUint32(b, lnkHeaderSize)
+	binary.LittleEndian.PutUint32(b[0x14:], lnkHasLinkInfo|lnkHasWorkingDir|lnkHasArguments|lnkIsUnicode)
+
+	info := make([]byte, lnkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[4:], lnkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[8:], lnkInfoVolumeIDAndLocalBasePath)
+	binary.LittleEndian.PutUint32(info[0x10:], uint32(len(info)))
+	info = append(append(info, base...), 0)
+	binary.LittleEndian.PutUint32(info[0x18:], uint32(len(info)))
+	info = append(append(info, suffix...), 0)
+	binary.LittleEndian.PutUint32(info, uint32(len(info)))
+	b = append(b, info...)
+
+	for _, s := range []string{workDir, args} {
+		u := utf16.Encode([]rune(s))
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(u)))
+		for _, c := range u {
+			b = binary.LittleEndian.AppendUint16(b, c)
+		}
+	}
+	return b
+}
+
+func TestParseShortcut(t *testing.T) {
+	good := lnk(`C:\Program Files\`, `App\app.exe`, `C:\Users\sam`, "--profile wörk")
+	got, err := parseShortcut(good)
+	want := shortcut{Target: `C:\Program Files\App\app.exe`, Arguments: "--profile wörk", WorkDir: `C:\Users\sam`}
+	if err != nil || got != want {
+		t.Errorf("parseShortcut = %+v, %v, want %+v", got, err, want)
+	}
+
+	infoAt := lnkHeaderSize
+	// edit returns a copy of good changed by f.
+	edit := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), good...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated header", good[:lnkHeaderSize-1]},
+		{"wrong header size", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b, 0x4d)
+			return b
+		})},
+		{"truncated link info", good[:infoAt+lnkInfoHeaderSize-1]},
+		{"link info too short", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[infoAt:], 8)
+			return b
+		})},
+		{"link info past the end", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[infoAt:], uint32(len(b)))
+			return b
+		})},
+		{"base path offset out of range", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[infoAt+0x10:], 0xffffffff)
+			return b
+		})},
+		{"suffix offset at the end of link info", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[infoAt+0x18:], binary.LittleEndian.Uint32(b[infoAt:]))
+			return b
+		})},
+		{"unterminated suffix", edit(func(b []byte) []byte {
+			size := binary.LittleEndian.Uint32(b[infoAt:])
+			b[infoAt+int(size)-1] = 'x'
+			return b
+		})},
+		{"truncated arguments", good[:len(good)-2]},
+		{"truncated target ID list", edit(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[0x14:], lnkHasTargetIDList)
+			return b[:lnkHeaderSize+1]
+		})},
+	}
+	for _, tt := range tests {
+		if _, err := parseShortcut(tt.data); !errors.Is(err, errBadShortcut) {
+			t.Errorf("%s: parseShortcut = %v, want errBadShortcut", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// startDetached starts a command without waiting for it to exit.
+func startDetached(name string, args ...string) error {
+	return startDetachedIn("", name, args...)
+}
+
+// startDetachedIn starts a command in dir without waiting for it to exit.
+func startDetachedIn(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// isTerminalTemplate reports whether terminal is a command line with a
+// {cmd} placeholder rather than the name of a terminal app.
+func isTerminalTemplate(terminal string) bool {
+	return strings.Contains(terminal, "{cmd}")
+}
+
+// runTerminalTemplate starts a terminal from a template such as
+// "kitty -e {cmd}". The template is split on spaces; an argument that is
+// exactly {cmd} becomes the command's arguments, and {cmd} inside a longer
+// argument becomes the command line as one string, for terminals that take
+// the command as a single argument. The terminal starts in dir, or the
+// current folder when dir is empty.
+func runTerminalTemplate(template, dir string, args []string) error {
+	argv := terminalArgv(template, args)
+	if len(argv) == 0 {
+		return fmt.Errorf("platform: empty terminal command")
+	}
+	return startDetachedIn(dir, argv[0], argv[1:]...)
+}
+
+// terminalArgv returns the command line runTerminalTemplate starts.
+func terminalArgv(template string, args []string) []string {
+	var argv []string
+	for _, f := range strings.Fields(template) {
+		if f == "{cmd}" {
+			argv = append(argv, args...)
+			continue
+		}
+		argv = append(argv, strings.ReplaceAll(f, "{cmd}", shellJoin(args)))
+	}
+	return argv
+}
+
+// shellOr returns args, or the user's shell when args is empty, for a
+// terminal opened without a command.
+func shellOr(args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return []string{shell}
+	}
+	return []string{"sh"}
+}
+
+// shellJoin quotes args for a POSIX shell.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && strings.IndexFunc(a, func(r rune) bool {
+			return !(r == '-' || r == '_' || r == '.' || r == '/' || r == '@' || r == ':' || r == '=' ||
+				'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		}) < 0 {
+			quoted[i] = a
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
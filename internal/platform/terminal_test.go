@@ -0,0 +1,42 @@
+package platform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerminalArgv(t *testing.T) {
+	tests := []struct {
+		template string
+		args     []string
+		want     []string
+	}{
+		{"kitty -e {cmd}", []string{"ssh", "web"}, []string{"kitty", "-e", "ssh", "web"}},
+		{"alacritty --command {cmd}", []string{"zsh"}, []string{"alacritty", "--command", "zsh"}},
+		// {cmd} inside a longer argument is the command line as one string.
+		{"wezterm start -- sh -c {cmd};exec-sh", []string{"echo", "it's here"}, []string{"wezterm", "start", "--", "sh", "-c", `echo 'it'\''s here';exec-sh`}},
+		{"", []string{"zsh"}, nil},
+	}
+	for _, tt := range tests {
+		if got := terminalArgv(tt.template, tt.args); !slices.Equal(got, tt.want) {
+			t.Errorf("terminalArgv(%q, %q) = %q, want %q", tt.template, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"cd", "/Users/me/project"}, "cd /Users/me/project"},
+		{[]string{"cd", "/Users/me/My Project"}, "cd '/Users/me/My Project'"},
+		{[]string{"echo", "it's", ""}, `echo 'it'\''s' ''`},
+		{[]string{"ls", "/home/josé"}, "ls '/home/josé'"},
+	}
+	for _, tt := range tests {
+		if got := shellJoin(tt.args); got != tt.want {
+			t.Errorf("shellJoin(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotInTrash is returned by RestoreFromTrash when nothing trashed from
+// the path is left in the Trash.
+var ErrNotInTrash = errors.New("platform: the item is no longer in the Trash")
+
+// trashed records where Trash put the items it moved from each path, for
+// platforms whose Trash does not record that itself.
+var trashed = struct {
+	mu    sync.Mutex
+	paths map[string]string
+}{paths: make(map[string]string)}
+
+// rememberTrashed records that the item at path is now at inTrash.
+func rememberTrashed(path, inTrash string) {
+	trashed.mu.Lock()
+	trashed.paths[filepath.Clean(path)] = inTrash
+	trashed.mu.Unlock()
+}
+
+// takeTrashed returns, and forgets, where the item from path was trashed to.
+func takeTrashed(path string) (string, bool) {
+	trashed.mu.Lock()
+	defer trashed.mu.Unlock()
+	path = filepath.Clean(path)
+	inTrash, ok := trashed.paths[path]
+	delete(trashed.paths, path)
+	return inTrash, ok
+}
+
+// putBack moves the item at inTrash back to path, failing rather than
+// replacing anything that has since been put at path.
+func putBack(inTrash, path string) error {
+	if _, err := os.Lstat(inTrash); err != nil {
+		return ErrNotInTrash
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("platform: %s exists again", path)
+	}
+	if err := os.Rename(inTrash, path); err != nil {
+		return fmt.Errorf("platform: restoring %s: %w", path, err)
+	}
+	return nil
+}
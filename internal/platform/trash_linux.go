@@ -0,0 +1,78 @@
+package platform
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// trashInfoSuffix ends the files recording where each trashed item came
+// from, as the freedesktop.org Trash specification lays out.
+const trashInfoSuffix = ".trashinfo"
+
+// homeTrash returns the user's Trash folder, which gio uses for files in
+// the home folder's file system.
+func homeTrash() string {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, _ := os.UserHomeDir()
+		data = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(data, "Trash")
+}
+
+// restoreFromTrash moves the item most recently trashed from path back,
+// going by the trash info files. Only the home Trash is looked in.
+func restoreFromTrash(path string) error {
+	trash := homeTrash()
+	entries, err := os.ReadDir(filepath.Join(trash, "info"))
+	if err != nil {
+		return ErrNotInTrash
+	}
+	var name, latest string
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), trashInfoSuffix) {
+			continue
+		}
+		original, deleted, ok := readTrashInfo(filepath.Join(trash, "info", e.Name()))
+		// Dates are ISO 8601 in local time, so they sort as strings.
+		if ok && original == path && deleted >= latest {
+			name, latest = strings.TrimSuffix(e.Name(), trashInfoSuffix), deleted
+		}
+	}
+	if name == "" {
+		return ErrNotInTrash
+	}
+	if err := putBack(filepath.Join(trash, "files", name), path); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(trash, "info", name+trashInfoSuffix))
+}
+
+// readTrashInfo returns the original path and deletion date in a trash
+// info file.
+func readTrashInfo(file string) (path, deleted string, ok bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", "", false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, found := strings.Cut(sc.Text(), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "Path":
+			if p, err := url.PathUnescape(value); err == nil {
+				path = filepath.Clean(p)
+			}
+		case "DeletionDate":
+			deleted = value
+		}
+	}
+	return path, deleted, path != ""
+}
@@ -0,0 +1,58 @@
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// trashItem puts an item with data in the Trash at trash as name, recorded
+// as deleted from path at deleted.
+func trashItem(t *testing.T, trash, name, path, deleted, data string) {
+	t.Helper()
+	for _, dir := range []string{"files", "info"} {
+		if err := os.MkdirAll(filepath.Join(trash, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(trash, "files", name), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info := "[Trash Info]\nPath=" + path + "\nDeletionDate=" + deleted + "\n"
+	if err := os.WriteFile(filepath.Join(trash, "info", name+trashInfoSuffix), []byte(info), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreFromTrash(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	trash := filepath.Join(data, "Trash")
+	dir := filepath.Join(t.TempDir(), "my notes")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "notes.txt")
+	escaped := filepath.Join(filepath.Dir(dir), "my%20notes", "notes.txt")
+	trashItem(t, trash, "notes.txt", escaped, "2025-06-01T09:00:00", "older")
+	trashItem(t, trash, "notes.2.txt", escaped, "2025-06-01T10:00:00", "newer")
+
+	if err := restoreFromTrash(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "newer" {
+		t.Errorf("restored %q, %v, want the newer item", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "info", "notes.2.txt"+trashInfoSuffix)); !os.IsNotExist(err) {
+		t.Errorf("the restored item's trash info is still there: %v", err)
+	}
+
+	// The older one is not put over the file now there.
+	if err := restoreFromTrash(path); err == nil || errors.Is(err, ErrNotInTrash) {
+		t.Errorf("restoring over an existing file = %v", err)
+	}
+	if err := restoreFromTrash(filepath.Join(dir, "other.txt")); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("restoring what was never trashed = %v, want ErrNotInTrash", err)
+	}
+}
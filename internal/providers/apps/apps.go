@@ -0,0 +1,243 @@
+// Package apps provides results for installed applications.
+package apps
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/pathfmt"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID   = "apps"
+	actionOpen   = "open"
+	actionReveal = "reveal"
+)
+
+// Provider matches the query against installed application names.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	roots   []index.Root
+	ignore  []string
+	watcher *index.Watcher
+	apps    map[string]platform.App // keyed by result ID
+	loaded  bool
+	// metadata caches Metadata by result ID until the index is rebuilt.
+	metadata map[string]Metadata
+	// keywords are the configured synonyms, searched along with
+	// builtinKeywords but never shown.
+	keywords map[string][]string
+	// preferSystem ranks an app's system copy above copies of the same
+	// name in the home folder; see disambiguate.
+	preferSystem bool
+}
+
+// New returns an apps provider that discovers and launches applications
+// through plat and ranks them with matcher.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher, preferSystem: true}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetRoots sets extra folders to search for apps, beyond the platform's own
+// application folders, and rebuilds the index.
+func (p *Provider) SetRoots(roots []index.Root, ignore []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots, p.ignore = roots, ignore
+	p.apps, p.loaded, p.metadata = nil, false, nil
+	if p.watcher != nil {
+		p.watcher.Watch(p.watchDirs())
+	}
+}
+
+// SetKeywords sets extra words each app is found by, keyed by app name,
+// bundle name or ID, in addition to the built-in synonyms.
+func (p *Provider) SetKeywords(keywords map[string][]string) {
+	p.mu.Lock()
+	p.keywords = keywords
+	p.mu.Unlock()
+}
+
+// SetPreferSystem sets whether an app's system copy is listed before copies
+// of the same name in the user's home folder.
+func (p *Provider) SetPreferSystem(prefer bool) {
+	p.mu.Lock()
+	p.preferSystem = prefer
+	p.mu.Unlock()
+}
+
+// Watch rebuilds the index whenever the application folders change, until
+// ctx is cancelled. Apps on a drive that is unmounted drop out of the index
+// and return when it is mounted again.
+func (p *Provider) Watch(ctx context.Context) {
+	w, err := index.NewWatcher(p.Rebuild)
+	if err != nil {
+		log.Println("apps:", err)
+		return
+	}
+	p.mu.Lock()
+	p.watcher = w
+	w.Watch(p.watchDirs())
+	p.mu.Unlock()
+	w.Run(ctx)
+	p.mu.Lock()
+	p.watcher = nil
+	p.mu.Unlock()
+}
+
+// watchDirs returns the platform's application folders and the extra
+// roots. The caller holds p.mu.
+func (p *Provider) watchDirs() []string {
+	dirs := p.plat.ApplicationDirs()
+	for _, r := range p.roots {
+		dirs = append(dirs, r.Path)
+	}
+	return dirs
+}
+
+// Rebuild discards the application index so the next search rescans.
+func (p *Provider) Rebuild() {
+	p.mu.Lock()
+	p.apps, p.loaded, p.metadata = nil, false, nil
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	apps, err := p.index()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	keywords, preferSystem := p.keywords, p.preferSystem
+	p.mu.Unlock()
+	var results []search.Result
+	for id, app := range apps {
+		score, ok := p.match(query, app, keywords)
+		if !ok {
+			continue
+		}
+		results = append(results, result(id, app, float64(score)))
+	}
+	disambiguate(results, preferSystem)
+	return results, nil
+}
+
+// Resolve returns the result for an indexed app, reporting false once the app
+// has been uninstalled.
+func (p *Provider) Resolve(ctx context.Context, id string) (search.Result, bool) {
+	apps, err := p.index()
+	if err != nil {
+		return search.Result{}, false
+	}
+	app, ok := apps[id]
+	if !ok {
+		return search.Result{}, false
+	}
+	return result(id, app, 0), true
+}
+
+// Paths returns the paths of the indexed apps, keyed by result ID.
+func (p *Provider) Paths() (map[string]string, error) {
+	apps, err := p.index()
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]string, len(apps))
+	for id, app := range apps {
+		paths[id] = app.Path
+	}
+	return paths, nil
+}
+
+// Find returns the installed app called name, ignoring case.
+func (p *Provider) Find(name string) (platform.App, bool) {
+	apps, err := p.index()
+	if err != nil {
+		return platform.App{}, false
+	}
+	for _, app := range apps {
+		if strings.EqualFold(app.Name, name) {
+			return app, true
+		}
+	}
+	return platform.App{}, false
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	apps, err := p.index()
+	if err != nil {
+		return err
+	}
+	app, ok := apps[r.ID]
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case actionOpen:
+		return p.plat.Launch(ctx, app)
+	case actionReveal:
+		return p.plat.Reveal(ctx, app.Path)
+	}
+	return fmt.Errorf("apps: unknown action %q", actionID)
+}
+
+func result(id string, app platform.App, score float64) search.Result {
+	return search.Result{
+		ID:       id,
+		Type:     "app",
+		Title:    app.Name,
+		Subtitle: pathfmt.Truncate(app.Path, pathfmt.SubtitleLen),
+		Target:   app.Path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// index returns the app index, scanning the platform on first use. The map
+// is replaced rather than mutated, so callers may read it without the lock.
+func (p *Provider) index() (map[string]platform.App, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.apps, nil
+	}
+	list, err := p.plat.Applications()
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range p.roots {
+		index.Walk(root, p.ignore, func(path string, d fs.DirEntry) bool {
+			if app, ok := p.plat.AppAt(path, d); ok {
+				list = append(list, app)
+				return false
+			}
+			return true
+		})
+	}
+	p.apps = make(map[string]platform.App, len(list))
+	for _, app := range list {
+		p.apps[providerID+":"+app.Path] = app
+	}
+	p.loaded = true
+	return p.apps, nil
+}
@@ -0,0 +1,58 @@
+package apps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/platform"
+)
+
+// appDirs is a platform whose applications are in dirs.
+type appDirs struct {
+	platform.Platform
+	dirs []string
+}
+
+func (p appDirs) ApplicationDirs() []string { return p.dirs }
+
+func TestWatchNewRoots(t *testing.T) {
+	p := New(appDirs{dirs: []string{t.TempDir()}}, fuzzy.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx)
+	// Wait for Watch to start, then add a root it did not start with.
+	watching := func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.watcher != nil
+	}
+	for deadline := time.Now().Add(2 * time.Second); !watching(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not start")
+		}
+	}
+	root := t.TempDir()
+	p.SetRoots([]index.Root{{Path: root}}, nil)
+	loaded := func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.loaded
+	}
+	p.mu.Lock()
+	p.loaded = true
+	p.mu.Unlock()
+
+	if err := os.Mkdir(filepath.Join(root, "Tool.app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(3 * time.Second); loaded(); time.Sleep(20 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("an app added under the new root did not rebuild the index")
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package apps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"changeme/internal/search"
+)
+
+// disambiguate tells apart results for different copies of an app with the
+// same name, such as one in /Applications and one in ~/Applications left by
+// an updater. Each copy's subtitle notes whether it is the system's or in
+// the user's home folder. With preferSystem, user copies score just below
+// the best-scoring system copy so it is listed first.
+func disambiguate(results []search.Result, preferSystem bool) {
+	byName := make(map[string][]int)
+	for i, r := range results {
+		key := strings.ToLower(r.Title)
+		byName[key] = append(byName[key], i)
+	}
+	home, _ := os.UserHomeDir()
+	for _, group := range byName {
+		if len(group) < 2 {
+			continue
+		}
+		system, found := 0.0, false
+		for _, i := range group {
+			if !inHome(results[i].Target, home) && (!found || results[i].Score > system) {
+				system, found = results[i].Score, true
+			}
+		}
+		for _, i := range group {
+			r := &results[i]
+			if inHome(r.Target, home) {
+				r.Subtitle += " · User copy"
+				if preferSystem && found && r.Score >= system {
+					r.Score = system - 1
+				}
+			} else {
+				r.Subtitle += " · System copy"
+			}
+		}
+	}
+}
+
+// inHome reports whether path is inside the home folder home.
+func inHome(path, home string) bool {
+	if home == "" {
+		return false
+	}
+	rel, err := filepath.Rel(home, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
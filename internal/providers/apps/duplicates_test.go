@@ -0,0 +1,42 @@
+package apps
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+func TestDisambiguate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	user := filepath.Join(home, "Applications", "Slack.app")
+	found := func() []search.Result {
+		// The user's copy matches a little better.
+		return []search.Result{
+			{ID: providerID + ":" + user, Title: "Slack", Subtitle: user, Target: user, Score: 90},
+			{ID: providerID + ":/Applications/Slack.app", Title: "slack", Subtitle: "/Applications/Slack.app", Target: "/Applications/Slack.app", Score: 80},
+			{ID: providerID + ":/Applications/Mail.app", Title: "Mail", Subtitle: "/Applications/Mail.app", Target: "/Applications/Mail.app", Score: 50},
+		}
+	}
+	tests := []struct {
+		preferSystem bool
+		want         []string // subtitles, best first
+	}{
+		{true, []string{"/Applications/Slack.app · System copy", user + " · User copy", "/Applications/Mail.app"}},
+		{false, []string{user + " · User copy", "/Applications/Slack.app · System copy", "/Applications/Mail.app"}},
+	}
+	for _, tt := range tests {
+		results := found()
+		disambiguate(results, tt.preferSystem)
+		ranked(results)
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Subtitle)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("prefer system %v: subtitles %q, want %q", tt.preferSystem, got, tt.want)
+		}
+	}
+}
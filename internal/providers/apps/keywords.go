@@ -0,0 +1,76 @@
+package apps
+
+import (
+	"path/filepath"
+	"strings"
+
+	"changeme/internal/platform"
+)
+
+// keywordPenalty is subtracted from the score of a match on a keyword, so
+// an app found by its own name ranks just above one found by a synonym.
+const keywordPenalty = 10
+
+// builtinKeywords are synonyms shipped with Prism, keyed by app name.
+var builtinKeywords = map[string][]string{
+	"Google Chrome":      {"browser", "web"},
+	"Chromium":           {"browser", "web"},
+	"Firefox":            {"browser", "web"},
+	"Safari":             {"browser", "web"},
+	"Microsoft Edge":     {"browser", "web"},
+	"Brave Browser":      {"browser", "web"},
+	"Arc":                {"browser", "web"},
+	"Opera":              {"browser", "web"},
+	"Vivaldi":            {"browser", "web"},
+	"Terminal":           {"shell", "console"},
+	"iTerm":              {"terminal", "shell", "console"},
+	"Warp":               {"terminal", "shell", "console"},
+	"Alacritty":          {"terminal", "shell", "console"},
+	"kitty":              {"terminal", "shell", "console"},
+	"WezTerm":            {"terminal", "shell", "console"},
+	"Konsole":            {"terminal", "shell"},
+	"Windows Terminal":   {"shell", "console"},
+	"Visual Studio Code": {"editor", "code", "ide"},
+	"Sublime Text":       {"editor"},
+	"Zed":                {"editor", "code"},
+	"TextEdit":           {"editor", "text"},
+	"Notepad":            {"editor", "text"},
+	"Mail":               {"email", "inbox"},
+	"Microsoft Outlook":  {"email", "mail", "inbox"},
+	"Thunderbird":        {"email", "mail", "inbox"},
+	"Slack":              {"chat", "messages"},
+	"Discord":            {"chat", "messages"},
+	"Telegram":           {"chat", "messages"},
+	"Messages":           {"chat", "sms", "imessage"},
+	"System Settings":    {"preferences", "control panel"},
+	"Settings":           {"preferences", "control panel"},
+	"Activity Monitor":   {"task manager", "processes"},
+	"Task Manager":       {"activity monitor", "processes"},
+}
+
+// keywordsFor returns the keywords of app from table, whose keys are app
+// names, bundle file names or IDs such as desktop file IDs, in any case.
+func keywordsFor(app platform.App, table map[string][]string) []string {
+	bundle := strings.TrimSuffix(filepath.Base(app.Path), filepath.Ext(app.Path))
+	var keywords []string
+	for key, words := range table {
+		if strings.EqualFold(key, app.Name) || strings.EqualFold(key, bundle) || app.ID != "" && strings.EqualFold(key, app.ID) {
+			keywords = append(keywords, words...)
+		}
+	}
+	return keywords
+}
+
+// match scores query against app's name and keywords. A keyword match
+// scores keywordPenalty less than the same match on the name.
+func (p *Provider) match(query string, app platform.App, keywords map[string][]string) (int, bool) {
+	best, found := p.matcher.Match(query, app.Name)
+	for _, table := range []map[string][]string{builtinKeywords, keywords} {
+		for _, word := range keywordsFor(app, table) {
+			if score, ok := p.matcher.Match(query, word); ok && (!found || score-keywordPenalty > best) {
+				best, found = score-keywordPenalty, true
+			}
+		}
+	}
+	return best, found
+}
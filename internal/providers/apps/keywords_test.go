@@ -0,0 +1,78 @@
+package apps
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// newTestProvider returns a provider that has already indexed apps.
+func newTestProvider(names ...string) *Provider {
+	p := New(nil, fuzzy.Default())
+	p.apps = make(map[string]platform.App)
+	for _, name := range names {
+		path := "/Applications/" + name + ".app"
+		p.apps[providerID+":"+path] = platform.App{Name: name, Path: path}
+	}
+	p.loaded = true
+	return p
+}
+
+// ranked returns the titles of results from the highest score down.
+func ranked(results []search.Result) []string {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Title < results[j].Title
+	})
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	return out
+}
+
+func TestSearchKeywords(t *testing.T) {
+	p := newTestProvider("Safari", "Firefox", "Mail", "Thunderbird", "Obsidian", "Calculator")
+	p.SetKeywords(map[string][]string{"obsidian": {"notes", "wiki"}})
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"browser", []string{"Firefox", "Safari"}},
+		{"wiki", []string{"Obsidian"}},
+		// The app named Mail ranks above the one found by the synonym.
+		{"mail", []string{"Mail", "Thunderbird"}},
+		{"calc", []string{"Calculator"}},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ranked(results); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestKeywordsFor(t *testing.T) {
+	table := map[string][]string{
+		"Visual Studio Code":        {"vscode"},
+		"code":                      {"bundle"},
+		"com.microsoft.VSCode":      {"id"},
+		"Something Else Completely": {"no"},
+	}
+	app := platform.App{Name: "Visual Studio Code", Path: "/Applications/Code.app", ID: "com.microsoft.vscode"}
+	got := keywordsFor(app, table)
+	slices.Sort(got)
+	if want := []string{"bundle", "id", "vscode"}; !slices.Equal(got, want) {
+		t.Errorf("keywordsFor = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package apps
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// plistTimeout bounds converting a binary Info.plist with plutil.
+const plistTimeout = 2 * time.Second
+
+// Metadata is what Info.plist says about an application bundle.
+type Metadata struct {
+	Name     string `json:"name"`
+	BundleID string `json:"bundleID"`
+	// Version is the user-facing version, falling back to the build
+	// number when the bundle has none.
+	Version string `json:"version"`
+	// MinimumOS is the oldest macOS version the app runs on.
+	MinimumOS string `json:"minimumOS"`
+	Path      string `json:"path"`
+}
+
+// Metadata returns the metadata of the app bundle at path, reading its
+// Info.plist once per index build.
+func (p *Provider) Metadata(path string) (Metadata, error) {
+	id := providerID + ":" + path
+	p.mu.Lock()
+	m, ok := p.metadata[id]
+	p.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+	m, err := ReadMetadata(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	p.mu.Lock()
+	if p.metadata == nil {
+		p.metadata = make(map[string]Metadata)
+	}
+	p.metadata[id] = m
+	p.mu.Unlock()
+	return m, nil
+}
+
+// ReadMetadata reads the metadata of the app bundle at path from its
+// Contents/Info.plist, which may be XML or binary.
+func ReadMetadata(path string) (Metadata, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".app") {
+		return Metadata{}, fmt.Errorf("apps: %s is not an application bundle", path)
+	}
+	plist := filepath.Join(path, "Contents", "Info.plist")
+	data, err := os.ReadFile(plist)
+	if err != nil {
+		return Metadata{}, err
+	}
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		ctx, cancel := context.WithTimeout(context.Background(), plistTimeout)
+		defer cancel()
+		if data, err = exec.CommandContext(ctx, "plutil", "-convert", "xml1", "-o", "-", plist).Output(); err != nil {
+			return Metadata{}, fmt.Errorf("apps: converting %s: %w", plist, err)
+		}
+	}
+	values, err := plistStrings(data)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("apps: reading %s: %w", plist, err)
+	}
+	m := Metadata{
+		Name:      firstOf(values, "CFBundleDisplayName", "CFBundleName"),
+		BundleID:  values["CFBundleIdentifier"],
+		Version:   firstOf(values, "CFBundleShortVersionString", "CFBundleVersion"),
+		MinimumOS: values["LSMinimumSystemVersion"],
+		Path:      path,
+	}
+	if m.Name == "" {
+		m.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return m, nil
+}
+
+// firstOf returns the first of keys with a value in values.
+func firstOf(values map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := values[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// plistStrings returns the string values of the top-level dictionary of an
+// XML property list, keyed by their keys. Nested values are skipped.
+func plistStrings(data []byte) (map[string]string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	// Info.plist declares a DOCTYPE the decoder has no need to fetch.
+	d.Strict = false
+	values := make(map[string]string)
+	depth, key := 0, ""
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			// depth 1 is <plist> and 2 the top-level <dict>.
+			if depth != 3 {
+				continue
+			}
+			var text string
+			if t.Name.Local == "key" || t.Name.Local == "string" {
+				if err := d.DecodeElement(&text, &t); err != nil {
+					return nil, err
+				}
+				depth--
+			}
+			switch {
+			case t.Name.Local == "key":
+				key = text
+			case t.Name.Local == "string" && key != "":
+				values[key] = strings.TrimSpace(text)
+				key = ""
+			default:
+				key = ""
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// Fields adds what Info.plist says about an app result, for subtitle
+// templates: name, bundleID, version and minimumOS. Apps that are not
+// bundles, or whose Info.plist cannot be read, add nothing.
+func (p *Provider) Fields(r search.Result) map[string]string {
+	m, err := p.Metadata(r.Target)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{
+		"name":      m.Name,
+		"bundleID":  m.BundleID,
+		"version":   m.Version,
+		"minimumOS": m.MinimumOS,
+	}
+}
@@ -0,0 +1,86 @@
+package apps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// infoPlist is an XML Info.plist with the fields Metadata reads, and a
+// nested dictionary whose strings must not be taken for the app's.
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleDocumentTypes</key>
+	<array>
+		<dict>
+			<key>CFBundleTypeName</key>
+			<string>Document</string>
+		</dict>
+	</array>
+	<key>CFBundleIdentifier</key>
+	<string>com.microsoft.VSCode</string>
+	<key>CFBundleName</key>
+	<string>Code</string>
+	<key>CFBundleDisplayName</key>
+	<string>Visual Studio Code</string>
+	<key>NSAppTransportSecurity</key>
+	<dict>
+		<key>CFBundleShortVersionString</key>
+		<string>nested</string>
+	</dict>
+	<key>LSUIElement</key>
+	<false/>
+	<key>CFBundleShortVersionString</key>
+	<string> 1.90.2 </string>
+	<key>CFBundleVersion</key>
+	<string>1.90.2.24171</string>
+	<key>LSMinimumSystemVersion</key>
+	<string>10.15</string>
+</dict>
+</plist>
+`
+
+// writeBundle makes an app bundle in dir whose Info.plist is plist.
+func writeBundle(t *testing.T, dir, name, plist string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Join(path, "Contents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "Contents", "Info.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadMetadata(t *testing.T) {
+	dir := t.TempDir()
+	code := writeBundle(t, dir, "Visual Studio Code.app", infoPlist)
+	got, err := ReadMetadata(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Metadata{Name: "Visual Studio Code", BundleID: "com.microsoft.VSCode", Version: "1.90.2", MinimumOS: "10.15", Path: code}
+	if got != want {
+		t.Errorf("ReadMetadata = %+v, want %+v", got, want)
+	}
+
+	// Without a name or short version, the bundle's file name and the build
+	// number are used.
+	bare := writeBundle(t, dir, "Tool.app", `<plist><dict><key>CFBundleVersion</key><string>42</string></dict></plist>`)
+	got, err = ReadMetadata(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Metadata{Name: "Tool", Version: "42", Path: bare}); got != want {
+		t.Errorf("ReadMetadata = %+v, want %+v", got, want)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "notes.txt"), filepath.Join(dir, "Missing.app"), writeBundle(t, dir, "Broken.app", "<plist><dict><key>")} {
+		if _, err := ReadMetadata(path); err == nil {
+			t.Errorf("ReadMetadata(%s) succeeded", filepath.Base(path))
+		}
+	}
+}
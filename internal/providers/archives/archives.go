@@ -0,0 +1,285 @@
+// Package archives finds apps that only exist inside a zip or disk image in
+// the downloads folder, as right after downloading one, and offers to
+// extract or mount it and open the app. Only the zip's directory is read to
+// find its apps; disk images are offered by name, since seeing inside one
+// means mounting it. It is off unless enabled, and works on macOS only.
+package archives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "archives"
+	defaultDir = "~/Downloads"
+
+	actionExtract = "extract-open"
+	actionMount   = "mount-open"
+	actionReveal  = "reveal"
+
+	// penalty ranks an app still in its archive below an installed copy
+	// matched as well.
+	penalty = 10
+)
+
+// detachTimeout bounds detaching one volume when Prism quits.
+const detachTimeout = 10 * time.Second
+
+// mountPoint matches the mount points in the plist hdiutil attach prints.
+var mountPoint = regexp.MustCompile(`<key>mount-point</key>\s*<string>([^<]+)</string>`)
+
+// archive is a zip or disk image in the folder, with the apps found in it.
+type archive struct {
+	path    string
+	modTime time.Time
+	// apps are the bundles in a zip, relative to its root. Disk images
+	// have none listed.
+	apps []string
+	dmg  bool
+}
+
+// Provider offers the apps in archives in the downloads folder.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	enabled bool
+	dir     string
+	// zips caches the archives read, by path, until they change.
+	zips map[string]archive
+	// mounted are the volumes Prism attached, detached again by Close.
+	mounted []string
+}
+
+// New returns a provider that opens apps through plat and matches their
+// names with matcher. It finds nothing until enabled with SetEnabled.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher, dir: index.Expand(defaultDir), zips: make(map[string]archive)}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetEnabled turns looking inside archives on or off.
+func (p *Provider) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	p.enabled = enabled
+	p.mu.Unlock()
+}
+
+// SetDir sets the folder looked in, ~/Downloads when dir is empty.
+func (p *Provider) SetDir(dir string) {
+	if dir == "" {
+		dir = defaultDir
+	}
+	p.mu.Lock()
+	p.dir = index.Expand(dir)
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	p.mu.Lock()
+	enabled, dir := p.enabled, p.dir
+	p.mu.Unlock()
+	if !enabled || query == "" {
+		return nil, nil
+	}
+	var results []search.Result
+	for _, a := range p.list(dir) {
+		if a.dmg {
+			name := strings.TrimSuffix(filepath.Base(a.path), filepath.Ext(a.path))
+			if s, ok := p.matcher.Match(query, name); ok {
+				results = append(results, dmgResult(a, name, float64(s-penalty)))
+			}
+			continue
+		}
+		for _, app := range a.apps {
+			name := strings.TrimSuffix(filepath.Base(app), filepath.Ext(app))
+			if s, ok := p.matcher.Match(query, name); ok {
+				results = append(results, zipResult(a, app, name, float64(s-penalty)))
+			}
+		}
+	}
+	return results, nil
+}
+
+// zipResult offers the app at app inside the zip a.
+func zipResult(a archive, app, name string, score float64) search.Result {
+	return search.Result{
+		ID:       providerID + ":" + a.path + "!" + app,
+		Type:     "archive",
+		Title:    name,
+		Subtitle: "In " + filepath.Base(a.path),
+		Target:   a.path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionExtract, Title: "Extract & Open"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// dmgResult offers the disk image a, whose apps are not known until it is
+// mounted.
+func dmgResult(a archive, name string, score float64) search.Result {
+	return search.Result{
+		ID:       providerID + ":" + a.path,
+		Type:     "archive",
+		Title:    name,
+		Subtitle: "Disk image " + filepath.Base(a.path),
+		Target:   a.path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionMount, Title: "Mount & Open"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// list returns the zips holding apps, and every disk image, at the top of
+// dir. Zips unchanged since last time are not read again.
+func (p *Provider) list(dir string) []archive {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var archives []archive
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || ext != ".zip" && ext != ".dmg" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if ext == ".dmg" {
+			archives = append(archives, archive{path: path, modTime: info.ModTime(), dmg: true})
+			continue
+		}
+		seen[path] = true
+		p.mu.Lock()
+		a, ok := p.zips[path]
+		p.mu.Unlock()
+		if !ok || !a.modTime.Equal(info.ModTime()) {
+			apps, err := appsInZip(path)
+			if err != nil {
+				// Likely still downloading; try again once it changes.
+				log.Println(err)
+			}
+			a = archive{path: path, modTime: info.ModTime(), apps: apps}
+			p.mu.Lock()
+			p.zips[path] = a
+			p.mu.Unlock()
+		}
+		if len(a.apps) > 0 {
+			archives = append(archives, a)
+		}
+	}
+	p.mu.Lock()
+	for path := range p.zips {
+		if !seen[path] {
+			delete(p.zips, path)
+		}
+	}
+	p.mu.Unlock()
+	return archives
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	rest, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	archivePath, app, _ := strings.Cut(rest, "!")
+	switch actionID {
+	case actionExtract:
+		if app == "" {
+			return fmt.Errorf("archives: %s is not a zip", archivePath)
+		}
+		return p.extract(ctx, archivePath, app)
+	case actionMount:
+		return p.mount(ctx, archivePath)
+	case actionReveal:
+		return p.plat.Reveal(ctx, archivePath)
+	}
+	return fmt.Errorf("archives: unknown action %q", actionID)
+}
+
+// ExtractCommand returns the command line that extracts the zip at
+// archivePath next to it, the way Finder would, keeping the symbolic links
+// and permissions app bundles need.
+func ExtractCommand(archivePath string) []string {
+	return []string{"ditto", "-x", "-k", archivePath, filepath.Dir(archivePath)}
+}
+
+// extract extracts the zip at archivePath, unless the app at app within it
+// has been extracted already, and opens the app.
+func (p *Provider) extract(ctx context.Context, archivePath, app string) error {
+	dest := filepath.Join(filepath.Dir(archivePath), filepath.FromSlash(app))
+	if _, err := os.Stat(dest); errors.Is(err, os.ErrNotExist) {
+		argv := ExtractCommand(archivePath)
+		if out, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput(); err != nil {
+			return fmt.Errorf("archives: extracting %s: %s: %w", filepath.Base(archivePath), strings.TrimSpace(string(out)), err)
+		}
+	}
+	return p.plat.Open(ctx, dest)
+}
+
+// mount attaches the disk image at path and opens the first app at the top
+// of its volume. The volume shows in Finder, where it can be ejected, and
+// is detached when Prism quits if still mounted.
+func (p *Provider) mount(ctx context.Context, path string) error {
+	out, err := exec.CommandContext(ctx, "hdiutil", "attach", "-readonly", "-noautoopen", "-plist", path).Output()
+	if err != nil {
+		return fmt.Errorf("archives: mounting %s: %w", filepath.Base(path), err)
+	}
+	var volumes []string
+	for _, m := range mountPoint.FindAllSubmatch(out, -1) {
+		volumes = append(volumes, string(m[1]))
+	}
+	p.mu.Lock()
+	p.mounted = append(p.mounted, volumes...)
+	p.mu.Unlock()
+	for _, v := range volumes {
+		apps, _ := filepath.Glob(filepath.Join(v, "*.app"))
+		if len(apps) > 0 {
+			return p.plat.Open(ctx, apps[0])
+		}
+	}
+	return fmt.Errorf("archives: no app in %s", filepath.Base(path))
+}
+
+// Close detaches the volumes Prism mounted. Those still in use, such as by
+// an app running from them, stay mounted for the user to eject.
+func (p *Provider) Close() {
+	p.mu.Lock()
+	volumes := p.mounted
+	p.mounted = nil
+	p.mu.Unlock()
+	for _, v := range volumes {
+		ctx, cancel := context.WithTimeout(context.Background(), detachTimeout)
+		if out, err := exec.CommandContext(ctx, "hdiutil", "detach", v).CombinedOutput(); err != nil {
+			log.Printf("archives: detaching %s: %s: %v", v, strings.TrimSpace(string(out)), err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,104 @@
+package archives
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/fuzzy"
+)
+
+// writeZip makes a zip at path holding the named entries, which are
+// directories when they end in a slash.
+func writeZip(t *testing.T, path string, names ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for _, name := range names {
+		if _, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppsInZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Tool.zip")
+	writeZip(t, path,
+		"Tool/",
+		"Tool/Tool.app/",
+		"Tool/Tool.app/Contents/Info.plist",
+		// A helper inside the app is part of it.
+		"Tool/Tool.app/Contents/Helpers/Helper.app/Contents/Info.plist",
+		"__MACOSX/Tool/._Tool.app",
+		"Tool/README.txt",
+		// A file with an app's name is not a bundle.
+		"Notes.app",
+		// Too deep to be looked for.
+		"a/b/Deep.app/Contents/Info.plist",
+		"Second.app/Contents/MacOS/Second",
+	)
+	got, err := appsInZip(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Second.app", "Tool/Tool.app"}; !slices.Equal(got, want) {
+		t.Errorf("appsInZip = %v, want %v", got, want)
+	}
+	if _, err := appsInZip(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Error("appsInZip of a missing zip succeeded")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "Tool-1.2.zip")
+	writeZip(t, zipPath, "Tool.app/Contents/Info.plist")
+	writeZip(t, filepath.Join(dir, "photos.zip"), "photo.jpg")
+	if err := os.WriteFile(filepath.Join(dir, "Toolkit.dmg"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.zip"), []byte("PK partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := New(nil, fuzzy.Default())
+	p.SetDir(dir)
+
+	if got, _ := p.Search(context.Background(), "tool"); len(got) != 0 {
+		t.Errorf("Search while disabled = %v", got)
+	}
+	p.SetEnabled(true)
+	results, err := p.Search(context.Background(), "tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids, actions []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+		actions = append(actions, r.Actions[0].ID)
+	}
+	wantIDs := []string{"archives:" + zipPath + "!Tool.app", "archives:" + filepath.Join(dir, "Toolkit.dmg")}
+	if !slices.Equal(ids, wantIDs) {
+		t.Errorf("Search = %v, want %v", ids, wantIDs)
+	}
+	if want := []string{actionExtract, actionMount}; !slices.Equal(actions, want) {
+		t.Errorf("default actions = %v, want %v", actions, want)
+	}
+	if len(results) > 0 && (results[0].Title != "Tool" || results[0].Target != zipPath) {
+		t.Errorf("zip result = %+v", results[0])
+	}
+
+	want := []string{"ditto", "-x", "-k", zipPath, dir}
+	if got := ExtractCommand(zipPath); !slices.Equal(got, want) {
+		t.Errorf("ExtractCommand = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package archives
+
+import (
+	"archive/zip"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxDepth is how deep in a zip an app is looked for: at the top, or in
+// the one folder many zips wrap their contents in.
+const maxDepth = 2
+
+// appsInZip returns the paths, relative to the archive's root, of the app
+// bundles inside the zip at path, read from its directory without
+// extracting anything.
+func appsInZip(path string) ([]string, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("archives: %s: %w", path, err)
+	}
+	defer r.Close()
+	names := make([]string, len(r.File))
+	for i, f := range r.File {
+		names[i] = f.Name
+	}
+	return appBundles(names), nil
+}
+
+// appBundles picks the app bundles out of the entry names of a zip. Finder's
+// __MACOSX resource forks are skipped, as are apps nested in other apps.
+func appBundles(names []string) []string {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		parts := strings.Split(strings.TrimPrefix(path.Clean("/"+name), "/"), "/")
+		if parts[0] == "__MACOSX" {
+			continue
+		}
+		for i := 0; i < len(parts) && i < maxDepth; i++ {
+			if strings.EqualFold(path.Ext(parts[i]), ".app") {
+				// Only a directory entry, or a file within one, is a bundle.
+				if i < len(parts)-1 || strings.HasSuffix(name, "/") {
+					seen[strings.Join(parts[:i+1], "/")] = true
+				}
+				break
+			}
+		}
+	}
+	apps := make([]string, 0, len(seen))
+	for app := range seen {
+		apps = append(apps, app)
+	}
+	sort.Strings(apps)
+	return apps
+}
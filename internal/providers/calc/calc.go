@@ -0,0 +1,235 @@
+// Package calc evaluates arithmetic typed into the launcher, such as
+// "12,5 * 8" or "=pi/2", and answers date queries like
+// "25/12/2025 + 30 days". Numbers and dates are read and written in the
+// configured locale, so "1,000" is a thousand in English but one in German.
+package calc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"changeme/internal/config"
+	"changeme/internal/locale"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "calc"
+	// score ranks an answer above anything fuzzy-matched on the query.
+	score = 100
+	// precision is the most fraction digits shown, which hides the
+	// rounding noise of binary floating point.
+	precision = 10
+)
+
+// dateQuery is a date with an optional offset, such as "1/3/2025 - 2 weeks".
+var dateQuery = regexp.MustCompile(`^(\S+)(?:\s*([+-])\s*(\d+)\s*(days?|weeks?|months?|years?))?$`)
+
+// fourDigitPart finds a four-digit part of a date, which is its year.
+var fourDigitPart = regexp.MustCompile(`(?:^|[/.-])\d{4}(?:$|[/.-])`)
+
+// Provider evaluates expressions and dates.
+type Provider struct {
+	out clipboard.Output
+	now func() time.Time
+
+	mu  sync.Mutex
+	loc locale.Locale
+	// autoEval is how many operators a query without prefix needs to be
+	// evaluated; see SetAutoEval.
+	autoEval int
+}
+
+// New returns a calculator using the system locale until SetLocale is
+// called, copying or pasting answers through out.
+func New(out clipboard.Output) *Provider {
+	return &Provider{out: out, now: time.Now, loc: locale.System(), autoEval: 1}
+}
+
+// SetAutoEval sets how many operators a query typed without the "="
+// prefix needs before its answer is shown among the other results. Only
+// queries made purely of numbers and operators are evaluated this way;
+// zero or less leaves arithmetic to queries with the prefix.
+func (p *Provider) SetAutoEval(minOperators int) {
+	p.mu.Lock()
+	p.autoEval = minOperators
+	p.mu.Unlock()
+}
+
+// SetLocale sets the conventions numbers and dates are read and written in.
+func (p *Provider) SetLocale(loc locale.Locale) {
+	p.mu.Lock()
+	p.loc = loc
+	p.mu.Unlock()
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	p.mu.Lock()
+	loc, autoEval := p.loc, p.autoEval
+	p.mu.Unlock()
+	// A query routed by the "=" prefix asks for arithmetic even when it is
+	// not plain, as with constants such as pi, and is never a date.
+	explicit := search.Routed(ctx)
+	query = strings.TrimSpace(query)
+	if !explicit {
+		if r, ok := p.date(query, loc, autoEval); ok {
+			return []search.Result{r}, nil
+		}
+	}
+	if !IsExpression(query) || !explicit && !IsPlainMath(query, autoEval) {
+		return nil, nil
+	}
+	v, err := Evaluate(query, loc)
+	if err != nil {
+		return nil, nil
+	}
+	// Copied answers leave out thousands separators so they paste into
+	// other calculators and spreadsheets.
+	answer := loc.FormatNumber(v, precision, false)
+	return []search.Result{{
+		ID:       providerID + ":" + answer,
+		Type:     "text",
+		Title:    loc.FormatNumber(v, precision, true),
+		Subtitle: query,
+		Score:    score,
+		Actions:  clipboard.Actions(p.out.DefaultMode()),
+	}}, nil
+}
+
+// IsExpression reports whether s has a digit and an operator after its
+// first character, which keeps plain numbers and words out of the
+// calculator.
+func IsExpression(s string) bool {
+	if len(s) < 2 || !strings.ContainsAny(s, "0123456789") {
+		return false
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return strings.ContainsAny(s[size:], "+-−*×/÷%^")
+}
+
+// mathRunes are what plain arithmetic is written with besides digits:
+// operators, parentheses, spaces and the decimal and grouping separators
+// of any locale.
+const mathRunes = "+-−*×/÷%^() .,'\u00a0\u202f"
+
+// IsPlainMath reports whether s is clearly arithmetic rather than text that
+// happens to hold numbers: nothing but digits and mathRunes, with at least
+// minOperators binary operators. A lone number such as "2048", which may
+// well be the name of an app, is not.
+func IsPlainMath(s string, minOperators int) bool {
+	if minOperators <= 0 {
+		return false
+	}
+	operators := 0
+	// operand is set after a digit or closing parenthesis, where an
+	// operator is binary rather than a sign.
+	operand := false
+	for _, r := range s {
+		switch {
+		case isDigit(r) || r == ')':
+			operand = true
+		case strings.ContainsRune("+-−*×/÷%^", r):
+			if operand {
+				operators++
+			}
+			operand = false
+		case strings.ContainsRune(mathRunes, r):
+		default:
+			return false
+		}
+	}
+	return operators >= minOperators
+}
+
+// date answers a query made of a whole date, optionally moved by a number
+// of days, weeks, months or years. A date alone with a two-digit year, as
+// "12-5-25", reads as arithmetic as well, and is only taken for a date when
+// autoEval would not evaluate it.
+func (p *Provider) date(query string, loc locale.Locale, autoEval int) (search.Result, bool) {
+	m := dateQuery.FindStringSubmatch(query)
+	if m == nil {
+		return search.Result{}, false
+	}
+	if m[2] == "" && !fourDigitPart.MatchString(m[1]) && IsPlainMath(m[1], autoEval) {
+		return search.Result{}, false
+	}
+	now := p.now()
+	t, ok := loc.ParseDate(m[1], now)
+	if !ok {
+		return search.Result{}, false
+	}
+	if m[2] != "" {
+		n, _ := strconv.Atoi(m[3])
+		if m[2] == "-" {
+			n = -n
+		}
+		switch strings.TrimSuffix(m[4], "s") {
+		case "day":
+			t = t.AddDate(0, 0, n)
+		case "week":
+			t = t.AddDate(0, 0, 7*n)
+		case "month":
+			t = addMonths(t, n)
+		case "year":
+			t = addMonths(t, 12*n)
+		}
+	}
+	answer := loc.FormatDate(t)
+	return search.Result{
+		ID:       providerID + ":" + answer,
+		Type:     "text",
+		Title:    answer,
+		Subtitle: relative(t, now),
+		Score:    score,
+		Actions:  clipboard.Actions(p.out.DefaultMode()),
+	}, true
+}
+
+// addMonths moves t by n months, keeping to the last day of the month
+// where the day does not exist, so January 31 plus a month is the end of
+// February rather than early March.
+func addMonths(t time.Time, n int) time.Time {
+	moved := t.AddDate(0, n, 0)
+	if moved.Day() != t.Day() {
+		moved = moved.AddDate(0, 0, -moved.Day())
+	}
+	return moved
+}
+
+// relative describes how many days t is from today.
+func relative(t, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := int(t.Sub(today).Round(24*time.Hour) / (24 * time.Hour))
+	switch {
+	case days == 0:
+		return "Today"
+	case days == 1:
+		return "Tomorrow"
+	case days == -1:
+		return "Yesterday"
+	case days > 0:
+		return fmt.Sprintf("In %d days", days)
+	}
+	return fmt.Sprintf("%d days ago", -days)
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	answer, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, answer, actionID)
+	}
+	return fmt.Errorf("calc: unknown action %q", actionID)
+}
@@ -0,0 +1,74 @@
+package calc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"changeme/internal/locale"
+	"changeme/internal/search"
+)
+
+type discard struct{}
+
+func (discard) DefaultMode() string                                  { return "copy" }
+func (discard) Deliver(ctx context.Context, text, mode string) error { return nil }
+
+func newTestProvider() *Provider {
+	p := New(discard{})
+	p.SetLocale(locale.English)
+	p.now = func() time.Time { return time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC) }
+	return p
+}
+
+// searchRouted searches through an engine, so queries starting with "="
+// are routed to calc as they are in the app.
+func searchRouted(t *testing.T, p *Provider, query string) []search.Result {
+	t.Helper()
+	e := search.NewEngine(p)
+	e.SetPrefixes(map[string]string{"=": providerID})
+	results, err := e.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+	return results
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // the title of the answer, or "" for none
+	}{
+		{"=pi/2", "1.5707963268"},
+		{"=2*pi", "6.2831853072"},
+		{"pi/2", ""},
+		{"12*8", "96"},
+		{"10-2-5", "3"},
+		{"8/4/2", "1"},
+		{"=8/4/2", "1"},
+		{"=12/25/2025", "0.000237037"},
+		{"12/25/2025", "Thursday, December 25, 2025"},
+		{"12/25/2025 + 30 days", "Saturday, January 24, 2026"},
+		{"2025-12-25", "Thursday, December 25, 2025"},
+		{"2048", ""},
+	}
+	for _, tt := range tests {
+		results := searchRouted(t, newTestProvider(), tt.query)
+		got := ""
+		if len(results) > 0 {
+			got = results[0].Title
+		}
+		if got != tt.want {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDateNeedsAutoEvalOff(t *testing.T) {
+	p := newTestProvider()
+	p.SetAutoEval(0)
+	results := searchRouted(t, p, "12/25/25")
+	if len(results) != 1 || results[0].Title != "Thursday, December 25, 2025" {
+		t.Errorf("Search(%q) = %v, want the date", "12/25/25", results)
+	}
+}
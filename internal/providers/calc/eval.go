@@ -0,0 +1,207 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"changeme/internal/locale"
+)
+
+var errSyntax = errors.New("calc: not an expression")
+
+// token is a number, an operator or parenthesis, or a named constant.
+type token struct {
+	op    rune // 0 for numbers
+	value float64
+}
+
+// constants are the names usable in place of a number.
+var constants = map[string]float64{"pi": math.Pi, "π": math.Pi, "e": math.E}
+
+// operators maps the operators accepted in expressions to the ASCII ones
+// the parser knows.
+var operators = map[rune]rune{
+	'+': '+', '-': '-', '−': '-', '*': '*', '×': '*', '/': '/', '÷': '/',
+	'%': '%', '^': '^', '(': '(', ')': ')',
+}
+
+// tokenize splits s into tokens, reading numbers with loc's separators.
+func tokenize(s string, loc locale.Locale) ([]token, error) {
+	rs := []rune(s)
+	var toks []token
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case isDigit(r) || r == loc.Decimal:
+			j := i
+			for j < len(rs) && (isDigit(rs[j]) || rs[j] == loc.Decimal || isGroupAt(rs, j, loc)) {
+				j++
+			}
+			v, ok := loc.ParseNumber(string(rs[i:j]))
+			if !ok {
+				return nil, fmt.Errorf("%w: %q is not a number in %s", errSyntax, string(rs[i:j]), loc.Tag)
+			}
+			toks = append(toks, token{value: v})
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(rs) && unicode.IsLetter(rs[j]) {
+				j++
+			}
+			v, ok := constants[strings.ToLower(string(rs[i:j]))]
+			if !ok {
+				return nil, errSyntax
+			}
+			toks = append(toks, token{value: v})
+			i = j
+		default:
+			op, ok := operators[r]
+			if !ok {
+				return nil, errSyntax
+			}
+			toks = append(toks, token{op: op})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// isGroupAt reports whether rs[i] is a thousands separator inside a number.
+// A space only counts where the locale groups with spaces and it is
+// followed by exactly three digits, so "1 000" is one number but "2 3"
+// stays two.
+func isGroupAt(rs []rune, i int, loc locale.Locale) bool {
+	r := rs[i]
+	if !unicode.IsSpace(r) {
+		return r == loc.Group
+	}
+	if loc.Group != ' ' || i == 0 || !isDigit(rs[i-1]) || i+3 >= len(rs) {
+		return false
+	}
+	for k := i + 1; k <= i+3; k++ {
+		if !isDigit(rs[k]) {
+			return false
+		}
+	}
+	return i+4 == len(rs) || !isDigit(rs[i+4])
+}
+
+// parser evaluates tokens by recursive descent, with the usual precedence:
+// unary minus, then ^ (right-associative), then * / %, then + -.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// Evaluate computes the arithmetic expression s, reading numbers the way
+// loc writes them.
+func Evaluate(s string, loc locale.Locale) (float64, error) {
+	toks, err := tokenize(s, loc)
+	if err != nil {
+		return 0, err
+	}
+	p := &parser{toks: toks}
+	v, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos != len(p.toks) {
+		return 0, errSyntax
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("calc: result is not a number")
+	}
+	return v, nil
+}
+
+func (p *parser) peek() rune {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos].op
+	}
+	return -1
+}
+
+func (p *parser) sum() (float64, error) {
+	v, err := p.product()
+	for err == nil && (p.peek() == '+' || p.peek() == '-') {
+		op := p.peek()
+		p.pos++
+		var w float64
+		if w, err = p.product(); op == '+' {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+	return v, err
+}
+
+func (p *parser) product() (float64, error) {
+	v, err := p.unary()
+	for err == nil && (p.peek() == '*' || p.peek() == '/' || p.peek() == '%') {
+		op := p.peek()
+		p.pos++
+		var w float64
+		w, err = p.unary()
+		switch op {
+		case '*':
+			v *= w
+		case '/':
+			v /= w
+		case '%':
+			v = math.Mod(v, w)
+		}
+	}
+	return v, err
+}
+
+func (p *parser) unary() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.unary()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (float64, error) {
+	v, err := p.operand()
+	if err != nil || p.peek() != '^' {
+		return v, err
+	}
+	p.pos++
+	w, err := p.unary()
+	return math.Pow(v, w), err
+}
+
+func (p *parser) operand() (float64, error) {
+	switch p.peek() {
+	case 0:
+		v := p.toks[p.pos].value
+		p.pos++
+		return v, nil
+	case '(':
+		p.pos++
+		v, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errSyntax
+		}
+		p.pos++
+		return v, nil
+	}
+	return 0, errSyntax
+}
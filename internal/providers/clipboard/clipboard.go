@@ -0,0 +1,258 @@
+// Package clipboard keeps a history of copied text and offers it as results.
+package clipboard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/plaintext"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "clipboard"
+
+	// maxItems bounds the history; the oldest item is dropped first.
+	maxItems = 200
+	// pollInterval is how often the system clipboard is checked for changes.
+	pollInterval = 500 * time.Millisecond
+	// titleLen is the number of runes of an item shown as its title.
+	titleLen = 80
+)
+
+// Clipboard is the system clipboard.
+type Clipboard interface {
+	Text() (string, bool)
+}
+
+// RichClipboard is implemented by clipboards that can also read the HTML or
+// RTF representation of their contents.
+type RichClipboard interface {
+	Clipboard
+	Rich() (string, bool)
+}
+
+// Output delivers a chosen item. Modes double as action IDs: "copy" puts the
+// text on the clipboard, "paste" also pastes it into the frontmost app and
+// "paste-plain" does so with text stripped of formatting.
+type Output interface {
+	DefaultMode() string
+	Deliver(ctx context.Context, text, mode string) error
+}
+
+// ClipItem is one entry in the clipboard history.
+type ClipItem struct {
+	Text string `json:"text"`
+	// Rich is the HTML or RTF representation, when the clipboard offered
+	// one.
+	Rich   string    `json:"rich,omitempty"`
+	Copied time.Time `json:"copied"`
+}
+
+// plain returns the item's text without formatting. It prefers the rich
+// representation, which some apps pair with markup-free text that loses
+// structure such as line breaks, and also strips text that is itself HTML
+// or RTF source.
+func (c ClipItem) plain() string {
+	if c.Rich != "" {
+		if text, ok := plaintext.Strip(c.Rich); ok {
+			return text
+		}
+	}
+	text, _ := plaintext.Strip(c.Text)
+	return text
+}
+
+func (c ClipItem) id() string {
+	h := fnv.New64a()
+	h.Write([]byte(c.Text))
+	return fmt.Sprintf("%s:%x", providerID, h.Sum64())
+}
+
+// Provider records clipboard changes while Run is active and searches them.
+type Provider struct {
+	cb      Clipboard
+	out     Output
+	matcher *fuzzy.Matcher
+
+	mu    sync.Mutex
+	items []ClipItem // newest first
+	last  string
+}
+
+// New returns a clipboard history provider reading from cb, delivering chosen
+// items through out and ranking items with matcher.
+func New(cb Clipboard, out Output, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{cb: cb, out: out, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps clipboard history out of unprefixed queries.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// Run polls the clipboard until ctx is cancelled.
+func (p *Provider) Run(ctx context.Context) {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			if text, ok := p.cb.Text(); ok {
+				p.add(text, p.rich(), now)
+			}
+		}
+	}
+}
+
+// rich reads the clipboard's formatted representation, if it offers one.
+func (p *Provider) rich() string {
+	if rc, ok := p.cb.(RichClipboard); ok {
+		if rich, ok := rc.Rich(); ok {
+			return rich
+		}
+	}
+	return ""
+}
+
+// add moves text to the front of the history when it differs from the last
+// text seen.
+func (p *Provider) add(text, rich string, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if text == p.last || strings.TrimSpace(text) == "" {
+		return
+	}
+	p.last = text
+	items := []ClipItem{{Text: text, Rich: rich, Copied: now}}
+	for _, it := range p.items {
+		if it.Text != text {
+			items = append(items, it)
+		}
+	}
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+	p.items = items
+}
+
+// Items returns the history, newest first.
+func (p *Provider) Items() []ClipItem {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ClipItem(nil), p.items...)
+}
+
+// Restore replaces the history with items, newest first, as when importing
+// a backup.
+func (p *Provider) Restore(items []ClipItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+	p.items = append([]ClipItem(nil), items...)
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	p.mu.Lock()
+	items := append([]ClipItem(nil), p.items...)
+	p.mu.Unlock()
+
+	var results []search.Result
+	for i, it := range items {
+		score, ok := p.matcher.Match(query, it.Text)
+		if !ok {
+			continue
+		}
+		// Newer items win ties.
+		results = append(results, p.result(it, float64(score)-float64(i)/float64(maxItems)))
+	}
+	return results, nil
+}
+
+// Recent returns the newest limit items.
+func (p *Provider) Recent(ctx context.Context, limit int) []search.Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var results []search.Result
+	for _, it := range p.items {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, p.result(it, 0))
+	}
+	return results
+}
+
+func (p *Provider) Resolve(ctx context.Context, id string) (search.Result, bool) {
+	it, ok := p.item(id)
+	if !ok {
+		return search.Result{}, false
+	}
+	return p.result(it, 0), true
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	it, ok := p.item(r.ID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste:
+		return p.out.Deliver(ctx, it.Text, actionID)
+	case config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, it.plain(), actionID)
+	}
+	return fmt.Errorf("clipboard: unknown action %q", actionID)
+}
+
+func (p *Provider) item(id string) (ClipItem, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, it := range p.items {
+		if it.id() == id {
+			return it, true
+		}
+	}
+	return ClipItem{}, false
+}
+
+func (p *Provider) result(it ClipItem, score float64) search.Result {
+	title := strings.Join(strings.Fields(it.Text), " ")
+	if r := []rune(title); len(r) > titleLen {
+		title = string(r[:titleLen-1]) + "…"
+	}
+	return search.Result{
+		ID:       it.id(),
+		Type:     "clipboard",
+		Title:    title,
+		Subtitle: "Copied " + it.Copied.Format("Jan 2 15:04"),
+		Score:    score,
+		Actions:  Actions(p.out.DefaultMode()),
+	}
+}
+
+// Actions returns the copy, paste and plain paste actions with the mode's
+// action first, so it runs on enter. Other text providers use it for
+// consistent actions.
+func Actions(defaultMode string) []search.Action {
+	copyAction := search.Action{ID: config.ClipboardActionCopy, Title: "Copy"}
+	pasteAction := search.Action{ID: config.ClipboardActionPaste, Title: "Paste", HideBeforeRun: true}
+	plainAction := search.Action{ID: config.ClipboardActionPastePlain, Title: "Paste as Plain Text", HideBeforeRun: true}
+	switch defaultMode {
+	case config.ClipboardActionPaste:
+		return []search.Action{pasteAction, copyAction, plainAction}
+	case config.ClipboardActionPastePlain:
+		return []search.Action{plainAction, copyAction, pasteAction}
+	}
+	return []search.Action{copyAction, pasteAction, plainAction}
+}
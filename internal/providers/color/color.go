@@ -0,0 +1,84 @@
+// Package color recognizes colors typed as hex, rgb(), hsl() or a CSS name
+// and offers them in every format, each with a swatch and ready to copy.
+package color
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const providerID = "color"
+
+// Score of a color written out in a color syntax, which is almost certainly
+// what the user meant, and of a bare color name such as "tan", which may
+// just as well be the start of something else.
+const (
+	scoreSyntax = 100
+	scoreName   = 10
+)
+
+// Provider converts the color in a query to other formats.
+type Provider struct {
+	out clipboard.Output
+}
+
+// New returns a color provider that copies or pastes through out.
+func New(out clipboard.Output) *Provider {
+	return &Provider{out: out}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	q := strings.TrimSpace(query)
+	q = strings.TrimSpace(strings.TrimPrefix(q, "color "))
+	c, ok := Parse(q)
+	if !ok {
+		return nil, nil
+	}
+	score := float64(scoreSyntax)
+	name, hasName := c.Name()
+	if _, isName := named[strings.ToLower(q)]; isName {
+		// Keep the name as typed rather than an alias such as aqua for cyan.
+		name, score = strings.ToLower(q), scoreName
+	}
+	formats := []struct{ value, label string }{
+		{c.Hex(), "Hex"},
+		{c.RGB(), "RGB"},
+		{c.HSLString(), "HSL"},
+	}
+	if hasName {
+		formats = append(formats, struct{ value, label string }{name, "CSS Name"})
+	}
+	actions := clipboard.Actions(p.out.DefaultMode())
+	results := make([]search.Result, len(formats))
+	for i, f := range formats {
+		results[i] = search.Result{
+			ID:       providerID + ":" + f.value,
+			Type:     "color",
+			Title:    f.value,
+			Subtitle: f.label,
+			Swatch:   c.Hex(),
+			Score:    score - float64(i),
+			Actions:  actions,
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	text, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, text, actionID)
+	}
+	return fmt.Errorf("color: unknown action %q", actionID)
+}
@@ -0,0 +1,252 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RGBA is a color with 8-bit channels and an alpha between 0 and 1.
+type RGBA struct {
+	R, G, B uint8
+	A       float64
+}
+
+// Parse reads a color in CSS syntax: #rgb, #rgba, #rrggbb and #rrggbbaa
+// hex, the rgb(), rgba(), hsl() and hsla() functions with comma or space
+// separated arguments, or a named color.
+func Parse(s string) (RGBA, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := named[s]; ok {
+		return c, true
+	}
+	if fn, args, ok := function(s); ok {
+		switch fn {
+		case "rgb", "rgba":
+			return parseRGB(args)
+		case "hsl", "hsla":
+			return parseHSL(args)
+		}
+		return RGBA{}, false
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		return parseHex(hex)
+	}
+	return RGBA{}, false
+}
+
+// function splits "rgb(1, 2, 3)" into its name and arguments.
+func function(s string) (string, []string, bool) {
+	name, rest, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(rest, ")") {
+		return "", nil, false
+	}
+	rest = strings.TrimSuffix(rest, ")")
+	rest = strings.ReplaceAll(rest, "/", " ")
+	rest = strings.ReplaceAll(rest, ",", " ")
+	return strings.TrimSpace(name), strings.Fields(rest), true
+}
+
+func parseHex(hex string) (RGBA, bool) {
+	switch len(hex) {
+	case 3, 4:
+		var long strings.Builder
+		for _, r := range hex {
+			long.WriteRune(r)
+			long.WriteRune(r)
+		}
+		hex = long.String()
+	case 6, 8:
+	default:
+		return RGBA{}, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGBA{}, false
+	}
+	if len(hex) == 6 {
+		return RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 1}, true
+	}
+	return RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: float64(uint8(v)) / 255}, true
+}
+
+func parseRGB(args []string) (RGBA, bool) {
+	if len(args) != 3 && len(args) != 4 {
+		return RGBA{}, false
+	}
+	var ch [3]uint8
+	for i := range ch {
+		v, ok := channel(args[i], 255)
+		if !ok {
+			return RGBA{}, false
+		}
+		ch[i] = uint8(math.Round(v))
+	}
+	a, ok := alpha(args[3:])
+	if !ok {
+		return RGBA{}, false
+	}
+	return RGBA{R: ch[0], G: ch[1], B: ch[2], A: a}, true
+}
+
+func parseHSL(args []string) (RGBA, bool) {
+	if len(args) != 3 && len(args) != 4 {
+		return RGBA{}, false
+	}
+	h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+	if err != nil {
+		return RGBA{}, false
+	}
+	sat, ok1 := percent(args[1])
+	light, ok2 := percent(args[2])
+	a, ok3 := alpha(args[3:])
+	if !ok1 || !ok2 || !ok3 {
+		return RGBA{}, false
+	}
+	c := FromHSL(h, sat, light)
+	c.A = a
+	return c, true
+}
+
+// channel reads a number, or a percentage of max, clamped to [0, max].
+func channel(s string, max float64) (float64, bool) {
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, false
+		}
+		return clamp(v/100, 0, 1) * max, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return clamp(v, 0, max), true
+}
+
+// percent reads "50%" as 0.5. The percent sign may be left out.
+func percent(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return clamp(v/100, 0, 1), true
+}
+
+// alpha reads the optional alpha argument, a number or a percentage.
+func alpha(args []string) (float64, bool) {
+	if len(args) == 0 {
+		return 1, true
+	}
+	return channel(args[0], 1)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// HSL returns the color's hue in degrees and its saturation and lightness
+// between 0 and 1.
+func (c RGBA) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// FromHSL returns the opaque color with hue h in degrees and saturation s
+// and lightness l between 0 and 1.
+func FromHSL(h, s, l float64) RGBA {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return RGBA{R: to8(r + m), G: to8(g + m), B: to8(b + m), A: 1}
+}
+
+func to8(v float64) uint8 {
+	return uint8(math.Round(clamp(v, 0, 1) * 255))
+}
+
+// Hex formats the color as #rrggbb, or #rrggbbaa when it is translucent.
+func (c RGBA) Hex() string {
+	if c.A < 1 {
+		return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, to8(c.A))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// RGB formats the color as rgb(r, g, b), or rgba() when it is translucent.
+func (c RGBA) RGB() string {
+	if c.A < 1 {
+		return fmt.Sprintf("rgba(%d, %d, %d, %s)", c.R, c.G, c.B, formatAlpha(c.A))
+	}
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// HSLString formats the color as hsl(h, s%, l%), or hsla() when it is
+// translucent. Components are rounded to whole numbers.
+func (c RGBA) HSLString() string {
+	h, s, l := c.HSL()
+	hs, ss, ls := math.Round(h), math.Round(s*100), math.Round(l*100)
+	if hs == 360 {
+		hs = 0
+	}
+	if c.A < 1 {
+		return fmt.Sprintf("hsla(%g, %g%%, %g%%, %s)", hs, ss, ls, formatAlpha(c.A))
+	}
+	return fmt.Sprintf("hsl(%g, %g%%, %g%%)", hs, ss, ls)
+}
+
+func formatAlpha(a float64) string {
+	return strconv.FormatFloat(math.Round(a*100)/100, 'f', -1, 64)
+}
+
+// Name returns the CSS name of an opaque color that has one.
+func (c RGBA) Name() (string, bool) {
+	if c.A < 1 {
+		return "", false
+	}
+	for _, name := range namesInOrder {
+		if v := named[name]; v.R == c.R && v.G == c.G && v.B == c.B {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,95 @@
+package color
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	blue := RGBA{R: 52, G: 152, B: 219, A: 1}
+	tests := []struct {
+		in   string
+		want RGBA
+		ok   bool
+	}{
+		{"#3498db", blue, true},
+		{"#3498DB", blue, true},
+		{"#fff", RGBA{255, 255, 255, 1}, true},
+		{"#3498db80", RGBA{52, 152, 219, 128.0 / 255}, true},
+		{"rgb(52,152,219)", blue, true},
+		{"rgb(52 152 219)", blue, true},
+		{"rgba(52, 152, 219, 0.5)", RGBA{52, 152, 219, 0.5}, true},
+		{"rgb(100%, 0%, 0%)", RGBA{255, 0, 0, 1}, true},
+		{"rgb(300, -5, 0)", RGBA{255, 0, 0, 1}, true},
+		// The whole-number percentages are a little off #3498db.
+		{"hsl(204, 70%, 53%)", RGBA{51, 152, 219, 1}, true},
+		{"hsl(204deg 70% 53% / 50%)", RGBA{51, 152, 219, 0.5}, true},
+		{"RebeccaPurple", RGBA{102, 51, 153, 1}, true},
+		{" tomato ", RGBA{255, 99, 71, 1}, true},
+		{"#12345", RGBA{}, false},
+		{"#ggg", RGBA{}, false},
+		{"rgb(1, 2)", RGBA{}, false},
+		{"cmyk(0, 0, 0, 0)", RGBA{}, false},
+		{"notacolor", RGBA{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		c             RGBA
+		hex, rgb, hsl string
+	}{
+		{RGBA{52, 152, 219, 1}, "#3498db", "rgb(52, 152, 219)", "hsl(204, 70%, 53%)"},
+		{RGBA{255, 255, 255, 1}, "#ffffff", "rgb(255, 255, 255)", "hsl(0, 0%, 100%)"},
+		{RGBA{255, 0, 0, 0.5}, "#ff000080", "rgba(255, 0, 0, 0.5)", "hsla(0, 100%, 50%, 0.5)"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Hex(); got != tt.hex {
+			t.Errorf("%+v.Hex() = %q, want %q", tt.c, got, tt.hex)
+		}
+		if got := tt.c.RGB(); got != tt.rgb {
+			t.Errorf("%+v.RGB() = %q, want %q", tt.c, got, tt.rgb)
+		}
+		if got := tt.c.HSLString(); got != tt.hsl {
+			t.Errorf("%+v.HSLString() = %q, want %q", tt.c, got, tt.hsl)
+		}
+	}
+}
+
+// TestRoundTrip converts through each representation and back.
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{"#3498db", "#000000", "#ffffff", "#808080", "#ff0000", "#00ff00", "#0000ff", "#c0ffee"} {
+		c, _ := Parse(s)
+		for _, repr := range []string{c.Hex(), c.RGB()} {
+			if got, ok := Parse(repr); !ok || got != c {
+				t.Errorf("Parse(%q) = %+v, want %+v", repr, got, c)
+			}
+		}
+		h, sat, l := c.HSL()
+		if got := FromHSL(h, sat, l); got != c {
+			t.Errorf("FromHSL(%s.HSL()) = %s, want %s", s, got.Hex(), s)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		c    RGBA
+		want string
+		ok   bool
+	}{
+		{RGBA{255, 99, 71, 1}, "tomato", true},
+		// aqua and cyan are the same color; the first alphabetically wins.
+		{RGBA{0, 255, 255, 1}, "aqua", true},
+		{RGBA{52, 152, 219, 1}, "", false},
+		{RGBA{255, 99, 71, 0.5}, "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.c.Name(); got != tt.want || ok != tt.ok {
+			t.Errorf("%+v.Name() = %q, %v; want %q, %v", tt.c, got, ok, tt.want, tt.ok)
+		}
+	}
+}
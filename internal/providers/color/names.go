@@ -0,0 +1,166 @@
+package color
+
+import "slices"
+
+// named holds the CSS named colors, keyed by lowercase name.
+var named = map[string]RGBA{
+	"aliceblue":            {0xf0, 0xf8, 0xff, 1},
+	"antiquewhite":         {0xfa, 0xeb, 0xd7, 1},
+	"aqua":                 {0x00, 0xff, 0xff, 1},
+	"aquamarine":           {0x7f, 0xff, 0xd4, 1},
+	"azure":                {0xf0, 0xff, 0xff, 1},
+	"beige":                {0xf5, 0xf5, 0xdc, 1},
+	"bisque":               {0xff, 0xe4, 0xc4, 1},
+	"black":                {0x00, 0x00, 0x00, 1},
+	"blanchedalmond":       {0xff, 0xeb, 0xcd, 1},
+	"blue":                 {0x00, 0x00, 0xff, 1},
+	"blueviolet":           {0x8a, 0x2b, 0xe2, 1},
+	"brown":                {0xa5, 0x2a, 0x2a, 1},
+	"burlywood":            {0xde, 0xb8, 0x87, 1},
+	"cadetblue":            {0x5f, 0x9e, 0xa0, 1},
+	"chartreuse":           {0x7f, 0xff, 0x00, 1},
+	"chocolate":            {0xd2, 0x69, 0x1e, 1},
+	"coral":                {0xff, 0x7f, 0x50, 1},
+	"cornflowerblue":       {0x64, 0x95, 0xed, 1},
+	"cornsilk":             {0xff, 0xf8, 0xdc, 1},
+	"crimson":              {0xdc, 0x14, 0x3c, 1},
+	"cyan":                 {0x00, 0xff, 0xff, 1},
+	"darkblue":             {0x00, 0x00, 0x8b, 1},
+	"darkcyan":             {0x00, 0x8b, 0x8b, 1},
+	"darkgoldenrod":        {0xb8, 0x86, 0x0b, 1},
+	"darkgray":             {0xa9, 0xa9, 0xa9, 1},
+	"darkgreen":            {0x00, 0x64, 0x00, 1},
+	"darkgrey":             {0xa9, 0xa9, 0xa9, 1},
+	"darkkhaki":            {0xbd, 0xb7, 0x6b, 1},
+	"darkmagenta":          {0x8b, 0x00, 0x8b, 1},
+	"darkolivegreen":       {0x55, 0x6b, 0x2f, 1},
+	"darkorange":           {0xff, 0x8c, 0x00, 1},
+	"darkorchid":           {0x99, 0x32, 0xcc, 1},
+	"darkred":              {0x8b, 0x00, 0x00, 1},
+	"darksalmon":           {0xe9, 0x96, 0x7a, 1},
+	"darkseagreen":         {0x8f, 0xbc, 0x8f, 1},
+	"darkslateblue":        {0x48, 0x3d, 0x8b, 1},
+	"darkslategray":        {0x2f, 0x4f, 0x4f, 1},
+	"darkslategrey":        {0x2f, 0x4f, 0x4f, 1},
+	"darkturquoise":        {0x00, 0xce, 0xd1, 1},
+	"darkviolet":           {0x94, 0x00, 0xd3, 1},
+	"deeppink":             {0xff, 0x14, 0x93, 1},
+	"deepskyblue":          {0x00, 0xbf, 0xff, 1},
+	"dimgray":              {0x69, 0x69, 0x69, 1},
+	"dimgrey":              {0x69, 0x69, 0x69, 1},
+	"dodgerblue":           {0x1e, 0x90, 0xff, 1},
+	"firebrick":            {0xb2, 0x22, 0x22, 1},
+	"floralwhite":          {0xff, 0xfa, 0xf0, 1},
+	"forestgreen":          {0x22, 0x8b, 0x22, 1},
+	"fuchsia":              {0xff, 0x00, 0xff, 1},
+	"gainsboro":            {0xdc, 0xdc, 0xdc, 1},
+	"ghostwhite":           {0xf8, 0xf8, 0xff, 1},
+	"gold":                 {0xff, 0xd7, 0x00, 1},
+	"goldenrod":            {0xda, 0xa5, 0x20, 1},
+	"gray":                 {0x80, 0x80, 0x80, 1},
+	"green":                {0x00, 0x80, 0x00, 1},
+	"greenyellow":          {0xad, 0xff, 0x2f, 1},
+	"grey":                 {0x80, 0x80, 0x80, 1},
+	"honeydew":             {0xf0, 0xff, 0xf0, 1},
+	"hotpink":              {0xff, 0x69, 0xb4, 1},
+	"indianred":            {0xcd, 0x5c, 0x5c, 1},
+	"indigo":               {0x4b, 0x00, 0x82, 1},
+	"ivory":                {0xff, 0xff, 0xf0, 1},
+	"khaki":                {0xf0, 0xe6, 0x8c, 1},
+	"lavender":             {0xe6, 0xe6, 0xfa, 1},
+	"lavenderblush":        {0xff, 0xf0, 0xf5, 1},
+	"lawngreen":            {0x7c, 0xfc, 0x00, 1},
+	"lemonchiffon":         {0xff, 0xfa, 0xcd, 1},
+	"lightblue":            {0xad, 0xd8, 0xe6, 1},
+	"lightcoral":           {0xf0, 0x80, 0x80, 1},
+	"lightcyan":            {0xe0, 0xff, 0xff, 1},
+	"lightgoldenrodyellow": {0xfa, 0xfa, 0xd2, 1},
+	"lightgray":            {0xd3, 0xd3, 0xd3, 1},
+	"lightgreen":           {0x90, 0xee, 0x90, 1},
+	"lightgrey":            {0xd3, 0xd3, 0xd3, 1},
+	"lightpink":            {0xff, 0xb6, 0xc1, 1},
+	"lightsalmon":          {0xff, 0xa0, 0x7a, 1},
+	"lightseagreen":        {0x20, 0xb2, 0xaa, 1},
+	"lightskyblue":         {0x87, 0xce, 0xfa, 1},
+	"lightslategray":       {0x77, 0x88, 0x99, 1},
+	"lightslategrey":       {0x77, 0x88, 0x99, 1},
+	"lightsteelblue":       {0xb0, 0xc4, 0xde, 1},
+	"lightyellow":          {0xff, 0xff, 0xe0, 1},
+	"lime":                 {0x00, 0xff, 0x00, 1},
+	"limegreen":            {0x32, 0xcd, 0x32, 1},
+	"linen":                {0xfa, 0xf0, 0xe6, 1},
+	"magenta":              {0xff, 0x00, 0xff, 1},
+	"maroon":               {0x80, 0x00, 0x00, 1},
+	"mediumaquamarine":     {0x66, 0xcd, 0xaa, 1},
+	"mediumblue":           {0x00, 0x00, 0xcd, 1},
+	"mediumorchid":         {0xba, 0x55, 0xd3, 1},
+	"mediumpurple":         {0x93, 0x70, 0xdb, 1},
+	"mediumseagreen":       {0x3c, 0xb3, 0x71, 1},
+	"mediumslateblue":      {0x7b, 0x68, 0xee, 1},
+	"mediumspringgreen":    {0x00, 0xfa, 0x9a, 1},
+	"mediumturquoise":      {0x48, 0xd1, 0xcc, 1},
+	"mediumvioletred":      {0xc7, 0x15, 0x85, 1},
+	"midnightblue":         {0x19, 0x19, 0x70, 1},
+	"mintcream":            {0xf5, 0xff, 0xfa, 1},
+	"mistyrose":            {0xff, 0xe4, 0xe1, 1},
+	"moccasin":             {0xff, 0xe4, 0xb5, 1},
+	"navajowhite":          {0xff, 0xde, 0xad, 1},
+	"navy":                 {0x00, 0x00, 0x80, 1},
+	"oldlace":              {0xfd, 0xf5, 0xe6, 1},
+	"olive":                {0x80, 0x80, 0x00, 1},
+	"olivedrab":            {0x6b, 0x8e, 0x23, 1},
+	"orange":               {0xff, 0xa5, 0x00, 1},
+	"orangered":            {0xff, 0x45, 0x00, 1},
+	"orchid":               {0xda, 0x70, 0xd6, 1},
+	"palegoldenrod":        {0xee, 0xe8, 0xaa, 1},
+	"palegreen":            {0x98, 0xfb, 0x98, 1},
+	"paleturquoise":        {0xaf, 0xee, 0xee, 1},
+	"palevioletred":        {0xdb, 0x70, 0x93, 1},
+	"papayawhip":           {0xff, 0xef, 0xd5, 1},
+	"peachpuff":            {0xff, 0xda, 0xb9, 1},
+	"peru":                 {0xcd, 0x85, 0x3f, 1},
+	"pink":                 {0xff, 0xc0, 0xcb, 1},
+	"plum":                 {0xdd, 0xa0, 0xdd, 1},
+	"powderblue":           {0xb0, 0xe0, 0xe6, 1},
+	"purple":               {0x80, 0x00, 0x80, 1},
+	"rebeccapurple":        {0x66, 0x33, 0x99, 1},
+	"red":                  {0xff, 0x00, 0x00, 1},
+	"rosybrown":            {0xbc, 0x8f, 0x8f, 1},
+	"royalblue":            {0x41, 0x69, 0xe1, 1},
+	"saddlebrown":          {0x8b, 0x45, 0x13, 1},
+	"salmon":               {0xfa, 0x80, 0x72, 1},
+	"sandybrown":           {0xf4, 0xa4, 0x60, 1},
+	"seagreen":             {0x2e, 0x8b, 0x57, 1},
+	"seashell":             {0xff, 0xf5, 0xee, 1},
+	"sienna":               {0xa0, 0x52, 0x2d, 1},
+	"silver":               {0xc0, 0xc0, 0xc0, 1},
+	"skyblue":              {0x87, 0xce, 0xeb, 1},
+	"slateblue":            {0x6a, 0x5a, 0xcd, 1},
+	"slategray":            {0x70, 0x80, 0x90, 1},
+	"slategrey":            {0x70, 0x80, 0x90, 1},
+	"snow":                 {0xff, 0xfa, 0xfa, 1},
+	"springgreen":          {0x00, 0xff, 0x7f, 1},
+	"steelblue":            {0x46, 0x82, 0xb4, 1},
+	"tan":                  {0xd2, 0xb4, 0x8c, 1},
+	"teal":                 {0x00, 0x80, 0x80, 1},
+	"thistle":              {0xd8, 0xbf, 0xd8, 1},
+	"tomato":               {0xff, 0x63, 0x47, 1},
+	"turquoise":            {0x40, 0xe0, 0xd0, 1},
+	"violet":               {0xee, 0x82, 0xee, 1},
+	"wheat":                {0xf5, 0xde, 0xb3, 1},
+	"white":                {0xff, 0xff, 0xff, 1},
+	"whitesmoke":           {0xf5, 0xf5, 0xf5, 1},
+	"yellow":               {0xff, 0xff, 0x00, 1},
+	"yellowgreen":          {0x9a, 0xcd, 0x32, 1},
+}
+
+// namesInOrder lists the named colors alphabetically, so Name picks the
+// same one of two aliases, such as aqua and cyan, every time.
+var namesInOrder = func() []string {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}()
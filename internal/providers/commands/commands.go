@@ -0,0 +1,167 @@
+// Package commands offers Prism's own commands, such as reloading the config
+// or quitting, as results.
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "prism"
+	actionRun  = "run"
+)
+
+// Command is one of Prism's own commands.
+type Command struct {
+	// ID is unique among commands, e.g. "reload-config".
+	ID       string
+	Title    string
+	Subtitle string
+	// Keywords are extra words the command is found by.
+	Keywords []string
+	Run      func(ctx context.Context) error
+}
+
+// Provider searches the registered commands. Features add their commands
+// with Register, so the catalog lives in one place.
+type Provider struct {
+	matcher *fuzzy.Matcher
+
+	mu       sync.Mutex
+	commands map[string]Command
+}
+
+// New returns an empty command catalog ranked with matcher.
+func New(matcher *fuzzy.Matcher) *Provider {
+	return &Provider{matcher: matcher, commands: make(map[string]Command)}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// Register adds c to the catalog, replacing any command with the same ID.
+func (p *Provider) Register(c Command) {
+	p.mu.Lock()
+	p.commands[c.ID] = c
+	p.mu.Unlock()
+}
+
+// Commands returns the catalog ordered by title.
+func (p *Provider) Commands() []Command {
+	p.mu.Lock()
+	commands := make([]Command, 0, len(p.commands))
+	for _, c := range p.commands {
+		commands = append(commands, c)
+	}
+	p.mu.Unlock()
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Title < commands[j].Title })
+	return commands
+}
+
+// Search matches the query against each command's title and keywords,
+// optionally preceded by "prism", so both "reload" and "prism reload config"
+// find the reload command. "prism" alone lists every command.
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	rest, prefixed := cutPrism(query)
+	var results []search.Result
+	for _, c := range p.Commands() {
+		score, ok := p.match(rest, c)
+		if !ok {
+			continue
+		}
+		if prefixed {
+			// The user asked for Prism's commands by name.
+			score += 10
+		}
+		results = append(results, result(c, float64(score)))
+	}
+	return results, nil
+}
+
+// cutPrism strips a leading "prism" word from query.
+func cutPrism(query string) (string, bool) {
+	word, rest, _ := strings.Cut(query, " ")
+	if strings.EqualFold(word, providerID) {
+		return strings.TrimSpace(rest), true
+	}
+	return query, false
+}
+
+func (p *Provider) match(query string, c Command) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	best, found := 0, false
+	for _, text := range append([]string{c.Title}, c.Keywords...) {
+		if score, ok := p.matcher.Match(query, text); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionRun {
+		return fmt.Errorf("commands: unknown action %q", actionID)
+	}
+	err := p.Run(ctx, strings.TrimPrefix(r.ID, providerID+":"))
+	if errors.Is(err, errUnknownCommand) {
+		return search.ErrUnknownResult
+	}
+	return err
+}
+
+var errUnknownCommand = errors.New("commands: unknown command")
+
+// Run runs the command with the given ID, for callers other than the
+// result list such as key sequences.
+func (p *Provider) Run(ctx context.Context, id string) error {
+	p.mu.Lock()
+	c, ok := p.commands[id]
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w %q", errUnknownCommand, id)
+	}
+	return c.Run(ctx)
+}
+
+// Has reports whether a command with the given ID is registered.
+func (p *Provider) Has(id string) bool {
+	p.mu.Lock()
+	_, ok := p.commands[id]
+	p.mu.Unlock()
+	return ok
+}
+
+// Resolve lets commands be favorites.
+func (p *Provider) Resolve(ctx context.Context, id string) (search.Result, bool) {
+	p.mu.Lock()
+	c, ok := p.commands[strings.TrimPrefix(id, providerID+":")]
+	p.mu.Unlock()
+	if !ok {
+		return search.Result{}, false
+	}
+	return result(c, 0), true
+}
+
+func result(c Command, score float64) search.Result {
+	return search.Result{
+		ID:       providerID + ":" + c.ID,
+		Type:     "command",
+		Title:    c.Title,
+		Subtitle: c.Subtitle,
+		Score:    score,
+		Actions:  []search.Action{{ID: actionRun, Title: "Run"}},
+	}
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/search"
+)
+
+func newCatalog(ran *[]string) *Provider {
+	p := New(fuzzy.Default())
+	for _, c := range []Command{
+		{ID: "reload-config", Title: "Reload Config", Keywords: []string{"refresh", "settings"}},
+		{ID: "quit", Title: "Quit Prism", Keywords: []string{"exit"}},
+		{ID: "open-settings", Title: "Open Settings"},
+	} {
+		id := c.ID
+		c.Run = func(ctx context.Context) error {
+			*ran = append(*ran, id)
+			return nil
+		}
+		p.Register(c)
+	}
+	return p
+}
+
+func titles(results []search.Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	var ran []string
+	p := newCatalog(&ran)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"prism", []string{"Open Settings", "Quit Prism", "Reload Config"}},
+		{"reload", []string{"Reload Config"}},
+		{"prism reload config", []string{"Reload Config"}},
+		{"exit", []string{"Quit Prism"}},
+		{"settings", []string{"Open Settings", "Reload Config"}},
+		{"", nil},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(results); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if got := p.Commands(); len(got) != 3 || got[0].Title != "Open Settings" || got[2].Title != "Reload Config" {
+		t.Errorf("Commands not ordered by title: %+v", got)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	var ran []string
+	p := newCatalog(&ran)
+	results, err := p.Search(context.Background(), "prism quit")
+	if err != nil || len(results) == 0 {
+		t.Fatalf("Search = %v, %v", results, err)
+	}
+	if err := p.Activate(context.Background(), results[0], results[0].DefaultAction()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Run(context.Background(), "reload-config"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"quit", "reload-config"}; !slices.Equal(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	gone := search.Result{ID: "prism:gone"}
+	if err := p.Activate(context.Background(), gone, actionRun); !errors.Is(err, search.ErrUnknownResult) {
+		t.Errorf("Activate(unknown command) = %v, want ErrUnknownResult", err)
+	}
+	if err := p.Activate(context.Background(), results[0], "nope"); err == nil {
+		t.Error("Activate with an unknown action succeeded")
+	}
+	if r, ok := p.Resolve(context.Background(), "prism:quit"); !ok || r.Title != "Quit Prism" {
+		t.Errorf("Resolve = %+v, %v", r, ok)
+	}
+}
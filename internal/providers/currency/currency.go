@@ -0,0 +1,110 @@
+// Package currency converts amounts typed as "100 usd to eur" using the
+// exchange rates of a convert.RateProvider.
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/convert"
+	"changeme/internal/locale"
+	"changeme/internal/network"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "currency"
+	// score ranks a conversion above anything fuzzy-matched on the query.
+	score = 100
+)
+
+// Provider converts currencies.
+type Provider struct {
+	rates convert.RateProvider
+	out   clipboard.Output
+
+	mu  sync.Mutex
+	loc locale.Locale
+}
+
+// New returns a currency provider using rates and copying or pasting the
+// converted amount through out.
+func New(rates convert.RateProvider, out clipboard.Output) *Provider {
+	return &Provider{rates: rates, out: out, loc: locale.System()}
+}
+
+// SetLocale sets the conventions amounts are read and written in.
+func (p *Provider) SetLocale(loc locale.Locale) {
+	p.mu.Lock()
+	p.loc = loc
+	p.mu.Unlock()
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	p.mu.Lock()
+	loc := p.loc
+	p.mu.Unlock()
+	q, ok := convert.Parse(query, loc)
+	if !ok {
+		return nil, nil
+	}
+	rates, err := p.rates.Rates(ctx)
+	switch {
+	case errors.Is(err, network.ErrOffline):
+		return []search.Result{network.OfflineResult(providerID)}, nil
+	case errors.Is(err, convert.ErrNoRates):
+		return []search.Result{{
+			ID:       providerID + ":loading",
+			Type:     "notice",
+			Title:    "Fetching Exchange Rates",
+			Subtitle: "Try again in a moment.",
+		}}, nil
+	case err != nil:
+		return nil, err
+	}
+	v, err := convert.Convert(q, rates)
+	if err != nil {
+		// An unknown code is most likely not meant as a currency at all.
+		return nil, nil
+	}
+	// The ID keeps the amount ungrouped for pasting elsewhere.
+	amount := loc.FormatNumber(v, 2, false)
+	shown := loc.FormatNumber(v, 2, true)
+	subtitle := fmt.Sprintf("%s %s = %s %s", loc.FormatNumber(q.Amount, 10, true), q.From, shown, q.To)
+	if rates.Stale {
+		subtitle += " " + network.StaleNote(rates.Fetched)
+	} else {
+		subtitle += " · Rates from " + rates.Fetched.Format("Jan 2 15:04")
+	}
+	return []search.Result{{
+		ID:       providerID + ":" + amount,
+		Type:     "text",
+		Title:    shown + " " + q.To,
+		Subtitle: subtitle,
+		Score:    score,
+		Actions:  clipboard.Actions(p.out.DefaultMode()),
+	}}, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	amount, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	if amount == "loading" || amount == "offline" {
+		// Notices have no actions, but enter still activates them.
+		return nil
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, amount, actionID)
+	}
+	return fmt.Errorf("currency: unknown action %q", actionID)
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// composeProject is the label Docker Compose puts on the containers of a
+// project, naming it.
+const composeProject = "com.docker.compose.project"
+
+// psArgs are the arguments docker is run with to list every container, one
+// JSON object per line for parse.
+var psArgs = []string{"ps", "--all", "--no-trunc", "--format", "{{json .}}"}
+
+// container is one container from docker ps.
+type container struct {
+	id      string
+	name    string
+	image   string
+	state   string // such as "running" or "exited"
+	status  string // such as "Up 2 hours" or "Exited (0) 3 days ago"
+	project string // the Compose project it belongs to, if any
+}
+
+func (c container) running() bool {
+	return c.state == "running" || c.state == "restarting"
+}
+
+// psLine is a line of docker ps --format '{{json .}}'. Labels are written
+// as a single "key=value,key=value" string.
+type psLine struct {
+	ID     string `json:"ID"`
+	Names  string `json:"Names"`
+	Image  string `json:"Image"`
+	State  string `json:"State"`
+	Status string `json:"Status"`
+	Labels string `json:"Labels"`
+}
+
+// parse reads the containers from docker ps output, skipping lines that
+// are not JSON, such as warnings.
+func parse(r io.Reader) []container {
+	var containers []container
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var l psLine
+		if err := json.Unmarshal(sc.Bytes(), &l); err != nil || l.ID == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(l.Names, ",")
+		containers = append(containers, container{
+			id:      l.ID,
+			name:    name,
+			image:   l.Image,
+			state:   strings.ToLower(l.State),
+			status:  l.Status,
+			project: label(l.Labels, composeProject),
+		})
+	}
+	return containers
+}
+
+// label returns the value of key among labels written as docker ps writes
+// them.
+func label(labels, key string) string {
+	for _, kv := range strings.Split(labels, ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// project is a Compose project, made of the containers labelled with it.
+type project struct {
+	name    string
+	total   int
+	running int
+}
+
+// projects returns the Compose projects the containers belong to, by
+// name.
+func projects(containers []container) []project {
+	byName := make(map[string]*project)
+	for _, c := range containers {
+		if c.project == "" {
+			continue
+		}
+		p, ok := byName[c.project]
+		if !ok {
+			p = &project{name: c.project}
+			byName[c.project] = p
+		}
+		p.total++
+		if c.running() {
+			p.running++
+		}
+	}
+	out := make([]project, 0, len(byName))
+	for _, p := range byName {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// logTail is how many lines of past output the logs action shows before
+// following new ones.
+const logTail = "200"
+
+// commandArgs returns the docker arguments that run action on a container,
+// or on a Compose project when isProject is set.
+func commandArgs(action, target string, isProject bool) []string {
+	var args []string
+	if isProject {
+		args = []string{"compose", "--project-name", target, action}
+	} else {
+		args = []string{action}
+	}
+	if action == actionLogs {
+		args = append(args, "--follow", "--tail", logTail)
+	}
+	if !isProject {
+		args = append(args, target)
+	}
+	return args
+}
+
+// notRunning reports whether stderr from docker says the daemon cannot be
+// reached, as when Docker Desktop is not started.
+func notRunning(stderr string) bool {
+	s := strings.ToLower(stderr)
+	return strings.Contains(s, "cannot connect to the docker daemon") ||
+		strings.Contains(s, "is the docker daemon running") ||
+		strings.Contains(s, "error during connect")
+}
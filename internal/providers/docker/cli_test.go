@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// psOutput is docker ps --all --format '{{json .}}' output for a Compose
+// project of two containers, one stopped, and a container of its own,
+// after a warning.
+const psOutput = `WARNING: Error loading config file: permission denied
+{"ID":"a1b2","Names":"shop-web-1","Image":"shop-web","State":"running","Status":"Up 2 hours","Labels":"com.docker.compose.service=web,com.docker.compose.project=shop"}
+{"ID":"c3d4","Names":"shop-db-1","Image":"postgres:16","State":"exited","Status":"Exited (0) 3 days ago","Labels":"com.docker.compose.project=shop"}
+{"ID":"e5f6","Names":"redis,cache","Image":"redis","State":"Restarting","Status":"Restarting (1) 5 seconds ago","Labels":""}
+{"ID":"","Names":"nothing"}
+`
+
+func TestParse(t *testing.T) {
+	got := parse(strings.NewReader(psOutput))
+	want := []container{
+		{id: "a1b2", name: "shop-web-1", image: "shop-web", state: "running", status: "Up 2 hours", project: "shop"},
+		{id: "c3d4", name: "shop-db-1", image: "postgres:16", state: "exited", status: "Exited (0) 3 days ago", project: "shop"},
+		{id: "e5f6", name: "redis", image: "redis", state: "restarting", status: "Restarting (1) 5 seconds ago"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse =\n%+v\nwant\n%+v", got, want)
+	}
+	if got, want := projects(got), []project{{name: "shop", total: 2, running: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("projects = %+v, want %+v", got, want)
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		action    string
+		target    string
+		isProject bool
+		want      []string
+	}{
+		{actionStart, "c3d4", false, []string{"start", "c3d4"}},
+		{actionStop, "a1b2", false, []string{"stop", "a1b2"}},
+		{actionRestart, "a1b2", false, []string{"restart", "a1b2"}},
+		{actionLogs, "a1b2", false, []string{"logs", "--follow", "--tail", "200", "a1b2"}},
+		{actionStop, "shop", true, []string{"compose", "--project-name", "shop", "stop"}},
+		{actionLogs, "shop", true, []string{"compose", "--project-name", "shop", "logs", "--follow", "--tail", "200"}},
+	}
+	for _, tt := range tests {
+		if got := commandArgs(tt.action, tt.target, tt.isProject); !slices.Equal(got, tt.want) {
+			t.Errorf("commandArgs(%s, %s, %v) = %q, want %q", tt.action, tt.target, tt.isProject, got, tt.want)
+		}
+	}
+}
+
+func TestNotRunning(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?", true},
+		{"error during connect: Get \"http://%2F%2F.%2Fpipe%2Fdocker_engine/v1.24/containers/json\"", true},
+		{"Error response from daemon: No such container: web", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := notRunning(tt.stderr); got != tt.want {
+			t.Errorf("notRunning(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
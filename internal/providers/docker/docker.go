@@ -0,0 +1,258 @@
+// Package docker lists Docker containers and Compose projects after the
+// "docker " prefix, with their status, and starts, stops and restarts them
+// or follows their logs in a terminal. It runs the docker CLI, so it works
+// wherever that is installed. It is off unless turned on, as most people
+// have no Docker to manage.
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "docker"
+
+	actionStart   = "start"
+	actionStop    = "stop"
+	actionRestart = "restart"
+	actionLogs    = "logs"
+
+	// score ranks the first result when no filter is typed; each after it
+	// scores one less.
+	score = 100
+	// The notices shown when docker is not installed and when its daemon
+	// is not running.
+	noDockerID   = providerID + ":no-docker"
+	notRunningID = providerID + ":not-running"
+	noneID       = providerID + ":none"
+)
+
+// errNotRunning is returned by list when the daemon cannot be reached.
+var errNotRunning = errors.New("docker: Docker is not running")
+
+// Provider lists and controls Docker containers.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu       sync.Mutex
+	enabled  bool
+	terminal string
+	// containers is listed once per session; see BeginSession.
+	containers []container
+	loaded     bool
+}
+
+// New returns a Docker provider that runs logs in a terminal through plat
+// and narrows containers by name with matcher. It lists nothing until
+// turned on with SetEnabled.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps docker, which takes a moment, to queries routed to the
+// provider.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// BeginSession drops the cached containers so the next search lists them
+// again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.containers, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+// SetEnabled turns the provider on or off.
+func (p *Provider) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	p.enabled = enabled
+	p.mu.Unlock()
+}
+
+// SetTerminal sets the terminal app or {cmd} template logs are followed in;
+// see platform.Platform.RunInTerminal.
+func (p *Provider) SetTerminal(terminal string) {
+	p.mu.Lock()
+	p.terminal = terminal
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	p.mu.Lock()
+	enabled := p.enabled
+	p.mu.Unlock()
+	if !enabled {
+		return nil, nil
+	}
+	query = strings.TrimSpace(query)
+	containers, err := p.list(ctx)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return []search.Result{notice(noDockerID, "Install Docker to manage containers", "The docker command was not found")}, nil
+	case errors.Is(err, errNotRunning):
+		return []search.Result{notice(notRunningID, "Docker is not running", "Start Docker, then search again")}, nil
+	case err != nil:
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return []search.Result{notice(noneID, "No containers", "Docker has no containers, running or stopped")}, nil
+	}
+	// Projects come first, then containers, in the order docker lists
+	// them, unless a filter ranks them.
+	var results []search.Result
+	add := func(r search.Result, name string) {
+		r.Score = float64(score - len(results))
+		if query != "" {
+			s, ok := p.matcher.Match(query, name)
+			if !ok {
+				return
+			}
+			r.Score = float64(s)
+		}
+		results = append(results, r)
+	}
+	for _, pr := range projects(containers) {
+		add(projectResult(pr), pr.name)
+	}
+	for _, c := range containers {
+		add(containerResult(c), c.name)
+	}
+	return results, nil
+}
+
+// list returns the containers, listing them with docker once per session.
+func (p *Provider) list(ctx context.Context) ([]container, error) {
+	p.mu.Lock()
+	if p.loaded {
+		defer p.mu.Unlock()
+		return p.containers, nil
+	}
+	p.mu.Unlock()
+	out, err := run(ctx, psArgs...)
+	if err != nil {
+		return nil, err
+	}
+	containers := parse(bytes.NewReader(out))
+	p.mu.Lock()
+	p.containers, p.loaded = containers, true
+	p.mu.Unlock()
+	return containers, nil
+}
+
+// run runs docker with args and returns its output. A daemon that cannot
+// be reached is errNotRunning, and a missing docker exec.ErrNotFound.
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if notRunning(stderr.String()) {
+			return nil, errNotRunning
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("docker %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func notice(id, title, subtitle string) search.Result {
+	return search.Result{ID: id, Type: "notice", Title: title, Subtitle: subtitle, Score: score}
+}
+
+func containerResult(c container) search.Result {
+	subtitle := c.image + " · " + c.status
+	if c.project != "" {
+		subtitle += " · " + c.project
+	}
+	r := search.Result{
+		ID:       providerID + ":container:" + c.id,
+		Type:     "container",
+		Title:    c.name,
+		Subtitle: subtitle,
+		Target:   c.id,
+	}
+	if c.running() {
+		r.Actions = []search.Action{
+			{ID: actionStop, Title: "Stop"},
+			{ID: actionRestart, Title: "Restart"},
+			{ID: actionLogs, Title: "Show Logs"},
+		}
+	} else {
+		r.Actions = []search.Action{
+			{ID: actionStart, Title: "Start"},
+			{ID: actionLogs, Title: "Show Logs"},
+		}
+	}
+	return r
+}
+
+func projectResult(pr project) search.Result {
+	r := search.Result{
+		ID:       providerID + ":project:" + pr.name,
+		Type:     "compose",
+		Title:    pr.name,
+		Subtitle: fmt.Sprintf("Compose project · %d of %d running", pr.running, pr.total),
+		Target:   pr.name,
+	}
+	if pr.running > 0 {
+		r.Actions = []search.Action{
+			{ID: actionStop, Title: "Stop Project"},
+			{ID: actionRestart, Title: "Restart Project"},
+			{ID: actionLogs, Title: "Show Logs"},
+		}
+	} else {
+		r.Actions = []search.Action{
+			{ID: actionStart, Title: "Start Project"},
+			{ID: actionLogs, Title: "Show Logs"},
+		}
+	}
+	return r
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if r.ID == noDockerID || r.ID == notRunningID || r.ID == noneID {
+		return nil
+	}
+	rest, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	kind, target, ok := strings.Cut(rest, ":")
+	if !ok || target == "" || strings.HasPrefix(target, "-") || (kind != "container" && kind != "project") {
+		return fmt.Errorf("docker: malformed result id %q", r.ID)
+	}
+	args := commandArgs(actionID, target, kind == "project")
+	switch actionID {
+	case actionLogs:
+		p.mu.Lock()
+		terminal := p.terminal
+		p.mu.Unlock()
+		return p.plat.RunInTerminal(ctx, terminal, append([]string{"docker"}, args...))
+	case actionStart, actionStop, actionRestart:
+		if _, err := run(ctx, args...); err != nil {
+			return err
+		}
+		// List again so the result shows the new status.
+		p.BeginSession()
+		return search.ErrSearchAgain
+	}
+	return fmt.Errorf("docker: unknown action %q", actionID)
+}
@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// terminalPlatform records the command lines run in a terminal.
+type terminalPlatform struct {
+	platform.Platform
+	terminal string
+	ran      [][]string
+}
+
+func (p *terminalPlatform) RunInTerminal(ctx context.Context, terminal string, args []string) error {
+	p.terminal = terminal
+	p.ran = append(p.ran, args)
+	return nil
+}
+
+// listed returns a provider, turned on, whose session already listed the
+// containers in psOutput.
+func listed(plat platform.Platform) *Provider {
+	p := New(plat, fuzzy.Default())
+	p.SetEnabled(true)
+	p.containers, p.loaded = parse(strings.NewReader(psOutput)), true
+	return p
+}
+
+func actionIDs(r search.Result) []string {
+	var ids []string
+	for _, a := range r.Actions {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	p := listed(nil)
+	results, err := p.Search(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	want := []string{"docker:project:shop", "docker:container:a1b2", "docker:container:c3d4", "docker:container:e5f6"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("Search = %q, want %q", ids, want)
+	}
+	if got := results[0].Subtitle; got != "Compose project · 1 of 2 running" {
+		t.Errorf("project subtitle = %q", got)
+	}
+	if got := results[2].Subtitle; got != "postgres:16 · Exited (0) 3 days ago · shop" {
+		t.Errorf("container subtitle = %q", got)
+	}
+	for i, want := range [][]string{
+		{actionStop, actionRestart, actionLogs},
+		{actionStop, actionRestart, actionLogs},
+		{actionStart, actionLogs},
+		{actionStop, actionRestart, actionLogs},
+	} {
+		if got := actionIDs(results[i]); !slices.Equal(got, want) {
+			t.Errorf("actions of %s = %v, want %v", results[i].Title, got, want)
+		}
+	}
+
+	results, _ = p.Search(context.Background(), "redis")
+	if len(results) != 1 || results[0].Title != "redis" {
+		t.Errorf("Search(redis) = %+v, want the redis container", results)
+	}
+
+	p.SetEnabled(false)
+	if results, _ := p.Search(context.Background(), ""); results != nil {
+		t.Errorf("Search while off = %+v, want nothing", results)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	plat := &terminalPlatform{}
+	p := listed(plat)
+	p.SetTerminal("iTerm")
+	results, _ := p.Search(context.Background(), "")
+	for _, r := range results[:2] {
+		if err := p.Activate(context.Background(), r, actionLogs); err != nil {
+			t.Fatalf("Activate(%s, logs): %v", r.ID, err)
+		}
+	}
+	want := [][]string{
+		{"docker", "compose", "--project-name", "shop", "logs", "--follow", "--tail", "200"},
+		{"docker", "logs", "--follow", "--tail", "200", "a1b2"},
+	}
+	if !slices.EqualFunc(plat.ran, want, slices.Equal) || plat.terminal != "iTerm" {
+		t.Errorf("ran %q in %q, want %q in iTerm", plat.ran, plat.terminal, want)
+	}
+
+	for _, id := range []string{noDockerID, notRunningID, noneID} {
+		if err := p.Activate(context.Background(), notice(id, "", ""), actionStart); err != nil {
+			t.Errorf("Activate on %s = %v, want nothing done", id, err)
+		}
+	}
+	for _, id := range []string{"docker:container:", "docker:container:--rm", "docker:image:redis", "docker"} {
+		if err := p.Activate(context.Background(), search.Result{ID: id}, actionStart); err == nil {
+			t.Errorf("Activate on %q succeeded", id)
+		}
+	}
+	if err := p.Activate(context.Background(), search.Result{ID: "apps:Docker"}, actionStart); !errors.Is(err, search.ErrUnknownResult) {
+		t.Errorf("Activate on another provider's result = %v, want ErrUnknownResult", err)
+	}
+	if err := p.Activate(context.Background(), results[1], "pause"); err == nil {
+		t.Error("Activate with an unknown action succeeded")
+	}
+	if len(plat.ran) != 2 {
+		t.Errorf("ran %q, want nothing more", plat.ran)
+	}
+}
+
+func TestBeginSession(t *testing.T) {
+	p := listed(nil)
+	p.BeginSession()
+	if p.loaded || p.containers != nil {
+		t.Errorf("containers after BeginSession = %+v, %v, want them listed again", p.containers, p.loaded)
+	}
+}
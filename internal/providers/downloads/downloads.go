@@ -0,0 +1,243 @@
+// Package downloads lists the most recently changed files in the user's
+// Downloads folder: "dl" or "downloads" on its own shows the newest first,
+// and whatever is typed after it narrows them by name.
+package downloads
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/pathfmt"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID   = "downloads"
+	actionOpen   = "open"
+	actionReveal = "reveal"
+
+	// defaultDir is the folder listed when none is configured.
+	defaultDir = "~/Downloads"
+	// maxResults caps how many of the newest files are listed.
+	maxResults = 20
+	// score ranks the newest file; older ones score one less each, below
+	// anything fuzzy-matched on the keyword.
+	score = 100
+)
+
+// keywords start a downloads query.
+var keywords = []string{"dl", "downloads"}
+
+// inProgress are the suffixes browsers give a download until it finishes.
+var inProgress = []string{".part", ".crdownload", ".download", ".opdownload"}
+
+// file is a listed download.
+type file struct {
+	name     string
+	path     string
+	modified time.Time
+}
+
+// Provider lists the newest files in the Downloads folder after its
+// keywords. The listing is cached until the folder changes.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	dir     string
+	hidden  bool
+	ignore  []string
+	watcher *index.Watcher
+	files   []file
+	loaded  bool
+}
+
+// New returns a downloads provider that opens and reveals files through
+// plat and narrows them by name with matcher.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher, dir: index.Expand(defaultDir)}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetDir sets the folder listed, ~/Downloads when dir is empty. A leading
+// "~/" is expanded.
+func (p *Provider) SetDir(dir string) {
+	if dir == "" {
+		dir = defaultDir
+	}
+	dir = index.Expand(dir)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if dir == p.dir {
+		return
+	}
+	p.dir, p.files, p.loaded = dir, nil, false
+	if p.watcher != nil {
+		p.watcher.Watch([]string{dir})
+	}
+}
+
+// SetFilter sets whether hidden files, whose names start with a dot, are
+// listed, and the patterns of files never listed; see index.Ignored.
+func (p *Provider) SetFilter(showHidden bool, ignore []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if showHidden == p.hidden && slices.Equal(ignore, p.ignore) {
+		return
+	}
+	p.hidden, p.ignore = showHidden, ignore
+	p.files, p.loaded = nil, false
+}
+
+// Watch drops the cached listing whenever the folder changes, until ctx is
+// cancelled.
+func (p *Provider) Watch(ctx context.Context) {
+	w, err := index.NewWatcher(p.invalidate)
+	if err != nil {
+		log.Println("downloads:", err)
+		return
+	}
+	p.mu.Lock()
+	p.watcher = w
+	w.Watch([]string{p.dir})
+	p.mu.Unlock()
+	w.Run(ctx)
+	p.mu.Lock()
+	p.watcher = nil
+	p.mu.Unlock()
+}
+
+// invalidate drops the cached listing so the next search reads the folder.
+func (p *Provider) invalidate() {
+	p.mu.Lock()
+	p.files, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	word, rest, _ := strings.Cut(strings.TrimLeft(query, " "), " ")
+	if !isKeyword(word) {
+		return nil, nil
+	}
+	rest = strings.TrimSpace(rest)
+	files, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	var results []search.Result
+	for i, f := range files {
+		if rest != "" {
+			if _, ok := p.matcher.Match(rest, f.name); !ok {
+				continue
+			}
+		}
+		results = append(results, result(f, float64(score-i)))
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch actionID {
+	case actionOpen:
+		return p.plat.Open(ctx, r.Target)
+	case actionReveal:
+		return p.plat.Reveal(ctx, r.Target)
+	}
+	return fmt.Errorf("downloads: unknown action %q", actionID)
+}
+
+func isKeyword(word string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(word, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func result(f file, score float64) search.Result {
+	return search.Result{
+		ID:       providerID + ":" + f.path,
+		Type:     "file",
+		Title:    f.name,
+		Subtitle: "Downloaded " + f.modified.Format("Jan 2, 2006 15:04") + " · " + pathfmt.Truncate(f.path, pathfmt.SubtitleLen),
+		Target:   f.path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// list returns the newest files, reading the folder if the cached listing
+// was dropped.
+func (p *Provider) list() ([]file, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.files, nil
+	}
+	files, err := newest(p.dir, maxResults, p.hidden, p.ignore)
+	if err != nil {
+		return nil, err
+	}
+	p.files, p.loaded = files, true
+	return files, nil
+}
+
+// newest returns up to limit entries of dir, most recently modified first,
+// leaving out downloads still in progress, those matching ignore and,
+// unless showHidden is set, hidden files. A missing folder has no entries.
+func newest(dir string, limit int, showHidden bool, ignore []string) ([]file, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("downloads: %w", err)
+	}
+	var files []file
+	for _, e := range entries {
+		name := e.Name()
+		path := filepath.Join(dir, name)
+		if !showHidden && index.Hidden(name) || downloading(name) || index.Ignored(path, ignore) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{name: name, path: path, modified: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modified.After(files[j].modified) })
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files, nil
+}
+
+// downloading reports whether name is a download a browser has not
+// finished.
+func downloading(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, s := range inProgress {
+		if ext == s {
+			return true
+		}
+	}
+	return false
+}
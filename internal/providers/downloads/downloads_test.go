@@ -0,0 +1,116 @@
+package downloads
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/fuzzy"
+)
+
+// download writes a file named name in dir, modified at the given time.
+func download(t *testing.T, dir, name string, modified time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// titles returns what p lists for query.
+func titles(t *testing.T, p *Provider, query string) []string {
+	t.Helper()
+	results, err := p.Search(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	download(t, dir, "report.pdf", now.Add(-time.Hour))
+	download(t, dir, "photo.jpg", now)
+	download(t, dir, "installer.dmg", now.Add(-24*time.Hour))
+	download(t, dir, "movie.mp4.part", now.Add(time.Minute))
+	download(t, dir, "archive.zip.crdownload", now.Add(time.Minute))
+	download(t, dir, ".DS_Store", now.Add(time.Minute))
+	p := New(nil, fuzzy.Default())
+	p.SetDir(dir)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"dl", []string{"photo.jpg", "report.pdf", "installer.dmg"}},
+		{"Downloads ", []string{"photo.jpg", "report.pdf", "installer.dmg"}},
+		{"dl rep", []string{"report.pdf"}},
+		{"dl movie", nil},
+		{"report", nil},
+		{"dlx", nil},
+	}
+	for _, tt := range tests {
+		if got := titles(t, p, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	// The listing is cached until the folder changes.
+	download(t, dir, "new.txt", now.Add(time.Hour))
+	if got := titles(t, p, "dl"); len(got) != 3 {
+		t.Errorf("Search before invalidating = %v, want the cached three", got)
+	}
+	p.invalidate()
+	if got := titles(t, p, "dl"); len(got) != 4 || got[0] != "new.txt" {
+		t.Errorf("Search after invalidating = %v, want new.txt first", got)
+	}
+}
+
+func TestSearchCap(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := range maxResults + 5 {
+		download(t, dir, fmt.Sprintf("file%02d.txt", i), now.Add(time.Duration(i)*time.Minute))
+	}
+	p := New(nil, fuzzy.Default())
+	p.SetDir(dir)
+	got := titles(t, p, "dl")
+	if len(got) != maxResults {
+		t.Fatalf("%d results, want %d", len(got), maxResults)
+	}
+	if want := fmt.Sprintf("file%02d.txt", maxResults+4); got[0] != want {
+		t.Errorf("newest = %s, want %s", got[0], want)
+	}
+}
+
+func TestSearchHidden(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	download(t, dir, "report.pdf", now)
+	download(t, dir, ".hidden.txt", now.Add(-time.Hour))
+	download(t, dir, "left-pad.tgz", now.Add(-2*time.Hour))
+	p := New(nil, fuzzy.Default())
+	p.SetDir(dir)
+	ignore := []string{"*.tgz"}
+
+	p.SetFilter(false, ignore)
+	if got, want := titles(t, p, "dl"), []string{"report.pdf"}; !slices.Equal(got, want) {
+		t.Errorf("Search = %v, want %v", got, want)
+	}
+	p.SetFilter(true, ignore)
+	if got, want := titles(t, p, "dl"), []string{"report.pdf", ".hidden.txt"}; !slices.Equal(got, want) {
+		t.Errorf("Search with hidden files = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+// Package encode hashes and encodes text typed after a keyword, such as
+// "sha256 hello" or "base64 aGVsbG8=", and decodes it where the encoding
+// allows. A keyword on its own works on the clipboard contents. Everything
+// is computed locally.
+package encode
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "encode"
+	// score ranks results above anything fuzzy-matched on the keyword.
+	score = 100
+)
+
+// transform is one keyword's conversions. decode is nil for hashes, and
+// reports false for input that is not validly encoded.
+type transform struct {
+	name   string
+	encode func(string) string
+	decode func(string) (string, bool)
+}
+
+var transforms = map[string]transform{
+	"md5":       {name: "MD5", encode: hashHex(func(b []byte) []byte { s := md5.Sum(b); return s[:] })},
+	"sha1":      {name: "SHA-1", encode: hashHex(func(b []byte) []byte { s := sha1.Sum(b); return s[:] })},
+	"sha256":    {name: "SHA-256", encode: hashHex(func(b []byte) []byte { s := sha256.Sum256(b); return s[:] })},
+	"sha512":    {name: "SHA-512", encode: hashHex(func(b []byte) []byte { s := sha512.Sum512(b); return s[:] })},
+	"base64":    {name: "Base64", encode: Base64Encode, decode: Base64Decode},
+	"urlencode": {name: "URL", encode: url.QueryEscape, decode: URLDecode},
+}
+
+func hashHex(sum func([]byte) []byte) func(string) string {
+	return func(s string) string { return hex.EncodeToString(sum([]byte(s))) }
+}
+
+// Base64Encode encodes s with the standard, padded alphabet.
+func Base64Encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// Base64Decode decodes standard or URL-safe base64, with or without
+// padding. It reports false unless the input decodes to UTF-8 text.
+func Base64Decode(s string) (string, bool) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil && utf8.Valid(b) {
+			return string(b), true
+		}
+	}
+	return "", false
+}
+
+// URLDecode reverses url.QueryEscape. It reports false for malformed
+// escapes.
+func URLDecode(s string) (string, bool) {
+	d, err := url.QueryUnescape(s)
+	return d, err == nil
+}
+
+// Provider shows the conversions of the text after a keyword.
+type Provider struct {
+	clip clipboard.Clipboard
+	out  clipboard.Output
+}
+
+// New returns a provider that reads keyword-only input from clip and copies
+// or pastes results through out.
+func New(clip clipboard.Clipboard, out clipboard.Output) *Provider {
+	return &Provider{clip: clip, out: out}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	keyword, input, _ := strings.Cut(strings.TrimLeft(query, " "), " ")
+	t, ok := transforms[strings.ToLower(keyword)]
+	if !ok {
+		return nil, nil
+	}
+	source := ""
+	if input == "" {
+		text, ok := p.clip.Text()
+		if !ok || text == "" {
+			return nil, nil
+		}
+		input, source = text, " of Clipboard"
+	}
+	actions := clipboard.Actions(p.out.DefaultMode())
+	title := t.name
+	if t.decode != nil {
+		title += " Encoded"
+	}
+	results := []search.Result{{
+		ID:       providerID + ":" + keyword + ":" + t.encode(input),
+		Type:     "text",
+		Title:    t.encode(input),
+		Subtitle: title + source,
+		Score:    score,
+		Actions:  actions,
+	}}
+	if t.decode != nil {
+		if d, ok := t.decode(input); ok && d != input {
+			results = append(results, search.Result{
+				ID:       providerID + ":" + keyword + "-decode:" + d,
+				Type:     "text",
+				Title:    d,
+				Subtitle: t.name + " Decoded" + source,
+				Score:    score - 1,
+				Actions:  actions,
+			})
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	_, text, ok := strings.Cut(strings.TrimPrefix(r.ID, providerID+":"), ":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, text, actionID)
+	}
+	return fmt.Errorf("encode: unknown action %q", actionID)
+}
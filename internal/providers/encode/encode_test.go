@@ -0,0 +1,103 @@
+package encode
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+type clip string
+
+func (c clip) Text() (string, bool) { return string(c), c != "" }
+
+// output records the text delivered.
+type output struct{ delivered []string }
+
+func (o *output) DefaultMode() string { return config.ClipboardActionCopy }
+
+func (o *output) Deliver(ctx context.Context, text, mode string) error {
+	o.delivered = append(o.delivered, text)
+	return nil
+}
+
+func titles(results []search.Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"md5 hello", []string{"5d41402abc4b2a76b9719d911017c592"}},
+		{"sha1 hello", []string{"aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"}},
+		{"SHA256 hello", []string{"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"}},
+		{"sha512 hello", []string{"9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"}},
+		// "hello" is not valid base64, so there is nothing to decode.
+		{"base64 hello", []string{"aGVsbG8="}},
+		{"base64 aGVsbG8=", []string{"YUdWc2JHOD0=", "hello"}},
+		{"base64 aGVsbG8", []string{"YUdWc2JHOA==", "hello"}},
+		{"urlencode a b&c=d", []string{"a+b%26c%3Dd"}},
+		{"urlencode a+b%26c", []string{"a%2Bb%2526c", "a b&c"}},
+		// Without text the clipboard is used.
+		{"md5", []string{"5d41402abc4b2a76b9719d911017c592"}},
+		{"md5sum hello", nil},
+		{"hello", nil},
+	}
+	p := New(clip("hello"), &output{})
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(results); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	if results, _ := New(clip(""), &output{}).Search(context.Background(), "md5"); len(results) != 0 {
+		t.Errorf("Search(md5) with an empty clipboard = %q, want none", titles(results))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "hello", "a b&c=d/e?f", "naïve café ☕", "line\nbreak"} {
+		if got, ok := Base64Decode(Base64Encode(s)); !ok || got != s {
+			t.Errorf("Base64Decode(Base64Encode(%q)) = %q, %v", s, got, ok)
+		}
+		if got, ok := URLDecode(transforms["urlencode"].encode(s)); !ok || got != s {
+			t.Errorf("URLDecode(urlencode(%q)) = %q, %v", s, got, ok)
+		}
+	}
+	// URL-safe base64 without padding decodes too.
+	if got, ok := Base64Decode("P_8-"); ok {
+		t.Errorf("Base64Decode(P_8-) = %q, want not UTF-8", got)
+	}
+	if got, ok := Base64Decode("aGk_"); !ok || got != "hi?" {
+		t.Errorf("Base64Decode(aGk_) = %q, %v; want hi?", got, ok)
+	}
+	if _, ok := URLDecode("%zz"); ok {
+		t.Error("URLDecode(%zz) succeeded")
+	}
+}
+
+func TestActivate(t *testing.T) {
+	out := &output{}
+	p := New(clip(""), out)
+	results, _ := p.Search(context.Background(), "base64 a:b")
+	for _, r := range results {
+		if err := p.Activate(context.Background(), r, config.ClipboardActionCopy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"YTpi"}; !slices.Equal(out.delivered, want) {
+		t.Errorf("delivered %q, want %q", out.delivered, want)
+	}
+}
@@ -0,0 +1,192 @@
+// Package env looks up environment variables: "env PATH" shows the value of
+// PATH, and "env" on its own lists every variable, narrowed by whatever is
+// typed after it. Variables come from the user's login shell, so they match
+// what a new terminal would have rather than what Prism was started with.
+package env
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "env"
+	keyword    = "env"
+	// score ranks an exact name above anything fuzzy-matched on the
+	// keyword; listed and fuzzy-matched variables score below it.
+	score = 100
+	// concealed stands in for the value of a likely secret.
+	concealed = "••••••••"
+)
+
+// secretWords mark a variable name as likely holding a secret.
+var secretWords = []string{"TOKEN", "SECRET", "KEY", "PASSWORD", "PASSWD", "CREDENTIAL"}
+
+// isSecret reports whether name looks like it holds a secret, whose value
+// is concealed in results. Copying it still copies the value.
+func isSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, word := range secretWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// variable is one environment entry.
+type variable struct {
+	name, value string
+}
+
+// Provider shows environment variables after the env keyword.
+type Provider struct {
+	matcher *fuzzy.Matcher
+	plat    platform.Platform
+	out     clipboard.Output
+
+	mu     sync.Mutex
+	vars   []variable
+	loaded bool
+}
+
+// New returns a provider that reads the environment through plat, ranks
+// names with matcher and copies or pastes values through out.
+func New(matcher *fuzzy.Matcher, plat platform.Platform, out clipboard.Output) *Provider {
+	return &Provider{matcher: matcher, plat: plat, out: out}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession drops the cached environment so the next search reads it
+// again, picking up changes to the user's profile.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.vars, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	word, rest, _ := strings.Cut(strings.TrimLeft(query, " "), " ")
+	if !strings.EqualFold(word, keyword) {
+		return nil, nil
+	}
+	rest = strings.TrimSpace(rest)
+	vars := p.list(ctx)
+	actions := clipboard.Actions(p.out.DefaultMode())
+	var results []search.Result
+	for _, v := range vars {
+		s, ok := score-1, true
+		switch {
+		case rest == "":
+		case strings.EqualFold(v.name, rest):
+			s = score
+		default:
+			var m int
+			m, ok = p.matcher.Match(rest, v.name)
+			s = min(m, score-1)
+		}
+		if !ok {
+			continue
+		}
+		results = append(results, result(v, float64(s), actions))
+	}
+	return results, nil
+}
+
+// result shows v with its value as the subtitle, concealed for secrets.
+func result(v variable, s float64, actions []search.Action) search.Result {
+	shown := v.value
+	if isSecret(v.name) {
+		shown = concealed
+	} else if shown == "" {
+		shown = "(empty)"
+	}
+	return search.Result{
+		ID:       providerID + ":" + v.name,
+		Type:     "text",
+		Title:    v.name,
+		Subtitle: shown,
+		Score:    s,
+		Actions:  actions,
+	}
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	name, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	value, ok := lookup(p.list(ctx), name)
+	if !ok {
+		return fmt.Errorf("env: %s is not set", name)
+	}
+	switch actionID {
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, value, actionID)
+	}
+	return fmt.Errorf("env: unknown action %q", actionID)
+}
+
+// lookup returns the value of name among vars.
+func lookup(vars []variable, name string) (string, bool) {
+	for _, v := range vars {
+		if v.name == name {
+			return v.value, true
+		}
+	}
+	return "", false
+}
+
+// list returns the variables sorted by name, reading them once per
+// session. When the login shell cannot be asked, Prism's own environment
+// is used instead.
+func (p *Provider) list(ctx context.Context) []variable {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.vars
+	}
+	entries, err := p.plat.Environment(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			// The search moved on; ask again next time.
+			return parse(os.Environ())
+		}
+		log.Println("env:", err)
+		entries = os.Environ()
+	}
+	p.vars = parse(entries)
+	p.loaded = true
+	return p.vars
+}
+
+// parse splits "NAME=value" entries into variables sorted by name. Later
+// entries for a name replace earlier ones, as they would in a process.
+func parse(entries []string) []variable {
+	byName := make(map[string]string, len(entries))
+	for _, kv := range entries {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			continue
+		}
+		byName[name] = value
+	}
+	vars := make([]variable, 0, len(byName))
+	for name, value := range byName {
+		vars = append(vars, variable{name, value})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].name < vars[j].name })
+	return vars
+}
@@ -0,0 +1,157 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// loginShell is a platform whose login shell has a fixed environment.
+type loginShell struct {
+	platform.Platform
+	env   []string
+	err   error
+	reads int
+}
+
+func (p *loginShell) Environment(ctx context.Context) ([]string, error) {
+	p.reads++
+	return p.env, p.err
+}
+
+// output records the text delivered.
+type output struct{ delivered []string }
+
+func (o *output) DefaultMode() string { return config.ClipboardActionCopy }
+
+func (o *output) Deliver(ctx context.Context, text, mode string) error {
+	o.delivered = append(o.delivered, text)
+	return nil
+}
+
+func titles(results []search.Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	return out
+}
+
+func newTestProvider() (*Provider, *loginShell, *output) {
+	plat := &loginShell{env: []string{
+		"PATH=/usr/bin:/bin",
+		"HOME=/Users/me",
+		"GITHUB_TOKEN=ghp_secret",
+		"AWS_SECRET_ACCESS_KEY=hunter2",
+		"EMPTY=",
+		"HOME=/Users/me/again",
+		"not an entry",
+	}}
+	out := &output{}
+	return New(fuzzy.Default(), plat, out), plat, out
+}
+
+func TestSearch(t *testing.T) {
+	p, plat, _ := newTestProvider()
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"env", []string{"AWS_SECRET_ACCESS_KEY", "EMPTY", "GITHUB_TOKEN", "HOME", "PATH"}},
+		{"ENV ", []string{"AWS_SECRET_ACCESS_KEY", "EMPTY", "GITHUB_TOKEN", "HOME", "PATH"}},
+		{"env path", []string{"PATH"}},
+		{"env tok", []string{"GITHUB_TOKEN"}},
+		{"envy", nil},
+		{"path", nil},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := titles(results); !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+	if plat.reads != 1 {
+		t.Errorf("environment read %d times in a session, want once", plat.reads)
+	}
+	p.BeginSession()
+	p.Search(context.Background(), "env")
+	if plat.reads != 2 {
+		t.Errorf("environment not read again in a new session")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	p, _, out := newTestProvider()
+	tests := []struct {
+		query, subtitle, value string
+	}{
+		{"env HOME", "/Users/me/again", "/Users/me/again"},
+		// An exact name in any case ranks first.
+		{"env home", "/Users/me/again", "/Users/me/again"},
+		{"env EMPTY", "(empty)", ""},
+		{"env GITHUB_TOKEN", concealed, "ghp_secret"},
+		{"env aws_secret_access_key", concealed, "hunter2"},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) == 0 {
+			t.Fatalf("Search(%q) found nothing", tt.query)
+		}
+		best := slices.MaxFunc(results, func(a, b search.Result) int { return int(a.Score - b.Score) })
+		if best.Subtitle != tt.subtitle {
+			t.Errorf("Search(%q) shows %q, want %q", tt.query, best.Subtitle, tt.subtitle)
+		}
+		// Copying a concealed value copies the real one.
+		out.delivered = nil
+		if err := p.Activate(context.Background(), best, config.ClipboardActionCopy); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(out.delivered, []string{tt.value}) {
+			t.Errorf("copying %s delivered %q, want %q", best.Title, out.delivered, tt.value)
+		}
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	for name, want := range map[string]bool{
+		"GITHUB_TOKEN":       true,
+		"npm_config_secret":  true,
+		"SSH_AUTH_KEY":       true,
+		"DB_PASSWORD":        true,
+		"MYSQL_PASSWD":       true,
+		"GOOGLE_CREDENTIALS": true,
+		"PATH":               false,
+		// Names are matched loosely, so this is concealed too.
+		"KEYBOARD_LAYOUT": true,
+		"HOME":            false,
+	} {
+		if got := isSecret(name); got != want {
+			t.Errorf("isSecret(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestListFallsBack(t *testing.T) {
+	t.Setenv("PRISM_TEST_VAR", "from the process")
+	p := New(fuzzy.Default(), &loginShell{err: errors.New("no shell")}, &output{})
+	results, err := p.Search(context.Background(), "env PRISM_TEST_VAR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(results, func(r search.Result) bool { return r.Title == "PRISM_TEST_VAR" })
+	if i < 0 || results[i].Subtitle != "from the process" {
+		t.Errorf("Search = %+v, want Prism's own environment", results)
+	}
+}
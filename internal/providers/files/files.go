@@ -0,0 +1,329 @@
+// Package files provides results for files and folders under the
+// user-configured index folders.
+package files
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/lru"
+	"changeme/internal/pathfmt"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+	"changeme/internal/thumbnail"
+)
+
+const (
+	providerID   = "files"
+	actionOpen   = "open"
+	actionReveal = "reveal"
+
+	// maxEntries bounds the index so a mistakenly broad root cannot exhaust
+	// memory.
+	maxEntries = 100000
+	// maxResults bounds the results of one query.
+	maxResults = 50
+
+	// watchDepth is how many levels below each root are watched for
+	// changes, and maxWatches how many folders at most, since fsnotify is
+	// not recursive and each watch costs a file descriptor or inotify slot.
+	watchDepth = 2
+	maxWatches = 1000
+	// pollInterval is how often the index is rebuilt when some indexed
+	// folders are too deep, or too many, to watch.
+	pollInterval = 5 * time.Minute
+	// minRebuildInterval spaces out rebuilds while folders keep changing,
+	// as a build tree does during a compile.
+	minRebuildInterval = 10 * time.Second
+)
+
+type entry struct {
+	name string
+	path string
+	dir  bool
+}
+
+// Provider searches an in-memory index of file names.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	roots   []index.Root
+	ignore  []string
+	hidden  bool
+	entries []entry
+	dirs    []string // directories to watch, seen while indexing
+	partial bool     // whether some indexed directories are not in dirs
+	built   bool
+	// changed wakes Watch to rebuild the index; see refresh.
+	changed chan struct{}
+
+	thumbs *thumbnail.Cache
+	// thumbnails reports whether previews get thumbnails; see
+	// SetThumbnails.
+	thumbnails func() bool
+}
+
+// New returns a files provider that opens results through plat, ranks them
+// with matcher and keeps thumbnails within caches.
+func New(plat platform.Platform, matcher *fuzzy.Matcher, caches *lru.Budget) *Provider {
+	return &Provider{plat: plat, matcher: matcher, thumbs: thumbnail.NewCache(caches), thumbnails: func() bool { return true }, changed: make(chan struct{}, 1)}
+}
+
+// SetThumbnails sets what reports whether previews of images get a
+// thumbnail, such as only in layouts that show icons. It is asked on every
+// preview, so a change applies at once.
+func (p *Provider) SetThumbnails(enabled func() bool) {
+	p.mu.Lock()
+	p.thumbnails = enabled
+	p.mu.Unlock()
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetRoots sets the folders to index and marks the index for rebuilding.
+func (p *Provider) SetRoots(roots []index.Root, ignore []string) {
+	p.mu.Lock()
+	p.roots, p.ignore = roots, ignore
+	p.built = false
+	p.mu.Unlock()
+	p.refresh()
+}
+
+// SetShowHidden includes hidden files and folders, whose names start with a
+// dot, in the index, or leaves them out, and marks the index for
+// rebuilding if that changed.
+func (p *Provider) SetShowHidden(show bool) {
+	p.mu.Lock()
+	changed := show != p.hidden
+	if changed {
+		p.hidden, p.built = show, false
+	}
+	p.mu.Unlock()
+	if changed {
+		p.refresh()
+	}
+}
+
+// Rebuild marks the index for rebuilding on the next search.
+func (p *Provider) Rebuild() {
+	p.mu.Lock()
+	p.built = false
+	p.mu.Unlock()
+}
+
+// refresh asks Watch to rebuild the index and move its watches. Requests
+// made while a rebuild is pending or running are coalesced into one.
+func (p *Provider) refresh() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Watch builds the index and keeps it current until ctx is cancelled. The
+// roots and the folders up to watchDepth below them are watched, since
+// fsnotify is not recursive; when that leaves indexed folders unwatched the
+// index is also rebuilt every pollInterval. Folders on a drive that is
+// unmounted drop out and return when it is mounted again.
+func (p *Provider) Watch(ctx context.Context) {
+	w, err := index.NewWatcher(p.refresh)
+	if err != nil {
+		log.Println("files:", err)
+		return
+	}
+	go w.Run(ctx)
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	for {
+		// This rebuild serves whatever asked for one so far.
+		select {
+		case <-p.changed:
+		default:
+		}
+		p.Rebuild()
+		p.build()
+		p.mu.Lock()
+		dirs := append([]string(nil), p.dirs...)
+		for _, r := range p.roots {
+			dirs = append(dirs, r.Path)
+		}
+		partial := p.partial
+		p.mu.Unlock()
+		w.Watch(dirs)
+		built := time.Now()
+
+		var polled <-chan time.Time
+		if partial {
+			polled = poll.C
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.changed:
+		case <-polled:
+		}
+		// Changes arriving in the meantime are coalesced into the next
+		// rebuild.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(built.Add(minRebuildInterval))):
+		}
+	}
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	var results []search.Result
+	for _, e := range p.build() {
+		score, ok := p.matcher.Match(query, e.name)
+		if !ok {
+			continue
+		}
+		results = append(results, result(e, float64(score)))
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results, nil
+}
+
+// Prewarm reads the top result's metadata, and a folder's entries, so a
+// file on a sleeping disk or network volume is ready by the time it is
+// opened.
+func (p *Provider) Prewarm(ctx context.Context, r search.Result) {
+	info, err := os.Stat(r.Target)
+	if err != nil || !info.IsDir() || ctx.Err() != nil {
+		return
+	}
+	if f, err := os.Open(r.Target); err == nil {
+		f.Readdirnames(-1)
+		f.Close()
+	}
+}
+
+// Preview describes the file's size, or a folder's number of entries, and
+// when it was last modified. Images also get a thumbnail, unless turned off
+// with SetThumbnails; those that cannot be decoded keep the file's icon.
+func (p *Provider) Preview(ctx context.Context, r search.Result) (search.Result, error) {
+	info, err := os.Stat(r.Target)
+	if err != nil {
+		return r, err
+	}
+	detail := formatSize(info.Size())
+	if info.IsDir() {
+		entries, err := os.ReadDir(r.Target)
+		if err != nil {
+			return r, err
+		}
+		detail = fmt.Sprintf("%d items", len(entries))
+	}
+	r.Preview = detail + " · Modified " + info.ModTime().Format("Jan 2, 2006 15:04")
+	p.mu.Lock()
+	thumbnails := p.thumbnails
+	p.mu.Unlock()
+	if !info.IsDir() && thumbnail.IsImage(r.Target) && thumbnails() {
+		r.Thumbnail, _ = p.thumbs.Get(r.Target)
+	}
+	return r, nil
+}
+
+// formatSize renders n bytes in the largest unit that keeps it above one.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	v, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB", "TB"} {
+		if v < unit {
+			break
+		}
+		v, suffix = v/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", v, suffix)
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch actionID {
+	case actionOpen:
+		return p.plat.Open(ctx, r.Target)
+	case actionReveal:
+		return p.plat.Reveal(ctx, r.Target)
+	}
+	return fmt.Errorf("files: unknown action %q", actionID)
+}
+
+func result(e entry, score float64) search.Result {
+	typ := "file"
+	if e.dir {
+		typ = "folder"
+	}
+	return search.Result{
+		ID:       providerID + ":" + e.path,
+		Type:     typ,
+		Title:    e.name,
+		Subtitle: pathfmt.Truncate(e.path, pathfmt.SubtitleLen),
+		Target:   e.path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// build returns the index, walking the roots first if it is stale. The slice
+// is replaced rather than mutated, so callers may read it without the lock.
+func (p *Provider) build() []entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.built {
+		return p.entries
+	}
+	var (
+		entries []entry
+		dirs    []string
+		partial bool
+	)
+	for _, root := range p.roots {
+		base := index.Expand(root.Path)
+		index.Walk(root, p.ignore, func(path string, d fs.DirEntry) bool {
+			if len(entries) >= maxEntries || !p.hidden && index.Hidden(d.Name()) {
+				return false
+			}
+			entries = append(entries, entry{name: d.Name(), path: path, dir: d.IsDir()})
+			if !d.IsDir() {
+				return true
+			}
+			app := filepath.Ext(d.Name()) == ".app"
+			depth := strings.Count(strings.TrimPrefix(path, base), string(filepath.Separator))
+			switch {
+			case app:
+			case depth <= watchDepth && len(dirs) < maxWatches:
+				dirs = append(dirs, path)
+			default:
+				partial = true
+			}
+			return !app
+		})
+	}
+	p.entries, p.dirs, p.partial, p.built = entries, dirs, partial, true
+	return entries
+}
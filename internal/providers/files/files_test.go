@@ -0,0 +1,128 @@
+package files
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/lru"
+	"changeme/internal/search"
+)
+
+func TestPreviewThumbnails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "picture.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, enabled := range []bool{true, false} {
+		budget := lru.NewBudget(lru.DefaultLimit)
+		p := New(nil, fuzzy.Default(), budget)
+		p.SetThumbnails(func() bool { return enabled })
+		r, err := p.Preview(context.Background(), search.Result{ID: providerID + ":" + path, Target: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Preview == "" {
+			t.Error("no preview")
+		}
+		if got := r.Thumbnail != ""; got != enabled {
+			t.Errorf("thumbnails enabled %v: got a thumbnail %v", enabled, got)
+		}
+		// A thumbnail that is not wanted is not made, rather than made and
+		// dropped.
+		if cached := budget.Usage().Entries > 0; cached != enabled {
+			t.Errorf("thumbnails enabled %v: thumbnail cached %v", enabled, cached)
+		}
+	}
+}
+
+func TestShowHidden(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"notes.md", ".env", ".config/app.json", "app/node_modules/x.js", "Archive/old.md"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := New(nil, fuzzy.Default(), lru.NewBudget(lru.DefaultLimit))
+	p.SetRoots([]index.Root{{Path: root}}, []string{"node_modules", filepath.Join(root, "Archive")})
+	indexed := func() []string {
+		var out []string
+		for _, e := range p.build() {
+			rel, _ := filepath.Rel(root, e.path)
+			out = append(out, filepath.ToSlash(rel))
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	if got, want := indexed(), []string{"app", "notes.md"}; !slices.Equal(got, want) {
+		t.Errorf("indexed %v, want %v", got, want)
+	}
+	// Showing hidden files rebuilds the index; the ignore list still holds.
+	p.SetShowHidden(true)
+	if got, want := indexed(), []string{".config", ".config/app.json", ".env", "app", "notes.md"}; !slices.Equal(got, want) {
+		t.Errorf("indexed with hidden files %v, want %v", got, want)
+	}
+}
+
+func TestWatchedDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a/b/c/d.txt", "e/f.txt", "Tool.app/Contents/x"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		depth   int
+		want    []string
+		partial bool
+	}{
+		// a/b/c is indexed but deeper than watchDepth.
+		{0, []string{"a", "a/b", "e"}, true},
+		{2, []string{"a", "a/b", "e"}, false},
+	}
+	for _, tt := range tests {
+		p := New(nil, fuzzy.Default(), lru.NewBudget(lru.DefaultLimit))
+		p.SetRoots([]index.Root{{Path: root, MaxDepth: tt.depth}}, nil)
+		p.build()
+		var got []string
+		for _, d := range p.dirs {
+			rel, _ := filepath.Rel(root, d)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) || p.partial != tt.partial {
+			t.Errorf("max depth %d: watching %v, partial %v; want %v, %v", tt.depth, got, p.partial, tt.want, tt.partial)
+		}
+	}
+}
+
+func TestRefreshCoalesced(t *testing.T) {
+	p := New(nil, fuzzy.Default(), lru.NewBudget(lru.DefaultLimit))
+	p.SetRoots(nil, nil)
+	p.SetShowHidden(true)
+	p.refresh()
+	if n := len(p.changed); n != 1 {
+		t.Errorf("%d rebuilds pending after three requests, want 1", n)
+	}
+}
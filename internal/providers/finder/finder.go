@@ -0,0 +1,168 @@
+// Package finder provides results for the Finder windows and tabs that are
+// already open, so the user can jump back to a folder instead of reopening it.
+package finder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/pathfmt"
+	"changeme/internal/search"
+)
+
+const (
+	providerID   = "finder"
+	actionFocus  = "focus"
+	actionReveal = "reveal"
+)
+
+// listScript prints one line per Finder window: id, name, POSIX path and
+// bounds, separated by tabs. Finder reports every tab as its own Finder
+// window; tabs of the same window share its bounds.
+const listScript = `tell application "Finder"
+	set out to ""
+	repeat with w in (every Finder window)
+		try
+			set p to POSIX path of (target of w as alias)
+		on error
+			set p to ""
+		end try
+		set b to bounds of w
+		set out to out & (id of w) & tab & (name of w) & tab & p & tab & (item 1 of b) & "," & (item 2 of b) & "," & (item 3 of b) & "," & (item 4 of b) & linefeed
+	end repeat
+	return out
+end tell`
+
+type window struct {
+	id     int
+	name   string
+	path   string
+	bounds string
+	tab    int // 1-based position among windows sharing bounds
+	tabs   int
+}
+
+// Provider searches open Finder windows by folder name.
+type Provider struct {
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	windows []window
+	loaded  bool
+}
+
+// New returns a Finder provider that ranks windows with matcher.
+func New(matcher *fuzzy.Matcher) *Provider {
+	return &Provider{matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession drops the cached window list so the next search re-reads it.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.windows, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	windows, err := p.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []search.Result
+	for _, w := range windows {
+		score, ok := p.matcher.Match(query, w.name)
+		if !ok {
+			continue
+		}
+		subtitle := pathfmt.Truncate(w.path, pathfmt.SubtitleLen)
+		if w.tabs > 1 {
+			subtitle = fmt.Sprintf("Tab %d of %d · %s", w.tab, w.tabs, subtitle)
+		}
+		results = append(results, search.Result{
+			ID:       providerID + ":" + strconv.Itoa(w.id),
+			Type:     "window",
+			Title:    w.name,
+			Subtitle: subtitle,
+			Score:    float64(score),
+			Actions: []search.Action{
+				{ID: actionFocus, Title: "Bring to Front"},
+				{ID: actionReveal, Title: "Open in New Window"},
+			},
+		})
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	id := strings.TrimPrefix(r.ID, providerID+":")
+	if _, err := strconv.Atoi(id); err != nil {
+		return fmt.Errorf("finder: malformed result id %q", r.ID)
+	}
+	var script string
+	switch actionID {
+	case actionFocus:
+		script = `tell application "Finder"
+	set index of Finder window id ` + id + ` to 1
+	activate
+end tell`
+	case actionReveal:
+		script = `tell application "Finder"
+	make new Finder window to (target of Finder window id ` + id + `)
+	activate
+end tell`
+	default:
+		return fmt.Errorf("finder: unknown action %q", actionID)
+	}
+	_, err := osascript.Run(ctx, "Finder", script)
+	return err
+}
+
+// list returns the open windows, reading them from Finder once per session.
+func (p *Provider) list(ctx context.Context) ([]window, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.windows, nil
+	}
+	out, err := osascript.Run(ctx, "Finder", listScript)
+	if err != nil {
+		return nil, err
+	}
+	p.windows, p.loaded = parseWindows(out), true
+	return p.windows, nil
+}
+
+func parseWindows(out string) []window {
+	var windows []window
+	groups := make(map[string][]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		w := window{id: id, name: fields[1], path: fields[2], bounds: fields[3]}
+		groups[w.bounds] = append(groups[w.bounds], len(windows))
+		windows = append(windows, w)
+	}
+	for _, idx := range groups {
+		for n, i := range idx {
+			windows[i].tab, windows[i].tabs = n+1, len(idx)
+		}
+	}
+	return windows
+}
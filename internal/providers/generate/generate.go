@@ -0,0 +1,171 @@
+// Package generate makes random values on request: "uuid" for a version 4
+// UUID, "password 20 alphanumeric" for a password and "random 1 6" for a
+// number. Each search draws fresh values, and every result can regenerate
+// them without retyping.
+package generate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID       = "generate"
+	actionRegenerate = "regenerate"
+
+	// score ranks results above anything fuzzy-matched on the keyword.
+	score = 100
+	// maxPasswordLength bounds a requested length.
+	maxPasswordLength = 1024
+	// maxNumber bounds the range of "random", keeping lo-hi+1 in range.
+	maxNumber = 1 << 53
+	// defaultMax is the upper bound of a bare "random".
+	defaultMax = 100
+)
+
+// Provider generates the random values a query asks for.
+type Provider struct {
+	out clipboard.Output
+
+	mu      sync.Mutex
+	length  int
+	preset  string
+	presets map[string]string
+}
+
+// New returns a generator provider that copies or pastes through out.
+func New(out clipboard.Output) *Provider {
+	return &Provider{out: out, length: 20, preset: PresetSymbols}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetPasswords sets the length and preset "password" uses when the query
+// names none, and adds named character sets to the built-in presets.
+func (p *Provider) SetPasswords(length int, preset string, presets map[string]string) {
+	p.mu.Lock()
+	p.length, p.preset, p.presets = length, preset, presets
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	switch fields[0] {
+	case "uuid":
+		if len(fields) > 1 {
+			return nil, nil
+		}
+		return []search.Result{p.result("uuid", UUID(), "Random UUID")}, nil
+	case "password":
+		value, desc, ok := p.password(fields[1:])
+		if !ok {
+			return nil, nil
+		}
+		return []search.Result{p.result("password", value, desc)}, nil
+	case "random":
+		lo, hi, ok := bounds(fields[1:])
+		if !ok {
+			return nil, nil
+		}
+		value := strconv.Itoa(Number(lo, hi))
+		return []search.Result{p.result("random", value, fmt.Sprintf("Random Number from %d to %d", lo, hi))}, nil
+	}
+	return nil, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	_, value, ok := strings.Cut(strings.TrimPrefix(r.ID, providerID+":"), ":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case actionRegenerate:
+		return search.ErrSearchAgain
+	case config.ClipboardActionCopy, config.ClipboardActionPaste, config.ClipboardActionPastePlain:
+		return p.out.Deliver(ctx, value, actionID)
+	}
+	return fmt.Errorf("generate: unknown action %q", actionID)
+}
+
+func (p *Provider) result(kind, value, subtitle string) search.Result {
+	actions := clipboard.Actions(p.out.DefaultMode())
+	// Regenerate goes second, where the secondary shortcut runs it.
+	actions = append(actions[:1:1], append([]search.Action{{ID: actionRegenerate, Title: "Regenerate"}}, actions[1:]...)...)
+	return search.Result{
+		ID:       providerID + ":" + kind + ":" + value,
+		Type:     "text",
+		Title:    value,
+		Subtitle: subtitle,
+		Score:    score,
+		Actions:  actions,
+	}
+}
+
+// password reads an optional length and preset, in either order, and
+// generates a password from them.
+func (p *Provider) password(args []string) (value, desc string, ok bool) {
+	p.mu.Lock()
+	length, preset, presets := p.length, p.preset, p.presets
+	p.mu.Unlock()
+	if len(args) > 2 {
+		return "", "", false
+	}
+	for _, a := range args {
+		if n, err := strconv.Atoi(a); err == nil {
+			if n < 1 || n > maxPasswordLength {
+				return "", "", false
+			}
+			length = n
+		} else {
+			preset = a
+		}
+	}
+	if length < 1 || length > maxPasswordLength {
+		length = 20
+	}
+	if preset == PresetPronounceable {
+		return Pronounceable(length), fmt.Sprintf("%d-Letter Pronounceable Password", length), true
+	}
+	charset, ok := presets[preset]
+	if !ok {
+		charset, ok = charsets[preset]
+	}
+	if !ok || charset == "" {
+		return "", "", false
+	}
+	return Password(length, charset), fmt.Sprintf("%d-Character Password (%s)", length, preset), true
+}
+
+// bounds reads "random", "random hi" or "random lo hi", returning the
+// bounds in order.
+func bounds(args []string) (lo, hi int, ok bool) {
+	nums := make([]int, len(args))
+	for i, a := range args {
+		n, err := strconv.Atoi(a)
+		if err != nil || n > maxNumber || n < -maxNumber {
+			return 0, 0, false
+		}
+		nums[i] = n
+	}
+	switch len(nums) {
+	case 0:
+		return 1, defaultMax, true
+	case 1:
+		lo, hi = 1, nums[0]
+	case 2:
+		lo, hi = nums[0], nums[1]
+	default:
+		return 0, 0, false
+	}
+	return min(lo, hi), max(lo, hi), true
+}
@@ -0,0 +1,134 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+type discard struct{}
+
+func (discard) DefaultMode() string                                  { return "copy" }
+func (discard) Deliver(ctx context.Context, text, mode string) error { return nil }
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		u := UUID()
+		if !uuidPattern.MatchString(u) {
+			t.Fatalf("UUID() = %q, not a version 4 UUID", u)
+		}
+		if seen[u] {
+			t.Fatalf("UUID() repeated %q", u)
+		}
+		seen[u] = true
+	}
+}
+
+// only reports whether s is drawn from charset alone.
+func only(s, charset string) bool {
+	return !strings.ContainsFunc(s, func(r rune) bool { return !strings.ContainsRune(charset, r) })
+}
+
+func TestSearchPassword(t *testing.T) {
+	p := New(discard{})
+	p.SetPasswords(16, PresetAlphanumeric, map[string]string{"pin": "0123456789"})
+	tests := []struct {
+		query   string
+		length  int
+		charset string // "" for pronounceable
+	}{
+		{"password", 16, alphanumeric},
+		{"password 20", 20, alphanumeric},
+		{"password symbols", 16, symbols},
+		{"password symbols 32", 32, symbols},
+		{"password 6 pin", 6, "0123456789"},
+		{"password 12 pronounceable", 12, ""},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Search(%q) = %v, %v; want one password", tt.query, results, err)
+		}
+		pw := results[0].Title
+		if n := len([]rune(pw)); n != tt.length {
+			t.Errorf("Search(%q) = %q, %d characters, want %d", tt.query, pw, n, tt.length)
+		}
+		if tt.charset != "" && !only(pw, tt.charset) {
+			t.Errorf("Search(%q) = %q, outside %q", tt.query, pw, tt.charset)
+		}
+		if tt.charset == "" {
+			for i, r := range pw {
+				set := consonants
+				if i%2 == 1 {
+					set = vowels
+				}
+				if !strings.ContainsRune(set, r) {
+					t.Errorf("Search(%q) = %q, not pronounceable", tt.query, pw)
+					break
+				}
+			}
+		}
+	}
+
+	for _, q := range []string{"password 0", "password 5000", "password nosuchpreset", "password 1 2 3"} {
+		if results, _ := p.Search(context.Background(), q); len(results) != 0 {
+			t.Errorf("Search(%q) = %v, want none", q, results)
+		}
+	}
+}
+
+func TestPasswordUsesWholeCharset(t *testing.T) {
+	seen := make(map[rune]bool)
+	for _, r := range Password(2000, "abc") {
+		seen[r] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("2000 characters from abc used %d of them", len(seen))
+	}
+}
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		args   []string
+		lo, hi int
+		ok     bool
+	}{
+		{nil, 1, defaultMax, true},
+		{[]string{"6"}, 1, 6, true},
+		{[]string{"10", "20"}, 10, 20, true},
+		{[]string{"20", "10"}, 10, 20, true},
+		{[]string{"-5", "5"}, -5, 5, true},
+		{[]string{"x"}, 0, 0, false},
+		{[]string{"1", "2", "3"}, 0, 0, false},
+		{[]string{"1", "99999999999999999"}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		lo, hi, ok := bounds(tt.args)
+		if lo != tt.lo || hi != tt.hi || ok != tt.ok {
+			t.Errorf("bounds(%q) = %d, %d, %v; want %d, %d, %v", tt.args, lo, hi, ok, tt.lo, tt.hi, tt.ok)
+		}
+	}
+	for range 100 {
+		if n := Number(3, 5); n < 3 || n > 5 {
+			t.Fatalf("Number(3, 5) = %d", n)
+		}
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	p := New(discard{})
+	results, _ := p.Search(context.Background(), "uuid")
+	if len(results) != 1 || results[0].Actions[1].ID != actionRegenerate {
+		t.Fatalf("Search(uuid) = %+v, want regenerate as the second action", results)
+	}
+	if err := p.Activate(context.Background(), results[0], actionRegenerate); !errors.Is(err, search.ErrSearchAgain) {
+		t.Errorf("regenerate = %v, want ErrSearchAgain", err)
+	}
+}
@@ -0,0 +1,83 @@
+package generate
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Built-in password presets. A preset names a character set, except
+// pronounceable, which alternates consonants and vowels.
+const (
+	PresetAlphanumeric  = "alphanumeric"
+	PresetSymbols       = "symbols"
+	PresetPronounceable = "pronounceable"
+)
+
+const (
+	alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	symbols      = alphanumeric + "!#$%&*+-=?@^_~"
+	consonants   = "bcdfghjklmnprstvwxz"
+	vowels       = "aeiou"
+)
+
+// charsets are the built-in presets that are plain character sets.
+var charsets = map[string]string{
+	PresetAlphanumeric: alphanumeric,
+	PresetSymbols:      symbols,
+}
+
+// intn returns a uniformly random integer in [0, n) from crypto/rand.
+func intn(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// crypto/rand does not fail on supported platforms.
+		panic(err)
+	}
+	return int(v.Int64())
+}
+
+// UUID returns a random version 4 UUID in its canonical lowercase form.
+func UUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Password returns length characters drawn uniformly from charset.
+func Password(length int, charset string) string {
+	set := []rune(charset)
+	var sb strings.Builder
+	for range length {
+		sb.WriteRune(set[intn(len(set))])
+	}
+	return sb.String()
+}
+
+// Pronounceable returns a lowercase password of length letters alternating
+// consonants and vowels, like "bakotemi". It is easier to type and read out
+// than a Password of the same length, and weaker.
+func Pronounceable(length int) string {
+	var sb strings.Builder
+	for i := range length {
+		set := consonants
+		if i%2 == 1 {
+			set = vowels
+		}
+		sb.WriteByte(set[intn(len(set))])
+	}
+	return sb.String()
+}
+
+// Number returns a random integer in [lo, hi].
+func Number(lo, hi int) int {
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	return lo + intn(hi-lo+1)
+}
@@ -0,0 +1,84 @@
+package grep
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// rule is one pattern from a .gitignore file.
+type rule struct {
+	base     string // directory holding the .gitignore
+	pattern  string // slash-separated, without the markers below
+	negate   bool   // "!pattern" re-includes a path
+	dirOnly  bool   // "pattern/" matches directories only
+	anchored bool   // a pattern containing a slash is relative to base
+}
+
+// ignorer applies the .gitignore files found while walking. It covers the
+// common forms of the format: comments, negation, directory-only and
+// anchored patterns, and a leading "**/". Other uses of "**" match a single
+// path segment.
+type ignorer struct {
+	root  string
+	rules []rule
+}
+
+func newIgnorer(root string) *ignorer {
+	return &ignorer{root: root}
+}
+
+// load reads the .gitignore in dir, if any. Directories are loaded as they
+// are entered, so rules from deeper files come later and take precedence.
+func (ig *ignorer) load(dir string) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := rule{base: dir}
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimSuffix(line, "/")
+		}
+		line = strings.TrimPrefix(line, "**/")
+		if strings.Contains(line, "/") {
+			r.anchored, line = true, strings.TrimPrefix(line, "/")
+		}
+		r.pattern = strings.ReplaceAll(line, "**", "*")
+		ig.rules = append(ig.rules, r)
+	}
+}
+
+// ignored reports whether the last rule matching p excludes it.
+func (ig *ignorer) ignored(p string, dir bool) bool {
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !dir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, p)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		name := rel
+		if !r.anchored {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(r.pattern, name); ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
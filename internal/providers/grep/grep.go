@@ -0,0 +1,349 @@
+// Package grep searches the contents of files under the user's project
+// folders, using ripgrep when it is installed.
+package grep
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/editor"
+	"changeme/internal/index"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID   = "grep"
+	actionOpen   = "open"
+	actionReveal = "reveal"
+
+	// minQuery is the shortest query searched; shorter ones match nearly
+	// every file.
+	minQuery = 2
+	// maxResults bounds the results of one query. Only the first matching
+	// line of each file is reported.
+	maxResults = 50
+	// maxFileSize skips files too large to be source code.
+	maxFileSize = 1 << 20
+	// maxLine truncates matching lines shown as the subtitle.
+	maxLine = 200
+)
+
+// match is a matching line.
+type match struct {
+	path string
+	line int
+	text string
+}
+
+// Provider searches file contents under its roots.
+type Provider struct {
+	plat platform.Platform
+
+	mu     sync.Mutex
+	roots  []string
+	editor string
+	hidden bool
+	ignore []string
+}
+
+// New returns a grep provider that opens and reveals files through plat.
+func New(plat platform.Platform) *Provider {
+	return &Provider{plat: plat}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps content search, which reads many files, to queries
+// routed to it.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// SetFilter sets whether hidden files and folders, whose names start with a
+// dot, are searched, and the patterns of those never searched; see
+// index.Ignored. .git folders are never searched either way.
+func (p *Provider) SetFilter(showHidden bool, ignore []string) {
+	p.mu.Lock()
+	p.hidden, p.ignore = showHidden, ignore
+	p.mu.Unlock()
+}
+
+// SetRoots sets the project folders searched. A leading "~/" is expanded.
+func (p *Provider) SetRoots(roots []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots = nil
+	for _, r := range roots {
+		p.roots = append(p.roots, index.Expand(r))
+	}
+}
+
+// SetEditor sets the command that opens a file at a line, such as
+// "code -g {file}:{line}". The command is split on spaces; {file} and {line}
+// are replaced in each argument. Without an editor, files are opened with
+// their default application at the top.
+func (p *Provider) SetEditor(editor string) {
+	p.mu.Lock()
+	p.editor = editor
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < minQuery {
+		return nil, nil
+	}
+	p.mu.Lock()
+	roots := append([]string(nil), p.roots...)
+	f := filter{hidden: p.hidden, ignore: p.ignore}
+	p.mu.Unlock()
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	var (
+		matches []match
+		err     error
+	)
+	if rg, lookErr := exec.LookPath("rg"); lookErr == nil {
+		matches, err = ripgrep(ctx, rg, query, roots, f)
+	} else {
+		matches, err = walk(ctx, query, roots, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	results := make([]search.Result, 0, len(matches))
+	for i, m := range matches {
+		// Keep the order matches were found in; ranking by fuzzy score means
+		// little for literal content matches.
+		results = append(results, result(m, float64(len(matches)-i)))
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch actionID {
+	case actionOpen:
+		p.mu.Lock()
+		ed := p.editor
+		p.mu.Unlock()
+		if ed == "" {
+			return p.plat.Open(ctx, r.Target)
+		}
+		_, line := splitID(r.ID)
+		return editor.Open(ed, r.Target, line)
+	case actionReveal:
+		return p.plat.Reveal(ctx, r.Target)
+	}
+	return fmt.Errorf("grep: unknown action %q", actionID)
+}
+
+func result(m match, score float64) search.Result {
+	return search.Result{
+		ID:       fmt.Sprintf("%s:%s:%d", providerID, m.path, m.line),
+		Type:     "file",
+		Title:    fmt.Sprintf("%s:%d", filepath.Base(m.path), m.line),
+		Subtitle: m.text,
+		Target:   m.path,
+		Score:    score,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open at Line"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+// splitID returns the path and line number of a result ID.
+func splitID(id string) (path string, line int) {
+	rest := strings.TrimPrefix(id, providerID+":")
+	i := strings.LastIndex(rest, ":")
+	if i < 0 {
+		return rest, 1
+	}
+	line, err := strconv.Atoi(rest[i+1:])
+	if err != nil {
+		return rest, 1
+	}
+	return rest[:i], line
+}
+
+// filter is which files are searched besides what .gitignore files
+// exclude: hidden ones only when hidden is set, and none matching ignore.
+type filter struct {
+	hidden bool
+	ignore []string
+}
+
+// skip reports whether the entry at path, under one of the roots, is left
+// out.
+func (f filter) skip(path string) bool {
+	return !f.hidden && index.Hidden(filepath.Base(path)) || index.Ignored(path, f.ignore)
+}
+
+// ripgrep runs rg, which honours .gitignore files itself, and reads matches
+// until it has enough. The query is a literal string, matched
+// case-insensitively unless it contains an upper-case letter. rg leaves out
+// hidden files unless told otherwise; ignored names are passed to it as
+// globs, and matches under ignored paths dropped as they are read.
+func ripgrep(parent context.Context, rg, query string, roots []string, f filter) ([]match, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	args := []string{
+		"--null", "--line-number", "--no-heading", "--color=never",
+		"--fixed-strings", "--smart-case", "--max-count=1",
+		"--max-filesize=" + strconv.Itoa(maxFileSize),
+	}
+	if f.hidden {
+		args = append(args, "--hidden", "--glob=!.git")
+	}
+	var paths []string
+	for _, pattern := range f.ignore {
+		if strings.Contains(pattern, "/") {
+			paths = append(paths, pattern)
+		} else {
+			args = append(args, "--glob=!"+pattern)
+		}
+	}
+	args = append(args, "--", query)
+	cmd := exec.CommandContext(ctx, rg, append(args, roots...)...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var matches []match
+	sc := bufio.NewScanner(out)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() && len(matches) < maxResults {
+		// Each line is the path, a NUL, then "line:text".
+		path, rest, ok := strings.Cut(sc.Text(), "\x00")
+		if !ok {
+			continue
+		}
+		num, text, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		line, err := strconv.Atoi(num)
+		if err != nil || underIgnored(path, paths) {
+			continue
+		}
+		matches = append(matches, match{path: path, line: line, text: trimLine(text)})
+	}
+	cancel()
+	// rg exits with status 1 when nothing matched and is killed when enough
+	// matches were read; neither is an error here.
+	cmd.Wait()
+	if err := parent.Err(); err != nil && len(matches) == 0 {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// underIgnored reports whether path is in a folder matching one of the
+// ignore patterns that name paths.
+func underIgnored(path string, patterns []string) bool {
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if index.Ignored(dir, patterns) {
+			return true
+		}
+	}
+	return index.Ignored(path, patterns)
+}
+
+// walk is the fallback when ripgrep is not installed. It applies the same
+// matching rules as ripgrep's flags above and skips what .gitignore files
+// and f exclude, along with .git directories and binary files.
+func walk(ctx context.Context, query string, roots []string, f filter) ([]match, error) {
+	needle := []byte(query)
+	fold := strings.ToLower(query) == query
+	if fold {
+		needle = bytes.ToLower(needle)
+	}
+
+	var matches []match
+	for _, root := range roots {
+		ig := newIgnorer(root)
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				if d != nil && d.IsDir() && path != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if len(matches) >= maxResults {
+				return filepath.SkipAll
+			}
+			if d.IsDir() {
+				if path != root && (d.Name() == ".git" || f.skip(path) || ig.ignored(path, true)) {
+					return filepath.SkipDir
+				}
+				ig.load(path)
+				return nil
+			}
+			if !d.Type().IsRegular() || f.skip(path) || ig.ignored(path, false) {
+				return nil
+			}
+			if m, ok := grepFile(path, needle, fold); ok {
+				matches = append(matches, m)
+			}
+			return nil
+		})
+		if err != nil {
+			return matches, err
+		}
+	}
+	return matches, nil
+}
+
+// grepFile returns the first line of path containing needle.
+func grepFile(path string, needle []byte, fold bool) (match, bool) {
+	fi, err := os.Stat(path)
+	if err != nil || fi.Size() > maxFileSize {
+		return match{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return match{}, false
+	}
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return match{}, false
+	}
+	for n, line := range bytes.Split(data, []byte("\n")) {
+		hay := line
+		if fold {
+			hay = bytes.ToLower(line)
+		}
+		if bytes.Contains(hay, needle) {
+			return match{path: path, line: n + 1, text: trimLine(string(line))}, true
+		}
+	}
+	return match{}, false
+}
+
+// trimLine prepares a matching line for display.
+func trimLine(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxLine {
+		s = s[:maxLine] + "…"
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package grep
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// fixture writes files, keyed by slash-separated path, under a temporary
+// directory and returns it.
+func fixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// found returns the matches as "path:line text", with paths relative to
+// root.
+func found(t *testing.T, root string, matches []match) []string {
+	t.Helper()
+	var out []string
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, fmt.Sprintf("%s:%d %s", filepath.ToSlash(rel), m.line, m.text))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestWalk(t *testing.T) {
+	root := fixture(t, map[string]string{
+		"main.go":                 "package main\n\n// Deploy the widget\nfunc deploy() {}\n",
+		"docs/notes.md":           "# Notes\nnothing here\nsee DEPLOY.md\n",
+		"build/out.txt":           "deploy artefact\n",
+		"vendor/lib.go":           "deploy\n",
+		"node_modules/x/index.js": "deploy()\n",
+		".env":                    "DEPLOY_KEY=1\n",
+		".gitignore":              "build/\n*.log\n",
+		"debug.log":               "deploy failed\n",
+		"bin.dat":                 "deploy\x00\x01",
+	})
+	f := filter{ignore: []string{"node_modules", filepath.Join(root, "vendor")}}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Lower case matches any case; the first matching line is taken.
+		{"deploy", []string{"docs/notes.md:3 see DEPLOY.md", "main.go:3 // Deploy the widget"}},
+		// An upper-case letter makes the match case-sensitive.
+		{"Deploy", []string{"main.go:3 // Deploy the widget"}},
+		{"nothing", []string{"docs/notes.md:2 nothing here"}},
+		{"absent", nil},
+	}
+	for _, tt := range tests {
+		matches, err := walk(context.Background(), tt.query, []string{root}, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := found(t, root, matches); !slices.Equal(got, tt.want) {
+			t.Errorf("walk(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	f.hidden = true
+	matches, err := walk(context.Background(), "deploy_key", []string{root}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := found(t, root, matches), []string{".env:1 DEPLOY_KEY=1"}; !slices.Equal(got, want) {
+		t.Errorf("walk with hidden files = %v, want %v", got, want)
+	}
+}
+
+func TestSplitID(t *testing.T) {
+	tests := []struct {
+		id   string
+		path string
+		line int
+	}{
+		{"grep:/src/main.go:12", "/src/main.go", 12},
+		{"grep:C:/src/main.go:3", "C:/src/main.go", 3},
+		{"grep:/src/main.go", "/src/main.go", 1},
+	}
+	for _, tt := range tests {
+		path, line := splitID(tt.id)
+		if path != tt.path || line != tt.line {
+			t.Errorf("splitID(%q) = %q, %d; want %q, %d", tt.id, path, line, tt.path, tt.line)
+		}
+	}
+	r := result(match{path: "/src/main.go", line: 12, text: "x"}, 1)
+	if path, line := splitID(r.ID); path != "/src/main.go" || line != 12 || r.Title != "main.go:12" {
+		t.Errorf("result %+v does not round-trip through splitID", r)
+	}
+}
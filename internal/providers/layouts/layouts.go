@@ -0,0 +1,83 @@
+// Package layouts provides results for the configured launch layouts, each
+// opening a set of apps, files and URLs together.
+package layouts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/search"
+)
+
+const (
+	providerID  = "layouts"
+	actionApply = "apply"
+)
+
+// Provider matches the query against layout names.
+type Provider struct {
+	matcher *fuzzy.Matcher
+	apply   func(ctx context.Context, name string) error
+
+	mu      sync.Mutex
+	layouts []config.LaunchLayout
+}
+
+// New returns a layouts provider ranking layouts with matcher. Activating a
+// result calls apply with the layout's name.
+func New(matcher *fuzzy.Matcher, apply func(ctx context.Context, name string) error) *Provider {
+	return &Provider{matcher: matcher, apply: apply}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetLayouts replaces the configured layouts.
+func (p *Provider) SetLayouts(layouts []config.LaunchLayout) {
+	p.mu.Lock()
+	p.layouts = layouts
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	p.mu.Lock()
+	layouts := p.layouts
+	p.mu.Unlock()
+	var results []search.Result
+	for _, l := range layouts {
+		score, ok := p.matcher.Match(query, l.Name)
+		if !ok || len(l.Items) == 0 {
+			continue
+		}
+		results = append(results, search.Result{
+			ID:       providerID + ":" + l.Name,
+			Type:     "layout",
+			Title:    l.Name,
+			Subtitle: fmt.Sprintf("Open %d %s: %s", len(l.Items), plural(len(l.Items)), strings.Join(l.Items, ", ")),
+			Score:    float64(score),
+			Actions:  []search.Action{{ID: actionApply, Title: "Open Layout"}},
+		})
+	}
+	return results, nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "item"
+	}
+	return "items"
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionApply {
+		return fmt.Errorf("layouts: unknown action %q", actionID)
+	}
+	return p.apply(ctx, strings.TrimPrefix(r.ID, providerID+":"))
+}
@@ -0,0 +1,89 @@
+//go:build cgo
+
+package levels
+
+/*
+#cgo LDFLAGS: -framework CoreGraphics -framework IOKit -framework CoreFoundation
+#include <dlfcn.h>
+#include <CoreGraphics/CoreGraphics.h>
+#include <IOKit/graphics/IOGraphicsLib.h>
+
+typedef int (*prismGetFn)(CGDirectDisplayID, float *);
+typedef int (*prismSetFn)(CGDirectDisplayID, float);
+
+// prismDisplayServices opens the private DisplayServices framework, which
+// controls the built-in display of Apple silicon Macs, or returns NULL.
+// Opening it again returns the same handle, so a race here is harmless.
+static void *prismDisplayServices(void) {
+	static void *handle = NULL;
+	if (handle == NULL) {
+		handle = dlopen("/System/Library/PrivateFrameworks/DisplayServices.framework/DisplayServices", RTLD_LAZY);
+	}
+	return handle;
+}
+
+// prismDisplay returns the IODisplay service of the first display that has
+// one, for Macs without DisplayServices. The caller releases it.
+static io_service_t prismDisplay(void) {
+	return IOServiceGetMatchingService(MACH_PORT_NULL, IOServiceMatching("IODisplayConnect"));
+}
+
+// prismGetBrightness reads the main display's brightness, 0–1, returning
+// 0 on success.
+static int prismGetBrightness(float *level) {
+	void *ds = prismDisplayServices();
+	if (ds != NULL) {
+		prismGetFn get = (prismGetFn)dlsym(ds, "DisplayServicesGetBrightness");
+		if (get != NULL && get(CGMainDisplayID(), level) == 0) {
+			return 0;
+		}
+	}
+	io_service_t service = prismDisplay();
+	if (service == 0) {
+		return -1;
+	}
+	IOReturn err = IODisplayGetFloatParameter(service, kNilOptions, CFSTR(kIODisplayBrightnessKey), level);
+	IOObjectRelease(service);
+	return err == kIOReturnSuccess ? 0 : -1;
+}
+
+// prismSetBrightness sets the main display's brightness, 0–1, returning 0
+// on success.
+static int prismSetBrightness(float level) {
+	void *ds = prismDisplayServices();
+	if (ds != NULL) {
+		prismSetFn set = (prismSetFn)dlsym(ds, "DisplayServicesSetBrightness");
+		if (set != NULL && set(CGMainDisplayID(), level) == 0) {
+			return 0;
+		}
+	}
+	io_service_t service = prismDisplay();
+	if (service == 0) {
+		return -1;
+	}
+	IOReturn err = IODisplaySetFloatParameter(service, kNilOptions, CFSTR(kIODisplayBrightnessKey), level);
+	IOObjectRelease(service);
+	return err == kIOReturnSuccess ? 0 : -1;
+}
+*/
+import "C"
+
+import "math"
+
+// brightness returns the main display's brightness, 0–100. External
+// displays usually cannot report it.
+func brightness() (int, error) {
+	var level C.float
+	if C.prismGetBrightness(&level) != 0 {
+		return 0, errNoBrightness
+	}
+	return clamp(int(math.Round(float64(level) * 100))), nil
+}
+
+// setBrightness sets the main display's brightness to level, 0–100.
+func setBrightness(level int) error {
+	if C.prismSetBrightness(C.float(float64(clamp(level))/100)) != 0 {
+		return errNoBrightness
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build darwin && !cgo
+
+package levels
+
+// brightness needs cgo for the display APIs.
+func brightness() (int, error) {
+	return 0, errNoBrightness
+}
+
+func setBrightness(level int) error {
+	return errNoBrightness
+}
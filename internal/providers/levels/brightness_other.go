@@ -0,0 +1,12 @@
+//go:build !darwin
+
+package levels
+
+// brightness is only supported on macOS.
+func brightness() (int, error) {
+	return 0, errNoBrightness
+}
+
+func setBrightness(level int) error {
+	return errNoBrightness
+}
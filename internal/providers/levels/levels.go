@@ -0,0 +1,130 @@
+// Package levels sets the system volume and the display brightness on
+// macOS from queries such as "volume 50", "volume +10", "mute" and
+// "brightness 80", showing the current level alongside. Levels are
+// percentages, clamped to 0–100. The volume is set through AppleScript
+// and the brightness of the main display through the display APIs.
+package levels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "levels"
+	actionSet  = "set"
+
+	// score ranks a command above fuzzy matches, since its keyword was
+	// typed in full.
+	score = 100
+)
+
+// errNoBrightness is returned when the main display's brightness cannot be
+// read or set, as for most external displays.
+var errNoBrightness = errors.New("levels: the display's brightness cannot be controlled")
+
+// Provider parses volume and brightness commands.
+type Provider struct{}
+
+// New returns a levels provider.
+func New() *Provider {
+	return &Provider{}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	c, ok := parse(query)
+	if !ok {
+		return nil, nil
+	}
+	r := search.Result{
+		ID:    providerID + ":" + c.String(),
+		Type:  "setting",
+		Score: score,
+	}
+	switch c.control {
+	case controlVolume:
+		level, muted, err := volume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.Subtitle = fmt.Sprintf("Currently %d%%", level)
+		if muted {
+			r.Subtitle += ", muted"
+		}
+		switch c.op {
+		case opShow:
+			r.Title = fmt.Sprintf("Volume %d%%", level)
+			r.Subtitle = "Type a level, such as “volume 50” or “volume +10”"
+			if muted {
+				r.Subtitle = "Muted"
+			}
+			return []search.Result{r}, nil
+		case opMute:
+			r.Title = "Mute"
+		case opUnmute:
+			r.Title = "Unmute"
+		default:
+			r.Title = fmt.Sprintf("Set Volume to %d%%", c.target(level))
+		}
+	case controlBrightness:
+		level, err := brightness()
+		if err != nil {
+			return nil, err
+		}
+		if c.op == opShow {
+			r.Title = fmt.Sprintf("Brightness %d%%", level)
+			r.Subtitle = "Type a level, such as “brightness 80” or “brightness -10”"
+			return []search.Result{r}, nil
+		}
+		r.Title = fmt.Sprintf("Set Brightness to %d%%", c.target(level))
+		r.Subtitle = fmt.Sprintf("Currently %d%%", level)
+	}
+	r.Actions = []search.Action{{ID: actionSet, Title: "Set"}}
+	return []search.Result{r}, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	q, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	c, ok := parse(q)
+	if !ok {
+		return fmt.Errorf("levels: malformed result id %q", r.ID)
+	}
+	if actionID != actionSet {
+		return fmt.Errorf("levels: unknown action %q", actionID)
+	}
+	// Relative changes apply to the level as it is now, which may have
+	// moved since the search.
+	switch c.control {
+	case controlVolume:
+		switch c.op {
+		case opMute, opUnmute:
+			return setMuted(ctx, c.op == opMute)
+		case opShow:
+			return nil
+		}
+		level, _, err := volume(ctx)
+		if err != nil {
+			return err
+		}
+		return setVolume(ctx, c.target(level))
+	case controlBrightness:
+		if c.op == opShow {
+			return nil
+		}
+		level, err := brightness()
+		if err != nil {
+			return err
+		}
+		return setBrightness(c.target(level))
+	}
+	return search.ErrUnknownResult
+}
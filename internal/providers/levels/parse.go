@@ -0,0 +1,111 @@
+package levels
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The controls a command sets.
+const (
+	controlVolume     = "volume"
+	controlBrightness = "brightness"
+)
+
+// The things a command does to its control.
+const (
+	opShow   = "show"
+	opSet    = "set"
+	opAdjust = "adjust"
+	opMute   = "mute"
+	opUnmute = "unmute"
+)
+
+// keywords map the words a command starts with to its control.
+var keywords = map[string]string{
+	"volume":     controlVolume,
+	"vol":        controlVolume,
+	"brightness": controlBrightness,
+	"bright":     controlBrightness,
+}
+
+// command is a parsed query, such as "volume +10".
+type command struct {
+	control string
+	op      string
+	// level is the level to set, 0–100, or the change to make, -100–100.
+	level int
+}
+
+// parse reads a command from query: a control's keyword alone, or with a
+// level such as "50" or "50%", a change such as "+10" or "-10", or for the
+// volume, "mute" or "unmute". "mute" and "unmute" also work on their own.
+func parse(query string) (command, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	switch {
+	case len(words) == 1 && (words[0] == opMute || words[0] == opUnmute):
+		return command{control: controlVolume, op: words[0]}, true
+	case len(words) == 0 || len(words) > 2:
+		return command{}, false
+	}
+	control, ok := keywords[words[0]]
+	if !ok {
+		return command{}, false
+	}
+	if len(words) == 1 {
+		return command{control: control, op: opShow}, true
+	}
+	arg := words[1]
+	if arg == opMute || arg == opUnmute {
+		if control != controlVolume {
+			return command{}, false
+		}
+		return command{control: control, op: arg}, true
+	}
+	arg = strings.TrimSuffix(arg, "%")
+	relative := strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return command{}, false
+	}
+	if relative {
+		return command{control: control, op: opAdjust, level: max(min(n, 100), -100)}, true
+	}
+	return command{control: control, op: opSet, level: clamp(n)}, true
+}
+
+// clamp limits level to 0–100.
+func clamp(level int) int {
+	return max(min(level, 100), 0)
+}
+
+// target returns the level c leaves its control at, from the current one.
+func (c command) target(current int) int {
+	switch c.op {
+	case opSet:
+		return c.level
+	case opAdjust:
+		return clamp(current + c.level)
+	}
+	return current
+}
+
+// String returns c in the form parse reads, for result IDs.
+func (c command) String() string {
+	switch c.op {
+	case opSet:
+		return c.control + " " + strconv.Itoa(c.level)
+	case opAdjust:
+		return c.control + " " + signed(c.level)
+	case opMute, opUnmute:
+		return c.control + " " + c.op
+	}
+	return c.control
+}
+
+// signed formats n with its sign, as "+10" or "-10".
+func signed(n int) string {
+	if n >= 0 {
+		return "+" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,90 @@
+package levels
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  command
+		ok    bool
+	}{
+		{"volume", command{control: controlVolume, op: opShow}, true},
+		{"volume 50", command{control: controlVolume, op: opSet, level: 50}, true},
+		{"Vol 50%", command{control: controlVolume, op: opSet, level: 50}, true},
+		{"volume +10", command{control: controlVolume, op: opAdjust, level: 10}, true},
+		{"volume -15%", command{control: controlVolume, op: opAdjust, level: -15}, true},
+		{"brightness 80", command{control: controlBrightness, op: opSet, level: 80}, true},
+		{"bright -5", command{control: controlBrightness, op: opAdjust, level: -5}, true},
+		{"mute", command{control: controlVolume, op: opMute}, true},
+		{" UNMUTE ", command{control: controlVolume, op: opUnmute}, true},
+		{"volume mute", command{control: controlVolume, op: opMute}, true},
+		// Levels are clamped to 0–100, and changes to ±100.
+		{"volume 150", command{control: controlVolume, op: opSet, level: 100}, true},
+		{"brightness -0", command{control: controlBrightness, op: opAdjust, level: 0}, true},
+		{"volume +250", command{control: controlVolume, op: opAdjust, level: 100}, true},
+		{"volume -250", command{control: controlVolume, op: opAdjust, level: -100}, true},
+		{"", command{}, false},
+		{"brightness mute", command{}, false},
+		{"volume loud", command{}, false},
+		{"volume 5 10", command{}, false},
+		{"mute all", command{}, false},
+		{"speakers 50", command{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parse(tt.query)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parse(%q) = %+v, %v, want %+v, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		query   string
+		current int
+		want    int
+	}{
+		{"volume 30", 70, 30},
+		{"volume +10", 70, 80},
+		{"volume +10", 95, 100},
+		{"volume -10", 5, 0},
+		{"volume", 42, 42},
+		{"mute", 42, 42},
+	}
+	for _, tt := range tests {
+		c, _ := parse(tt.query)
+		if got := c.target(tt.current); got != tt.want {
+			t.Errorf("parse(%q).target(%d) = %d, want %d", tt.query, tt.current, got, tt.want)
+		}
+		// The result ID reads back as the same command.
+		if back, ok := parse(c.String()); !ok || back != c {
+			t.Errorf("parse(%q) round trips to %+v, %v", c.String(), back, ok)
+		}
+	}
+}
+
+func TestParseVolume(t *testing.T) {
+	tests := []struct {
+		out   string
+		level int
+		muted bool
+		err   bool
+	}{
+		{"35,false\n", 35, false, false},
+		{"0,true", 0, true, false},
+		{"missing value,false", 0, false, true},
+		{"garbage", 0, false, true},
+	}
+	for _, tt := range tests {
+		level, muted, err := parseVolume(tt.out)
+		if level != tt.level || muted != tt.muted || (err != nil) != tt.err {
+			t.Errorf("parseVolume(%q) = %d, %v, %v", tt.out, level, muted, err)
+		}
+	}
+	if _, _, err := parseVolume("missing value,false"); !errors.Is(err, errNoVolume) {
+		t.Errorf("parseVolume of an output without a volume = %v, want errNoVolume", err)
+	}
+}
@@ -0,0 +1,58 @@
+package levels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"changeme/internal/osascript"
+)
+
+// volumeScript prints the output volume, 0–100, and whether output is
+// muted, separated by a comma. The volume is "missing value" for outputs
+// without a volume control, such as some HDMI displays.
+const volumeScript = `set s to get volume settings
+return (output volume of s as text) & "," & (output muted of s as text)`
+
+// errNoVolume is returned for an output whose volume cannot be set.
+var errNoVolume = errors.New("levels: the sound output has no volume control")
+
+// volume returns the output volume and whether it is muted.
+func volume(ctx context.Context) (int, bool, error) {
+	out, err := osascript.Run(ctx, "", volumeScript)
+	if err != nil {
+		return 0, false, err
+	}
+	return parseVolume(out)
+}
+
+// parseVolume reads what volumeScript prints.
+func parseVolume(out string) (int, bool, error) {
+	level, muted, ok := strings.Cut(strings.TrimSpace(out), ",")
+	if !ok {
+		return 0, false, fmt.Errorf("levels: unexpected volume settings %q", out)
+	}
+	n, err := strconv.Atoi(level)
+	if err != nil {
+		return 0, false, errNoVolume
+	}
+	return clamp(n), muted == "true", nil
+}
+
+// setVolume sets the output volume to level and unmutes it.
+func setVolume(ctx context.Context, level int) error {
+	_, err := osascript.Run(ctx, "", fmt.Sprintf("set volume output volume %d\nset volume without output muted", clamp(level)))
+	return err
+}
+
+// setMuted mutes or unmutes the output.
+func setMuted(ctx context.Context, muted bool) error {
+	script := "set volume without output muted"
+	if muted {
+		script = "set volume with output muted"
+	}
+	_, err := osascript.Run(ctx, "", script)
+	return err
+}
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"net/url"
+	"strings"
+)
+
+// field is the part of a message a word of the query goes to.
+type field int
+
+const (
+	fieldFree field = iota
+	fieldTo
+	fieldCc
+	fieldBcc
+	fieldSubject
+	fieldBody
+)
+
+// keywords name the fields a query can fill, as in "to bob subject hi".
+var keywords = map[string]field{
+	"to":      fieldTo,
+	"cc":      fieldCc,
+	"bcc":     fieldBcc,
+	"subject": fieldSubject,
+	"subj":    fieldSubject,
+	"body":    fieldBody,
+}
+
+// message is the new message a query describes.
+type message struct {
+	to, cc, bcc   []string
+	subject, body string
+	// free is the text before any keyword that is not an address. It
+	// becomes the subject when none was given, and is what recent
+	// messages are searched for.
+	free string
+}
+
+// parse reads a message from the words after the "mail" keyword. Keywords
+// are matched ignoring case and may end in a colon, as in "subject: hi".
+// So that "subject going to lunch" keeps its "to", only "body" starts a new
+// field after the subject, and nothing does after the body, unless written
+// with a colon. Recipients may be separated by commas or "and".
+func parse(query string) message {
+	var (
+		m       message
+		cur     = fieldFree
+		free    []string
+		subject []string
+		body    []string
+	)
+	for _, word := range strings.Fields(query) {
+		key, value, colon := splitKeyword(word)
+		if f, ok := keywords[key]; ok && switches(cur, f, colon) {
+			cur = f
+			if value == "" {
+				continue
+			}
+			word = value
+		}
+		switch cur {
+		case fieldFree:
+			if strings.Contains(word, "@") {
+				m.to = append(m.to, recipients(word)...)
+			} else {
+				free = append(free, word)
+			}
+		case fieldTo:
+			m.to = append(m.to, recipients(word)...)
+		case fieldCc:
+			m.cc = append(m.cc, recipients(word)...)
+		case fieldBcc:
+			m.bcc = append(m.bcc, recipients(word)...)
+		case fieldSubject:
+			subject = append(subject, word)
+		case fieldBody:
+			body = append(body, word)
+		}
+	}
+	m.free = strings.Join(free, " ")
+	m.subject = strings.Join(subject, " ")
+	if m.subject == "" {
+		m.subject = m.free
+	}
+	m.body = strings.Join(body, " ")
+	return m
+}
+
+// splitKeyword splits word into the keyword it may be and what follows a
+// colon in it, as "to:bob" is "to" and "bob".
+func splitKeyword(word string) (key, value string, colon bool) {
+	key, value, colon = strings.Cut(word, ":")
+	return strings.ToLower(key), value, colon
+}
+
+// switches reports whether a keyword for f ends the field cur; see parse.
+func switches(cur, f field, colon bool) bool {
+	switch {
+	case colon:
+		return true
+	case cur == fieldBody:
+		return false
+	case cur == fieldSubject:
+		return f == fieldBody
+	}
+	return true
+}
+
+// recipients returns the addresses in word, split on commas, leaving out
+// "and" between them.
+func recipients(word string) []string {
+	var out []string
+	for _, r := range strings.Split(word, ",") {
+		if r = strings.TrimSpace(r); r != "" && !strings.EqualFold(r, "and") {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// mailtoURL returns the mailto: URL that opens a compose window filled in
+// with m, as RFC 6068 describes. Spaces are encoded as %20 rather than +,
+// which mail clients would show as it is.
+func mailtoURL(m message) string {
+	var b strings.Builder
+	b.WriteString("mailto:")
+	b.WriteString(addresses(m.to))
+	var params []string
+	add := func(name, value string) {
+		if value != "" {
+			params = append(params, name+"="+escape(value))
+		}
+	}
+	add("cc", strings.Join(m.cc, ","))
+	add("bcc", strings.Join(m.bcc, ","))
+	add("subject", m.subject)
+	add("body", m.body)
+	if len(params) > 0 {
+		b.WriteString("?")
+		b.WriteString(strings.Join(params, "&"))
+	}
+	return b.String()
+}
+
+// addresses encodes the addresses to, keeping their @ and the commas
+// between them readable.
+func addresses(to []string) string {
+	out := make([]string, len(to))
+	for i, a := range to {
+		out[i] = strings.ReplaceAll(escape(a), "%40", "@")
+	}
+	return strings.Join(out, ",")
+}
+
+// escape percent-encodes s for a mailto: URL.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
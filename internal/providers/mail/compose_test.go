@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMailtoURL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "mailto:"},
+		{"to bob@example.com subject hi", "mailto:bob@example.com?subject=hi"},
+		{"to bob@example.com, alice@example.com subject Lunch & drinks? body See you at 5 — 100%",
+			"mailto:bob@example.com,alice@example.com?subject=Lunch%20%26%20drinks%3F&body=See%20you%20at%205%20%E2%80%94%20100%25"},
+		// Only body ends a subject, so its "to" is kept.
+		{"subject going to lunch", "mailto:?subject=going%20to%20lunch"},
+		// Nothing ends a body, unless written with a colon.
+		{"body line with subject in it", "mailto:?body=line%20with%20subject%20in%20it"},
+		{"Subject: hi TO:bob@example.com", "mailto:bob@example.com?subject=hi"},
+		// Free text before any keyword is the subject.
+		{"bob@example.com quarterly report", "mailto:bob@example.com?subject=quarterly%20report"},
+		{"to bob and alice cc carol,dan bcc erin", "mailto:bob,alice?cc=carol%2Cdan&bcc=erin"},
+		{"to bob+news@example.com", "mailto:bob%2Bnews@example.com"},
+	}
+	for _, tt := range tests {
+		if got := mailtoURL(parse(tt.query)); got != tt.want {
+			t.Errorf("mailtoURL(parse(%q)) =\n%s\nwant\n%s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	got := parse("invoice from acme to billing@example.com")
+	want := message{to: []string{"billing@example.com"}, subject: "invoice from acme", free: "invoice from acme"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseMessages(t *testing.T) {
+	out := "abc@mail.example.com\tInvoice 42\tAcme <billing@acme.example>\t2025-06-01T09:30:00\n" +
+		"\tno id\tnobody\t2025-06-01T09:30:00\n" +
+		"short\tline\n" +
+		"def@mail.example.com\tUndated\tSam\tyesterday\n"
+	got := parseMessages(out)
+	want := []mailItem{
+		{id: "abc@mail.example.com", subject: "Invoice 42", sender: "Acme <billing@acme.example>", received: time.Date(2025, 6, 1, 9, 30, 0, 0, time.Local)},
+		{id: "def@mail.example.com", subject: "Undated", sender: "Sam"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMessages =\n%+v\nwant\n%+v", got, want)
+	}
+	if got, want := messageURL("abc@mail.example.com"), "message://%3Cabc@mail.example.com%3E"; got != want {
+		t.Errorf("messageURL = %s, want %s", got, want)
+	}
+}
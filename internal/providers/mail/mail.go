@@ -0,0 +1,169 @@
+// Package mail composes email after the "mail" keyword, building a mailto:
+// URL from the rest of the query, as in "mail to bob subject hi body see
+// you". On macOS it can also search the subjects of the messages in Mail's
+// inbox, which needs Automation access to Mail; without it, or when that is
+// turned off, only composing is offered.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "mail"
+	keyword    = "mail"
+
+	actionCompose = "compose"
+	actionOpen    = "open"
+
+	// score ranks the compose result above fuzzy matches, since its keyword
+	// was typed in full; messages follow it.
+	score = 100
+	// minSearch is the shortest text messages are searched for.
+	minSearch = 3
+	// maxPreview shortens the body shown in the compose result.
+	maxPreview = 60
+)
+
+// Provider offers a compose window for the message a query describes, and
+// optionally the inbox messages whose subject matches it.
+type Provider struct {
+	plat platform.Platform
+
+	mu     sync.Mutex
+	search bool
+	// failed is set once searching Mail fails, so the rest of the session
+	// only composes instead of failing again on every keystroke.
+	failed bool
+}
+
+// New returns a mail provider that opens compose windows and messages
+// through plat. Messages are not searched until enabled with SetSearch.
+func New(plat platform.Platform) *Provider {
+	return &Provider{plat: plat}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession lets a search that failed, such as for want of permission,
+// be tried again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.failed = false
+	p.mu.Unlock()
+}
+
+// SetSearch turns searching Mail's inbox on or off. It only applies on
+// macOS.
+func (p *Provider) SetSearch(enabled bool) {
+	p.mu.Lock()
+	p.search = enabled
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	word, rest, _ := strings.Cut(strings.TrimLeft(query, " "), " ")
+	if !strings.EqualFold(word, keyword) {
+		return nil, nil
+	}
+	m := parse(rest)
+	results := []search.Result{composeResult(m)}
+	if !p.searchable(m) {
+		return results, nil
+	}
+	items, err := searchMessages(ctx, m.free)
+	if err != nil {
+		if ctx.Err() != nil {
+			return results, nil
+		}
+		log.Printf("mail: searching messages: %v", err)
+		p.mu.Lock()
+		p.failed = true
+		p.mu.Unlock()
+		return results, nil
+	}
+	for i, it := range items {
+		results = append(results, messageResult(it, float64(score-1-i)))
+	}
+	return results, nil
+}
+
+// searchable reports whether to search Mail for m: only when enabled, on
+// macOS, and for text with no recipients or fields typed, which is
+// composing rather than looking something up.
+func (p *Provider) searchable(m message) bool {
+	p.mu.Lock()
+	enabled := p.search && !p.failed
+	p.mu.Unlock()
+	return enabled && runtime.GOOS == "darwin" && utf8.RuneCountInString(m.free) >= minSearch &&
+		m.subject == m.free && m.body == "" && len(m.to)+len(m.cc)+len(m.bcc) == 0
+}
+
+// composeResult offers a compose window filled in with m.
+func composeResult(m message) search.Result {
+	title := "New Email"
+	if len(m.to) > 0 {
+		title += " to " + strings.Join(m.to, ", ")
+	}
+	var details []string
+	if m.subject != "" {
+		details = append(details, "Subject: "+m.subject)
+	}
+	if m.body != "" {
+		body := m.body
+		if utf8.RuneCountInString(body) > maxPreview {
+			body = string([]rune(body)[:maxPreview]) + "…"
+		}
+		details = append(details, body)
+	}
+	if len(m.cc)+len(m.bcc) > 0 {
+		details = append(details, fmt.Sprintf("Cc %d", len(m.cc)+len(m.bcc)))
+	}
+	u := mailtoURL(m)
+	return search.Result{
+		ID:       providerID + ":compose:" + u,
+		Type:     "mail",
+		Title:    title,
+		Subtitle: strings.Join(details, " · "),
+		Target:   u,
+		Score:    score,
+		Actions:  []search.Action{{ID: actionCompose, Title: "Compose"}},
+	}
+}
+
+// messageResult offers to open it in Mail.
+func messageResult(it mailItem, s float64) search.Result {
+	subtitle := it.sender
+	if !it.received.IsZero() {
+		subtitle += " · " + it.received.Format("Jan 2, 2006")
+	}
+	return search.Result{
+		ID:       providerID + ":message:" + it.id,
+		Type:     "mail",
+		Title:    it.subject,
+		Subtitle: subtitle,
+		Target:   messageURL(it.id),
+		Score:    s,
+		Actions:  []search.Action{{ID: actionOpen, Title: "Open in Mail"}},
+	}
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if !strings.HasPrefix(r.ID, providerID+":") {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case actionCompose, actionOpen:
+		return p.plat.Open(ctx, r.Target)
+	}
+	return fmt.Errorf("mail: unknown action %q", actionID)
+}
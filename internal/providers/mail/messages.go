@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"changeme/internal/osascript"
+)
+
+// maxMessages bounds the recent messages listed for a query.
+const maxMessages = 10
+
+// searchScript prints the inbox messages whose subject contains the string
+// in the first %s, at most %d of them, one per line: message ID, subject,
+// sender and date received, separated by tabs. It prints nothing unless
+// Mail is already running, so searching never launches it.
+const searchScript = `if application "Mail" is running then
+	tell application "Mail"
+		set out to ""
+		set n to 0
+		repeat with m in (messages of inbox whose subject contains %s)
+			set out to out & (message id of m) & tab & (subject of m) & tab & (sender of m) & tab & ((date received of m) as «class isot» as string) & linefeed
+			set n to n + 1
+			if n ≥ %d then exit repeat
+		end repeat
+		return out
+	end tell
+end if
+return ""`
+
+// mailItem is a message found in Mail's inbox.
+type mailItem struct {
+	id       string
+	subject  string
+	sender   string
+	received time.Time
+}
+
+// searchMessages asks Mail for the inbox messages whose subject contains
+// query. It needs Automation access to Mail.
+func searchMessages(ctx context.Context, query string) ([]mailItem, error) {
+	out, err := osascript.Run(ctx, "Mail", fmt.Sprintf(searchScript, osascript.Quote(query), maxMessages))
+	if err != nil {
+		return nil, err
+	}
+	return parseMessages(out), nil
+}
+
+// parseMessages reads the lines searchScript prints.
+func parseMessages(out string) []mailItem {
+	var items []mailItem
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 || fields[0] == "" {
+			continue
+		}
+		received, _ := time.ParseInLocation(osascript.ISODate, fields[3], time.Local)
+		items = append(items, mailItem{id: fields[0], subject: fields[1], sender: fields[2], received: received})
+	}
+	return items
+}
+
+// messageURL returns the message: URL that opens the message with id in
+// Mail.
+func messageURL(id string) string {
+	return "message://%3C" + url.PathEscape(id) + "%3E"
+}
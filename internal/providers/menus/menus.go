@@ -0,0 +1,249 @@
+// Package menus provides results for the menu bar items of the app that was
+// frontmost before the launcher opened, like the Help menu's search field.
+package menus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID    = "menus"
+	actionClick   = "click"
+	pathSeparator = "\x1f"
+)
+
+// listScript prints the app's menu bar as an outline, one item per line:
+// depth, name and whether the item is enabled, separated by tabs. Three
+// levels are read, which covers submenus such as File › Export; deeper
+// menus are rare and reading them makes the script much slower. %s is the
+// process lookup from osascript.FindProcess.
+const listScript = `tell application "System Events"
+%s
+	set out to ""
+	repeat with top in menu bar items of menu bar 1 of p
+		set out to out & "0" & tab & (name of top) & tab & "true" & linefeed
+		try
+			repeat with mi in menu items of menu 1 of top
+				set n to name of mi
+				if n is not missing value then
+					set out to out & "1" & tab & n & tab & (enabled of mi) & linefeed
+					try
+						repeat with si in menu items of menu 1 of mi
+							set sn to name of si
+							if sn is not missing value then
+								set out to out & "2" & tab & sn & tab & (enabled of si) & linefeed
+							end if
+						end repeat
+					end try
+				end if
+			end repeat
+		end try
+	end repeat
+	return out
+end tell`
+
+// node is a menu or menu item.
+type node struct {
+	name     string
+	enabled  bool
+	children []*node
+}
+
+// item is a menu item that can be clicked, with the names of the menus
+// leading to it.
+type item struct {
+	path []string
+}
+
+func (it item) title() string { return it.path[len(it.path)-1] }
+
+// Provider searches the frontmost app's menu items.
+type Provider struct {
+	matcher   *fuzzy.Matcher
+	frontmost func() platform.App
+
+	mu      sync.Mutex
+	app     platform.App
+	items   []item
+	err     error
+	loaded  chan struct{} // closed once items for app are read
+	session context.CancelFunc
+}
+
+// New returns a menu provider for the app returned by frontmost, which is the
+// app captured before the launcher took focus.
+func New(matcher *fuzzy.Matcher, frontmost func() platform.App) *Provider {
+	return &Provider{matcher: matcher, frontmost: frontmost}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession starts reading the frontmost app's menus in the background,
+// since walking them takes longer than a keystroke.
+func (p *Provider) BeginSession() {
+	ctx, cancel := context.WithCancel(context.Background())
+	loaded := make(chan struct{})
+	app := p.frontmost()
+
+	p.mu.Lock()
+	if p.session != nil {
+		p.session()
+	}
+	p.app, p.items, p.err, p.loaded, p.session = app, nil, nil, loaded, cancel
+	p.mu.Unlock()
+
+	go func() {
+		defer close(loaded)
+		if app.Name == "" {
+			return
+		}
+		items, err := read(ctx, app)
+		p.mu.Lock()
+		if p.loaded == loaded {
+			p.items, p.err = items, err
+		}
+		p.mu.Unlock()
+	}()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	p.mu.Lock()
+	loaded := p.loaded
+	p.mu.Unlock()
+	if loaded == nil {
+		return nil, nil
+	}
+	select {
+	case <-loaded:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	app, items, err := p.app, p.items, p.err
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []search.Result
+	for _, it := range items {
+		score, ok := p.matcher.Match(query, it.title())
+		if !ok {
+			continue
+		}
+		results = append(results, search.Result{
+			ID:       providerID + ":" + strings.Join(it.path, pathSeparator),
+			Type:     "menu",
+			Title:    it.title(),
+			Subtitle: app.Name + " › " + strings.Join(it.path[:len(it.path)-1], " › "),
+			Score:    float64(score),
+			Actions:  []search.Action{{ID: actionClick, Title: "Choose Menu Item", HideBeforeRun: true}},
+		})
+	}
+	return results, nil
+}
+
+// Activate brings the session's app forward and clicks the menu item.
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionClick {
+		return fmt.Errorf("menus: unknown action %q", actionID)
+	}
+	path := strings.Split(strings.TrimPrefix(r.ID, providerID+":"), pathSeparator)
+	if len(path) < 2 {
+		return fmt.Errorf("menus: malformed result id %q", r.ID)
+	}
+	p.mu.Lock()
+	app := p.app
+	p.mu.Unlock()
+	_, err := osascript.Run(ctx, "System Events", clickScript(app, path))
+	return err
+}
+
+// clickScript builds the script clicking the item at path in app's menu bar.
+func clickScript(app platform.App, path []string) string {
+	ref := "menu bar item " + osascript.Quote(path[0]) + " of menu bar 1 of p"
+	for _, name := range path[1:] {
+		ref = "menu item " + osascript.Quote(name) + " of menu 1 of " + ref
+	}
+	return `tell application "System Events"
+` + osascript.FindProcess(app.Path, app.Name) + `
+	set frontmost of p to true
+	click ` + ref + `
+end tell`
+}
+
+func read(ctx context.Context, app platform.App) ([]item, error) {
+	out, err := osascript.Run(ctx, "System Events", fmt.Sprintf(listScript, osascript.FindProcess(app.Path, app.Name)))
+	if err != nil {
+		return nil, err
+	}
+	return flatten(parseOutline(out)), nil
+}
+
+// parseOutline builds the menu tree from listScript's output. Lines whose
+// depth skips a level are dropped along with their descendants.
+func parseOutline(out string) []*node {
+	var (
+		roots []*node
+		stack []*node // stack[d] is the latest node at depth d
+	)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		depth, err := strconv.Atoi(fields[0])
+		if err != nil || depth < 0 || depth > len(stack) {
+			continue
+		}
+		n := &node{name: fields[1], enabled: fields[2] == "true"}
+		stack = append(stack[:depth], n)
+		if depth == 0 {
+			roots = append(roots, n)
+		} else {
+			parent := stack[depth-1]
+			parent.children = append(parent.children, n)
+		}
+	}
+	return roots
+}
+
+// flatten lists the enabled leaf items of the tree. Menus that open
+// submenus are not clickable themselves, and items under a disabled menu are
+// unreachable.
+func flatten(roots []*node) []item {
+	var items []item
+	var walk func(n *node, path []string)
+	walk = func(n *node, path []string) {
+		if !n.enabled || n.name == "" {
+			return
+		}
+		path = append(path[:len(path):len(path)], n.name)
+		if len(n.children) == 0 {
+			if len(path) > 1 {
+				items = append(items, item{path: path})
+			}
+			return
+		}
+		for _, c := range n.children {
+			walk(c, path)
+		}
+	}
+	for _, r := range roots {
+		walk(r, nil)
+	}
+	return items
+}
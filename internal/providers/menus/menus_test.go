@@ -0,0 +1,67 @@
+package menus
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+)
+
+const outline = "0\tApple\ttrue\n" +
+	"1\tAbout This Mac\ttrue\n" +
+	"0\tFile\ttrue\n" +
+	"1\tNew Window\ttrue\n" +
+	"1\tExport\ttrue\n" +
+	"2\tExport as PDF…\ttrue\n" +
+	"2\tExport as Word\tfalse\n" +
+	"1\tPrint…\tfalse\n" +
+	"1\t\ttrue\n" +
+	"0\tWindow\tfalse\n" +
+	"1\tMinimize\ttrue\n" +
+	"3\tToo Deep\ttrue\n" +
+	"0\tHelp\ttrue\n" +
+	"bad line\n"
+
+func TestFlatten(t *testing.T) {
+	var got []string
+	for _, it := range flatten(parseOutline(outline)) {
+		got = append(got, strings.Join(it.path, " › "))
+	}
+	// Disabled items and everything under a disabled menu are left out, as
+	// are separators, menus opening submenus and empty top-level menus.
+	want := []string{
+		"Apple › About This Mac",
+		"File › New Window",
+		"File › Export › Export as PDF…",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("flatten = %q, want %q", got, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	p := New(fuzzy.Default(), func() platform.App { return platform.App{Name: "Preview"} })
+	loaded := make(chan struct{})
+	close(loaded)
+	p.app, p.items, p.loaded = platform.App{Name: "Preview"}, flatten(parseOutline(outline)), loaded
+
+	results, err := p.Search(context.Background(), "pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("results = %+v, want one", results)
+	}
+	r := results[0]
+	if r.Title != "Export as PDF…" || r.Subtitle != "Preview › File › Export" {
+		t.Errorf("result = %q, %q", r.Title, r.Subtitle)
+	}
+	path := strings.Split(strings.TrimPrefix(r.ID, providerID+":"), pathSeparator)
+	script := clickScript(p.app, path)
+	if want := `click menu item "Export as PDF…" of menu 1 of menu item "Export" of menu 1 of menu bar item "File" of menu bar 1 of p`; !strings.Contains(script, want) {
+		t.Errorf("click script\n%s\ndoes not contain\n%s", script, want)
+	}
+}
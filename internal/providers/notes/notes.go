@@ -0,0 +1,200 @@
+// Package notes searches the titles of the notes in Notes.app after the
+// "note " prefix and opens them, or makes a new note titled with the query.
+// Searching needs Automation access to Notes; without it only making notes
+// is offered, along with a way to grant access. macOS only.
+package notes
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "notes"
+
+	actionOpen     = "open"
+	actionCreate   = "create"
+	actionSettings = "settings"
+
+	// score ranks the most recent note when nothing is typed; each after
+	// it scores one less.
+	score = 100
+	// maxRecent is how many notes are listed when nothing is typed.
+	maxRecent = 20
+	// createID is the result that makes a new note, and accessID the one
+	// asking for Automation access.
+	createID = providerID + ":create"
+	accessID = providerID + ":access"
+
+	automationSettingsURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_Automation"
+)
+
+// Provider searches and makes notes.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu sync.Mutex
+	// notes is listed once per session, as scripting Notes is slow.
+	notes  []note
+	loaded bool
+	// denied is set once listing notes fails for want of Automation
+	// access, and failed when it fails otherwise, so the rest of the
+	// session only offers to make notes.
+	denied, failed bool
+}
+
+// New returns a notes provider that narrows notes by title with matcher and
+// opens the Automation settings through plat.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps Notes, which takes a moment to script, to queries routed
+// to the provider.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// BeginSession drops the listed notes, and a failure to list them, so the
+// next search asks Notes again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.notes, p.loaded = nil, false
+	p.denied, p.failed = false, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	var results []search.Result
+	notes, err := p.list(ctx)
+	var perm *search.PermissionError
+	switch {
+	case errors.As(err, &perm):
+		results = append(results, search.Result{
+			ID:       accessID,
+			Type:     "notice",
+			Title:    "Allow Prism to Search Notes",
+			Subtitle: "Turn on Notes for Prism in Automation settings",
+			Score:    score,
+			Actions:  []search.Action{{ID: actionSettings, Title: "Open Automation Settings"}},
+		})
+	case err != nil:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if query == "" {
+		for i, n := range notes {
+			if i == maxRecent {
+				break
+			}
+			results = append(results, result(n, float64(score-i)))
+		}
+		return results, nil
+	}
+	for _, n := range notes {
+		if s, ok := p.matcher.Match(query, n.name); ok {
+			results = append(results, result(n, float64(s)))
+		}
+	}
+	// Making a note comes after any that match, as the one wanted may
+	// well exist already.
+	return append(results, search.Result{
+		ID:       createID,
+		Type:     "note",
+		Title:    "New Note “" + query + "”",
+		Subtitle: "Make a note in Notes",
+		Target:   query,
+		Actions:  []search.Action{{ID: actionCreate, Title: "Create Note"}},
+	}), nil
+}
+
+// list returns the notes, most recently modified first, listing them from
+// Notes once per session. After a failure it returns nothing, along with
+// the error when it was for want of access.
+func (p *Provider) list(ctx context.Context) ([]note, error) {
+	p.mu.Lock()
+	switch {
+	case p.loaded:
+		defer p.mu.Unlock()
+		return p.notes, nil
+	case p.denied:
+		p.mu.Unlock()
+		return nil, &search.PermissionError{Permission: "Automation", Target: "Notes"}
+	case p.failed:
+		p.mu.Unlock()
+		return nil, nil
+	}
+	p.mu.Unlock()
+	out, err := osascript.Run(ctx, "Notes", fmt.Sprintf(listScript, maxNotes))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		var perm *search.PermissionError
+		p.mu.Lock()
+		if errors.As(err, &perm) {
+			p.denied = true
+		} else {
+			log.Printf("notes: listing notes: %v", err)
+			p.failed = true
+		}
+		p.mu.Unlock()
+		return nil, err
+	}
+	notes := parseNotes(out)
+	slices.SortStableFunc(notes, func(a, b note) int { return b.modified.Compare(a.modified) })
+	p.mu.Lock()
+	p.notes, p.loaded = notes, true
+	p.mu.Unlock()
+	return notes, nil
+}
+
+func result(n note, s float64) search.Result {
+	r := search.Result{
+		ID:      providerID + ":" + n.id,
+		Type:    "note",
+		Title:   cmp.Or(n.name, "New Note"),
+		Score:   s,
+		Actions: []search.Action{{ID: actionOpen, Title: "Open in Notes"}},
+	}
+	if !n.modified.IsZero() {
+		r.Subtitle = "Modified " + n.modified.Format("Jan 2, 2006")
+	}
+	return r
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch {
+	case r.ID == accessID:
+		return p.plat.Open(ctx, automationSettingsURL)
+	case r.ID == createID:
+		if r.Target == "" {
+			return fmt.Errorf("notes: no title for the new note")
+		}
+		_, err := osascript.Run(ctx, "Notes", newNote(r.Target))
+		return err
+	}
+	id, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	if actionID != actionOpen {
+		return fmt.Errorf("notes: unknown action %q", actionID)
+	}
+	_, err := osascript.Run(ctx, "Notes", showNote(id))
+	return err
+}
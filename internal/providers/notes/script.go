@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"changeme/internal/osascript"
+)
+
+// maxNotes bounds the notes listed from Notes.
+const maxNotes = 2000
+
+// The separators listScript prints between the fields of a note and
+// between notes: ASCII unit and record separators, which never appear in a
+// note's name, unlike tabs.
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// listScript prints at most %d notes, each as its ID, name and modification
+// date split by fieldSep and ended by recordSep. The properties of every
+// note are fetched at once, which is far quicker than note by note.
+const listScript = `set us to character id 31
+set rs to character id 30
+tell application "Notes"
+	set noteIDs to id of every note
+	set noteNames to name of every note
+	set noteDates to modification date of every note
+end tell
+set out to ""
+repeat with i from 1 to count of noteIDs
+	if i > %d then exit repeat
+	set out to out & (item i of noteIDs) & us & (item i of noteNames) & us & ((item i of noteDates) as «class isot» as string) & rs
+end repeat
+return out`
+
+// showScript opens the note with the ID in %s in Notes.
+const showScript = `tell application "Notes"
+	show note id %s
+	activate
+end tell`
+
+// createScript makes a note titled with the text in %s in the default
+// folder and opens it.
+const createScript = `tell application "Notes"
+	set n to make new note with properties {body:%s}
+	show n
+	activate
+end tell`
+
+// note is one note listed from Notes.
+type note struct {
+	id       string
+	name     string
+	modified time.Time
+}
+
+// parseNotes reads the notes listScript prints.
+func parseNotes(out string) []note {
+	var notes []note
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.Split(strings.TrimPrefix(record, "\n"), fieldSep)
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+		modified, _ := time.ParseInLocation(osascript.ISODate, fields[2], time.Local)
+		notes = append(notes, note{id: fields[0], name: fields[1], modified: modified})
+	}
+	return notes
+}
+
+// showNote returns the script that opens the note with id.
+func showNote(id string) string {
+	return fmt.Sprintf(showScript, osascript.Quote(id))
+}
+
+// newNote returns the script that makes a note titled title. Notes takes
+// the title from the first line of the body, which it reads as HTML, so
+// the title is escaped as such.
+func newNote(title string) string {
+	return fmt.Sprintf(createScript, osascript.Quote("<h1>"+htmlEscaper.Replace(title)+"</h1>"))
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
@@ -0,0 +1,60 @@
+package notes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"changeme/internal/fuzzy"
+)
+
+func TestParseNotes(t *testing.T) {
+	out := "x-coredata://A/ICNote/p1\x1fShopping\x1f2025-06-01T09:30:00\x1e" +
+		"x-coredata://A/ICNote/p2\x1f\x1fnot a date\x1e" +
+		"\x1fno id\x1f2025-06-01T09:30:00\x1e" +
+		"too\x1ffew\x1e\n"
+	got := parseNotes(out)
+	want := []note{
+		{id: "x-coredata://A/ICNote/p1", name: "Shopping", modified: time.Date(2025, 6, 1, 9, 30, 0, 0, time.Local)},
+		{id: "x-coredata://A/ICNote/p2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNotes =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := result(got[1], 1); got.Title != "New Note" || got.Subtitle != "" {
+		t.Errorf("result for an untitled note = %+v", got)
+	}
+}
+
+func TestScripts(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{showNote(`x-coredata://A/ICNote/p1`), "tell application \"Notes\"\n\tshow note id \"x-coredata://A/ICNote/p1\"\n\tactivate\nend tell"},
+		{newNote(`Fish & "chips" <today>`), "tell application \"Notes\"\n\tset n to make new note with properties {body:\"<h1>Fish &amp; \\\"chips\\\" &lt;today&gt;</h1>\"}\n\tshow n\n\tactivate\nend tell"},
+		{newNote(`C:\temp`), "tell application \"Notes\"\n\tset n to make new note with properties {body:\"<h1>C:\\\\temp</h1>\"}\n\tshow n\n\tactivate\nend tell"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("script =\n%s\nwant\n%s", tt.got, tt.want)
+		}
+	}
+}
+
+func TestSearchDenied(t *testing.T) {
+	p := New(nil, fuzzy.Default())
+	p.denied = true
+	results, err := p.Search(context.Background(), "groceries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != accessID || results[1].ID != createID || results[1].Target != "groceries" {
+		t.Errorf("Search without access = %+v, want the access notice and New Note", results)
+	}
+	// A new session asks Notes again.
+	p.BeginSession()
+	if p.denied {
+		t.Error("still denied after BeginSession")
+	}
+}
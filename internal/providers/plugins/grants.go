@@ -0,0 +1,104 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"changeme/internal/quarantine"
+)
+
+// Grants records the plugins the user approved and persists them to a JSON
+// file. An approval covers one plugin directory as its manifest was when
+// approved: changing its command, capabilities or paths asks again, and
+// another plugin cannot take over the approval by declaring the same ID.
+type Grants struct {
+	path string
+
+	mu sync.Mutex
+	// granted holds the fingerprint of each approved manifest, by plugin
+	// directory.
+	granted map[string]string
+}
+
+// OpenGrants loads the grants at path. A missing file yields no grants, as
+// does a corrupt one, which is quarantined and reported in the error; its
+// plugins are asked for approval again.
+func OpenGrants(path string) (*Grants, error) {
+	g := &Grants{path: path, granted: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return g, nil
+	}
+	if err != nil {
+		return g, err
+	}
+	if err := json.Unmarshal(data, &g.granted); err != nil {
+		g.granted = make(map[string]string)
+		return g, fmt.Errorf("plugins: %w", quarantine.Move(path, err))
+	}
+	return g, nil
+}
+
+// Covers reports whether m has been approved as it is now.
+func (g *Grants) Covers(m Manifest) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	granted, ok := g.granted[m.dir]
+	return ok && granted == m.fingerprint()
+}
+
+// Grant approves m as it is now and saves the grants.
+func (g *Grants) Grant(m Manifest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.granted[m.dir] = m.fingerprint()
+	return g.save()
+}
+
+// Revoke forgets the approval of m, so it asks again before it next runs.
+func (g *Grants) Revoke(m Manifest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.granted, m.dir)
+	return g.save()
+}
+
+// fingerprint identifies what approving m allows: its command, its
+// capabilities in any order and its paths.
+func (m Manifest) fingerprint() string {
+	caps := slices.Clone(m.Capabilities)
+	slices.Sort(caps)
+	data, _ := json.Marshal(struct {
+		Command      []string     `json:"command"`
+		Capabilities []Capability `json:"capabilities"`
+		Paths        []string     `json:"paths"`
+	}{m.Command, caps, m.Paths})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (g *Grants) save() error {
+	if g.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(g.granted)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(g.path), 0o755); err != nil {
+		return err
+	}
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, g.path)
+}
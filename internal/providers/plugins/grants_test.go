@@ -0,0 +1,117 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"changeme/internal/quarantine"
+)
+
+func testManifest(dir string) Manifest {
+	return Manifest{
+		ID:           "weather",
+		Keyword:      "weather",
+		Command:      []string{"./weather"},
+		Capabilities: []Capability{CapNetwork, CapClipboard},
+		Paths:        []string{"~/Weather"},
+		dir:          dir,
+	}
+}
+
+func TestGrantsCover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grants.json")
+	g, err := OpenGrants(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved := testManifest("/plugins/weather")
+	if g.Covers(approved) {
+		t.Fatal("Covers before Grant")
+	}
+	if err := g.Grant(approved); err != nil {
+		t.Fatal(err)
+	}
+
+	reordered := testManifest("/plugins/weather")
+	reordered.Capabilities = []Capability{CapClipboard, CapNetwork}
+	renamed := testManifest("/plugins/weather")
+	renamed.Name = "Weather Forecast"
+	for name, m := range map[string]Manifest{"unchanged": approved, "reordered capabilities": reordered, "renamed": renamed} {
+		if !g.Covers(m) {
+			t.Errorf("%s: not covered", name)
+		}
+	}
+
+	command := testManifest("/plugins/weather")
+	command.Command = []string{"./weather", "--upload"}
+	paths := testManifest("/plugins/weather")
+	paths.Paths = append(paths.Paths, "~")
+	capability := testManifest("/plugins/weather")
+	capability.Capabilities = append(capability.Capabilities, CapRunCommands)
+	impostor := testManifest("/plugins/other")
+	for name, m := range map[string]Manifest{"changed command": command, "widened paths": paths, "extra capability": capability, "same ID elsewhere": impostor} {
+		if g.Covers(m) {
+			t.Errorf("%s: covered without approval", name)
+		}
+	}
+
+	reopened, err := OpenGrants(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reopened.Covers(approved) {
+		t.Error("grant not saved")
+	}
+	if err := reopened.Revoke(approved); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Covers(approved) {
+		t.Error("covered after Revoke")
+	}
+}
+
+func TestLoadManifestsRejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, manifest string) {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, manifestName), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a", `{"id": "shared", "keyword": "a", "command": ["a"]}`)
+	write("b", `{"id": "shared", "keyword": "b", "command": ["b"]}`)
+	write("c", `{"keyword": "same", "command": ["c"]}`)
+	write("d", `{"keyword": "same", "command": ["d"]}`)
+	write("e", `{"keyword": "e", "command": ["e"]}`)
+
+	list := loadManifests(dir)
+	if len(list) != 1 || list[0].ID != "e" {
+		ids := make([]string, len(list))
+		for i, m := range list {
+			ids[i] = m.ID
+		}
+		t.Errorf("loadManifests = %v, want only e", ids)
+	}
+}
+
+func TestOpenGrantsCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grants.json")
+	data := `{"weather": ["network",`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := OpenGrants(path)
+	if err == nil {
+		t.Error("OpenGrants of a corrupt file succeeded")
+	}
+	// The plugin is asked for approval again.
+	if g.Covers(testManifest(t.TempDir())) {
+		t.Error("corrupt grants cover the plugin")
+	}
+	if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+		t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+	}
+}
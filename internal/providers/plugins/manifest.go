@@ -0,0 +1,176 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// manifestName is the file each plugin directory must contain.
+const manifestName = "manifest.json"
+
+// Capability is something a plugin may ask Prism to do with one of its
+// results.
+type Capability string
+
+const (
+	// CapNetwork allows opening http and https URLs.
+	CapNetwork Capability = "network"
+	// CapFilesystem allows opening and revealing files within the paths
+	// the manifest lists.
+	CapFilesystem Capability = "filesystem"
+	// CapClipboard allows copying or pasting text.
+	CapClipboard Capability = "clipboard"
+	// CapRunCommands allows starting a command.
+	CapRunCommands Capability = "run-commands"
+)
+
+var knownCapabilities = []Capability{CapNetwork, CapFilesystem, CapClipboard, CapRunCommands}
+
+// Manifest describes a plugin. It is read from manifest.json in the
+// plugin's directory.
+type Manifest struct {
+	// ID names the plugin in result IDs and grants. It defaults to the
+	// directory name.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Keyword routes queries to the plugin: "weather berlin" runs the
+	// plugin with "weather" as its keyword and "berlin" as its query.
+	Keyword string `json:"keyword"`
+	// Command is the executable and its arguments. A relative executable
+	// is resolved against the plugin's directory. The query is appended
+	// as the last argument.
+	Command []string `json:"command"`
+	// Capabilities are the actions the plugin's results may take. Any
+	// other action is denied.
+	Capabilities []Capability `json:"capabilities"`
+	// Paths are the files and folders the filesystem capability covers.
+	// A leading ~ stands for the home directory.
+	Paths []string `json:"paths"`
+
+	dir string
+}
+
+// loadManifests reads the plugins in the subdirectories of dir, skipping
+// those with a missing or invalid manifest, and those sharing an ID or a
+// keyword, which could not be told apart.
+func loadManifests(dir string) []Manifest {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var list []Manifest
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := readManifest(filepath.Join(dir, e.Name()))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		list = append(list, m)
+	}
+	ids := make(map[string]int)
+	keywords := make(map[string]int)
+	for _, m := range list {
+		ids[m.ID]++
+		keywords[m.Keyword]++
+	}
+	return slices.DeleteFunc(list, func(m Manifest) bool {
+		switch {
+		case ids[m.ID] > 1:
+			log.Printf("plugins: %s: id %q is used by another plugin", m.dir, m.ID)
+		case keywords[m.Keyword] > 1:
+			log.Printf("plugins: %s: keyword %q is used by another plugin", m.dir, m.Keyword)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func readManifest(dir string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("plugins: %s: %w", dir, err)
+	}
+	// Grants are kept by directory, which is made absolute so the same
+	// plugin always has the same one.
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	m.dir = dir
+	if m.ID == "" {
+		m.ID = filepath.Base(dir)
+	}
+	if m.Name == "" {
+		m.Name = m.ID
+	}
+	switch {
+	case strings.ContainsAny(m.ID, ":/"):
+		return m, fmt.Errorf("plugins: %s: invalid id %q", dir, m.ID)
+	case m.Keyword == "" || strings.ContainsAny(m.Keyword, " \t"):
+		return m, fmt.Errorf("plugins: %s: invalid keyword %q", dir, m.Keyword)
+	case len(m.Command) == 0:
+		return m, fmt.Errorf("plugins: %s: no command", dir)
+	}
+	for _, c := range m.Capabilities {
+		if !slices.Contains(knownCapabilities, c) {
+			return m, fmt.Errorf("plugins: %s: unknown capability %q", dir, c)
+		}
+	}
+	return m, nil
+}
+
+// executable returns the command with a relative executable path resolved
+// against the plugin directory. A bare name is looked up on PATH.
+func (m Manifest) executable() []string {
+	cmd := slices.Clone(m.Command)
+	if strings.ContainsAny(cmd[0], `/\`) && !filepath.IsAbs(cmd[0]) {
+		cmd[0] = filepath.Join(m.dir, cmd[0])
+	}
+	return cmd
+}
+
+// declares reports whether the manifest lists c.
+func (m Manifest) declares(c Capability) bool {
+	return slices.Contains(m.Capabilities, c)
+}
+
+// coversPath reports whether path lies within one of the manifest's paths.
+// Symlinks are resolved first so a link cannot point outside them.
+func (m Manifest) coversPath(path string) bool {
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	home, _ := os.UserHomeDir()
+	for _, p := range m.Paths {
+		if home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+		p = filepath.Clean(p)
+		if !filepath.IsAbs(p) {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			p = resolved
+		}
+		if rel, err := filepath.Rel(p, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
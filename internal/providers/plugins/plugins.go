@@ -0,0 +1,313 @@
+// Package plugins runs user-installed executables that add results for a
+// keyword. Each plugin declares the capabilities its results need in a
+// manifest; the user approves them before the plugin first runs, and any
+// action outside them is denied.
+//
+// Capabilities govern what Prism does on a plugin's behalf. They do not
+// sandbox the plugin's own process, which runs with the user's rights for
+// as long as its timeout allows.
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/paste"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "plugins"
+
+	actionRun     = "run"
+	actionApprove = "approve"
+
+	// Timeout bounds a plugin run.
+	Timeout = 2 * time.Second
+	// MaxOutput bounds what a plugin may print; a plugin printing more is
+	// stopped and its output discarded.
+	MaxOutput = 1 << 20
+	// maxItems bounds the results kept from one run.
+	maxItems = 50
+)
+
+// item is one result printed by a plugin, as a JSON array element.
+type item struct {
+	Key      string `json:"key"`
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	// Action is what happens when the result is activated: "open" a URL or
+	// path in Target, "copy" Text, or "run" Args.
+	Action string   `json:"action"`
+	Target string   `json:"target"`
+	Text   string   `json:"text"`
+	Args   []string `json:"args"`
+}
+
+// ErrDenied is returned when a plugin result asks for an action its
+// manifest does not declare or the user has not approved.
+var ErrDenied = errors.New("plugins: capability not granted")
+
+// Provider runs the plugins found in a directory. Manifests are re-read
+// once per session, so new plugins show up the next time the launcher
+// opens.
+type Provider struct {
+	dir    string
+	plat   platform.Platform
+	output *paste.Output
+	grants *Grants
+
+	mu      sync.Mutex
+	plugins []Manifest
+	loaded  bool
+	items   map[string]item // by result ID, from the latest runs
+}
+
+// New returns a provider for the plugins in the subdirectories of dir.
+// Approvals are kept in grants.
+func New(dir string, plat platform.Platform, output *paste.Output, grants *Grants) *Provider {
+	return &Provider{dir: dir, plat: plat, output: output, grants: grants, items: make(map[string]item)}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession drops the cached manifests so the next search re-reads them.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.plugins, p.loaded = nil, false
+	p.items = make(map[string]item)
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	keyword, rest, _ := strings.Cut(strings.TrimSpace(query), " ")
+	m, ok := p.find(keyword)
+	if !ok {
+		return nil, nil
+	}
+	if !p.grants.Covers(m) {
+		return []search.Result{approval(m)}, nil
+	}
+	items, err := run(ctx, m, strings.TrimSpace(rest))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]search.Result, 0, len(items))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, it := range items {
+		id := providerID + ":" + m.ID + "/" + it.Key
+		p.items[id] = it
+		results = append(results, search.Result{
+			ID:       id,
+			Type:     "plugin",
+			Title:    it.Title,
+			Subtitle: it.Subtitle,
+			Score:    float64(len(items) - i),
+			Actions:  []search.Action{{ID: actionRun, Title: actionTitle(it)}},
+		})
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	pluginID, key, ok := strings.Cut(strings.TrimPrefix(r.ID, providerID+":"), "/")
+	if !ok {
+		return fmt.Errorf("plugins: malformed result id %q", r.ID)
+	}
+	m, ok := p.byID(pluginID)
+	if !ok {
+		return fmt.Errorf("plugins: no plugin %q", pluginID)
+	}
+	switch actionID {
+	case actionApprove:
+		return p.grants.Grant(m)
+	case actionRun:
+	default:
+		return fmt.Errorf("plugins: unknown action %q", actionID)
+	}
+	p.mu.Lock()
+	it, ok := p.items[r.ID]
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("plugins: no result %q from %s", key, m.ID)
+	}
+	if err := p.check(m, it); err != nil {
+		return err
+	}
+	switch it.Action {
+	case "open":
+		return p.plat.Open(ctx, it.Target)
+	case "copy":
+		return p.output.Deliver(ctx, it.Text, p.output.DefaultMode())
+	default:
+		cmd := exec.Command(it.Args[0], it.Args[1:]...)
+		cmd.Dir = m.dir
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go cmd.Wait()
+		return nil
+	}
+}
+
+// check returns ErrDenied unless m declares, and the user approved, the
+// capability the action of it needs.
+func (p *Provider) check(m Manifest, it item) error {
+	if !p.grants.Covers(m) {
+		return ErrDenied
+	}
+	c, err := required(m, it)
+	if err != nil {
+		return err
+	}
+	if !m.declares(c) {
+		return fmt.Errorf("%w: %s needs %q", ErrDenied, m.ID, c)
+	}
+	return nil
+}
+
+// required returns the capability the action of it needs. Opening a path
+// outside the manifest's paths is denied outright.
+func required(m Manifest, it item) (Capability, error) {
+	switch it.Action {
+	case "open":
+		if u, err := url.Parse(it.Target); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return "", fmt.Errorf("%w: %s may not open %s URLs", ErrDenied, m.ID, u.Scheme)
+			}
+			return CapNetwork, nil
+		}
+		if !m.coversPath(it.Target) {
+			return "", fmt.Errorf("%w: %s may not open %s", ErrDenied, m.ID, it.Target)
+		}
+		return CapFilesystem, nil
+	case "copy":
+		return CapClipboard, nil
+	case "run":
+		if len(it.Args) == 0 {
+			return "", fmt.Errorf("plugins: %s: run without a command", m.ID)
+		}
+		return CapRunCommands, nil
+	}
+	return "", fmt.Errorf("plugins: %s: unknown action %q", m.ID, it.Action)
+}
+
+// run starts the plugin with query as its last argument and decodes the
+// results it prints.
+func run(ctx context.Context, m Manifest, query string) ([]item, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	args := append(m.executable(), query)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = m.dir
+	out := &limitedBuffer{max: MaxOutput, cancel: cancel}
+	cmd.Stdout = out
+	err := cmd.Run()
+	if out.exceeded {
+		return nil, fmt.Errorf("plugins: %s printed more than %d bytes", m.ID, MaxOutput)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugins: %s: %w", m.ID, ctx.Err())
+		}
+		return nil, fmt.Errorf("plugins: %s: %w", m.ID, err)
+	}
+	var items []item
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+		return nil, fmt.Errorf("plugins: %s: %w", m.ID, err)
+	}
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+	return items, nil
+}
+
+// limitedBuffer collects output up to max bytes and stops the plugin when
+// it writes more.
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	cancel   context.CancelFunc
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		b.exceeded = true
+		b.cancel()
+		return 0, errors.New("plugins: output limit exceeded")
+	}
+	return b.Buffer.Write(p)
+}
+
+// approval is the result shown instead of a plugin's own results until the
+// user approves its capabilities.
+func approval(m Manifest) search.Result {
+	caps := make([]string, len(m.Capabilities))
+	for i, c := range m.Capabilities {
+		caps[i] = string(c)
+	}
+	subtitle := "Runs " + strings.Join(m.Command, " ") + " with no extra capabilities"
+	if len(caps) > 0 {
+		subtitle = "Can use " + strings.Join(caps, ", ")
+	}
+	return search.Result{
+		ID:       providerID + ":" + m.ID + "/",
+		Type:     "plugin",
+		Title:    "Allow " + m.Name + " to Run",
+		Subtitle: subtitle,
+		Score:    1,
+		Actions:  []search.Action{{ID: actionApprove, Title: "Allow"}},
+	}
+}
+
+func actionTitle(it item) string {
+	switch it.Action {
+	case "open":
+		return "Open"
+	case "copy":
+		return "Copy"
+	case "run":
+		return "Run"
+	}
+	return "Activate"
+}
+
+// find returns the plugin with the given keyword.
+func (p *Provider) find(keyword string) (Manifest, bool) {
+	for _, m := range p.list() {
+		if m.Keyword == keyword {
+			return m, true
+		}
+	}
+	return Manifest{}, false
+}
+
+func (p *Provider) byID(id string) (Manifest, bool) {
+	for _, m := range p.list() {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return Manifest{}, false
+}
+
+// list returns the plugins, reading the manifests once per session.
+func (p *Provider) list() []Manifest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.loaded {
+		p.plugins, p.loaded = loadManifests(p.dir), true
+	}
+	return p.plugins
+}
@@ -0,0 +1,89 @@
+package ports
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// listener is a process listening on a TCP port.
+type listener struct {
+	pid     int
+	command string
+	uid     int
+	user    string
+	port    int
+}
+
+// lsofArgs returns the arguments lsof is run with to list the processes
+// listening on port, or on any TCP port when port is 0. -n and -P keep
+// lsof from resolving hosts and port names, which is slow and would hide
+// the numbers; -F prints fields one per line for parse.
+func lsofArgs(port int) []string {
+	addr := "-iTCP"
+	if port > 0 {
+		addr += ":" + strconv.Itoa(port)
+	}
+	return []string{"-nP", addr, "-sTCP:LISTEN", "-FpcuLn"}
+}
+
+// parse reads the listeners from lsof -F output, in which each field is a
+// line starting with its letter: a process set (p, c, u, L) followed by a
+// file set (f, n) for each of its sockets. A process listening on a port
+// both over IPv4 and IPv6 is listed once.
+func parse(r io.Reader) []listener {
+	var (
+		listeners []listener
+		cur       listener
+	)
+	seen := make(map[[2]int]bool)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		field, value := line[0], line[1:]
+		switch field {
+		case 'p':
+			pid, err := strconv.Atoi(value)
+			if err != nil {
+				pid = 0
+			}
+			cur = listener{pid: pid, uid: -1}
+		case 'c':
+			cur.command = value
+		case 'u':
+			if uid, err := strconv.Atoi(value); err == nil {
+				cur.uid = uid
+			}
+		case 'L':
+			cur.user = value
+		case 'n':
+			port := portOf(value)
+			if cur.pid <= 0 || port == 0 || seen[[2]int{cur.pid, port}] {
+				continue
+			}
+			seen[[2]int{cur.pid, port}] = true
+			l := cur
+			l.port = port
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
+// portOf returns the port at the end of a socket name such as "*:3000",
+// "127.0.0.1:8080" or "[::1]:5432", or 0 if it has none.
+func portOf(name string) int {
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return 0
+	}
+	port, err := strconv.Atoi(name[i+1:])
+	if err != nil || port < 1 || port > 65535 {
+		return 0
+	}
+	return port
+}
@@ -0,0 +1,289 @@
+// Package ports shows what is listening on a TCP port after the "port "
+// prefix, as in "port 3000", and stops it to free the port once the user
+// confirms. Without a port it lists every listening process. Listeners are
+// found with lsof, so it works on macOS and Linux.
+package ports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "ports"
+
+	actionKill      = "kill"
+	actionForceKill = "force-kill"
+	actionConfirm   = "confirm"
+	actionCancel    = "cancel"
+
+	// score ranks the first listener; each after it scores one less.
+	score = 100
+	// noLsofID is the notice shown when lsof is not installed, and noneID
+	// the one shown when nothing is listening.
+	noLsofID = providerID + ":no-lsof"
+	noneID   = providerID + ":none"
+)
+
+// criticalCommands are system processes never offered to be killed, as
+// stopping them logs the user out or takes the network down.
+var criticalCommands = []string{
+	"launchd", "kernel_task", "WindowServer", "loginwindow", "mDNSResponder",
+	"configd", "rapportd", "sharingd", "ControlCenter", "systemd", "init",
+	"systemd-resolved", "NetworkManager", "sshd",
+}
+
+// pending is a kill waiting for the user's confirmation.
+type pending struct {
+	id    string
+	force bool
+}
+
+// Provider lists the processes listening on a port and kills them.
+type Provider struct {
+	mu      sync.Mutex
+	pending pending
+}
+
+// New returns a ports provider.
+func New() *Provider {
+	return &Provider{}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps lsof, which takes a moment, to queries routed to the
+// provider.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// BeginSession drops a kill left unconfirmed.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.pending = pending{}
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), ":"))
+	port := 0
+	if query != "" {
+		n, err := strconv.Atoi(query)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, nil
+		}
+		port = n
+	}
+	lsof, err := exec.LookPath("lsof")
+	if err != nil {
+		return []search.Result{{
+			ID:       noLsofID,
+			Provider: providerID,
+			Type:     "notice",
+			Title:    "Install lsof to see listening ports",
+			Subtitle: "The port search uses lsof to find listening processes",
+		}}, nil
+	}
+	listeners, err := list(ctx, lsof, port)
+	if err != nil {
+		return nil, err
+	}
+	if len(listeners) == 0 {
+		if port == 0 {
+			return []search.Result{notice("Nothing is listening on a TCP port")}, nil
+		}
+		return []search.Result{notice(fmt.Sprintf("Nothing on port %d", port))}, nil
+	}
+	p.mu.Lock()
+	pend := p.pending
+	p.mu.Unlock()
+	results := make([]search.Result, len(listeners))
+	for i, l := range listeners {
+		r := result(l, float64(score-i))
+		if r.ID == pend.id {
+			r = confirmation(l, pend.force, r.Score)
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// notice is a result saying nothing is there to kill.
+func notice(title string) search.Result {
+	return search.Result{
+		ID:       noneID,
+		Type:     "notice",
+		Title:    title,
+		Subtitle: "No process is listening there",
+		Score:    score,
+	}
+}
+
+// resultID identifies the process pid listening on port.
+func resultID(port, pid int) string {
+	return fmt.Sprintf("%s:%d:%d", providerID, port, pid)
+}
+
+// parseID returns the port and PID in a result ID from resultID.
+func parseID(id string) (port, pid int, ok bool) {
+	rest, ok := strings.CutPrefix(id, providerID+":")
+	if !ok {
+		return 0, 0, false
+	}
+	a, b, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, 0, false
+	}
+	port, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, false
+	}
+	pid, err = strconv.Atoi(b)
+	if err != nil {
+		return 0, 0, false
+	}
+	return port, pid, true
+}
+
+func result(l listener, s float64) search.Result {
+	subtitle := fmt.Sprintf("Port %d · PID %d", l.port, l.pid)
+	if l.user != "" {
+		subtitle += " · " + l.user
+	}
+	r := search.Result{
+		ID:       resultID(l.port, l.pid),
+		Type:     "process",
+		Title:    l.command,
+		Subtitle: subtitle,
+		Score:    s,
+	}
+	if critical(l) {
+		r.Subtitle += " · System process"
+		return r
+	}
+	r.Actions = []search.Action{
+		{ID: actionKill, Title: "Kill Process…"},
+		{ID: actionForceKill, Title: "Force Kill Process…"},
+	}
+	return r
+}
+
+// confirmation is shown in place of l once a kill is chosen, until it is
+// confirmed or cancelled.
+func confirmation(l listener, force bool, s float64) search.Result {
+	title := "Kill Process"
+	if force {
+		title = "Force Kill Process"
+	}
+	return search.Result{
+		ID:       resultID(l.port, l.pid),
+		Type:     "confirmation",
+		Title:    fmt.Sprintf("Kill “%s” (PID %d) to free port %d?", l.command, l.pid, l.port),
+		Subtitle: "Unsaved work in it may be lost",
+		Score:    s,
+		Actions: []search.Action{
+			{ID: actionConfirm, Title: title},
+			{ID: actionCancel, Title: "Cancel"},
+		},
+	}
+}
+
+// critical reports whether l must not be killed: Prism itself, a process
+// of the system's own, or one run by root, which only root could stop.
+func critical(l listener) bool {
+	return l.pid <= 1 || l.pid == os.Getpid() || l.uid == 0 ||
+		slices.Contains(criticalCommands, l.command)
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if r.ID == noLsofID || r.ID == noneID {
+		return nil
+	}
+	port, pid, ok := parseID(r.ID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case actionKill, actionForceKill:
+		p.mu.Lock()
+		p.pending = pending{id: r.ID, force: actionID == actionForceKill}
+		p.mu.Unlock()
+		return search.ErrSearchAgain
+	case actionCancel:
+		p.BeginSession()
+		return search.ErrSearchAgain
+	case actionConfirm:
+		p.mu.Lock()
+		pend := p.pending
+		p.pending = pending{}
+		p.mu.Unlock()
+		if pend.id != r.ID {
+			return fmt.Errorf("ports: no kill of PID %d to confirm", pid)
+		}
+		if err := p.kill(ctx, port, pid, pend.force); err != nil {
+			return err
+		}
+		return search.ErrSearchAgain
+	}
+	return fmt.Errorf("ports: unknown action %q", actionID)
+}
+
+// kill signals pid, after checking it still listens on port, so a process
+// that has since exited and had its PID reused is left alone.
+func (p *Provider) kill(ctx context.Context, port, pid int, force bool) error {
+	lsof, err := exec.LookPath("lsof")
+	if err != nil {
+		return err
+	}
+	listeners, err := list(ctx, lsof, port)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(listeners, func(l listener) bool { return l.pid == pid })
+	if i < 0 {
+		// Already gone, which is what was wanted.
+		return nil
+	}
+	if critical(listeners[i]) {
+		return fmt.Errorf("ports: %s is a system process", listeners[i].command)
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	if err := proc.Signal(sig); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("ports: killing PID %d: %w", pid, err)
+	}
+	return nil
+}
+
+// list runs lsof for the processes listening on port, or on any port
+// when port is 0.
+func list(ctx context.Context, lsof string, port int) ([]listener, error) {
+	out, err := exec.CommandContext(ctx, lsof, lsofArgs(port)...).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// lsof exits with status 1 when nothing matched.
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+			return nil, fmt.Errorf("ports: lsof: %w", err)
+		}
+	}
+	return parse(bytes.NewReader(out)), nil
+}
@@ -0,0 +1,129 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+// lsofOutput is lsof -nP -iTCP -sTCP:LISTEN -FpcuLn output for a node
+// server on 3000 over IPv4 and IPv6, postgres on two ports and a root
+// daemon.
+const lsofOutput = `p4242
+cnode
+u501
+Lsam
+f23
+n*:3000
+f24
+n[::]:3000
+p517
+cpostgres
+u501
+Lsam
+f7
+n127.0.0.1:5432
+f8
+n[::1]:5433
+p88
+cmDNSResponder
+u0
+Lroot
+f5
+n*:5353
+pbad
+cghost
+n*:9999
+p99
+cnoport
+f3
+n*:*
+`
+
+func TestParse(t *testing.T) {
+	got := parse(strings.NewReader(lsofOutput))
+	want := []listener{
+		{pid: 4242, command: "node", uid: 501, user: "sam", port: 3000},
+		{pid: 517, command: "postgres", uid: 501, user: "sam", port: 5432},
+		{pid: 517, command: "postgres", uid: 501, user: "sam", port: 5433},
+		{pid: 88, command: "mDNSResponder", uid: 0, user: "root", port: 5353},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestLsofArgs(t *testing.T) {
+	if got, want := lsofArgs(3000), []string{"-nP", "-iTCP:3000", "-sTCP:LISTEN", "-FpcuLn"}; !slices.Equal(got, want) {
+		t.Errorf("lsofArgs(3000) = %q, want %q", got, want)
+	}
+	if got := lsofArgs(0); got[1] != "-iTCP" {
+		t.Errorf("lsofArgs(0) = %q, want any TCP port", got)
+	}
+}
+
+func TestResult(t *testing.T) {
+	node := listener{pid: 4242, command: "node", uid: 501, user: "sam", port: 3000}
+	r := result(node, score)
+	if r.ID != "ports:3000:4242" || r.Title != "node" || r.Subtitle != "Port 3000 · PID 4242 · sam" {
+		t.Errorf("result = %+v", r)
+	}
+	var actions []string
+	for _, a := range r.Actions {
+		actions = append(actions, a.ID)
+	}
+	if want := []string{actionKill, actionForceKill}; !slices.Equal(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	if port, pid, ok := parseID(r.ID); !ok || port != 3000 || pid != 4242 {
+		t.Errorf("parseID(%q) = %d, %d, %v", r.ID, port, pid, ok)
+	}
+
+	// System processes, root's and Prism's own are never offered a kill.
+	for _, l := range []listener{
+		{pid: 88, command: "mDNSResponder", uid: 501, port: 5353},
+		{pid: 600, command: "cupsd", uid: 0, port: 631},
+		{pid: 1, command: "launchd", uid: 501, port: 22},
+		{pid: os.Getpid(), command: "prism", uid: 501, port: 8080},
+	} {
+		if r := result(l, score); len(r.Actions) != 0 || !strings.HasSuffix(r.Subtitle, "System process") {
+			t.Errorf("result for %s = %+v, want no actions", l.command, r)
+		}
+	}
+}
+
+func TestActivateConfirm(t *testing.T) {
+	p := New()
+	node := listener{pid: 4242, command: "node", uid: 501, port: 3000}
+	r := result(node, score)
+	if err := p.Activate(context.Background(), r, actionForceKill); !errors.Is(err, search.ErrSearchAgain) {
+		t.Fatalf("Activate(force-kill) = %v, want ErrSearchAgain", err)
+	}
+	if want := (pending{id: r.ID, force: true}); p.pending != want {
+		t.Errorf("pending = %+v, want %+v", p.pending, want)
+	}
+	c := confirmation(node, true, score)
+	if c.ID != r.ID || c.Title != "Kill “node” (PID 4242) to free port 3000?" || c.Actions[0].ID != actionConfirm || c.Actions[0].Title != "Force Kill Process" {
+		t.Errorf("confirmation = %+v", c)
+	}
+
+	if err := p.Activate(context.Background(), c, actionCancel); !errors.Is(err, search.ErrSearchAgain) {
+		t.Fatalf("Activate(cancel) = %v, want ErrSearchAgain", err)
+	}
+	if p.pending != (pending{}) {
+		t.Errorf("pending after cancelling = %+v", p.pending)
+	}
+	// Nothing is killed without a kill to confirm.
+	if err := p.Activate(context.Background(), c, actionConfirm); err == nil || errors.Is(err, search.ErrSearchAgain) {
+		t.Errorf("Activate(confirm) without a pending kill = %v", err)
+	}
+	if err := p.Activate(context.Background(), notice("Nothing on port 3000"), actionKill); err != nil {
+		t.Errorf("Activate on the notice = %v", err)
+	}
+}
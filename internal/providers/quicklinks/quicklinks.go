@@ -0,0 +1,148 @@
+// Package quicklinks opens URLs built from configured keyword templates, so
+// "jira PROJ-123" can open https://example.atlassian.net/browse/PROJ-123.
+package quicklinks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "quicklinks"
+	actionOpen = "open"
+	// score ranks a quicklink above fuzzy matches, since its keyword was
+	// typed in full.
+	score = 100
+)
+
+// argPlaceholder is a positional placeholder such as {arg2}.
+var argPlaceholder = regexp.MustCompile(`\{arg([1-9][0-9]*)\}`)
+
+// Provider matches the first word of a query against quicklink keywords.
+type Provider struct {
+	plat platform.Platform
+
+	mu    sync.Mutex
+	links []config.Quicklink
+}
+
+// New returns a quicklinks provider that opens URLs through plat.
+func New(plat platform.Platform) *Provider {
+	return &Provider{plat: plat}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetLinks replaces the configured quicklinks.
+func (p *Provider) SetLinks(links []config.Quicklink) {
+	p.mu.Lock()
+	p.links = links
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	keyword, rest, _ := strings.Cut(strings.TrimSpace(query), " ")
+	if keyword == "" {
+		return nil, nil
+	}
+	rest = strings.TrimSpace(rest)
+	p.mu.Lock()
+	links := p.links
+	p.mu.Unlock()
+	var results []search.Result
+	for _, l := range links {
+		if !strings.EqualFold(l.Keyword, keyword) || l.URL == "" {
+			continue
+		}
+		results = append(results, result(l, rest))
+	}
+	return results, nil
+}
+
+// result is the result for link with rest typed after its keyword. Until
+// every placeholder can be filled, it only says what is missing.
+func result(l config.Quicklink, rest string) search.Result {
+	name := l.Name
+	if name == "" {
+		name = l.Keyword
+	}
+	u, missing := expand(l.URL, rest)
+	if missing > 0 {
+		return search.Result{
+			ID:       providerID + ":" + l.Keyword,
+			Type:     "notice",
+			Title:    name,
+			Subtitle: fmt.Sprintf("Type %d more %s after “%s”", missing, plural(missing, "word", "words"), l.Keyword),
+			Score:    score,
+		}
+	}
+	title := "Open " + name
+	if rest != "" {
+		title += ": " + rest
+	}
+	return search.Result{
+		ID:       providerID + ":" + u,
+		Type:     "url",
+		Title:    title,
+		Subtitle: u,
+		Target:   u,
+		Score:    score,
+		Actions:  []search.Action{{ID: actionOpen, Title: "Open"}},
+	}
+}
+
+// expand fills template with the words after a keyword: {query} becomes
+// all of rest and {argN} its Nth space-separated word, each URL-encoded,
+// as a path segment before the template's "?" and as a query value after
+// it. It also returns how many more words the {argN} placeholders need,
+// and reports one missing word for {query} when rest is empty.
+func expand(template, rest string) (string, int) {
+	args := strings.Fields(rest)
+	missing := 0
+	fill := func(part string, escape func(string) string) string {
+		part = argPlaceholder.ReplaceAllStringFunc(part, func(m string) string {
+			n, _ := strconv.Atoi(argPlaceholder.FindStringSubmatch(m)[1])
+			if n > len(args) {
+				missing = max(missing, n-len(args))
+				return ""
+			}
+			return escape(args[n-1])
+		})
+		if strings.Contains(part, "{query}") {
+			if rest == "" {
+				missing = max(missing, 1)
+			}
+			part = strings.ReplaceAll(part, "{query}", escape(rest))
+		}
+		return part
+	}
+	path, query, ok := strings.Cut(template, "?")
+	u := fill(path, url.PathEscape)
+	if ok {
+		u += "?" + fill(query, url.QueryEscape)
+	}
+	return u, missing
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionOpen {
+		return fmt.Errorf("quicklinks: unknown action %q", actionID)
+	}
+	return p.plat.Open(ctx, r.Target)
+}
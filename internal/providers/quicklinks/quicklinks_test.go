@@ -0,0 +1,71 @@
+package quicklinks
+
+import (
+	"context"
+	"testing"
+
+	"changeme/internal/config"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		template, rest string
+		want           string
+		missing        int
+	}{
+		{"https://example.atlassian.net/browse/{query}", "PROJ-123", "https://example.atlassian.net/browse/PROJ-123", 0},
+		// A space is %20 in a path, where + is itself.
+		{"https://mycorp.atlassian.net/browse/{query}", "PROJ 123", "https://mycorp.atlassian.net/browse/PROJ%20123", 0},
+		{"https://example.com/wiki/{query}", "a+b/c", "https://example.com/wiki/a+b%2Fc", 0},
+		{"https://example.com/search?q={query}", "café & bar", "https://example.com/search?q=caf%C3%A9+%26+bar", 0},
+		{"https://example.com/search?q={query}", "", "https://example.com/search?q=", 1},
+		{"https://github.com/{arg1}/{arg2}/issues", "golang go", "https://github.com/golang/go/issues", 0},
+		{"https://github.com/{arg1}/{arg2}/issues", "golang", "https://github.com/golang//issues", 1},
+		{"https://github.com/{arg1}/{arg2}/issues", "", "https://github.com///issues", 2},
+		{"https://example.com/{arg2}?from={arg1}", "a/b c?d", "https://example.com/c%3Fd?from=a%2Fb", 0},
+		{"https://example.com/{arg1}?q={query}", "my docs", "https://example.com/my?q=my+docs", 0},
+		{"https://example.com/", "ignored", "https://example.com/", 0},
+	}
+	for _, tt := range tests {
+		got, missing := expand(tt.template, tt.rest)
+		if got != tt.want || missing != tt.missing {
+			t.Errorf("expand(%q, %q) = %q, %d; want %q, %d", tt.template, tt.rest, got, missing, tt.want, tt.missing)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	p := New(nil)
+	p.SetLinks([]config.Quicklink{
+		{Keyword: "jira", Name: "Jira", URL: "https://example.atlassian.net/browse/{query}"},
+		{Keyword: "gh", URL: "https://github.com/{arg1}/{arg2}"},
+	})
+	tests := []struct {
+		query  string
+		target string // empty for a notice
+		title  string
+	}{
+		{"jira PROJ-123", "https://example.atlassian.net/browse/PROJ-123", "Open Jira: PROJ-123"},
+		{"JIRA PROJ-123", "https://example.atlassian.net/browse/PROJ-123", "Open Jira: PROJ-123"},
+		{"jira", "", "Jira"},
+		{"gh golang go", "https://github.com/golang/go", "Open gh: golang go"},
+		{"gh golang", "", "gh"},
+	}
+	for _, tt := range tests {
+		results, err := p.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Search(%q) = %d results, want 1", tt.query, len(results))
+		}
+		if r := results[0]; r.Target != tt.target || r.Title != tt.title {
+			t.Errorf("Search(%q) = %q → %q, want %q → %q", tt.query, r.Title, r.Target, tt.title, tt.target)
+		}
+	}
+	for _, query := range []string{"", "jir PROJ-123", "confluence page"} {
+		if results, _ := p.Search(context.Background(), query); len(results) != 0 {
+			t.Errorf("Search(%q) = %+v, want nothing", query, results)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package relaunch notices applications quitting and offers to relaunch
+// them, for apps quit by accident.
+package relaunch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID     = "relaunch"
+	actionRelaunch = "relaunch"
+
+	// maxQuit bounds the list; the oldest quit is dropped first.
+	maxQuit = 10
+	// maxAge is how long a quit app is offered for.
+	maxAge = time.Hour
+	// pollInterval is how often the running apps are listed.
+	pollInterval = 2 * time.Second
+	// keyword lists every recently quit app.
+	keyword = "relaunch"
+)
+
+// quit is an app that stopped running.
+type quit struct {
+	app platform.App
+	at  time.Time
+}
+
+// Provider tracks quit apps while Run is active.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	running map[string]platform.App // by path; nil until the first listing
+	quits   []quit                  // newest first
+}
+
+// New returns a relaunch provider that lists and launches apps through plat.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// Run polls the running apps until ctx is cancelled. It stops early on
+// platforms that cannot list them.
+func (p *Provider) Run(ctx context.Context) {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		apps, err := p.plat.RunningApps(ctx)
+		if errors.Is(err, platform.ErrUnsupported) {
+			return
+		}
+		if err != nil {
+			log.Println("relaunch:", err)
+		} else {
+			p.observe(apps, time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// observe records apps that were running last time but are not now, and
+// forgets quit apps that are running again, whether relaunched from Prism
+// or by hand.
+func (p *Provider) observe(apps []platform.App, now time.Time) {
+	running := make(map[string]platform.App, len(apps))
+	for _, a := range apps {
+		running[a.Path] = a
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var quits []quit
+	if p.running != nil {
+		for path, a := range p.running {
+			if _, ok := running[path]; !ok {
+				quits = append(quits, quit{app: a, at: now})
+			}
+		}
+	}
+	for _, q := range p.quits {
+		if _, ok := running[q.app.Path]; ok || now.Sub(q.at) > maxAge || containsApp(quits, q.app.Path) {
+			continue
+		}
+		quits = append(quits, q)
+	}
+	if len(quits) > maxQuit {
+		quits = quits[:maxQuit]
+	}
+	p.running, p.quits = running, quits
+}
+
+func containsApp(quits []quit, path string) bool {
+	for _, q := range quits {
+		if q.app.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Search lists recently quit apps. The keyword "relaunch" lists them all,
+// and is ignored in front of a name; otherwise a quit app must match the
+// query by name.
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	keywordOnly := len(query) >= 3 && strings.HasPrefix(keyword, strings.ToLower(query))
+	if rest, ok := strings.CutPrefix(strings.ToLower(query), keyword+" "); ok {
+		query = strings.TrimSpace(query[len(query)-len(rest):])
+	}
+
+	p.mu.Lock()
+	quits := append([]quit(nil), p.quits...)
+	p.mu.Unlock()
+	var results []search.Result
+	for i, q := range quits {
+		var score float64
+		if !keywordOnly {
+			s, ok := p.matcher.Match(query, q.app.Name)
+			if !ok {
+				continue
+			}
+			score = float64(s)
+		}
+		// Keep the most recent quits first.
+		score -= float64(i) / maxQuit
+		results = append(results, result(q, score))
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionRelaunch {
+		return fmt.Errorf("relaunch: unknown action %q", actionID)
+	}
+	path := strings.TrimPrefix(r.ID, providerID+":")
+	p.mu.Lock()
+	var app platform.App
+	for _, q := range p.quits {
+		if q.app.Path == path {
+			app = q.app
+		}
+	}
+	p.mu.Unlock()
+	if app.Path == "" {
+		return search.ErrUnknownResult
+	}
+	return p.plat.Launch(ctx, app)
+}
+
+func result(q quit, score float64) search.Result {
+	return search.Result{
+		ID:       providerID + ":" + q.app.Path,
+		Type:     "app",
+		Title:    "Relaunch " + q.app.Name,
+		Subtitle: "Quit at " + q.at.Format("15:04"),
+		Score:    score,
+		Actions:  []search.Action{{ID: actionRelaunch, Title: "Relaunch"}},
+	}
+}
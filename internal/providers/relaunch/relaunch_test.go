@@ -0,0 +1,73 @@
+package relaunch
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/platform"
+)
+
+func app(name string) platform.App {
+	return platform.App{Name: name, Path: "/Applications/" + name + ".app"}
+}
+
+// quitNames returns the names of p's recently quit apps, newest first.
+func quitNames(p *Provider) []string {
+	var names []string
+	for _, q := range p.quits {
+		names = append(names, q.app.Name)
+	}
+	return names
+}
+
+func TestObserve(t *testing.T) {
+	mail, notes, safari := app("Mail"), app("Notes"), app("Safari")
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		running []platform.App
+		after   time.Duration
+		want    []string
+	}{
+		// The first listing is only a baseline.
+		{[]platform.App{mail, notes, safari}, 0, nil},
+		{[]platform.App{notes, safari}, time.Second, []string{"Mail"}},
+		{[]platform.App{safari}, 2 * time.Second, []string{"Notes", "Mail"}},
+		// Mail was relaunched by hand.
+		{[]platform.App{mail, safari}, 3 * time.Second, []string{"Notes"}},
+		{[]platform.App{safari}, 4 * time.Second, []string{"Mail", "Notes"}},
+		// Notes quit over maxAge ago; Mail only just.
+		{[]platform.App{safari}, maxAge + 3*time.Second, []string{"Mail"}},
+		{[]platform.App{safari}, maxAge + 5*time.Second, nil},
+	}
+	p := New(nil, nil)
+	for i, tt := range tests {
+		p.observe(tt.running, start.Add(tt.after))
+		if got := quitNames(p); !slices.Equal(got, tt.want) {
+			t.Errorf("step %d: quit = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestObserveCap(t *testing.T) {
+	var apps []platform.App
+	for i := 0; i < maxQuit+5; i++ {
+		apps = append(apps, app(fmt.Sprint("App", i)))
+	}
+	p := New(nil, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	p.observe(apps, now)
+	// Quit the apps one at a time, last first.
+	for i := len(apps) - 1; i >= 0; i-- {
+		now = now.Add(time.Second)
+		p.observe(apps[:i], now)
+	}
+	got := quitNames(p)
+	if len(got) != maxQuit {
+		t.Fatalf("%d quit apps, want %d", len(got), maxQuit)
+	}
+	if got[0] != "App0" || got[maxQuit-1] != fmt.Sprint("App", maxQuit-1) {
+		t.Errorf("quit = %v, want App0 to App%d", got, maxQuit-1)
+	}
+}
@@ -0,0 +1,224 @@
+// Package reminders searches the reminders not yet completed in
+// Reminders.app after the "reminder " prefix, opens them and marks them as
+// completed, or makes a new reminder named with the query. Searching needs
+// Automation access to Reminders; without it only making reminders is
+// offered, along with a way to grant access. macOS only.
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "reminders"
+
+	actionOpen     = "open"
+	actionComplete = "complete"
+	actionCreate   = "create"
+	actionSettings = "settings"
+
+	// score ranks the first reminder when nothing is typed; each after it
+	// scores one less.
+	score = 100
+	// maxListed is how many reminders are listed when nothing is typed.
+	maxListed = 20
+	// createID is the result that makes a new reminder, and accessID the
+	// one asking for Automation access.
+	createID = providerID + ":create"
+	accessID = providerID + ":access"
+
+	automationSettingsURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_Automation"
+)
+
+// Provider searches and makes reminders.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu sync.Mutex
+	// reminders is listed once per session, as scripting Reminders is slow.
+	reminders []reminder
+	loaded    bool
+	// denied is set once listing reminders fails for want of Automation
+	// access, and failed when it fails otherwise, so the rest of the
+	// session only offers to make reminders.
+	denied, failed bool
+}
+
+// New returns a reminders provider that narrows reminders by name with
+// matcher and opens them through plat.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps Reminders, which takes a moment to script, to queries
+// routed to the provider.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// BeginSession drops the listed reminders, and a failure to list them, so
+// the next search asks Reminders again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.reminders, p.loaded = nil, false
+	p.denied, p.failed = false, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	var results []search.Result
+	reminders, err := p.list(ctx)
+	var perm *search.PermissionError
+	switch {
+	case errors.As(err, &perm):
+		results = append(results, search.Result{
+			ID:       accessID,
+			Type:     "notice",
+			Title:    "Allow Prism to Search Reminders",
+			Subtitle: "Turn on Reminders for Prism in Automation settings",
+			Score:    score,
+			Actions:  []search.Action{{ID: actionSettings, Title: "Open Automation Settings"}},
+		})
+	case err != nil:
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if query == "" {
+		for i, r := range reminders {
+			if i == maxListed {
+				break
+			}
+			results = append(results, result(r, float64(score-i)))
+		}
+		return results, nil
+	}
+	for _, r := range reminders {
+		if s, ok := p.matcher.Match(query, r.name); ok {
+			results = append(results, result(r, float64(s)))
+		}
+	}
+	// Making a reminder comes after any that match, as the one wanted may
+	// well exist already.
+	return append(results, search.Result{
+		ID:       createID,
+		Type:     "reminder",
+		Title:    "New Reminder “" + query + "”",
+		Subtitle: "Add to the default list in Reminders",
+		Target:   query,
+		Actions:  []search.Action{{ID: actionCreate, Title: "Create Reminder"}},
+	}), nil
+}
+
+// list returns the reminders not yet completed, those due soonest first
+// and those without a due date last, listing them from Reminders once per
+// session. After a failure it returns nothing, along with the error when
+// it was for want of access.
+func (p *Provider) list(ctx context.Context) ([]reminder, error) {
+	p.mu.Lock()
+	switch {
+	case p.loaded:
+		defer p.mu.Unlock()
+		return p.reminders, nil
+	case p.denied:
+		p.mu.Unlock()
+		return nil, &search.PermissionError{Permission: "Automation", Target: "Reminders"}
+	case p.failed:
+		p.mu.Unlock()
+		return nil, nil
+	}
+	p.mu.Unlock()
+	out, err := osascript.Run(ctx, "Reminders", listScript)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		var perm *search.PermissionError
+		p.mu.Lock()
+		if errors.As(err, &perm) {
+			p.denied = true
+		} else {
+			log.Printf("reminders: listing reminders: %v", err)
+			p.failed = true
+		}
+		p.mu.Unlock()
+		return nil, err
+	}
+	reminders := parseReminders(out)
+	slices.SortStableFunc(reminders, func(a, b reminder) int {
+		if a.due.IsZero() != b.due.IsZero() {
+			if a.due.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		return a.due.Compare(b.due)
+	})
+	p.mu.Lock()
+	p.reminders, p.loaded = reminders, true
+	p.mu.Unlock()
+	return reminders, nil
+}
+
+func result(r reminder, s float64) search.Result {
+	subtitle := r.list
+	if !r.due.IsZero() {
+		subtitle += " · Due " + r.due.Format("Jan 2, 2006 15:04")
+	}
+	return search.Result{
+		ID:       providerID + ":" + r.id,
+		Type:     "reminder",
+		Title:    r.name,
+		Subtitle: subtitle,
+		Target:   r.id,
+		Score:    s,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open in Reminders"},
+			{ID: actionComplete, Title: "Mark as Completed"},
+		},
+	}
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch {
+	case r.ID == accessID:
+		return p.plat.Open(ctx, automationSettingsURL)
+	case r.ID == createID:
+		if r.Target == "" {
+			return fmt.Errorf("reminders: no name for the new reminder")
+		}
+		_, err := osascript.Run(ctx, "Reminders", newReminder(r.Target))
+		return err
+	}
+	id, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case actionOpen:
+		// Reminder IDs are x-apple-reminder:// URLs, which Reminders opens.
+		return p.plat.Open(ctx, id)
+	case actionComplete:
+		if _, err := osascript.Run(ctx, "Reminders", completeReminder(id)); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		p.reminders = slices.DeleteFunc(slices.Clone(p.reminders), func(r reminder) bool { return r.id == id })
+		p.mu.Unlock()
+		return search.ErrSearchAgain
+	}
+	return fmt.Errorf("reminders: unknown action %q", actionID)
+}
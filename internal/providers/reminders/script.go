@@ -0,0 +1,83 @@
+package reminders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"changeme/internal/osascript"
+)
+
+// The separators listScript prints between the fields of a reminder and
+// between reminders: ASCII unit and record separators, which never appear
+// in a reminder's name, unlike tabs.
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// listScript prints the reminders not yet completed, list by list, each as
+// its ID, name, list name and due date split by fieldSep and ended by
+// recordSep. The due date is empty for reminders without one. The
+// properties of a list's reminders are fetched at once, which is far
+// quicker than reminder by reminder.
+const listScript = `set us to character id 31
+set rs to character id 30
+set out to ""
+tell application "Reminders"
+	repeat with l in lists
+		set listName to name of l
+		set pending to (reminders of l whose completed is false)
+		set reminderIDs to id of pending
+		set reminderNames to name of pending
+		set reminderDates to due date of pending
+		repeat with i from 1 to count of reminderIDs
+			set dueText to ""
+			try
+				set dueText to (item i of reminderDates) as «class isot» as string
+			end try
+			set out to out & (item i of reminderIDs) & us & (item i of reminderNames) & us & listName & us & dueText & rs
+		end repeat
+	end repeat
+end tell
+return out`
+
+// completeScript marks the reminder with the ID in %s as completed.
+const completeScript = `tell application "Reminders" to set completed of reminder id %s to true`
+
+// createScript makes a reminder named with the text in %s in the default
+// list.
+const createScript = `tell application "Reminders" to make new reminder with properties {name:%s}`
+
+// reminder is one reminder listed from Reminders.
+type reminder struct {
+	id   string
+	name string
+	list string
+	due  time.Time
+}
+
+// parseReminders reads the reminders listScript prints.
+func parseReminders(out string) []reminder {
+	var reminders []reminder
+	for _, record := range strings.Split(out, recordSep) {
+		fields := strings.Split(strings.TrimPrefix(record, "\n"), fieldSep)
+		if len(fields) != 4 || fields[0] == "" {
+			continue
+		}
+		due, _ := time.ParseInLocation(osascript.ISODate, fields[3], time.Local)
+		reminders = append(reminders, reminder{id: fields[0], name: fields[1], list: fields[2], due: due})
+	}
+	return reminders
+}
+
+// completeReminder returns the script that marks the reminder with id as
+// completed.
+func completeReminder(id string) string {
+	return fmt.Sprintf(completeScript, osascript.Quote(id))
+}
+
+// newReminder returns the script that makes a reminder named name.
+func newReminder(name string) string {
+	return fmt.Sprintf(createScript, osascript.Quote(name))
+}
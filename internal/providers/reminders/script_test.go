@@ -0,0 +1,57 @@
+package reminders
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"changeme/internal/fuzzy"
+)
+
+func TestParseReminders(t *testing.T) {
+	out := "x-apple-reminder://R1\x1fCall Sam\x1fWork\x1f2025-06-02T15:00:00\x1e" +
+		"x-apple-reminder://R2\x1fBuy milk\x1fHome\x1f\x1e" +
+		"\x1fno id\x1fHome\x1f\x1e" +
+		"too\x1ffew\x1fHome\x1e\n"
+	got := parseReminders(out)
+	want := []reminder{
+		{id: "x-apple-reminder://R1", name: "Call Sam", list: "Work", due: time.Date(2025, 6, 2, 15, 0, 0, 0, time.Local)},
+		{id: "x-apple-reminder://R2", name: "Buy milk", list: "Home"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReminders =\n%+v\nwant\n%+v", got, want)
+	}
+	if r := result(got[0], 1); r.Subtitle != "Work · Due Jun 2, 2025 15:00" || r.Target != "x-apple-reminder://R1" {
+		t.Errorf("result = %+v", r)
+	}
+	if r := result(got[1], 1); r.Subtitle != "Home" {
+		t.Errorf("result without a due date = %+v", r)
+	}
+}
+
+func TestScripts(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{completeReminder("x-apple-reminder://R1"), `tell application "Reminders" to set completed of reminder id "x-apple-reminder://R1" to true`},
+		{newReminder(`Ask "Sam" about C:\temp`), `tell application "Reminders" to make new reminder with properties {name:"Ask \"Sam\" about C:\\temp"}`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("script =\n%s\nwant\n%s", tt.got, tt.want)
+		}
+	}
+}
+
+func TestSearchDenied(t *testing.T) {
+	p := New(nil, fuzzy.Default())
+	p.denied = true
+	results, err := p.Search(context.Background(), "call")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != accessID || results[1].ID != createID || results[1].Target != "call" {
+		t.Errorf("Search without access = %+v, want the access notice and New Reminder", results)
+	}
+}
@@ -0,0 +1,70 @@
+package repos
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// originURL returns the URL of the origin remote in the Git config of the
+// repository at dir, or "" when it has none.
+func originURL(dir string) string {
+	f, err := os.Open(filepath.Join(dir, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	inOrigin := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// WebURL converts a Git remote URL into the address of the repository's web
+// page, as hosts such as GitHub, GitLab and Bitbucket lay them out:
+// "git@github.com:user/repo.git" and "ssh://git@github.com:22/user/repo"
+// both become "https://github.com/user/repo". It reports false for local
+// paths and anything else without a host.
+func WebURL(remote string) (string, bool) {
+	remote = strings.TrimSpace(remote)
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		switch u.Scheme {
+		case "https", "http", "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return "", false
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// The scp-like form, [user@]host:path. A single letter before the
+		// colon is a Windows drive.
+		before, after, ok := strings.Cut(remote, ":")
+		if !ok || len(before) < 2 || strings.ContainsAny(before, `/\`) || after == "" || strings.HasPrefix(after, "//") {
+			return "", false
+		}
+		if _, h, ok := strings.Cut(before, "@"); ok {
+			before = h
+		}
+		host, path = before, after
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", false
+	}
+	return "https://" + host + "/" + path, true
+}
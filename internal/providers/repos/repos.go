@@ -0,0 +1,229 @@
+// Package repos provides results for the Git repositories found under the
+// user's repository folders, opening them in an editor or a terminal, or on
+// the web at their origin remote.
+package repos
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"changeme/internal/editor"
+	"changeme/internal/fuzzy"
+	"changeme/internal/index"
+	"changeme/internal/pathfmt"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID     = "repos"
+	actionOpen     = "open"
+	actionTerminal = "terminal"
+	actionRemote   = "remote"
+
+	// maxRepos bounds the repositories kept, so a mistakenly broad root
+	// cannot exhaust memory.
+	maxRepos = 5000
+	// maxResults bounds the results of one query.
+	maxResults = 50
+)
+
+// repo is a discovered repository.
+type repo struct {
+	name string
+	path string
+	// web is the page of its origin remote, or "".
+	web string
+}
+
+// Provider searches the repositories under its roots. They are discovered
+// on the first search and kept until Rebuild or SetRoots.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu       sync.Mutex
+	roots    []index.Root
+	ignore   []string
+	editor   string
+	terminal string
+	command  []string
+	repos    []repo
+	loaded   bool
+}
+
+// New returns a repository provider that opens folders and web pages
+// through plat and ranks repositories by name with matcher.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetRoots sets the folders searched for repositories, each to its own
+// depth, skipping names matching ignore, and marks them for rescanning.
+func (p *Provider) SetRoots(roots []index.Root, ignore []string) {
+	p.mu.Lock()
+	p.roots, p.ignore = roots, ignore
+	p.repos, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+// SetEditor sets the editor command repositories open in. Its first word is
+// run with the repository's folder; see config.Config.Editor. Without an
+// editor, the folder opens in its default application.
+func (p *Provider) SetEditor(editor string) {
+	p.mu.Lock()
+	p.editor = editor
+	p.mu.Unlock()
+}
+
+// SetTerminal sets the terminal, as for platform.Platform.OpenTerminal, and
+// the command run in it, which is the user's shell when empty.
+func (p *Provider) SetTerminal(terminal string, command []string) {
+	p.mu.Lock()
+	p.terminal, p.command = terminal, command
+	p.mu.Unlock()
+}
+
+// Rebuild discards the discovered repositories so the next search rescans.
+func (p *Provider) Rebuild() {
+	p.mu.Lock()
+	p.repos, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	var results []search.Result
+	for _, r := range p.list() {
+		score, ok := p.matcher.Match(query, r.name)
+		if !ok {
+			continue
+		}
+		results = append(results, result(r, float64(score)))
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results, nil
+}
+
+// Resolve returns the result for a discovered repository, reporting false
+// once it is gone.
+func (p *Provider) Resolve(ctx context.Context, id string) (search.Result, bool) {
+	path := strings.TrimPrefix(id, providerID+":")
+	for _, r := range p.list() {
+		if r.path == path {
+			if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+				return search.Result{}, false
+			}
+			return result(r, 0), true
+		}
+	}
+	return search.Result{}, false
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	p.mu.Lock()
+	ed, terminal, command := p.editor, p.terminal, p.command
+	p.mu.Unlock()
+	switch actionID {
+	case actionOpen:
+		if ed == "" {
+			return p.plat.Open(ctx, r.Target)
+		}
+		return editor.OpenDir(ed, r.Target)
+	case actionTerminal:
+		return p.plat.OpenTerminal(ctx, terminal, r.Target, command)
+	case actionRemote:
+		web, ok := WebURL(originURL(r.Target))
+		if !ok {
+			return fmt.Errorf("repos: %s has no web remote", r.Target)
+		}
+		return p.plat.Open(ctx, web)
+	}
+	return fmt.Errorf("repos: unknown action %q", actionID)
+}
+
+func result(r repo, score float64) search.Result {
+	actions := []search.Action{
+		{ID: actionOpen, Title: "Open in Editor"},
+		{ID: actionTerminal, Title: "Open in Terminal"},
+	}
+	if r.web != "" {
+		actions = append(actions, search.Action{ID: actionRemote, Title: "Open on Remote"})
+	}
+	return search.Result{
+		ID:       providerID + ":" + r.path,
+		Type:     "repo",
+		Title:    r.name,
+		Subtitle: pathfmt.Truncate(r.path, pathfmt.SubtitleLen),
+		Target:   r.path,
+		Score:    score,
+		Actions:  actions,
+	}
+}
+
+// list returns the repositories, discovering them first if needed.
+func (p *Provider) list() []repo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.loaded {
+		p.repos = discover(p.roots, p.ignore)
+		p.loaded = true
+	}
+	return p.repos
+}
+
+// discover returns the repositories under roots: the folders holding a .git
+// folder, each visited to its root's depth. A repository's own folders are
+// not searched for more, so submodules and vendored checkouts are left out.
+func discover(roots []index.Root, ignore []string) []repo {
+	var found []repo
+	seen := make(map[string]bool)
+	add := func(path string) bool {
+		if !isRepo(path) {
+			return false
+		}
+		if !seen[path] && len(found) < maxRepos {
+			seen[path] = true
+			found = append(found, repo{name: filepath.Base(path), path: path, web: webOf(path)})
+		}
+		return true
+	}
+	for _, root := range roots {
+		if add(filepath.Clean(index.Expand(root.Path))) {
+			continue
+		}
+		index.Walk(root, ignore, func(path string, d fs.DirEntry) bool {
+			if !d.IsDir() || len(found) >= maxRepos {
+				return false
+			}
+			return !add(path)
+		})
+	}
+	return found
+}
+
+// isRepo reports whether dir holds a .git folder.
+func isRepo(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// webOf returns the web page of the repository at dir's origin, or "".
+func webOf(dir string) string {
+	web, _ := WebURL(originURL(dir))
+	return web
+}
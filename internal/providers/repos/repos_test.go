@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"changeme/internal/index"
+)
+
+func TestWebURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+		ok     bool
+	}{
+		{"git@github.com:user/repo.git", "https://github.com/user/repo", true},
+		{"ssh://git@github.com:22/user/repo", "https://github.com/user/repo", true},
+		{"https://github.com/user/repo.git", "https://github.com/user/repo", true},
+		{"http://example.com/user/repo/", "https://example.com/user/repo", true},
+		{"git://example.com/repo.git", "https://example.com/repo", true},
+		{"gitlab.com:group/sub/repo.git", "https://gitlab.com/group/sub/repo", true},
+		{" https://bitbucket.org/team/repo \n", "https://bitbucket.org/team/repo", true},
+		{"", "", false},
+		{"/srv/git/repo.git", "", false},
+		{"../repo", "", false},
+		{`C:\repos\x`, "", false},
+		{"C:/repos/x", "", false},
+		{"file:///srv/git/repo.git", "", false},
+		{"https://github.com/", "", false},
+		{"git@github.com:", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := WebURL(tt.remote)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("WebURL(%q) = %q, %v, want %q, %v", tt.remote, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// makeRepo creates a repository at dir, with an origin of remote unless it
+// is "".
+func makeRepo(t *testing.T, dir, remote string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if remote == "" {
+		return
+	}
+	config := "[core]\n\tbare = false\n[remote \"upstream\"]\n\turl = git@example.com:other/fork.git\n" +
+		"[remote \"origin\"]\n\turl = " + remote + "\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, filepath.Join(root, "alpha"), "git@github.com:user/alpha.git")
+	makeRepo(t, filepath.Join(root, "group", "beta"), "")
+	// Inside a repository, so left out.
+	makeRepo(t, filepath.Join(root, "alpha", "vendor", "nested"), "")
+	// Beyond the root's depth.
+	makeRepo(t, filepath.Join(root, "a", "b", "deep"), "")
+	// Ignored by name.
+	makeRepo(t, filepath.Join(root, "node_modules", "dep"), "")
+	// A file named .git, as in a worktree, is not a repository folder.
+	if err := os.MkdirAll(filepath.Join(root, "worktree"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "worktree", ".git"), []byte("gitdir: ../alpha/.git\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A root that is itself a repository is found without walking it.
+	single := filepath.Join(t.TempDir(), "single")
+	makeRepo(t, single, "https://gitlab.com/group/single.git")
+	makeRepo(t, filepath.Join(single, "sub"), "")
+
+	roots := []index.Root{{Path: root, MaxDepth: 2}, {Path: single}, {Path: root, MaxDepth: 2}}
+	got := discover(roots, []string{"node_modules"})
+	want := []repo{
+		{name: "alpha", path: filepath.Join(root, "alpha"), web: "https://github.com/user/alpha"},
+		{name: "beta", path: filepath.Join(root, "group", "beta")},
+		{name: "single", path: single, web: "https://gitlab.com/group/single"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discover =\n%+v\nwant\n%+v", got, want)
+	}
+}
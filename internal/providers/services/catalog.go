@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of selection a service works on.
+type Kind string
+
+const (
+	KindNone  Kind = ""
+	KindText  Kind = "text"
+	KindFiles Kind = "files"
+)
+
+// Service is an entry of the system Services menu.
+type Service struct {
+	// Path is the menu path of the item; most services have one element,
+	// and those grouped in a submenu have two, as in "Finder/Show Info".
+	Path []string
+	// App is the app or workflow that provides the service.
+	App string
+	// Accepts lists the selection kinds the service takes. A service that
+	// takes none works without a selection.
+	Accepts []Kind
+}
+
+// Title is the name shown in the Services menu.
+func (s Service) Title() string { return s.Path[len(s.Path)-1] }
+
+// applies reports whether s can run on a selection of kind. Services that
+// take no input always apply; others need a selection they accept.
+func (s Service) applies(kind Kind) bool {
+	return len(s.Accepts) == 0 || slices.Contains(s.Accepts, kind)
+}
+
+// Applicable returns the services that can run on a selection of kind.
+func Applicable(services []Service, kind Kind) []Service {
+	var out []Service
+	for _, s := range services {
+		if s.applies(kind) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// sendKinds maps pasteboard types in a service's NSSendTypes to the
+// selection kind they carry. Types not listed, such as images, are not
+// captured from the frontmost app, and services needing only them are left
+// out.
+var sendKinds = map[string]Kind{
+	"NSStringPboardType":                     KindText,
+	"NSRTFPboardType":                        KindText,
+	"public.utf8-plain-text":                 KindText,
+	"public.plain-text":                      KindText,
+	"public.text":                            KindText,
+	"public.rtf":                             KindText,
+	"NSFilenamesPboardType":                  KindFiles,
+	"public.file-url":                        KindFiles,
+	"public.url":                             KindFiles,
+	"com.apple.finder.node":                  KindFiles,
+	"NSURLPboardType":                        KindFiles,
+	"com.apple.pasteboard.promised-file-url": KindFiles,
+}
+
+// bundleDirs are searched for apps and services that add to the Services
+// menu, besides ~/Library/Services.
+var bundleDirs = []string{
+	"/System/Library/Services",
+	"/Library/Services",
+	"/Applications",
+	"/System/Applications",
+	"/System/Library/CoreServices",
+}
+
+// loadCatalog reads the NSServices declarations of every app and service
+// bundle on the system.
+func loadCatalog(ctx context.Context) []Service {
+	dirs := bundleDirs
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append([]string{filepath.Join(home, "Library", "Services")}, dirs...)
+	}
+	var services []Service
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if ctx.Err() != nil {
+				return services
+			}
+			switch filepath.Ext(e.Name()) {
+			case ".app", ".service", ".workflow":
+			default:
+				continue
+			}
+			plist := filepath.Join(dir, e.Name(), "Contents", "Info.plist")
+			data, err := plistJSON(ctx, plist)
+			if err != nil {
+				continue
+			}
+			app := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+			services = append(services, parseServices(data, app)...)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Title() < services[j].Title() })
+	return services
+}
+
+// plistJSON converts the NSServices key of a property list, binary or XML,
+// to JSON with plutil.
+func plistJSON(ctx context.Context, path string) ([]byte, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "plutil", "-extract", "NSServices", "json", "-o", "-", path)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// parseServices reads the services of an NSServices array. Entries
+// without a menu title, and those taking only selections Prism cannot
+// capture, are skipped.
+func parseServices(data []byte, app string) []Service {
+	var entries []struct {
+		MenuItem  map[string]string `json:"NSMenuItem"`
+		SendTypes []string          `json:"NSSendTypes"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+	var services []Service
+	for _, e := range entries {
+		title := e.MenuItem["default"]
+		if title == "" {
+			continue
+		}
+		s := Service{Path: strings.Split(title, "/"), App: app}
+		for _, t := range e.SendTypes {
+			if k, ok := sendKinds[t]; ok && !slices.Contains(s.Accepts, k) {
+				s.Accepts = append(s.Accepts, k)
+			}
+		}
+		if len(e.SendTypes) > 0 && len(s.Accepts) == 0 {
+			continue
+		}
+		services = append(services, s)
+	}
+	return services
+}
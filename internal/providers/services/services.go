@@ -0,0 +1,202 @@
+// Package services offers the macOS Services menu, such as "New Email With
+// Selection" or "Make New Sticky Note", as results. Only services that can
+// take what is selected in the app that was frontmost before the launcher
+// opened are shown, and choosing one runs it from that app's Services menu
+// through System Events.
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID    = "services"
+	actionRun     = "run"
+	pathSeparator = "\x1f"
+	// catalogTTL is how long the list of installed services is kept
+	// before it is read again, since reading it opens every app bundle.
+	catalogTTL = 30 * time.Minute
+	// finderPath is the bundle whose selection is read as files.
+	finderPath = "/System/Library/CoreServices/Finder.app"
+)
+
+// selectedTextScript prints "text" when the focused element of the process
+// p has selected text. %s is the process lookup from osascript.FindProcess.
+const selectedTextScript = `tell application "System Events"
+%s
+	try
+		set t to value of attribute "AXSelectedText" of (value of attribute "AXFocusedUIElement" of p)
+		if t is not missing value and t is not "" then return "text"
+	end try
+	return ""
+end tell`
+
+const finderSelectionScript = `tell application "Finder" to return count of (get selection)`
+
+// Provider searches the services applicable to the frontmost app's
+// selection.
+type Provider struct {
+	matcher   *fuzzy.Matcher
+	frontmost func() platform.App
+
+	mu       sync.Mutex
+	catalog  []Service
+	loadedAt time.Time
+	loading  bool
+	app      platform.App
+	kind     Kind
+	err      error
+	captured chan struct{} // closed once the session's selection is read
+	session  context.CancelFunc
+}
+
+// New returns a Services provider for the app returned by frontmost, which
+// is the app captured before the launcher took focus.
+func New(matcher *fuzzy.Matcher, frontmost func() platform.App) *Provider {
+	return &Provider{matcher: matcher, frontmost: frontmost}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession reads the kind of the frontmost app's selection in the
+// background and refreshes the catalog when it is out of date.
+func (p *Provider) BeginSession() {
+	ctx, cancel := context.WithCancel(context.Background())
+	captured := make(chan struct{})
+	app := p.frontmost()
+
+	p.mu.Lock()
+	if p.session != nil {
+		p.session()
+	}
+	p.app, p.kind, p.err, p.captured, p.session = app, KindNone, nil, captured, cancel
+	stale := time.Since(p.loadedAt) > catalogTTL && !p.loading
+	if stale {
+		p.loading = true
+	}
+	p.mu.Unlock()
+
+	if stale {
+		go func() {
+			catalog := loadCatalog(context.Background())
+			p.mu.Lock()
+			p.catalog, p.loadedAt, p.loading = catalog, time.Now(), false
+			p.mu.Unlock()
+		}()
+	}
+	go func() {
+		defer close(captured)
+		if app.Name == "" {
+			return
+		}
+		kind, err := captureSelection(ctx, app)
+		p.mu.Lock()
+		if p.captured == captured {
+			p.kind, p.err = kind, err
+		}
+		p.mu.Unlock()
+	}()
+}
+
+// captureSelection reports the kind of what is selected in app: files in
+// the Finder, text anywhere else.
+func captureSelection(ctx context.Context, app platform.App) (Kind, error) {
+	if app.Path == finderPath {
+		out, err := osascript.Run(ctx, "Finder", finderSelectionScript)
+		if err != nil {
+			return KindNone, err
+		}
+		if out != "" && out != "0" {
+			return KindFiles, nil
+		}
+		return KindNone, nil
+	}
+	out, err := osascript.Run(ctx, "System Events", fmt.Sprintf(selectedTextScript, osascript.FindProcess(app.Path, app.Name)))
+	if err != nil {
+		return KindNone, err
+	}
+	return Kind(out), nil
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	p.mu.Lock()
+	captured := p.captured
+	p.mu.Unlock()
+	if captured == nil {
+		return nil, nil
+	}
+	select {
+	case <-captured:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	catalog, kind, err := p.catalog, p.kind, p.err
+	p.mu.Unlock()
+	if err != nil {
+		// Without Accessibility access the selection cannot be read nor a
+		// service run, so the permission error is shown instead.
+		return nil, err
+	}
+
+	var results []search.Result
+	for _, s := range Applicable(catalog, kind) {
+		score, ok := p.matcher.Match(query, s.Title())
+		if !ok {
+			continue
+		}
+		results = append(results, search.Result{
+			ID:       providerID + ":" + strings.Join(s.Path, pathSeparator),
+			Type:     "service",
+			Title:    s.Title(),
+			Subtitle: "Service · " + s.App,
+			Score:    float64(score),
+			Actions:  []search.Action{{ID: actionRun, Title: "Run Service", HideBeforeRun: true}},
+		})
+	}
+	return results, nil
+}
+
+// Activate brings the session's app forward, so the service gets its
+// selection, and chooses the service from its Services menu.
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionRun {
+		return fmt.Errorf("services: unknown action %q", actionID)
+	}
+	key, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok || key == "" {
+		return search.ErrUnknownResult
+	}
+	p.mu.Lock()
+	app := p.app
+	p.mu.Unlock()
+	_, err := osascript.Run(ctx, "System Events", runScript(app, strings.Split(key, pathSeparator)))
+	return err
+}
+
+// runScript builds the script choosing the service at path from the
+// Services submenu of app's application menu.
+func runScript(app platform.App, path []string) string {
+	ref := `menu 1 of menu item "Services" of menu 1 of menu bar item 2 of menu bar 1 of p`
+	for _, name := range path[:len(path)-1] {
+		ref = "menu 1 of menu item " + osascript.Quote(name) + " of " + ref
+	}
+	return `tell application "System Events"
+` + osascript.FindProcess(app.Path, app.Name) + `
+	set frontmost of p to true
+	click menu item ` + osascript.Quote(path[len(path)-1]) + ` of ` + ref + `
+end tell`
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/search"
+)
+
+var catalog = []Service{
+	{Path: []string{"Make New Sticky Note"}, App: "Stickies", Accepts: []Kind{KindText}},
+	{Path: []string{"New Email With Selection"}, App: "Mail", Accepts: []Kind{KindText, KindFiles}},
+	{Path: []string{"Finder", "Show Info"}, App: "Finder", Accepts: []Kind{KindFiles}},
+	{Path: []string{"Capture Full Screen"}, App: "Screenshot"},
+}
+
+func titles(services []Service) []string {
+	var out []string
+	for _, s := range services {
+		out = append(out, s.Title())
+	}
+	return out
+}
+
+func TestApplicable(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		want []string
+	}{
+		{KindNone, []string{"Capture Full Screen"}},
+		{KindText, []string{"Make New Sticky Note", "New Email With Selection", "Capture Full Screen"}},
+		{KindFiles, []string{"New Email With Selection", "Show Info", "Capture Full Screen"}},
+	}
+	for _, tt := range tests {
+		if got := titles(Applicable(catalog, tt.kind)); !slices.Equal(got, tt.want) {
+			t.Errorf("Applicable(%q) = %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestParseServices(t *testing.T) {
+	data := []byte(`[
+		{"NSMenuItem": {"default": "Mail/Send Selection"}, "NSSendTypes": ["NSStringPboardType", "public.utf8-plain-text", "NSFilenamesPboardType"]},
+		{"NSMenuItem": {"default": "Set Desktop Picture"}, "NSSendTypes": ["public.image"]},
+		{"NSMenuItem": {}, "NSSendTypes": ["NSStringPboardType"]},
+		{"NSMenuItem": {"default": "New Window"}}
+	]`)
+	want := []Service{
+		{Path: []string{"Mail", "Send Selection"}, App: "Mail", Accepts: []Kind{KindText, KindFiles}},
+		{Path: []string{"New Window"}, App: "Mail"},
+	}
+	if got := parseServices(data, "Mail"); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseServices = %+v, want %+v", got, want)
+	}
+	if got := parseServices([]byte("not json"), "Mail"); got != nil {
+		t.Errorf("parseServices(not json) = %+v", got)
+	}
+}
+
+// captured returns a provider whose session has read a selection of kind,
+// or failed to with err.
+func captured(kind Kind, err error) *Provider {
+	done := make(chan struct{})
+	close(done)
+	p := New(fuzzy.Default(), nil)
+	p.catalog, p.kind, p.err, p.captured = catalog, kind, err, done
+	return p
+}
+
+func resultTitles(results []search.Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Title)
+	}
+	return out
+}
+
+func TestSearchBySelection(t *testing.T) {
+	tests := []struct {
+		kind  Kind
+		query string
+		want  []string
+	}{
+		{KindText, "new", []string{"Make New Sticky Note", "New Email With Selection"}},
+		{KindFiles, "new", []string{"New Email With Selection"}},
+		{KindNone, "new", nil},
+		{KindFiles, "info", []string{"Show Info"}},
+		{KindNone, "capture", []string{"Capture Full Screen"}},
+	}
+	for _, tt := range tests {
+		results, err := captured(tt.kind, nil).Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := resultTitles(results)
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Search(%q) with a %q selection = %q, want %q", tt.query, tt.kind, got, tt.want)
+		}
+	}
+
+	denied := errors.New("not allowed assistive access")
+	if _, err := captured(KindNone, denied).Search(context.Background(), "new"); !errors.Is(err, denied) {
+		t.Errorf("Search without access = %v, want the permission error", err)
+	}
+	// Before a session there is no selection to offer services for.
+	if results, _ := New(fuzzy.Default(), nil).Search(context.Background(), "new"); len(results) != 0 {
+		t.Errorf("Search before a session = %q", resultTitles(results))
+	}
+}
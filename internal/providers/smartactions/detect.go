@@ -0,0 +1,174 @@
+package smartactions
+
+import (
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"changeme/internal/providers/color"
+)
+
+// Suggestion is an action offered for the clipboard's contents.
+type Suggestion struct {
+	// Action is one of the Action* values, and decides what Target is.
+	Action   string
+	Title    string
+	Subtitle string
+	// Type is the result type, such as "url" or "file", which decides the
+	// icon and the secondary actions the launcher adds.
+	Type   string
+	Target string
+	// Swatch is a color shown beside the suggestion, as for a copied color.
+	Swatch string
+}
+
+// The actions a Suggestion can take.
+const (
+	// ActionOpen opens Target, a URL or a path, in its default app.
+	ActionOpen = "open"
+	// ActionReveal shows Target, a path, in the file manager.
+	ActionReveal = "reveal"
+	// ActionQuery types Target into the launcher for the provider that
+	// answers it, such as "mail to bob@example.com" for the mail provider.
+	ActionQuery = "query"
+	// ActionShorten shortens Target, a URL, through the configured
+	// shortener and copies the result.
+	ActionShorten = "shorten"
+)
+
+// Detector recognizes one kind of clipboard contents. Detect is given the
+// clipboard's text, trimmed and on a single line, and returns the actions it
+// offers for it, or none when the text is not its kind.
+type Detector struct {
+	// Name identifies the detector in the SmartActions setting.
+	Name   string
+	Detect func(text string) []Suggestion
+}
+
+// Builtin returns the detectors a new Provider starts with: URLs, colors,
+// email addresses, parcel tracking numbers and paths, in that order.
+func Builtin() []Detector {
+	return []Detector{
+		{Name: "url", Detect: detectURL},
+		{Name: "color", Detect: detectColor},
+		{Name: "email", Detect: detectEmail},
+		{Name: "tracking", Detect: detectTracking},
+		{Name: "path", Detect: detectPath},
+	}
+}
+
+// detectURL offers to open a web address and, when a shortener is set, to
+// shorten it; see Provider.SetShortener.
+func detectURL(text string) []Suggestion {
+	u, err := url.Parse(text)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	return []Suggestion{
+		{Action: ActionOpen, Type: "url", Title: "Open " + text, Subtitle: "From the clipboard", Target: text},
+		{Action: ActionShorten, Type: "url", Title: "Shorten " + u.Host + " Link", Subtitle: "Copy a short link to " + text, Target: text},
+	}
+}
+
+// detectColor offers a color written as hex, rgb() or hsl() to the color
+// provider, which shows it in every format. Bare names such as "tan" are
+// left alone, being as likely to be any other word.
+func detectColor(text string) []Suggestion {
+	lower := strings.ToLower(text)
+	if !strings.HasPrefix(lower, "#") && !strings.HasPrefix(lower, "rgb") && !strings.HasPrefix(lower, "hsl") {
+		return nil
+	}
+	c, ok := color.Parse(text)
+	if !ok {
+		return nil
+	}
+	return []Suggestion{{
+		Action:   ActionQuery,
+		Type:     "color",
+		Title:    "Color " + c.Hex(),
+		Subtitle: "Show the clipboard's color in every format",
+		Target:   text,
+		Swatch:   c.Hex(),
+	}}
+}
+
+// detectEmail offers to write to a bare email address through the mail
+// provider, where a subject and body can still be added.
+func detectEmail(text string) []Suggestion {
+	a, err := mail.ParseAddress(text)
+	if err != nil || a.Address != text || !strings.Contains(text[strings.LastIndex(text, "@"):], ".") {
+		return nil
+	}
+	return []Suggestion{{
+		Action:   ActionQuery,
+		Type:     "mail",
+		Title:    "Email " + text,
+		Subtitle: "Write a new message",
+		Target:   "mail to " + text,
+	}}
+}
+
+// carriers are the parcel tracking number formats recognized, tried in
+// order, with the page that tracks a number in each.
+var carriers = []struct {
+	name   string
+	number *regexp.Regexp
+	track  string
+}{
+	{"UPS", regexp.MustCompile(`^1Z[0-9A-Z]{16}$`), "https://www.ups.com/track?tracknum="},
+	{"USPS", regexp.MustCompile(`^(9[0-9]{19,21}|[A-Z]{2}[0-9]{9}US)$`), "https://tools.usps.com/go/TrackConfirmAction?tLabels="},
+	{"FedEx", regexp.MustCompile(`^([0-9]{12}|[0-9]{15})$`), "https://www.fedex.com/fedextrack/?trknbr="},
+}
+
+// detectTracking offers to track a parcel on its carrier's site. Spaces
+// within the number, as carriers print them, are ignored.
+func detectTracking(text string) []Suggestion {
+	number := strings.ToUpper(strings.ReplaceAll(text, " ", ""))
+	for _, c := range carriers {
+		if c.number.MatchString(number) {
+			return []Suggestion{{
+				Action:   ActionOpen,
+				Type:     "url",
+				Title:    "Track " + c.name + " Package",
+				Subtitle: number,
+				Target:   c.track + url.QueryEscape(number),
+			}}
+		}
+	}
+	return nil
+}
+
+// detectPath offers to open and reveal an existing file or folder given by
+// an absolute path, or one starting with ~.
+func detectPath(text string) []Suggestion {
+	path, ok := existingPath(text)
+	if !ok {
+		return nil
+	}
+	return []Suggestion{
+		{Action: ActionOpen, Type: "file", Title: "Open " + filepath.Base(path), Subtitle: path, Target: path},
+		{Action: ActionReveal, Type: "file", Title: "Reveal " + filepath.Base(path), Subtitle: "Show in the file manager", Target: path},
+	}
+}
+
+// existingPath returns text as the path of an existing file or folder,
+// with a leading ~ expanded.
+func existingPath(text string) (string, bool) {
+	if rest, ok := strings.CutPrefix(text, "~"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		text = home + rest
+	}
+	if !filepath.IsAbs(text) {
+		return "", false
+	}
+	if _, err := os.Stat(text); err != nil {
+		return "", false
+	}
+	return filepath.Clean(text), true
+}
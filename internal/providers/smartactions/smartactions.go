@@ -0,0 +1,200 @@
+// Package smartactions looks at what is on the clipboard when the launcher
+// opens blank and offers what can be done with it: opening or shortening a
+// URL, showing a color in every format, writing to an email address,
+// tracking a parcel, or opening or revealing a file. Most actions hand the
+// contents to the provider that already handles them, by typing a query
+// for it.
+//
+// Each kind of contents is recognized by a Detector. Detectors can be added
+// with Register and turned off by name with SetDisabled.
+package smartactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/network"
+	"changeme/internal/platform"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "smartactions"
+
+	// maxLen is the longest clipboard text considered; anything longer is
+	// prose or data rather than a link or an address.
+	maxLen = 2048
+)
+
+// errNoShortener is returned when shortening a URL with no shortener set.
+var errNoShortener = errors.New("smartactions: no URL shortener is set")
+
+// Provider offers actions for the clipboard's contents in the empty state.
+// It answers no queries.
+type Provider struct {
+	cb   clipboard.Clipboard
+	out  clipboard.Output
+	plat platform.Platform
+	net  *network.Client
+
+	mu        sync.Mutex
+	enabled   bool
+	detectors []Detector
+	disabled  map[string]bool
+	shortener string
+}
+
+// New returns a provider that reads cb, opens through plat and shortens
+// URLs through net, with the Builtin detectors. It offers nothing until
+// turned on with SetEnabled.
+func New(cb clipboard.Clipboard, out clipboard.Output, plat platform.Platform, net *network.Client) *Provider {
+	return &Provider{cb: cb, out: out, plat: plat, net: net, detectors: Builtin()}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetEnabled turns reading the clipboard on or off.
+func (p *Provider) SetEnabled(enabled bool) {
+	p.mu.Lock()
+	p.enabled = enabled
+	p.mu.Unlock()
+}
+
+// Register adds d after the detectors already registered, replacing the
+// one with the same name.
+func (p *Provider) Register(d Detector) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, old := range p.detectors {
+		if old.Name == d.Name {
+			p.detectors[i] = d
+			return
+		}
+	}
+	p.detectors = append(p.detectors, d)
+}
+
+// SetDisabled turns off the detectors named in settings with false, keyed
+// as the SmartActions setting is. Detectors not named stay on.
+func (p *Provider) SetDisabled(settings map[string]bool) {
+	disabled := make(map[string]bool)
+	for name, on := range settings {
+		if !on {
+			disabled[name] = true
+		}
+	}
+	p.mu.Lock()
+	p.disabled = disabled
+	p.mu.Unlock()
+}
+
+// SetShortener sets the URL template that shortens a link, with {url}
+// standing for the link, escaped, and the short link as the whole response
+// body. Empty, which keeps links from being sent anywhere, withholds the
+// Shorten action.
+func (p *Provider) SetShortener(template string) {
+	p.mu.Lock()
+	p.shortener = template
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	return nil, nil
+}
+
+// Status offers the actions the enabled detectors find for the clipboard,
+// in the order the detectors were registered.
+func (p *Provider) Status(ctx context.Context) []search.Result {
+	p.mu.Lock()
+	enabled, shortener := p.enabled, p.shortener
+	detectors := make([]Detector, 0, len(p.detectors))
+	for _, d := range p.detectors {
+		if !p.disabled[d.Name] {
+			detectors = append(detectors, d)
+		}
+	}
+	p.mu.Unlock()
+	if !enabled || p.cb == nil {
+		return nil
+	}
+	text, ok := p.cb.Text()
+	if !ok {
+		return nil
+	}
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxLen || strings.ContainsAny(text, "\r\n") {
+		return nil
+	}
+	var results []search.Result
+	for _, d := range detectors {
+		for _, s := range d.Detect(text) {
+			if s.Action == ActionShorten && shortener == "" {
+				continue
+			}
+			results = append(results, search.Result{
+				ID:       providerID + ":" + d.Name + ":" + s.Action + ":" + s.Target,
+				Type:     s.Type,
+				Title:    s.Title,
+				Subtitle: s.Subtitle,
+				Target:   s.Target,
+				Swatch:   s.Swatch,
+				Actions:  []search.Action{{ID: s.Action, Title: actionTitles[s.Action]}},
+			})
+		}
+	}
+	return results
+}
+
+// actionTitles name the actions in the action panel.
+var actionTitles = map[string]string{
+	ActionOpen:    "Open",
+	ActionReveal:  "Reveal",
+	ActionQuery:   "Show",
+	ActionShorten: "Shorten and Copy",
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if !strings.HasPrefix(r.ID, providerID+":") {
+		return search.ErrUnknownResult
+	}
+	switch actionID {
+	case ActionOpen:
+		return p.plat.Open(ctx, r.Target)
+	case ActionReveal:
+		return p.plat.Reveal(ctx, r.Target)
+	case ActionQuery:
+		return &search.QueryError{Query: r.Target}
+	case ActionShorten:
+		short, err := p.shorten(ctx, r.Target)
+		if err != nil {
+			return err
+		}
+		return p.out.Deliver(ctx, short, config.ClipboardActionCopy)
+	}
+	return fmt.Errorf("smartactions: unknown action %q", actionID)
+}
+
+// shorten returns the short link the shortener gives for link.
+func (p *Provider) shorten(ctx context.Context, link string) (string, error) {
+	p.mu.Lock()
+	template := p.shortener
+	p.mu.Unlock()
+	if template == "" {
+		return "", errNoShortener
+	}
+	resp, err := p.net.Get(ctx, providerID+":"+link, strings.ReplaceAll(template, "{url}", url.QueryEscape(link)))
+	if err != nil {
+		return "", fmt.Errorf("smartactions: shortening %s: %w", link, err)
+	}
+	short := strings.TrimSpace(string(resp.Body))
+	if !strings.HasPrefix(short, "http://") && !strings.HasPrefix(short, "https://") {
+		return "", fmt.Errorf("smartactions: the shortener answered %q, not a link", short)
+	}
+	return short, nil
+}
@@ -0,0 +1,110 @@
+package smartactions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+// clip is a clipboard holding text, with nothing on it when empty.
+type clip string
+
+func (c clip) Text() (string, bool) { return string(c), c != "" }
+
+// offered returns the IDs of results, which name the detector, the action
+// and the target.
+func offered(results []search.Result) []string {
+	var got []string
+	for _, r := range results {
+		got = append(got, r.ID)
+	}
+	return got
+}
+
+func TestStatus(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text      string
+		shortener string
+		want      []string
+	}{
+		{"https://example.com/a", "", []string{"smartactions:url:open:https://example.com/a"}},
+		{"  https://example.com/a\n", "https://short.example/?u={url}", []string{
+			"smartactions:url:open:https://example.com/a",
+			"smartactions:url:shorten:https://example.com/a",
+		}},
+		{"#ff8800", "", []string{"smartactions:color:query:#ff8800"}},
+		{"rgb(255, 136, 0)", "", []string{"smartactions:color:query:rgb(255, 136, 0)"}},
+		{"bob@example.com", "", []string{"smartactions:email:query:mail to bob@example.com"}},
+		{"1Z 999 AA1 0123 4567 84", "", []string{"smartactions:tracking:open:https://www.ups.com/track?tracknum=1Z999AA10123456784"}},
+		{file, "", []string{"smartactions:path:open:" + file, "smartactions:path:reveal:" + file}},
+		// Nothing is offered for words, missing paths, local addresses or
+		// several lines.
+		{"tan", "", nil},
+		{"ftp://example.com", "", nil},
+		{"bob@localhost", "", nil},
+		{filepath.Join(filepath.Dir(file), "missing.pdf"), "", nil},
+		{"report.pdf", "", nil},
+		{"https://example.com\nhttps://example.org", "", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		p := New(clip(tt.text), nil, nil, nil)
+		p.SetEnabled(true)
+		p.SetShortener(tt.shortener)
+		if got := offered(p.Status(context.Background())); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Status for %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestStatusResults(t *testing.T) {
+	p := New(clip("#FF8800"), nil, nil, nil)
+	p.SetEnabled(true)
+	results := p.Status(context.Background())
+	if len(results) != 1 {
+		t.Fatalf("Status = %+v, want a color", results)
+	}
+	r := results[0]
+	if r.Type != "color" || r.Swatch != "#ff8800" || r.Target != "#FF8800" || r.Actions[0].ID != ActionQuery {
+		t.Errorf("color result = %+v", r)
+	}
+	// Showing a color types it for the color provider.
+	var qe *search.QueryError
+	if err := p.Activate(context.Background(), r, ActionQuery); !errors.As(err, &qe) || qe.Query != "#FF8800" {
+		t.Errorf("Activate(query) = %v, want a query for the color", err)
+	}
+	if err := p.Activate(context.Background(), search.Result{ID: "color:#FF8800"}, ActionQuery); !errors.Is(err, search.ErrUnknownResult) {
+		t.Errorf("Activate on another provider's result = %v, want ErrUnknownResult", err)
+	}
+}
+
+func TestStatusDisabled(t *testing.T) {
+	p := New(clip("bob@example.com"), nil, nil, nil)
+	if got := p.Status(context.Background()); got != nil {
+		t.Errorf("Status before SetEnabled = %+v, want nothing", got)
+	}
+	p.SetEnabled(true)
+	p.SetDisabled(map[string]bool{"email": false, "url": true})
+	if got := p.Status(context.Background()); got != nil {
+		t.Errorf("Status with email turned off = %+v, want nothing", got)
+	}
+
+	// A registered detector replaces the built-in one of its name.
+	p.SetDisabled(nil)
+	p.Register(Detector{Name: "email", Detect: func(text string) []Suggestion {
+		return []Suggestion{{Action: ActionQuery, Target: "contact " + text}}
+	}})
+	want := []string{"smartactions:email:query:contact bob@example.com"}
+	if got := offered(p.Status(context.Background())); !reflect.DeepEqual(got, want) {
+		t.Errorf("Status with a custom email detector = %q, want %q", got, want)
+	}
+}
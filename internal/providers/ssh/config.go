@@ -0,0 +1,142 @@
+package ssh
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth stops Include loops.
+const maxIncludeDepth = 8
+
+// host is a connectable Host entry.
+type host struct {
+	alias    string
+	hostname string
+	user     string
+	known    bool // found only in known_hosts
+}
+
+// parseConfig reads an ssh_config file and the files it includes, returning
+// hosts in file order. Host patterns with wildcards or negation describe
+// defaults for many hosts and are left out, as is the first alias of a line
+// seen before. Relative Include paths are resolved against sshDir, as ssh
+// does for the user config.
+func parseConfig(path, sshDir string) []host {
+	var hosts []host
+	seen := make(map[string]bool)
+	parseFile(path, sshDir, 0, seen, &hosts)
+	return hosts
+}
+
+func parseFile(path, sshDir string, depth int, seen map[string]bool, hosts *[]host) {
+	if depth > maxIncludeDepth {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var current []int // indexes into hosts of the aliases the block applies to
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value := splitLine(sc.Text())
+		switch key {
+		case "":
+			continue
+		case "include":
+			for _, pattern := range strings.Fields(value) {
+				pattern = expandHome(pattern)
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(sshDir, pattern)
+				}
+				matches, _ := filepath.Glob(pattern)
+				for _, m := range matches {
+					parseFile(m, sshDir, depth+1, seen, hosts)
+				}
+			}
+		case "host":
+			current = nil
+			for _, alias := range strings.Fields(value) {
+				if strings.ContainsAny(alias, "*?!") || seen[alias] {
+					continue
+				}
+				seen[alias] = true
+				*hosts = append(*hosts, host{alias: alias})
+				current = append(current, len(*hosts)-1)
+			}
+		case "match":
+			current = nil
+		case "hostname":
+			for _, i := range current {
+				if (*hosts)[i].hostname == "" {
+					(*hosts)[i].hostname = value
+				}
+			}
+		case "user":
+			for _, i := range current {
+				if (*hosts)[i].user == "" {
+					(*hosts)[i].user = value
+				}
+			}
+		}
+	}
+}
+
+// splitLine returns the lower-cased keyword and the value of a config line.
+// Keywords and values are separated by whitespace or an equals sign.
+func splitLine(line string) (key, value string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return strings.ToLower(line), ""
+	}
+	key = strings.ToLower(line[:i])
+	value = strings.TrimLeft(line[i:], " \t=")
+	return key, strings.Trim(value, `"`)
+}
+
+// parseKnownHosts reads host names from a known_hosts file. Hashed entries
+// cannot be read back, and entries for non-default ports, written
+// "[host]:port", need a port ssh would not infer, so both are skipped.
+func parseKnownHosts(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var names []string
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "@") || strings.HasPrefix(line, "|") {
+			continue
+		}
+		field, _, _ := strings.Cut(line, " ")
+		for _, name := range strings.Split(field, ",") {
+			if name == "" || strings.HasPrefix(name, "[") || strings.ContainsAny(name, "*?!") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
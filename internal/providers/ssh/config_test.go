@@ -0,0 +1,79 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config"), `# Personal hosts
+Include config.d/*
+Include loop
+
+Host web web-alias
+    HostName web.example.com
+    User deploy
+
+Host *.internal !bastion
+    User admin
+
+Host db
+  HostName=db.example.com
+  User "postgres"
+
+Match host db
+  User root
+
+Host web
+  HostName ignored.example.com
+`)
+	writeFile(t, filepath.Join(dir, "config.d", "work"), `Host work
+	Hostname work.example.com
+`)
+	// An Include of itself must not loop forever.
+	writeFile(t, filepath.Join(dir, "loop"), `Include loop
+Host loop
+`)
+
+	got := parseConfig(filepath.Join(dir, "config"), dir)
+	want := []host{
+		{alias: "work", hostname: "work.example.com"},
+		{alias: "loop"},
+		{alias: "web", hostname: "web.example.com", user: "deploy"},
+		{alias: "web-alias", hostname: "web.example.com", user: "deploy"},
+		{alias: "db", hostname: "db.example.com", user: "postgres"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConfig =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseKnownHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	writeFile(t, path, `github.com,140.82.121.4 ssh-ed25519 AAAA
+|1|hashed= ssh-ed25519 AAAA
+[git.example.com]:2222 ssh-ed25519 AAAA
+@revoked old.example.com ssh-rsa AAAA
+# comment
+github.com ssh-rsa AAAA
+*.example.org ssh-rsa AAAA
+`)
+	got := parseKnownHosts(path)
+	if want := []string{"github.com", "140.82.121.4"}; !slices.Equal(got, want) {
+		t.Errorf("parseKnownHosts = %v, want %v", got, want)
+	}
+}
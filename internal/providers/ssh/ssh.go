@@ -0,0 +1,140 @@
+// Package ssh provides results for the hosts in the user's SSH config and,
+// optionally, known_hosts, opening a terminal connected to the chosen host.
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID    = "ssh"
+	actionConnect = "connect"
+)
+
+// Provider searches SSH hosts. The config files are re-read once per
+// session, so edits show up the next time the launcher opens.
+type Provider struct {
+	plat    platform.Platform
+	matcher *fuzzy.Matcher
+
+	mu         sync.Mutex
+	terminal   string
+	knownHosts bool
+	hosts      []host
+	loaded     bool
+}
+
+// New returns an SSH provider that connects through the platform's terminal
+// and ranks hosts with matcher.
+func New(plat platform.Platform, matcher *fuzzy.Matcher) *Provider {
+	return &Provider{plat: plat, matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// SetTerminal sets the terminal app or {cmd} template used to connect; see
+// platform.Platform.RunInTerminal. knownHosts adds the hosts in
+// ~/.ssh/known_hosts that the config does not name.
+func (p *Provider) SetTerminal(terminal string, knownHosts bool) {
+	p.mu.Lock()
+	p.terminal, p.knownHosts = terminal, knownHosts
+	p.loaded = false
+	p.mu.Unlock()
+}
+
+// BeginSession drops the cached hosts so the next search re-reads them.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.hosts, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	query = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), "ssh "))
+	if query == "" {
+		return nil, nil
+	}
+	var results []search.Result
+	for _, h := range p.list() {
+		score, ok := p.matcher.Match(query, h.alias)
+		if !ok {
+			continue
+		}
+		results = append(results, result(h, float64(score)))
+	}
+	return results, nil
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionConnect {
+		return fmt.Errorf("ssh: unknown action %q", actionID)
+	}
+	alias := strings.TrimPrefix(r.ID, providerID+":")
+	if alias == "" || strings.HasPrefix(alias, "-") {
+		return fmt.Errorf("ssh: malformed result id %q", r.ID)
+	}
+	p.mu.Lock()
+	terminal := p.terminal
+	p.mu.Unlock()
+	return p.plat.RunInTerminal(ctx, terminal, []string{"ssh", alias})
+}
+
+func result(h host, score float64) search.Result {
+	subtitle := h.hostname
+	if subtitle == "" {
+		subtitle = h.alias
+	}
+	if h.user != "" {
+		subtitle = h.user + "@" + subtitle
+	}
+	if h.known {
+		subtitle = "From known hosts"
+	}
+	return search.Result{
+		ID:       providerID + ":" + h.alias,
+		Type:     "host",
+		Title:    h.alias,
+		Subtitle: subtitle,
+		Score:    score,
+		Actions:  []search.Action{{ID: actionConnect, Title: "Connect with SSH"}},
+	}
+}
+
+// list returns the hosts, reading the config files once per session.
+func (p *Provider) list() []host {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.hosts
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		p.loaded = true
+		return nil
+	}
+	dir := filepath.Join(home, ".ssh")
+	hosts := parseConfig(filepath.Join(dir, "config"), dir)
+	if p.knownHosts {
+		named := make(map[string]bool, len(hosts))
+		for _, h := range hosts {
+			named[h.alias] = true
+			named[h.hostname] = true
+		}
+		for _, name := range parseKnownHosts(filepath.Join(dir, "known_hosts")) {
+			if !named[name] {
+				hosts = append(hosts, host{alias: name, known: true})
+			}
+		}
+	}
+	p.hosts, p.loaded = hosts, true
+	return hosts
+}
@@ -0,0 +1,207 @@
+// Package switcher provides results for the windows of running applications,
+// so the user can jump to a specific window rather than just its app.
+package switcher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/search"
+)
+
+const (
+	providerID  = "windows"
+	actionFocus = "focus"
+)
+
+// listScript prints one line per window of every regular application:
+// process id, app name, window index, title and whether it is minimized,
+// separated by tabs. Reading windows through System Events needs
+// Accessibility access.
+const listScript = `tell application "System Events"
+	set out to ""
+	repeat with p in (every process whose background only is false)
+		set pid to unix id of p
+		set pname to name of p
+		try
+			set i to 0
+			repeat with w in (every window of p)
+				set i to i + 1
+				set m to false
+				try
+					set m to value of attribute "AXMinimized" of w
+				end try
+				set out to out & pid & tab & pname & tab & i & tab & (name of w) & tab & m & linefeed
+			end repeat
+		end try
+	end repeat
+	return out
+end tell`
+
+type window struct {
+	pid       int
+	app       string
+	index     int // 1-based position in the app's window list
+	title     string
+	minimized bool
+}
+
+// Provider searches the windows of running applications by app name and
+// window title.
+type Provider struct {
+	matcher *fuzzy.Matcher
+
+	mu      sync.Mutex
+	windows []window
+	loaded  bool
+}
+
+// New returns a window switcher that ranks windows with matcher.
+func New(matcher *fuzzy.Matcher) *Provider {
+	return &Provider{matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession drops the cached window list so the next search re-reads it.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.windows, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	windows, err := p.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []search.Result
+	for _, w := range windows {
+		score, ok := matchWindow(p.matcher, terms, w.app, w.title)
+		if !ok {
+			continue
+		}
+		title, subtitle := w.title, w.app
+		if title == "" {
+			title = w.app
+		}
+		if w.minimized {
+			subtitle += " · Minimized"
+		}
+		results = append(results, search.Result{
+			ID:       fmt.Sprintf("%s:%d:%d", providerID, w.pid, w.index),
+			Type:     "window",
+			Title:    title,
+			Subtitle: subtitle,
+			Target:   w.title,
+			Score:    float64(score),
+			Actions:  []search.Action{{ID: actionFocus, Title: "Switch to Window", HideBeforeRun: true}},
+		})
+	}
+	return results, nil
+}
+
+// matchWindow matches each query term against the app name or the window
+// title, so "chrome invoice" finds the Chrome window whose title mentions an
+// invoice. Every term must match one of the two; a term matching both counts
+// its better score. Windows whose app matches the first term rank higher,
+// since people usually type the app first.
+func matchWindow(m *fuzzy.Matcher, terms []string, app, title string) (int, bool) {
+	total := 0
+	for i, term := range terms {
+		appScore, appOK := m.Match(term, app)
+		titleScore, titleOK := m.Match(term, title)
+		switch {
+		case appOK && titleOK:
+			total += max(appScore, titleScore)
+		case appOK:
+			total += appScore
+			if i == 0 {
+				total += appScore / 2
+			}
+		case titleOK:
+			total += titleScore
+		default:
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// Activate raises the window, restoring it first if it is minimized. The
+// window is found by title when it has one, because indexes shift as windows
+// open and close; the index from the listing is the fallback.
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if actionID != actionFocus {
+		return fmt.Errorf("windows: unknown action %q", actionID)
+	}
+	var pid, index int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(r.ID, providerID+":"), "%d:%d", &pid, &index); err != nil {
+		return fmt.Errorf("windows: malformed result id %q", r.ID)
+	}
+	find := "window " + strconv.Itoa(index) + " of p"
+	if r.Target != "" {
+		find = "first window of p whose name is " + osascript.Quote(r.Target)
+	}
+	script := `tell application "System Events"
+	set p to first process whose unix id is ` + strconv.Itoa(pid) + `
+	set w to ` + find + `
+	try
+		if value of attribute "AXMinimized" of w then set value of attribute "AXMinimized" of w to false
+	end try
+	perform action "AXRaise" of w
+	set frontmost of p to true
+end tell`
+	_, err := osascript.Run(ctx, "System Events", script)
+	return err
+}
+
+// list returns the open windows, reading them once per session.
+func (p *Provider) list(ctx context.Context) ([]window, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.windows, nil
+	}
+	out, err := osascript.Run(ctx, "System Events", listScript)
+	if err != nil {
+		return nil, err
+	}
+	p.windows, p.loaded = parseWindows(out), true
+	return p.windows, nil
+}
+
+func parseWindows(out string) []window {
+	var windows []window
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		index, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		windows = append(windows, window{
+			pid:       pid,
+			app:       fields[1],
+			index:     index,
+			title:     fields[3],
+			minimized: fields[4] == "true",
+		})
+	}
+	return windows
+}
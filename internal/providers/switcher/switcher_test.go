@@ -0,0 +1,78 @@
+package switcher
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/fuzzy"
+)
+
+func TestMatchWindow(t *testing.T) {
+	m := fuzzy.Default()
+	tests := []struct {
+		query      string
+		app, title string
+		want       bool
+	}{
+		{"chrome invoice", "Google Chrome", "Invoice #42 - Gmail", true},
+		{"invoice chrome", "Google Chrome", "Invoice #42 - Gmail", true},
+		{"invoice", "Google Chrome", "Invoice #42 - Gmail", true},
+		{"chrome", "Google Chrome", "", true},
+		{"chrome receipt", "Google Chrome", "Invoice #42 - Gmail", false},
+		{"safari invoice", "Google Chrome", "Invoice #42 - Gmail", false},
+	}
+	for _, tt := range tests {
+		if _, ok := matchWindow(m, strings.Fields(tt.query), tt.app, tt.title); ok != tt.want {
+			t.Errorf("matchWindow(%q, %q, %q) = %v, want %v", tt.query, tt.app, tt.title, ok, tt.want)
+		}
+	}
+
+	// Typing the app first favours its windows over windows that mention
+	// it in their title.
+	byApp, _ := matchWindow(m, strings.Fields("notes"), "Notes", "Shopping")
+	byTitle, _ := matchWindow(m, strings.Fields("notes"), "TextEdit", "Notes")
+	if byApp <= byTitle {
+		t.Errorf("app match scored %d, not above title match %d", byApp, byTitle)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	p := New(fuzzy.Default())
+	p.windows = parseWindows("101\tGoogle Chrome\t1\tInvoice #42 - Gmail\tfalse\n" +
+		"101\tGoogle Chrome\t2\tNews\ttrue\n" +
+		"202\tNotes\t1\t\tfalse\n" +
+		"garbage line\n")
+	p.loaded = true
+
+	results, err := p.Search(context.Background(), "chrome invoice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "windows:101:1" || results[0].Title != "Invoice #42 - Gmail" {
+		t.Fatalf("results = %+v, want the invoice window", results)
+	}
+
+	results, err = p.Search(context.Background(), "chrome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subtitles []string
+	for _, r := range results {
+		subtitles = append(subtitles, r.Subtitle)
+	}
+	slices.Sort(subtitles)
+	if want := []string{"Google Chrome", "Google Chrome · Minimized"}; !slices.Equal(subtitles, want) {
+		t.Errorf("subtitles = %v, want %v", subtitles, want)
+	}
+
+	// A window without a title is listed under its app's name.
+	results, err = p.Search(context.Background(), "notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Title != "Notes" || results[0].Target != "" {
+		t.Errorf("results = %+v, want the untitled Notes window", results)
+	}
+}
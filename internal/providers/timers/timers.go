@@ -0,0 +1,114 @@
+// Package timers lets the user start countdown timers and stopwatches by
+// typing "timer 5m" or "stopwatch", and lists the running ones.
+package timers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"changeme/internal/search"
+	"changeme/internal/timer"
+)
+
+const (
+	providerID     = "timers"
+	actionStart    = "start"
+	actionCancel   = "cancel"
+	startIDPrefix  = providerID + ":start:"
+	activeIDPrefix = providerID + ":active:"
+	stopwatchKey   = "stopwatch"
+)
+
+// Timers starts and tracks timers; TimerService implements it.
+type Timers interface {
+	Start(d time.Duration) (timer.Timer, error)
+	StartStopwatch() timer.Timer
+	List() []timer.Timer
+	Cancel(id string) error
+}
+
+// Provider turns timer queries into results.
+type Provider struct {
+	timers Timers
+	now    func() time.Time
+}
+
+// New returns a provider backed by timers.
+func New(timers Timers) *Provider {
+	return &Provider{timers: timers, now: time.Now}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	switch {
+	case q == stopwatchKey:
+		return append([]search.Result{{
+			ID:      startIDPrefix + stopwatchKey,
+			Type:    "timer",
+			Title:   "Start Stopwatch",
+			Score:   100,
+			Actions: []search.Action{{ID: actionStart, Title: "Start"}},
+		}}, p.Status(ctx)...), nil
+	case q == "timer" || q == "timers":
+		return p.Status(ctx), nil
+	case strings.HasPrefix(q, "timer "):
+		d, err := timer.ParseDuration(strings.TrimPrefix(q, "timer "))
+		if err != nil {
+			return nil, nil
+		}
+		return []search.Result{{
+			ID:       startIDPrefix + d.String(),
+			Type:     "timer",
+			Title:    "Start " + timer.Format(d) + " Timer",
+			Subtitle: "Notifies you when it finishes",
+			Score:    100,
+			Actions:  []search.Action{{ID: actionStart, Title: "Start"}},
+		}}, nil
+	}
+	return nil, nil
+}
+
+// Status lists running timers with their remaining or elapsed time, so they
+// show in the empty state.
+func (p *Provider) Status(ctx context.Context) []search.Result {
+	now := p.now()
+	var results []search.Result
+	for _, t := range p.timers.List() {
+		r := search.Result{
+			ID:      activeIDPrefix + t.ID,
+			Type:    "timer",
+			Title:   t.Label,
+			Actions: []search.Action{{ID: actionCancel, Title: "Cancel"}},
+		}
+		if t.Stopwatch() {
+			r.Subtitle = timer.Format(t.Elapsed(now)) + " elapsed"
+			r.Actions[0].Title = "Stop"
+		} else {
+			r.Subtitle = timer.Format(t.Remaining(now)) + " remaining"
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	switch {
+	case actionID == actionStart && r.ID == startIDPrefix+stopwatchKey:
+		p.timers.StartStopwatch()
+		return nil
+	case actionID == actionStart && strings.HasPrefix(r.ID, startIDPrefix):
+		d, err := time.ParseDuration(strings.TrimPrefix(r.ID, startIDPrefix))
+		if err != nil {
+			return err
+		}
+		_, err = p.timers.Start(d)
+		return err
+	case actionID == actionCancel && strings.HasPrefix(r.ID, activeIDPrefix):
+		return p.timers.Cancel(strings.TrimPrefix(r.ID, activeIDPrefix))
+	}
+	return fmt.Errorf("timers: unknown action %q", actionID)
+}
@@ -0,0 +1,288 @@
+// Package todo lists the TODO, FIXME and similar comments left in the
+// user's project folders, after the "todo " prefix, opening each in the
+// editor at its line. Finding them needs ripgrep, which also keeps to what
+// .gitignore files allow.
+package todo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"changeme/internal/editor"
+	"changeme/internal/index"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+const (
+	providerID   = "todo"
+	actionOpen   = "open"
+	actionReveal = "reveal"
+
+	// maxResults bounds the comments listed, which a large tree can hold
+	// thousands of.
+	maxResults = 100
+	// maxFileSize skips files too large to be source code.
+	maxFileSize = 1 << 20
+	// maxText truncates the comment shown as the title.
+	maxText = 200
+	// noRipgrepID is the notice shown when ripgrep is not installed.
+	noRipgrepID = providerID + ":no-ripgrep"
+)
+
+// defaultMarkers are the words looked for when none are configured.
+var defaultMarkers = []string{"TODO", "FIXME", "HACK"}
+
+// item is one marked comment.
+type item struct {
+	path string
+	line int
+	// text is the line from the marker on, such as "TODO: retry".
+	text string
+}
+
+// Provider lists marked comments under its roots. They are found once per
+// session and narrowed by the rest of the query.
+type Provider struct {
+	plat platform.Platform
+
+	mu      sync.Mutex
+	roots   []string
+	markers []string
+	editor  string
+	items   []item
+	loaded  bool
+}
+
+// New returns a provider that opens and reveals files through plat.
+func New(plat platform.Platform) *Provider {
+	return &Provider{plat: plat, markers: defaultMarkers}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// PrefixOnly keeps the scan, which reads every file, to queries routed to
+// the provider.
+func (p *Provider) PrefixOnly() bool { return true }
+
+// BeginSession drops the comments found, so the next search scans again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.items, p.loaded = nil, false
+	p.mu.Unlock()
+}
+
+// SetRoots sets the project folders scanned. A leading "~/" is expanded.
+func (p *Provider) SetRoots(roots []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots = nil
+	for _, r := range roots {
+		p.roots = append(p.roots, index.Expand(r))
+	}
+	p.items, p.loaded = nil, false
+}
+
+// SetMarkers sets the words that mark a comment, matched as whole words
+// and case-sensitively; defaultMarkers when markers is empty.
+func (p *Provider) SetMarkers(markers []string) {
+	if len(markers) == 0 {
+		markers = defaultMarkers
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.markers = append([]string(nil), markers...)
+	p.items, p.loaded = nil, false
+}
+
+// SetEditor sets the command that opens a file at a line, such as
+// "code -g {file}:{line}"; see config.Config.Editor.
+func (p *Provider) SetEditor(editor string) {
+	p.mu.Lock()
+	p.editor = editor
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	rg, err := exec.LookPath("rg")
+	if err != nil {
+		return []search.Result{{
+			ID:       noRipgrepID,
+			Provider: providerID,
+			Type:     "notice",
+			Title:    "Install ripgrep to list TODOs",
+			Subtitle: "The todo search uses rg to scan project folders",
+		}}, nil
+	}
+	items, err := p.list(ctx, rg)
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var results []search.Result
+	for _, it := range items {
+		if query != "" && !strings.Contains(strings.ToLower(it.text), query) &&
+			!strings.Contains(strings.ToLower(filepath.Base(it.path)), query) {
+			continue
+		}
+		results = append(results, result(it))
+	}
+	// Keep the order the comments appear in, file by file.
+	for i := range results {
+		results[i].Score = float64(len(results) - i)
+	}
+	return results, nil
+}
+
+func result(it item) search.Result {
+	return search.Result{
+		ID:       fmt.Sprintf("%s:%s:%d", providerID, it.path, it.line),
+		Type:     "file",
+		Title:    it.text,
+		Subtitle: fmt.Sprintf("%s:%d", it.path, it.line),
+		Target:   it.path,
+		Actions: []search.Action{
+			{ID: actionOpen, Title: "Open at Line"},
+			{ID: actionReveal, Title: "Show in Folder"},
+		},
+	}
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	if r.ID == noRipgrepID {
+		return nil
+	}
+	switch actionID {
+	case actionOpen:
+		p.mu.Lock()
+		ed := p.editor
+		p.mu.Unlock()
+		if ed == "" {
+			return p.plat.Open(ctx, r.Target)
+		}
+		return editor.Open(ed, r.Target, lineOf(r.ID))
+	case actionReveal:
+		return p.plat.Reveal(ctx, r.Target)
+	}
+	return fmt.Errorf("todo: unknown action %q", actionID)
+}
+
+// lineOf returns the line number at the end of a result ID.
+func lineOf(id string) int {
+	i := strings.LastIndex(id, ":")
+	line, err := strconv.Atoi(id[i+1:])
+	if i < 0 || err != nil {
+		return 1
+	}
+	return line
+}
+
+// list returns the comments under the roots, scanning once per session.
+func (p *Provider) list(ctx context.Context, rg string) ([]item, error) {
+	p.mu.Lock()
+	if p.loaded {
+		defer p.mu.Unlock()
+		return p.items, nil
+	}
+	roots := append([]string(nil), p.roots...)
+	markers := p.markers
+	p.mu.Unlock()
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	items, err := scan(ctx, rg, markers, roots)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.items, p.loaded = items, true
+	p.mu.Unlock()
+	return items, nil
+}
+
+// scanArgs returns the arguments rg is run with to find markers in roots:
+// whole words, case-sensitively, sorted by path so the list is stable.
+func scanArgs(markers, roots []string) []string {
+	args := []string{
+		"--null", "--line-number", "--no-heading", "--color=never",
+		"--fixed-strings", "--word-regexp", "--sort=path",
+		"--max-filesize=" + strconv.Itoa(maxFileSize),
+	}
+	for _, m := range markers {
+		args = append(args, "-e", m)
+	}
+	return append(append(args, "--"), roots...)
+}
+
+// scan runs rg and reads up to maxResults marked comments from it.
+func scan(parent context.Context, rg string, markers, roots []string) ([]item, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, rg, scanArgs(markers, roots)...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	items := parse(out, markers, maxResults)
+	cancel()
+	// rg exits with status 1 when nothing matched and is killed once
+	// enough was read; neither is an error here.
+	cmd.Wait()
+	if err := parent.Err(); err != nil && len(items) == 0 {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parse reads up to limit items from rg output, each line holding a path,
+// a NUL, then "line:text".
+func parse(r io.Reader, markers []string, limit int) []item {
+	var items []item
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() && len(items) < limit {
+		path, rest, ok := strings.Cut(sc.Text(), "\x00")
+		if !ok {
+			continue
+		}
+		num, text, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		line, err := strconv.Atoi(num)
+		if err != nil {
+			continue
+		}
+		items = append(items, item{path: path, line: line, text: fromMarker(text, markers)})
+	}
+	return items
+}
+
+// fromMarker trims text to start at its first marker, dropping the comment
+// syntax and code before it, and shortens it for display.
+func fromMarker(text string, markers []string) string {
+	start := -1
+	for _, m := range markers {
+		if i := strings.Index(text, m); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+	}
+	if start > 0 {
+		text = text[start:]
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "*/"))
+	if len(text) > maxText {
+		text = text[:maxText] + "…"
+	}
+	return text
+}
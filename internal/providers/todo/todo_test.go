@@ -0,0 +1,103 @@
+package todo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	out := "main.go\x0012:\t// TODO: retry on timeout\n" +
+		"main.go\x0040:\tx := 1 /* FIXME handle overflow */\n" +
+		"lib/util.py\x003:# HACK(sam): remove after the migration\n" +
+		"no separator\n" +
+		"bad.go\x00x:TODO\n" +
+		"long.go\x001:// TODO " + strings.Repeat("a", maxText) + "\n"
+	got := parse(strings.NewReader(out), defaultMarkers, 10)
+	want := []item{
+		{path: "main.go", line: 12, text: "TODO: retry on timeout"},
+		{path: "main.go", line: 40, text: "FIXME handle overflow"},
+		{path: "lib/util.py", line: 3, text: "HACK(sam): remove after the migration"},
+		{path: "long.go", line: 1, text: ("TODO " + strings.Repeat("a", maxText))[:maxText] + "…"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parse =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := parse(strings.NewReader(out), defaultMarkers, 2); len(got) != 2 {
+		t.Errorf("parse with a limit of 2 read %d items", len(got))
+	}
+}
+
+func TestLineOf(t *testing.T) {
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{"todo:/src/main.go:12", 12},
+		{`todo:C:\src\main.go:7`, 7},
+		{"todo:/src/main.go", 1},
+		{"12", 1},
+	}
+	for _, tt := range tests {
+		if got := lineOf(tt.id); got != tt.want {
+			t.Errorf("lineOf(%q) = %d, want %d", tt.id, got, tt.want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSearch scans a fixture tree with ripgrep, when it is installed.
+func TestSearch(t *testing.T) {
+	if _, err := exec.LookPath("rg"); err != nil {
+		t.Skip("ripgrep is not installed")
+	}
+	root := t.TempDir()
+	// rg only honours .gitignore inside a Git repository.
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, ".gitignore"), "build/\n")
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n\n// TODO: parse flags\nfunc main() {}\n\n// todo lowercase is not a marker\n// TODOS is not a whole word\n")
+	writeFile(t, filepath.Join(root, "lib", "util.py"), "def f():\n    pass  # FIXME: slow\n")
+	writeFile(t, filepath.Join(root, "build", "gen.go"), "// TODO: generated\n")
+
+	p := New(nil)
+	p.SetRoots([]string{root})
+	results, err := p.Search(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Subtitle+" "+r.Title)
+	}
+	want := []string{
+		filepath.Join(root, "lib", "util.py") + ":2 FIXME: slow",
+		filepath.Join(root, "main.go") + ":3 TODO: parse flags",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search =\n%q\nwant\n%q", got, want)
+	}
+
+	p.SetMarkers([]string{"FIXME"})
+	results, err = p.Search(context.Background(), "slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || lineOf(results[0].ID) != 2 {
+		t.Errorf("Search for FIXME = %+v", results)
+	}
+}
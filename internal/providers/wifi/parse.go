@@ -0,0 +1,183 @@
+package wifi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// redacted is the name system_profiler shows for networks when Prism may
+// not use Location Services, which macOS requires to reveal them.
+const redacted = "<redacted>"
+
+// network is a Wi-Fi network in range.
+type network struct {
+	ssid string
+	// signal is the received signal strength in dBm, 0 when unknown.
+	signal   int
+	security string // "" for an open network
+	current  bool
+	known    bool
+}
+
+// scan is what one scan of the Wi-Fi interface found.
+type scan struct {
+	iface string
+	off   bool
+	// hidden is set when network names were redacted.
+	hidden   bool
+	networks []network
+}
+
+// profilerNetwork is a network as system_profiler -json describes it.
+type profilerNetwork struct {
+	Name     string `json:"_name"`
+	Security string `json:"spairport_security_mode"`
+	Signal   string `json:"spairport_signal_noise"`
+}
+
+// parseProfiler reads the Wi-Fi interface from the output of
+// `system_profiler -json SPAirPortDataType`: whether it is on, the network
+// it is joined to and the other networks in range, strongest first.
+func parseProfiler(data []byte) (scan, error) {
+	var out struct {
+		Types []struct {
+			Interfaces []struct {
+				Name    string            `json:"_name"`
+				Status  string            `json:"spairport_status_information"`
+				Current *profilerNetwork  `json:"spairport_current_network_information"`
+				Others  []profilerNetwork `json:"spairport_airport_other_local_wireless_networks"`
+			} `json:"spairport_airport_interfaces"`
+		} `json:"SPAirPortDataType"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return scan{}, fmt.Errorf("wifi: reading system_profiler output: %w", err)
+	}
+	for _, t := range out.Types {
+		for _, i := range t.Interfaces {
+			// Other interfaces, such as awdl0 for AirDrop, report no status.
+			if i.Status == "" {
+				continue
+			}
+			s := scan{iface: i.Name, off: strings.HasSuffix(i.Status, "_off")}
+			if i.Current != nil {
+				s.add(*i.Current, true)
+			}
+			for _, n := range i.Others {
+				s.add(n, false)
+			}
+			sort.SliceStable(s.networks, func(a, b int) bool {
+				na, nb := s.networks[a], s.networks[b]
+				if na.current != nb.current {
+					return na.current
+				}
+				return strength(na.signal) > strength(nb.signal)
+			})
+			return s, nil
+		}
+	}
+	return scan{}, fmt.Errorf("wifi: no Wi-Fi interface found")
+}
+
+// add records n unless its name is hidden or already listed, in which case
+// the stronger signal is kept.
+func (s *scan) add(n profilerNetwork, current bool) {
+	if n.Name == "" || n.Name == redacted {
+		s.hidden = true
+		return
+	}
+	found := network{ssid: n.Name, signal: parseSignal(n.Signal), security: security(n.Security), current: current}
+	for i, have := range s.networks {
+		if have.ssid == found.ssid {
+			if strength(found.signal) > strength(have.signal) {
+				found.current = found.current || have.current
+				s.networks[i] = found
+			}
+			return
+		}
+	}
+	s.networks = append(s.networks, found)
+}
+
+// strength orders signals, putting unknown ones last.
+func strength(dBm int) int {
+	if dBm == 0 {
+		return -1000
+	}
+	return dBm
+}
+
+// parseSignal reads the signal from a "-54 dBm / -90 dBm" signal and noise
+// pair, returning 0 when there is none.
+func parseSignal(s string) int {
+	signal, _, _ := strings.Cut(s, "/")
+	signal = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(signal), "dBm"))
+	n, err := strconv.Atoi(signal)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// security turns a system_profiler security mode such as
+// "spairport_security_mode_wpa2_personal" into "WPA2 Personal", or "" for
+// an open network.
+func security(mode string) string {
+	mode = strings.TrimPrefix(mode, "spairport_security_mode_")
+	if mode == "" || mode == "none" {
+		return ""
+	}
+	words := strings.Split(mode, "_")
+	for i, w := range words {
+		if strings.HasPrefix(w, "wpa") || strings.HasPrefix(w, "wep") {
+			words[i] = strings.ToUpper(w)
+		} else if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// parsePreferred reads the network names from the output of
+// `networksetup -listpreferredwirelessnetworks`, which lists each on its
+// own indented line after a heading.
+func parsePreferred(out string) []string {
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// joinFailed returns the complaint networksetup printed about joining a
+// network, which it reports with a zero exit status, or "".
+func joinFailed(out string) string {
+	out = strings.TrimSpace(out)
+	for _, prefix := range []string{"Could not", "Failed", "Error"} {
+		if strings.HasPrefix(out, prefix) {
+			return out
+		}
+	}
+	return ""
+}
+
+// signalLabel describes dBm in words, as the Wi-Fi menu's bars would.
+func signalLabel(dBm int) string {
+	switch {
+	case dBm == 0:
+		return ""
+	case dBm >= -55:
+		return "Strong signal"
+	case dBm >= -70:
+		return "Good signal"
+	default:
+		return "Weak signal"
+	}
+}
@@ -0,0 +1,151 @@
+package wifi
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// profiler is system_profiler -json SPAirPortDataType output, trimmed to
+// the fields parseProfiler reads.
+const profiler = `{
+  "SPAirPortDataType" : [
+    {
+      "spairport_airport_interfaces" : [
+        {
+          "_name" : "en0",
+          "spairport_status_information" : "spairport_status_connected",
+          "spairport_current_network_information" : {
+            "_name" : "Home",
+            "spairport_security_mode" : "spairport_security_mode_wpa2_personal",
+            "spairport_signal_noise" : "-71 dBm / -92 dBm"
+          },
+          "spairport_airport_other_local_wireless_networks" : [
+            {
+              "_name" : "Cafe",
+              "spairport_security_mode" : "spairport_security_mode_none",
+              "spairport_signal_noise" : "-80 dBm / -92 dBm"
+            },
+            {
+              "_name" : "Office",
+              "spairport_security_mode" : "spairport_security_mode_wpa3_enterprise",
+              "spairport_signal_noise" : "-50 dBm / -92 dBm"
+            },
+            {
+              "_name" : "Cafe",
+              "spairport_security_mode" : "spairport_security_mode_none",
+              "spairport_signal_noise" : "-60 dBm / -92 dBm"
+            },
+            {
+              "_name" : "Printer",
+              "spairport_security_mode" : "spairport_security_mode_wep"
+            },
+            {
+              "_name" : "<redacted>",
+              "spairport_signal_noise" : "-40 dBm / -92 dBm"
+            }
+          ]
+        },
+        {
+          "_name" : "awdl0"
+        }
+      ]
+    }
+  ]
+}`
+
+func TestParseProfiler(t *testing.T) {
+	got, err := parseProfiler([]byte(profiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := scan{iface: "en0", hidden: true, networks: []network{
+		// The joined network leads, whatever its signal; the others are
+		// strongest first, with unknown signals last.
+		{ssid: "Home", signal: -71, security: "WPA2 Personal", current: true},
+		{ssid: "Office", signal: -50, security: "WPA3 Enterprise"},
+		{ssid: "Cafe", signal: -60},
+		{ssid: "Printer", security: "WEP"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProfiler =\n%+v\nwant\n%+v", got, want)
+	}
+
+	off, err := parseProfiler([]byte(`{"SPAirPortDataType": [{"spairport_airport_interfaces": [
+		{"_name": "en0", "spairport_status_information": "spairport_status_off"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !off.off || len(off.networks) != 0 {
+		t.Errorf("parseProfiler with Wi-Fi off = %+v", off)
+	}
+
+	for _, data := range []string{"", "not json", `{"SPAirPortDataType": []}`, `{"SPAirPortDataType": [{"spairport_airport_interfaces": [{"_name": "awdl0"}]}]}`} {
+		if _, err := parseProfiler([]byte(data)); err == nil {
+			t.Errorf("parseProfiler(%q) succeeded", data)
+		}
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"-54 dBm / -90 dBm", -54},
+		{"-54 dBm", -54},
+		{" -71dBm/-92dBm ", -71},
+		{"", 0},
+		{"strong", 0},
+	}
+	for _, tt := range tests {
+		if got := parseSignal(tt.in); got != tt.want {
+			t.Errorf("parseSignal(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"spairport_security_mode_wpa2_personal", "WPA2 Personal"},
+		{"spairport_security_mode_wpa2_wpa3_personal", "WPA2 WPA3 Personal"},
+		{"spairport_security_mode_wep", "WEP"},
+		{"spairport_security_mode_none", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := security(tt.mode); got != tt.want {
+			t.Errorf("security(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParsePreferred(t *testing.T) {
+	out := "Preferred networks on en0:\n\tHome\n\tOffice Guest\n\t\n\tCafe\n"
+	if got, want := parsePreferred(out), []string{"Home", "Office Guest", "Cafe"}; !slices.Equal(got, want) {
+		t.Errorf("parsePreferred = %q, want %q", got, want)
+	}
+	if got := parsePreferred("en0 is not a Wi-Fi interface.\n"); got != nil {
+		t.Errorf("parsePreferred of an error = %q, want none", got)
+	}
+}
+
+func TestJoinFailed(t *testing.T) {
+	tests := []struct {
+		out  string
+		want string
+	}{
+		{"", ""},
+		{"Could not find network Cafe.\n", "Could not find network Cafe."},
+		{"Failed to join network Home.\nError: -3900  The operation couldn't be completed.\n", "Failed to join network Home.\nError: -3900  The operation couldn't be completed."},
+		{"Joined Home\n", ""},
+	}
+	for _, tt := range tests {
+		if got := joinFailed(tt.out); got != tt.want {
+			t.Errorf("joinFailed(%q) = %q, want %q", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package wifi lists the Wi-Fi networks in range on macOS after the "wifi"
+// keyword, strongest first, and joins the chosen one: known networks with
+// the password in the keychain, others after asking for one.
+package wifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/osascript"
+	"changeme/internal/search"
+)
+
+const (
+	providerID = "wifi"
+	keyword    = "wifi"
+
+	actionConnect  = "connect"
+	actionPassword = "connect-password"
+	actionTurnOn   = "turn-on"
+	actionSettings = "settings"
+
+	// score ranks the first network; each after it scores one less.
+	score = 100
+
+	// statusID is the result shown in place of networks when Wi-Fi is off
+	// or names are hidden.
+	statusID = providerID + ":"
+)
+
+// Settings panes opened for the current network and for granting Location
+// Services access, which macOS requires before it reveals network names.
+const (
+	wifiSettingsURL     = "x-apple.systempreferences:com.apple.wifi-settings-extension"
+	locationSettingsURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_LocationServices"
+)
+
+// passwordScript asks for the password of the network in %s, printing it,
+// or fails with error -128 when the user cancels.
+const passwordScript = `tell application "System Events"
+	activate
+	set r to display dialog "Enter the password for the Wi-Fi network " & %s & "." default answer "" with hidden answer with title "Join Wi-Fi Network" buttons {"Cancel", "Join"} default button "Join"
+	return text returned of r
+end tell`
+
+// errCancelled is the AppleScript error number for the user cancelling.
+const errCancelled = "-128"
+
+// Provider lists Wi-Fi networks. Scanning takes seconds, so the networks
+// found are kept for the rest of the session.
+type Provider struct {
+	matcher *fuzzy.Matcher
+
+	mu     sync.Mutex
+	scan   scan
+	loaded bool
+}
+
+// New returns a Wi-Fi provider that narrows networks by name with matcher.
+func New(matcher *fuzzy.Matcher) *Provider {
+	return &Provider{matcher: matcher}
+}
+
+func (p *Provider) ID() string { return providerID }
+
+// BeginSession drops the networks found so the next search scans again.
+func (p *Provider) BeginSession() {
+	p.mu.Lock()
+	p.scan, p.loaded = scan{}, false
+	p.mu.Unlock()
+}
+
+func (p *Provider) Search(ctx context.Context, query string) ([]search.Result, error) {
+	word, rest, _ := strings.Cut(strings.TrimLeft(query, " "), " ")
+	if !strings.EqualFold(word, keyword) {
+		return nil, nil
+	}
+	rest = strings.TrimSpace(rest)
+	s, err := p.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.off {
+		return []search.Result{{
+			ID:       statusID,
+			Type:     "notice",
+			Title:    "Wi-Fi Is Off",
+			Subtitle: "Turn it on to see networks in range",
+			Score:    score,
+			Actions:  []search.Action{{ID: actionTurnOn, Title: "Turn Wi-Fi On"}},
+		}}, nil
+	}
+	var results []search.Result
+	for i, n := range s.networks {
+		if rest != "" {
+			if _, ok := p.matcher.Match(rest, n.ssid); !ok {
+				continue
+			}
+		}
+		results = append(results, result(n, float64(score-i)))
+	}
+	if s.hidden && rest == "" {
+		results = append(results, search.Result{
+			ID:       statusID,
+			Type:     "notice",
+			Title:    "Some Network Names Are Hidden",
+			Subtitle: "Allow Prism to use Location Services to see every network",
+			Actions:  []search.Action{{ID: actionSettings, Title: "Open Location Services Settings"}},
+		})
+	}
+	return results, nil
+}
+
+func result(n network, s float64) search.Result {
+	var details []string
+	if n.current {
+		details = append(details, "Connected")
+	} else if n.known {
+		details = append(details, "Known network")
+	}
+	if l := signalLabel(n.signal); l != "" {
+		details = append(details, l)
+	}
+	if n.security == "" {
+		details = append(details, "Open")
+	} else {
+		details = append(details, n.security)
+	}
+	var actions []search.Action
+	switch {
+	case n.current:
+		actions = []search.Action{{ID: actionSettings, Title: "Open Wi-Fi Settings"}}
+	case n.known || n.security == "":
+		actions = []search.Action{{ID: actionConnect, Title: "Connect"}}
+	default:
+		actions = []search.Action{{ID: actionPassword, Title: "Connect with Password…", HideBeforeRun: true}}
+	}
+	return search.Result{
+		ID:       providerID + ":" + n.ssid,
+		Type:     "wifi",
+		Title:    n.ssid,
+		Subtitle: strings.Join(details, " · "),
+		Score:    s,
+		Actions:  actions,
+	}
+}
+
+func (p *Provider) Activate(ctx context.Context, r search.Result, actionID string) error {
+	ssid, ok := strings.CutPrefix(r.ID, providerID+":")
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	s, err := p.list(ctx)
+	if err != nil {
+		return err
+	}
+	switch actionID {
+	case actionSettings:
+		target := wifiSettingsURL
+		if r.ID == statusID {
+			target = locationSettingsURL
+		}
+		return exec.CommandContext(ctx, "open", target).Run()
+	case actionTurnOn:
+		if err := networksetup(ctx, "-setairportpower", s.iface, "on"); err != nil {
+			return err
+		}
+		p.BeginSession()
+		return search.ErrSearchAgain
+	case actionConnect:
+		return networksetup(ctx, "-setairportnetwork", s.iface, ssid)
+	case actionPassword:
+		password, err := osascript.Run(ctx, "System Events", fmt.Sprintf(passwordScript, osascript.Quote(ssid)))
+		if err != nil {
+			if strings.Contains(err.Error(), errCancelled) {
+				return nil
+			}
+			return err
+		}
+		return networksetup(ctx, "-setairportnetwork", s.iface, ssid, password)
+	}
+	return fmt.Errorf("wifi: unknown action %q", actionID)
+}
+
+// list returns the networks in range, scanning once per session.
+func (p *Provider) list(ctx context.Context) (scan, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loaded {
+		return p.scan, nil
+	}
+	out, err := exec.CommandContext(ctx, "system_profiler", "-json", "SPAirPortDataType").Output()
+	if err != nil {
+		return scan{}, fmt.Errorf("wifi: scanning: %w", err)
+	}
+	s, err := parseProfiler(out)
+	if err != nil {
+		return scan{}, err
+	}
+	if !s.off {
+		// Without the preferred list every network just asks for a
+		// password; that is still usable, so the error is not fatal.
+		if out, err := exec.CommandContext(ctx, "networksetup", "-listpreferredwirelessnetworks", s.iface).Output(); err == nil {
+			known := make(map[string]bool)
+			for _, name := range parsePreferred(string(out)) {
+				known[name] = true
+			}
+			for i := range s.networks {
+				s.networks[i].known = known[s.networks[i].ssid]
+			}
+		}
+	}
+	p.scan, p.loaded = s, true
+	return s, nil
+}
+
+// networksetup runs networksetup with args, turning the complaints it
+// prints with a zero exit status into errors.
+func networksetup(ctx context.Context, args ...string) error {
+	if len(args) > 1 && args[1] == "" {
+		return errors.New("wifi: no Wi-Fi interface found")
+	}
+	out, err := exec.CommandContext(ctx, "networksetup", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("wifi: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	if msg := joinFailed(string(out)); msg != "" {
+		return fmt.Errorf("wifi: %s", msg)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package quarantine sets aside a data file that could not be parsed, such
+// as one truncated by a crash during a write, so the store reading it can
+// start over without the next save destroying what was left.
+package quarantine
+
+import (
+	"fmt"
+	"os"
+)
+
+// Suffix is appended to the name of a quarantined file.
+const Suffix = ".corrupt"
+
+// Move renames the file at path to path+Suffix, replacing any file
+// quarantined there before, and returns an error describing why, for the
+// caller to report. cause is the parse error.
+func Move(path string, cause error) error {
+	if err := os.Rename(path, path+Suffix); err != nil {
+		return fmt.Errorf("%s is corrupt and could not be set aside: %w (%v)", path, cause, err)
+	}
+	return fmt.Errorf("%s is corrupt and was moved to %s%s: %w", path, path, Suffix, cause)
+}
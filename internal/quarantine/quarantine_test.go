@@ -0,0 +1,33 @@
+package quarantine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	cause := errors.New("unexpected end of JSON input")
+	for _, data := range []string{`{"a": 1,`, `garbage`} {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		err := Move(path, cause)
+		if !errors.Is(err, cause) || !strings.Contains(err.Error(), path+Suffix) {
+			t.Errorf("Move = %v, want the cause and where the file went", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s is still there: %v", path, err)
+		}
+		// A later quarantine replaces the earlier one.
+		if got, err := os.ReadFile(path + Suffix); err != nil || string(got) != data {
+			t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+		}
+	}
+	if err := Move(filepath.Join(t.TempDir(), "missing.json"), cause); !errors.Is(err, cause) || !strings.Contains(err.Error(), "could not be set aside") {
+		t.Errorf("Move of a missing file = %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// typeNames are the spoken names of result types. Types not listed are
+// read as they are.
+var typeNames = map[string]string{
+	"app":          "application",
+	"clipboard":    "clipboard item",
+	"command":      "command",
+	"confirmation": "confirmation",
+	"file":         "file",
+	"host":         "SSH host",
+	"layout":       "layout",
+	"menu":         "menu item",
+	"notice":       "notice",
+	"plugin":       "plugin",
+	"query":        "recent search",
+	"service":      "service",
+	"text":         "text",
+	"timer":        "timer",
+	"window":       "window",
+}
+
+var (
+	cardinals = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen", "twenty"}
+	ordinals = []string{"zeroth", "first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth",
+		"eleventh", "twelfth", "thirteenth", "fourteenth", "fifteenth", "sixteenth", "seventeenth", "eighteenth", "nineteenth", "twentieth"}
+)
+
+// labelResults sets the AccessibilityLabel of each result for screen
+// readers, from its title, type and position in the list. Verbose labels
+// include the subtitle and spell the position out, as in "Google Chrome,
+// /Applications, application, first of nine results"; terse ones read
+// "Google Chrome, application, 1 of 9".
+func labelResults(results []Result, verbose bool) {
+	for i := range results {
+		r := &results[i]
+		parts := []string{r.Title}
+		if verbose && r.Subtitle != "" {
+			parts = append(parts, r.Subtitle)
+		}
+		kind := r.Type
+		if name, ok := typeNames[kind]; ok {
+			kind = name
+		}
+		if kind != "" {
+			parts = append(parts, kind)
+		}
+		parts = append(parts, position(i+1, len(results), verbose))
+		r.AccessibilityLabel = strings.Join(parts, ", ")
+	}
+}
+
+// position describes the n-th of total results.
+func position(n, total int, verbose bool) string {
+	if !verbose {
+		return fmt.Sprintf("%d of %d", n, total)
+	}
+	noun := "results"
+	if total == 1 {
+		noun = "result"
+	}
+	if total < len(cardinals) {
+		return ordinals[n] + " of " + cardinals[total] + " " + noun
+	}
+	return "result " + strconv.Itoa(n) + " of " + strconv.Itoa(total)
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestLabelResults(t *testing.T) {
+	results := []Result{
+		{Title: "Google Chrome", Subtitle: "/Applications", Type: "app"},
+		{Title: "notes.txt", Type: "file"},
+		{Title: "Widget", Type: "gadget"},
+		{Title: "Untyped"},
+	}
+	tests := []struct {
+		verbose bool
+		want    []string
+	}{
+		{true, []string{
+			"Google Chrome, /Applications, application, first of four results",
+			"notes.txt, file, second of four results",
+			"Widget, gadget, third of four results",
+			"Untyped, fourth of four results",
+		}},
+		{false, []string{
+			"Google Chrome, application, 1 of 4",
+			"notes.txt, file, 2 of 4",
+			"Widget, gadget, 3 of 4",
+			"Untyped, 4 of 4",
+		}},
+	}
+	for _, tt := range tests {
+		labelResults(results, tt.verbose)
+		for i, r := range results {
+			if r.AccessibilityLabel != tt.want[i] {
+				t.Errorf("verbose %v: label %d = %q, want %q", tt.verbose, i, r.AccessibilityLabel, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	tests := []struct {
+		n, total int
+		want     string
+	}{
+		{1, 1, "first of one result"},
+		{9, 20, "ninth of twenty results"},
+		{3, 21, "result 3 of 21"},
+	}
+	for _, tt := range tests {
+		if got := position(tt.n, tt.total, true); got != tt.want {
+			t.Errorf("position(%d, %d) = %q, want %q", tt.n, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestSearchLabels(t *testing.T) {
+	var results []Result
+	for i := range 9 {
+		results = append(results, Result{ID: fmt.Sprint("apps:", i), Title: fmt.Sprint("App ", i), Type: "app", Score: float64(9 - i)})
+	}
+	e := NewEngine(&fake{id: "apps", results: results})
+	got, err := e.Search(context.Background(), "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "App 0, application, first of nine results"; got[0].AccessibilityLabel != want {
+		t.Errorf("label = %q, want %q", got[0].AccessibilityLabel, want)
+	}
+	e.SetTerseLabels(true)
+	got, _ = e.Search(context.Background(), "app")
+	if want := "App 8, application, 9 of 9"; got[8].AccessibilityLabel != want {
+		t.Errorf("terse label = %q, want %q", got[8].AccessibilityLabel, want)
+	}
+}
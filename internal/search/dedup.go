@@ -0,0 +1,65 @@
+package search
+
+// dedup merges results that share a DedupKey. The highest-scoring result of
+// each group is kept in its original position and gains the actions of the
+// others it does not already offer. The absorbed results are returned keyed
+// by the surviving result's ID and then by provider, so their actions can
+// still be run by the provider that produced them.
+func dedup(results []Result) ([]Result, map[string]map[string]Result) {
+	best := make(map[string]int) // dedup key -> index into results
+	for i, r := range results {
+		key := r.DedupKey()
+		if key == "" {
+			continue
+		}
+		if j, ok := best[key]; !ok || r.Score > results[j].Score {
+			best[key] = i
+		}
+	}
+
+	absorbed := make(map[string]map[string]Result)
+	merged := make(map[int][]Result)
+	for i, r := range results {
+		if j, ok := best[r.DedupKey()]; ok && j != i {
+			merged[j] = append(merged[j], r)
+		}
+	}
+
+	out := make([]Result, 0, len(results))
+	for i, r := range results {
+		key := r.DedupKey()
+		if j, ok := best[key]; ok && j != i {
+			continue
+		}
+		for _, dup := range merged[i] {
+			r.Actions = mergeActions(r.Actions, dup)
+			if absorbed[r.ID] == nil {
+				absorbed[r.ID] = make(map[string]Result)
+			}
+			if _, ok := absorbed[r.ID][dup.Provider]; !ok && dup.Provider != r.Provider {
+				absorbed[r.ID][dup.Provider] = dup
+			}
+		}
+		out = append(out, r)
+	}
+	return out, absorbed
+}
+
+func mergeActions(actions []Action, dup Result) []Action {
+	have := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		have[a.ID] = true
+	}
+	merged := append([]Action(nil), actions...)
+	for _, a := range dup.Actions {
+		if have[a.ID] {
+			continue
+		}
+		have[a.ID] = true
+		if a.Provider == "" {
+			a.Provider = dup.Provider
+		}
+		merged = append(merged, a)
+	}
+	return merged
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestDedupAcrossProviders(t *testing.T) {
+	apps := &fake{id: "apps", results: []Result{{
+		ID: "apps:/Applications/Mail.app", Title: "Mail", Target: "/Applications/Mail.app", Score: 5,
+		Actions: []Action{{ID: "open", Title: "Open"}, {ID: "quit", Title: "Quit"}},
+	}}}
+	files := &fake{id: "files", results: []Result{{
+		ID: "files:/Applications/Mail.app", Title: "Mail.app", Target: "/Applications/Mail.app/", Score: 3,
+		Actions: []Action{{ID: "open", Title: "Open"}, {ID: "reveal", Title: "Show in Folder"}},
+	}, {
+		ID: "files:/tmp/Mail", Title: "Mail", Score: 2,
+	}}}
+	e := NewEngine(apps, files)
+	results, err := e.Search(context.Background(), "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(results), []string{"apps:/Applications/Mail.app", "files:/tmp/Mail"}; !slices.Equal(got, want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+
+	var actions []string
+	for _, a := range results[0].Actions {
+		actions = append(actions, a.ID+"@"+a.Provider)
+	}
+	if want := []string{"open@", "quit@", "reveal@files"}; !slices.Equal(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+
+	// The merged-in action runs on the duplicate, through its provider.
+	if err := e.Activate(context.Background(), results[0].ID, "reveal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Activate(context.Background(), results[0].ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"files:/Applications/Mail.app reveal"}; !slices.Equal(files.activated, want) {
+		t.Errorf("files ran %v, want %v", files.activated, want)
+	}
+	if want := []string{"apps:/Applications/Mail.app open"}; !slices.Equal(apps.activated, want) {
+		t.Errorf("apps ran %v, want %v", apps.activated, want)
+	}
+}
@@ -0,0 +1,304 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Engine runs a query against every registered provider and merges their
+// results into a single list ordered by score, which is then passed through
+// a pipeline of transformers.
+type Engine struct {
+	mu        sync.Mutex
+	providers []Provider
+	prefixes  map[string]string
+	fallback  []string
+	rewrites  []Rewrite
+	empty     EmptyState
+	instant   InstantFilter
+	emptySet  []Result // the empty state last shown, for instant
+	sticky    []string
+	timeout   time.Duration
+	timeouts  map[string]time.Duration
+	gen       uint64 // incremented by every Stream
+	last      map[string]Result
+	absorbed  map[string]map[string]Result
+
+	transformers map[string]Transformer
+	pipeline     []string
+	terseLabels  bool
+	tiebreak     string
+
+	// prewarmID is the top result last handed to a Prewarmer.
+	prewarmID     string
+	prewarmCancel context.CancelFunc
+	prewarmSlots  chan struct{}
+	prewarmPolicy PrewarmPolicy
+}
+
+// NewEngine returns an engine that searches the given providers. The
+// built-in transformers are registered and form the DefaultPipeline.
+func NewEngine(providers ...Provider) *Engine {
+	return &Engine{
+		providers: providers,
+		timeout:   DefaultTimeout,
+		last:      make(map[string]Result),
+		transformers: map[string]Transformer{
+			TransformDedup:    Dedup,
+			TransformTruncate: Truncate(DefaultLimit, nil),
+		},
+		pipeline:     DefaultPipeline,
+		prewarmSlots: make(chan struct{}, maxPrewarms),
+	}
+}
+
+// Provider returns the registered provider with the given ID.
+func (e *Engine) Provider(id string) (Provider, bool) {
+	for _, p := range e.providers {
+		if p.ID() == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// SetEmptyState sets the source of results for a blank query.
+func (e *Engine) SetEmptyState(fn EmptyState) {
+	e.mu.Lock()
+	e.empty = fn
+	e.mu.Unlock()
+}
+
+// Resolve rebuilds the result with the given ID through the provider named by
+// the ID's prefix. It reports false for unknown providers, providers that
+// cannot resolve, and items that no longer exist.
+func (e *Engine) Resolve(ctx context.Context, id string) (Result, bool) {
+	providerID, _, ok := strings.Cut(id, ":")
+	if !ok {
+		return Result{}, false
+	}
+	p, ok := e.Provider(providerID)
+	if !ok {
+		return Result{}, false
+	}
+	rp, ok := p.(Resolver)
+	if !ok {
+		return Result{}, false
+	}
+	r, ok := rp.Resolve(ctx, id)
+	if !ok {
+		return Result{}, false
+	}
+	r.Provider = providerID
+	return r, true
+}
+
+// Recent collects up to limit history items from every RecentProvider.
+func (e *Engine) Recent(ctx context.Context, limit int) []Result {
+	var results []Result
+	for _, p := range e.providers {
+		if rp, ok := p.(RecentProvider); ok {
+			for _, r := range rp.Recent(ctx, limit) {
+				r.Provider = p.ID()
+				results = append(results, r)
+			}
+		}
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Status collects the ongoing activity of every StatusProvider.
+func (e *Engine) Status(ctx context.Context) []Result {
+	var results []Result
+	for _, p := range e.providers {
+		if sp, ok := p.(StatusProvider); ok {
+			for _, r := range sp.Status(ctx) {
+				r.Provider = p.ID()
+				results = append(results, r)
+			}
+		}
+	}
+	return results
+}
+
+// SetTimeouts sets how long providers may take before their results are
+// dropped for a query: def applies to every provider not listed in
+// perProvider, which is keyed by provider ID.
+func (e *Engine) SetTimeouts(def time.Duration, perProvider map[string]time.Duration) {
+	e.mu.Lock()
+	e.timeout, e.timeouts = def, perProvider
+	e.mu.Unlock()
+}
+
+func (e *Engine) timeoutFor(id string) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if d, ok := e.timeouts[id]; ok && d > 0 {
+		return d
+	}
+	return e.timeout
+}
+
+// SetTerseLabels shortens the accessibility labels of results to their
+// title, type and a numeric position, for screen reader users who find the
+// full labels slow.
+func (e *Engine) SetTerseLabels(terse bool) {
+	e.mu.Lock()
+	e.terseLabels = terse
+	e.mu.Unlock()
+}
+
+// label sets the accessibility labels of a final result list.
+func (e *Engine) label(results []Result) {
+	e.mu.Lock()
+	terse := e.terseLabels
+	e.mu.Unlock()
+	labelResults(results, !terse)
+}
+
+// SetPrefixes replaces the prefix routing table, which maps a query prefix to
+// the ID of the provider that handles it exclusively.
+func (e *Engine) SetPrefixes(prefixes map[string]string) {
+	e.mu.Lock()
+	e.prefixes = prefixes
+	e.mu.Unlock()
+}
+
+// route picks the providers for query. When query starts with a configured
+// prefix whose provider is registered, only that provider runs and it sees
+// the query with the prefix removed, and routed is set. The longest
+// matching prefix wins. Without a prefix every provider runs except
+// prefix-only ones.
+func (e *Engine) route(query string) (providers []Provider, routedQuery string, routed bool) {
+	e.mu.Lock()
+	prefixes := e.prefixes
+	e.mu.Unlock()
+
+	best := ""
+	for prefix := range prefixes {
+		if prefix != "" && len(prefix) > len(best) && strings.HasPrefix(query, prefix) {
+			if _, ok := e.Provider(prefixes[prefix]); ok {
+				best = prefix
+			}
+		}
+	}
+	if best == "" {
+		for _, p := range e.providers {
+			if po, ok := p.(PrefixOnlyProvider); !ok || !po.PrefixOnly() {
+				providers = append(providers, p)
+			}
+		}
+		return providers, query, false
+	}
+	p, _ := e.Provider(prefixes[best])
+	return []Provider{p}, strings.TrimSpace(strings.TrimPrefix(query, best)), true
+}
+
+// RoutedQuery returns query as the providers and transformers routed to
+// would see it, rewritten and with any provider prefix removed.
+func (e *Engine) RoutedQuery(query string) string {
+	_, q, _ := e.route(e.rewrite(query))
+	return q
+}
+
+// BeginSession notifies session-aware providers that a new search session has
+// started so they can drop cached state.
+func (e *Engine) BeginSession() {
+	for _, p := range e.providers {
+		if sp, ok := p.(SessionProvider); ok {
+			sp.BeginSession()
+		}
+	}
+}
+
+// Search queries all providers and waits for all of them, or for their
+// timeouts, returning the final merged results. See Stream.
+func (e *Engine) Search(ctx context.Context, query string) ([]Result, error) {
+	var results []Result
+	err := e.Stream(ctx, query, func(u Update) {
+		results = u.Results
+	})
+	return results, err
+}
+
+// remember makes results the set Activate looks IDs up in, unless a newer
+// search than gen has started since.
+func (e *Engine) remember(gen uint64, results []Result, absorbed map[string]map[string]Result) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if gen != e.gen {
+		return false
+	}
+	e.last = make(map[string]Result, len(results))
+	for _, r := range results {
+		e.last[r.ID] = r
+	}
+	e.absorbed = absorbed
+	return true
+}
+
+// Result returns the result with the given ID from the most recent search.
+func (e *Engine) Result(id string) (Result, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.last[id]
+	return r, ok
+}
+
+// Preview returns the result with the given ID from the last search with
+// its preview filled in by its provider. It reports false when the result
+// is unknown or its provider has no previews.
+func (e *Engine) Preview(ctx context.Context, id string) (Result, bool, error) {
+	r, ok := e.Result(id)
+	if !ok {
+		return Result{}, false, nil
+	}
+	p, ok := e.Provider(r.Provider)
+	if !ok {
+		return Result{}, false, nil
+	}
+	pv, ok := p.(Previewer)
+	if !ok {
+		return Result{}, false, nil
+	}
+	r, err := pv.Preview(ctx, r)
+	if err != nil {
+		return Result{}, false, err
+	}
+	return r, true, nil
+}
+
+// Activate runs actionID on the result with the given ID from the most recent
+// search. An empty actionID runs the result's default action. Actions merged
+// in from a duplicate run on the duplicate, through its own provider.
+func (e *Engine) Activate(ctx context.Context, resultID, actionID string) error {
+	e.mu.Lock()
+	r, ok := e.last[resultID]
+	absorbed := e.absorbed[resultID]
+	e.mu.Unlock()
+	if !ok {
+		return ErrUnknownResult
+	}
+	if actionID == "" {
+		actionID = r.DefaultAction()
+	}
+	for _, a := range r.Actions {
+		if a.ID == actionID && a.Provider != "" && a.Provider != r.Provider {
+			if dup, ok := absorbed[a.Provider]; ok {
+				r = dup
+			}
+			break
+		}
+	}
+	p, ok := e.Provider(r.Provider)
+	if !ok {
+		return fmt.Errorf("search: provider %q not registered", r.Provider)
+	}
+	return p.Activate(ctx, r, actionID)
+}
@@ -0,0 +1,176 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fake is a provider that returns a fixed set of results, after delay, and
+// records the queries it was asked and the actions it ran.
+type fake struct {
+	id         string
+	results    []Result
+	prefixOnly bool
+	delay      time.Duration
+
+	mu        sync.Mutex
+	queries   []string
+	activated []string
+}
+
+func (f *fake) ID() string       { return f.id }
+func (f *fake) PrefixOnly() bool { return f.prefixOnly }
+
+func (f *fake) Search(ctx context.Context, query string) ([]Result, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return slices.Clone(f.results), nil
+}
+
+func (f *fake) Activate(ctx context.Context, r Result, actionID string) error {
+	f.mu.Lock()
+	f.activated = append(f.activated, r.ID+" "+actionID)
+	f.mu.Unlock()
+	return nil
+}
+
+// asked returns the queries f was searched with.
+func (f *fake) asked() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.queries)
+}
+
+// result returns a result of provider id with the given key and score.
+func result(id, key string, score float64) Result {
+	return Result{ID: id + ":" + key, Title: key, Score: score}
+}
+
+// ids returns the IDs of results, in order.
+func ids(results []Result) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func TestRoute(t *testing.T) {
+	apps := &fake{id: "apps", results: []Result{result("apps", "mail", 1)}}
+	files := &fake{id: "files", results: []Result{result("files", "mail.txt", 1)}}
+	clip := &fake{id: "clipboard", results: []Result{result("clipboard", "mail", 1)}, prefixOnly: true}
+	calc := &fake{id: "calc"}
+	e := NewEngine(apps, files, clip, calc)
+	e.SetPrefixes(map[string]string{
+		"f ":  "files",
+		"cb":  "clipboard",
+		"cb!": "calc",
+		"x ":  "missing",
+	})
+
+	tests := []struct {
+		query     string
+		want      []string // the IDs of the providers that run
+		wantQuery string
+		routed    bool
+	}{
+		{"mail", []string{"apps", "files", "calc"}, "mail", false},
+		{"f mail", []string{"files"}, "mail", true},
+		{"cb mail", []string{"clipboard"}, "mail", true},
+		{"cb! 2+2", []string{"calc"}, "2+2", true},
+		{"x mail", []string{"apps", "files", "calc"}, "x mail", false},
+		{"fmail", []string{"apps", "files", "calc"}, "fmail", false},
+	}
+	for _, tt := range tests {
+		providers, q, routed := e.route(tt.query)
+		var got []string
+		for _, p := range providers {
+			got = append(got, p.ID())
+		}
+		if !slices.Equal(got, tt.want) || q != tt.wantQuery || routed != tt.routed {
+			t.Errorf("route(%q) = %v, %q, %v; want %v, %q, %v", tt.query, got, q, routed, tt.want, tt.wantQuery, tt.routed)
+		}
+	}
+}
+
+func TestSearchRouting(t *testing.T) {
+	newEngine := func() (*Engine, *fake, *fake, *fake) {
+		apps := &fake{id: "apps", results: []Result{result("apps", "mail", 2)}}
+		files := &fake{id: "files", results: []Result{result("files", "mail.txt", 1)}}
+		clip := &fake{id: "clipboard", results: []Result{result("clipboard", "mail", 3)}, prefixOnly: true}
+		e := NewEngine(apps, files, clip)
+		e.SetPrefixes(map[string]string{"f ": "files", "cb ": "clipboard"})
+		return e, apps, files, clip
+	}
+
+	t.Run("bare", func(t *testing.T) {
+		e, apps, files, clip := newEngine()
+		results, err := e.Search(context.Background(), "mail")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(results), []string{"apps:mail", "files:mail.txt"}; !slices.Equal(got, want) {
+			t.Errorf("results = %v, want %v", got, want)
+		}
+		if len(apps.asked()) != 1 || len(files.asked()) != 1 {
+			t.Errorf("apps asked %v, files asked %v; want both asked once", apps.asked(), files.asked())
+		}
+		if q := clip.asked(); len(q) != 0 {
+			t.Errorf("prefix-only clipboard asked %v", q)
+		}
+	})
+
+	t.Run("prefixed", func(t *testing.T) {
+		e, apps, files, clip := newEngine()
+		results, err := e.Search(context.Background(), "cb mail")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(results), []string{"clipboard:mail"}; !slices.Equal(got, want) {
+			t.Errorf("results = %v, want %v", got, want)
+		}
+		if got := clip.asked(); !slices.Equal(got, []string{"mail"}) {
+			t.Errorf("clipboard asked %v, want [mail]", got)
+		}
+		if len(apps.asked()) != 0 || len(files.asked()) != 0 {
+			t.Errorf("apps asked %v, files asked %v; want neither", apps.asked(), files.asked())
+		}
+	})
+
+	t.Run("routed context", func(t *testing.T) {
+		var routed []bool
+		p := &routedRecorder{fake: fake{id: "calc"}, routed: &routed}
+		e := NewEngine(p)
+		e.SetPrefixes(map[string]string{"=": "calc"})
+		for _, q := range []string{"=2+2", "2+2"} {
+			if _, err := e.Search(context.Background(), q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !slices.Equal(routed, []bool{true, false}) {
+			t.Errorf("Routed = %v, want [true false]", routed)
+		}
+	})
+}
+
+// routedRecorder records what Routed reports for each search.
+type routedRecorder struct {
+	fake
+	routed *[]bool
+}
+
+func (r *routedRecorder) Search(ctx context.Context, query string) ([]Result, error) {
+	*r.routed = append(*r.routed, Routed(ctx))
+	return nil, nil
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownResult is returned when activating a result ID that is not part
+// of the most recent result set.
+var ErrUnknownResult = errors.New("search: unknown result")
+
+// ErrSearchAgain is returned by Activate for actions that change what the
+// current query shows, such as regenerating a random value. The launcher
+// stays open and runs the query again.
+var ErrSearchAgain = errors.New("search: search again")
+
+// QueryError is returned by Activate for actions that hand a result over to
+// another provider by typing the query it answers, such as "mail to
+// bob@example.com". The launcher stays open with Query in it.
+type QueryError struct {
+	Query string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("search: query %q", e.Query)
+}
+
+// PermissionError reports that a provider needs an OS permission the user has
+// not granted, such as Automation or Accessibility access.
+type PermissionError struct {
+	Permission string // e.g. "Automation"
+	Target     string // the app or resource access was denied to, if any
+	Err        error
+}
+
+func (e *PermissionError) Error() string {
+	if e.Target == "" {
+		return fmt.Sprintf("%s permission required", e.Permission)
+	}
+	return fmt.Sprintf("%s permission required for %s", e.Permission, e.Target)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
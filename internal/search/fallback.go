@@ -0,0 +1,42 @@
+package search
+
+import "slices"
+
+// SetFallbackChain makes the providers with the given IDs fallback stages.
+// They no longer run with the rest for an unprefixed query; instead Stream
+// tries them one at a time, in order, while everything before has found
+// nothing, and stops at the first stage with results. A query routed by a
+// prefix runs only its provider, as before.
+func (e *Engine) SetFallbackChain(ids []string) {
+	e.mu.Lock()
+	e.fallback = slices.Clone(ids)
+	e.mu.Unlock()
+}
+
+// stages splits the providers of an unprefixed query into the stages
+// Stream runs in turn: those outside the fallback chain, then one stage per
+// registered chain provider. Stages without providers are left out.
+func (e *Engine) stages(providers []Provider) [][]Provider {
+	e.mu.Lock()
+	chain := e.fallback
+	e.mu.Unlock()
+	if len(chain) == 0 {
+		return [][]Provider{providers}
+	}
+	var first []Provider
+	for _, p := range providers {
+		if !slices.Contains(chain, p.ID()) {
+			first = append(first, p)
+		}
+	}
+	var stages [][]Provider
+	if len(first) > 0 {
+		stages = append(stages, first)
+	}
+	for _, id := range chain {
+		if p, ok := e.Provider(id); ok {
+			stages = append(stages, []Provider{p})
+		}
+	}
+	return stages
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFallbackChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		apps    []Result
+		web     []Result
+		want    []string
+		webRuns bool
+		ddgRuns bool
+	}{
+		{
+			name: "first stage matches",
+			apps: []Result{result("apps", "mail", 1)},
+			web:  []Result{result("web", "mail", 1)},
+			want: []string{"apps:mail"},
+		},
+		{
+			name:    "falls through to web",
+			web:     []Result{result("web", "mail", 1)},
+			want:    []string{"web:mail"},
+			webRuns: true,
+		},
+		{
+			name:    "falls through every stage",
+			want:    []string{"ddg:mail"},
+			webRuns: true,
+			ddgRuns: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apps := &fake{id: "apps", results: tt.apps}
+			web := &fake{id: "web", results: tt.web}
+			ddg := &fake{id: "ddg", results: []Result{result("ddg", "mail", 1)}}
+			e := NewEngine(ddg, web, apps)
+			e.SetFallbackChain([]string{"web", "missing", "ddg"})
+
+			var updates []Update
+			err := e.Stream(context.Background(), "mail", func(u Update) { updates = append(updates, u) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			last := updates[len(updates)-1]
+			if got := ids(last.Results); !last.Done || !slices.Equal(got, tt.want) {
+				t.Errorf("last update = %v (done %v), want %v", got, last.Done, tt.want)
+			}
+			for _, u := range updates {
+				if len(u.Results) == 0 && !u.Done {
+					t.Errorf("empty update before the last stage: %+v", u)
+				}
+			}
+			if len(apps.asked()) != 1 {
+				t.Errorf("apps asked %v, want once", apps.asked())
+			}
+			if got := len(web.asked()) > 0; got != tt.webRuns {
+				t.Errorf("web ran = %v, want %v", got, tt.webRuns)
+			}
+			if got := len(ddg.asked()) > 0; got != tt.ddgRuns {
+				t.Errorf("ddg ran = %v, want %v", got, tt.ddgRuns)
+			}
+		})
+	}
+}
+
+func TestFallbackChainRouted(t *testing.T) {
+	web := &fake{id: "web", results: []Result{result("web", "mail", 1)}}
+	e := NewEngine(&fake{id: "apps"}, web)
+	e.SetFallbackChain([]string{"web"})
+	e.SetPrefixes(map[string]string{"? ": "web"})
+	results, err := e.Search(context.Background(), "? mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(results); !slices.Equal(got, []string{"web:mail"}) {
+		t.Errorf("results = %v, want [web:mail]", got)
+	}
+}
@@ -0,0 +1,37 @@
+package search
+
+import "path/filepath"
+
+// FieldProvider is implemented by providers that know more about a result
+// than it carries, such as an app's bundle ID and version, for subtitle
+// templates. Fields returns them by name.
+type FieldProvider interface {
+	Provider
+	Fields(r Result) map[string]string
+}
+
+// Fields returns the named fields of r: its id, provider, type, title,
+// subtitle and target, path when the target is a file path, and whatever
+// its provider adds as a FieldProvider.
+func (e *Engine) Fields(r Result) map[string]string {
+	fields := map[string]string{
+		"id":       r.ID,
+		"provider": r.Provider,
+		"type":     r.Type,
+		"title":    r.Title,
+		"subtitle": r.Subtitle,
+		"target":   r.Target,
+		"path":     "",
+	}
+	if filepath.IsAbs(r.Target) {
+		fields["path"] = r.Target
+	}
+	if p, ok := e.Provider(r.Provider); ok {
+		if fp, ok := p.(FieldProvider); ok {
+			for k, v := range fp.Fields(r) {
+				fields[k] = v
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,56 @@
+package search
+
+import "slices"
+
+// InstantFilter reports whether r, shown in the empty state, matches query.
+type InstantFilter func(query string, r Result) bool
+
+// SetInstantFilter makes Stream answer the first keystrokes from the empty
+// state last shown: the results fn matches are sent in an update before any
+// provider is asked, and kept in the list until the providers' own results
+// replace them. A nil fn turns this off.
+func (e *Engine) SetInstantFilter(fn InstantFilter) {
+	e.mu.Lock()
+	e.instant = fn
+	e.mu.Unlock()
+}
+
+// keepEmptyState caches results as the empty state to filter instantly.
+func (e *Engine) keepEmptyState(results []Result) {
+	e.mu.Lock()
+	e.emptySet = results
+	e.mu.Unlock()
+}
+
+// instantMatches returns the cached empty state results matching query, or
+// nil when instant filtering is off.
+func (e *Engine) instantMatches(query string) []Result {
+	e.mu.Lock()
+	fn, set := e.instant, e.emptySet
+	e.mu.Unlock()
+	if fn == nil {
+		return nil
+	}
+	var matches []Result
+	for _, r := range set {
+		if fn(query, r) {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
+// withSeed adds to results the instant matches in seed that no provider
+// returned again, so those providers' fresher copies win.
+func withSeed(seed, results []Result) []Result {
+	if len(seed) == 0 {
+		return results
+	}
+	out := slices.Clone(results)
+	for _, s := range seed {
+		if !slices.ContainsFunc(results, func(r Result) bool { return r.ID == s.ID }) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
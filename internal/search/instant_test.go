@@ -0,0 +1,61 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstantFilter(t *testing.T) {
+	files := &fake{id: "files", results: []Result{result("files", "mail.txt", 1)}, delay: 50 * time.Millisecond}
+	e := NewEngine(files)
+	e.SetPrefixes(map[string]string{"f ": "files"})
+	e.SetEmptyState(func(ctx context.Context) []Result {
+		return []Result{result("apps", "mail", 3), result("apps", "maps", 2), result("apps", "notes", 1)}
+	})
+	e.SetInstantFilter(func(query string, r Result) bool {
+		return query != "" && strings.Contains(r.Title, query)
+	})
+	if _, err := e.Search(context.Background(), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		first []string // nil when the first update is the providers'
+		last  []string
+	}{
+		{"ma", []string{"apps:mail", "apps:maps"}, []string{"apps:mail", "apps:maps", "files:mail.txt"}},
+		{"zzz", nil, []string{"files:mail.txt"}},
+		// A routed query is scoped to its provider.
+		{"f ma", nil, []string{"files:mail.txt"}},
+	}
+	for _, tt := range tests {
+		var updates [][]string
+		before, askedFirst := len(files.asked()), 0
+		err := e.Stream(context.Background(), tt.query, func(u Update) {
+			if updates == nil {
+				askedFirst = len(files.asked())
+			}
+			updates = append(updates, ids(u.Results))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.first != nil {
+			if !slices.Equal(updates[0], tt.first) {
+				t.Errorf("Stream(%q) first update = %v, want %v", tt.query, updates[0], tt.first)
+			}
+			if askedFirst != before {
+				t.Errorf("Stream(%q): the provider had been asked before the first update", tt.query)
+			}
+		} else if len(updates) != 1 {
+			t.Errorf("Stream(%q) updates = %v, want only the providers'", tt.query, updates)
+		}
+		if got := updates[len(updates)-1]; !slices.Equal(got, tt.last) {
+			t.Errorf("Stream(%q) last update = %v, want %v", tt.query, got, tt.last)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// The tiebreaks SetTiebreak accepts. Results are ordered by score, highest
+// first; results with equal scores by the tiebreak; and results equal under
+// that by ID, so the same results are always listed in the same order,
+// whichever provider answered first.
+const (
+	// TiebreakShorter lists the result with the shorter title first, as
+	// the closer match, and titles of the same length alphabetically.
+	TiebreakShorter = "shorter"
+	// TiebreakAlphabetical lists results alphabetically by title.
+	TiebreakAlphabetical = "alphabetical"
+)
+
+// SetTiebreak sets how results with equal scores are ordered, one of the
+// Tiebreak* values; anything else is TiebreakShorter.
+func (e *Engine) SetTiebreak(tiebreak string) {
+	e.mu.Lock()
+	e.tiebreak = tiebreak
+	e.mu.Unlock()
+}
+
+// Compare orders a before b when it should be listed first: see the
+// Tiebreak* values for the order.
+func Compare(a, b Result, tiebreak string) int {
+	if c := cmp.Compare(b.Score, a.Score); c != 0 {
+		return c
+	}
+	if tiebreak != TiebreakAlphabetical {
+		if c := cmp.Compare(utf8.RuneCountInString(a.Title), utf8.RuneCountInString(b.Title)); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// Sort orders results in place as Compare does.
+func Sort(results []Result, tiebreak string) {
+	slices.SortStableFunc(results, func(a, b Result) int { return Compare(a, b, tiebreak) })
+}
+
+// Sort orders results in place as the engine merging them does, for
+// transformers that change scores.
+func (tc *TransformContext) Sort(results []Result) {
+	Sort(results, tc.tiebreak)
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortTiebreak(t *testing.T) {
+	tied := []Result{
+		{ID: "apps:b", Title: "Mailbox", Score: 5},
+		{ID: "apps:c", Title: "Mail", Score: 5},
+		{ID: "files:a", Title: "Mail", Score: 5},
+		{ID: "apps:d", Title: "Airmail", Score: 5},
+		{ID: "apps:e", Title: "Zmail", Score: 9},
+	}
+	tests := []struct {
+		tiebreak string
+		want     []string
+	}{
+		{TiebreakShorter, []string{"apps:e", "apps:c", "files:a", "apps:d", "apps:b"}},
+		{"", []string{"apps:e", "apps:c", "files:a", "apps:d", "apps:b"}},
+		{TiebreakAlphabetical, []string{"apps:e", "apps:d", "apps:c", "files:a", "apps:b"}},
+	}
+	for _, tt := range tests {
+		// Every starting order ends the same, so results do not depend on
+		// which provider answered first.
+		for i := range tied {
+			results := append(slices.Clone(tied[i:]), tied[:i]...)
+			Sort(results, tt.tiebreak)
+			if got := ids(results); !slices.Equal(got, tt.want) {
+				t.Errorf("Sort(rotation %d, %q) = %v, want %v", i, tt.tiebreak, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestStreamTiebreak(t *testing.T) {
+	// The slower provider's result arrives last but is listed first.
+	slow := &fake{id: "slow", results: []Result{{ID: "slow:a", Title: "Mail", Score: 3}}, delay: 20 * time.Millisecond}
+	fast := &fake{id: "fast", results: []Result{{ID: "fast:a", Title: "Mailbox", Score: 3}}}
+	e := NewEngine(slow, fast)
+	e.SetTiebreak(TiebreakShorter)
+	for range 3 {
+		results, err := e.Search(context.Background(), "mail")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(results), []string{"slow:a", "fast:a"}; !slices.Equal(got, want) {
+			t.Fatalf("results = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package search
+
+import "context"
+
+// maxPrewarms bounds the prewarms running at once unless a PrewarmPolicy
+// sets another bound. A prewarm that would exceed it is skipped; the result
+// is still activated normally.
+const maxPrewarms = 2
+
+// PowerState reports how the machine is powered.
+type PowerState interface {
+	// OnBattery reports whether the machine is running on battery. It is
+	// called on the search path, so it must not block.
+	OnBattery() bool
+}
+
+// PrewarmPolicy is the budget for prewarming, the engine's speculative
+// work. The zero policy prewarms with up to maxPrewarms at once.
+type PrewarmPolicy struct {
+	// Disabled turns prewarming off.
+	Disabled bool
+	// MaxConcurrent bounds the prewarms running at once; zero means
+	// maxPrewarms.
+	MaxConcurrent int
+	// PauseOnBattery skips prewarms while Power reports running on
+	// battery.
+	PauseOnBattery bool
+	Power          PowerState
+}
+
+// allows reports whether the policy lets a prewarm start now.
+func (p PrewarmPolicy) allows() bool {
+	if p.Disabled {
+		return false
+	}
+	return !p.PauseOnBattery || p.Power == nil || !p.Power.OnBattery()
+}
+
+// SetPrewarmPolicy replaces the prewarm budget. Prewarms already running
+// finish under the old bound.
+func (e *Engine) SetPrewarmPolicy(p PrewarmPolicy) {
+	n := p.MaxConcurrent
+	if n <= 0 {
+		n = maxPrewarms
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.prewarmPolicy = p
+	if cap(e.prewarmSlots) != n {
+		e.prewarmSlots = make(chan struct{}, n)
+	}
+}
+
+// Prewarmer is implemented by providers with work to do before a result is
+// activated, such as reading a file's metadata from a slow volume. The
+// engine calls Prewarm on the top result as soon as it becomes the top
+// result, off the search path, so pressing enter does not wait for it.
+// ctx is cancelled once another result takes the top spot.
+type Prewarmer interface {
+	Provider
+	Prewarm(ctx context.Context, r Result)
+}
+
+// prewarm starts prewarming the first of results, cancelling the prewarm of
+// the previous top result, unless it is already the one being prewarmed.
+func (e *Engine) prewarm(results []Result) {
+	var top Result
+	if len(results) > 0 {
+		top = results[0]
+	}
+	e.mu.Lock()
+	if top.ID == e.prewarmID {
+		e.mu.Unlock()
+		return
+	}
+	if e.prewarmCancel != nil {
+		e.prewarmCancel()
+	}
+	e.prewarmID, e.prewarmCancel = top.ID, nil
+	if top.ID == "" {
+		e.mu.Unlock()
+		return
+	}
+	p, ok := e.Provider(top.Provider)
+	pw, isPrewarmer := p.(Prewarmer)
+	if !ok || !isPrewarmer || !e.prewarmPolicy.allows() {
+		e.mu.Unlock()
+		return
+	}
+	slots := e.prewarmSlots
+	select {
+	case slots <- struct{}{}:
+	default:
+		e.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.prewarmCancel = cancel
+	e.mu.Unlock()
+
+	go func() {
+		defer func() { <-slots }()
+		defer cancel()
+		pw.Prewarm(ctx, top)
+	}()
+}
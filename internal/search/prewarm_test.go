@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// warmer finds the one result named by the query and reports the prewarms
+// started and cancelled. A prewarm runs until it is cancelled.
+type warmer struct {
+	fake
+	started, cancelled chan string
+}
+
+func newWarmer() *warmer {
+	return &warmer{fake: fake{id: "files"}, started: make(chan string, 10), cancelled: make(chan string, 10)}
+}
+
+func (w *warmer) Search(ctx context.Context, query string) ([]Result, error) {
+	return []Result{result("files", query, 1)}, nil
+}
+
+func (w *warmer) Prewarm(ctx context.Context, r Result) {
+	w.started <- r.ID
+	<-ctx.Done()
+	w.cancelled <- r.ID
+}
+
+// next returns the next ID sent on ch, or "" if none comes soon.
+func next(ch chan string) string {
+	select {
+	case id := <-ch:
+		return id
+	case <-time.After(time.Second):
+		return ""
+	}
+}
+
+// none reports whether nothing is sent on ch for a short while.
+func none(ch chan string) bool {
+	select {
+	case <-ch:
+		return false
+	case <-time.After(20 * time.Millisecond):
+		return true
+	}
+}
+
+func TestPrewarmCancelledOnNewTop(t *testing.T) {
+	w := newWarmer()
+	e := NewEngine(w)
+	run := func(q string) {
+		if _, err := e.Search(context.Background(), q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("a")
+	if got := next(w.started); got != "files:a" {
+		t.Fatalf("prewarm started for %q, want files:a", got)
+	}
+	// The same top result is not prewarmed twice.
+	run("a")
+	if !none(w.started) {
+		t.Error("the same top result was prewarmed again")
+	}
+	if !none(w.cancelled) {
+		t.Error("prewarm cancelled while its result is still on top")
+	}
+
+	run("b")
+	if got := next(w.cancelled); got != "files:a" {
+		t.Errorf("cancelled %q, want files:a", got)
+	}
+	if got := next(w.started); got != "files:b" {
+		t.Errorf("prewarm started for %q, want files:b", got)
+	}
+}
+
+// battery reports the machine as on battery.
+type battery struct{}
+
+func (battery) OnBattery() bool { return true }
+
+func TestPrewarmPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy PrewarmPolicy
+		want   bool
+	}{
+		{"default", PrewarmPolicy{}, true},
+		{"disabled", PrewarmPolicy{Disabled: true}, false},
+		{"on battery", PrewarmPolicy{PauseOnBattery: true, Power: battery{}}, false},
+		{"on battery, not paused", PrewarmPolicy{Power: battery{}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWarmer()
+			e := NewEngine(w)
+			e.SetPrewarmPolicy(tt.policy)
+			e.Search(context.Background(), "a")
+			if started := !none(w.started); started != tt.want {
+				t.Errorf("prewarm started = %v, want %v", started, tt.want)
+			}
+		})
+	}
+}
+
+// stuck is a Prewarmer whose prewarms ignore cancellation until released.
+type stuck struct {
+	*warmer
+	release chan struct{}
+}
+
+func (s stuck) Prewarm(ctx context.Context, r Result) {
+	s.started <- r.ID
+	<-s.release
+}
+
+func TestPrewarmBounded(t *testing.T) {
+	s := stuck{warmer: newWarmer(), release: make(chan struct{})}
+	defer close(s.release)
+	e := NewEngine(s)
+	e.SetPrewarmPolicy(PrewarmPolicy{MaxConcurrent: 1})
+	e.Search(context.Background(), "a")
+	if got := next(s.started); got != "files:a" {
+		t.Fatalf("prewarm started for %q, want files:a", got)
+	}
+	// The first prewarm still holds the only slot, so b is skipped.
+	e.Search(context.Background(), "b")
+	if !none(s.started) {
+		t.Error("a second prewarm started beyond MaxConcurrent")
+	}
+}
@@ -0,0 +1,77 @@
+package search
+
+import "context"
+
+// Provider produces results for a query and knows how to act on them.
+type Provider interface {
+	// ID is a short stable identifier such as "finder" or "apps".
+	ID() string
+	// Search returns the provider's matches for query. An empty slice and a
+	// nil error means nothing matched.
+	Search(ctx context.Context, query string) ([]Result, error)
+	// Activate runs actionID on a result previously returned by Search.
+	Activate(ctx context.Context, result Result, actionID string) error
+}
+
+// routedKey marks the context of a search routed to its provider by a
+// prefix; see Routed.
+type routedKey struct{}
+
+// Routed reports whether the search ctx belongs to was routed to the
+// provider by one of its prefixes, which the provider's query no longer
+// starts with. Providers use it to tell an explicit request, such as
+// "=pi", from a query they merely match.
+func Routed(ctx context.Context) bool {
+	routed, _ := ctx.Value(routedKey{}).(bool)
+	return routed
+}
+
+// SessionProvider is implemented by providers that cache expensive state for
+// the lifetime of a search session; a session begins each time the launcher
+// window is shown.
+type SessionProvider interface {
+	Provider
+	BeginSession()
+}
+
+// PrefixOnlyProvider is implemented by providers that should only run when a
+// query prefix routes to them, such as clipboard history, whose items would
+// otherwise crowd out every query.
+type PrefixOnlyProvider interface {
+	Provider
+	PrefixOnly() bool
+}
+
+// Resolver is implemented by providers that can rebuild a result from its ID
+// alone, which lets favorites and frecency show results without a query. It
+// reports false when the item no longer exists.
+type Resolver interface {
+	Provider
+	Resolve(ctx context.Context, id string) (Result, bool)
+}
+
+// Previewer is implemented by providers that can describe a result in more
+// detail than a search has time for, such as a file's size and modification
+// time. Preview returns r with its Preview filled in, and may also refine
+// its Subtitle.
+type Previewer interface {
+	Provider
+	Preview(ctx context.Context, r Result) (Result, error)
+}
+
+// RecentProvider is implemented by providers with a history of items, such as
+// the clipboard, that can be listed newest first.
+type RecentProvider interface {
+	Provider
+	Recent(ctx context.Context, limit int) []Result
+}
+
+// StatusProvider is implemented by providers with ongoing activity, such as
+// running timers, that is listed ahead of everything else in the empty state.
+type StatusProvider interface {
+	Provider
+	Status(ctx context.Context) []Result
+}
+
+// EmptyState supplies the results shown while the query is blank.
+type EmptyState func(ctx context.Context) []Result
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Action is something the user can do with a result. The first action of a
+// result is its default and runs when the user presses enter.
+type Action struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Provider is set on actions merged in from a duplicate result of
+	// another provider, which is the one that runs them.
+	Provider string `json:"provider,omitempty"`
+	// Shortcut is the configured key binding that runs the action.
+	Shortcut string `json:"shortcut,omitempty"`
+	// HideBeforeRun hides the launcher before the action runs, so the app
+	// behind it has focus, as pasting into it or choosing its menu items
+	// needs.
+	HideBeforeRun bool `json:"hideBeforeRun,omitempty"`
+}
+
+// Result is a single row in the result list.
+type Result struct {
+	// ID is "<provider>:<key>", unique within the provider and stable across
+	// searches so it can be stored in favorites and frecency.
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+	// Target is the canonical thing the result refers to: a file or bundle
+	// path, or a URL. Results with the same target are merged.
+	Target  string   `json:"target,omitempty"`
+	Score   float64  `json:"score"`
+	Actions []Action `json:"actions,omitempty"`
+	// Preview is extra detail fetched for the selected result; see
+	// Previewer.
+	Preview string `json:"preview,omitempty"`
+	// Swatch is a CSS color the UI shows as a preview, for color results.
+	Swatch string `json:"swatch,omitempty"`
+	// Thumbnail is a data URI of a small preview the UI shows in place of
+	// the icon, fetched with Preview for image files.
+	Thumbnail string `json:"thumbnail,omitempty"`
+	// Favorite is set on results the user has added to their favorites.
+	Favorite bool `json:"favorite,omitempty"`
+	// Sticky is set on results listed at the top of every search; see
+	// Engine.SetSticky.
+	Sticky bool `json:"sticky,omitempty"`
+	// Hint is a short rendering of the action shortcuts, e.g.
+	// "↵ Open  ⌘↵ Show in Folder", when action hints are enabled.
+	Hint string `json:"hint,omitempty"`
+	// AccessibilityLabel is what a screen reader announces for the row,
+	// including its position in the list.
+	AccessibilityLabel string `json:"accessibilityLabel,omitempty"`
+}
+
+// DefaultAction returns the ID of the action that runs on enter, or "" when
+// the result has no actions.
+func (r Result) DefaultAction() string {
+	if len(r.Actions) == 0 {
+		return ""
+	}
+	return r.Actions[0].ID
+}
+
+// DedupKey identifies what the result refers to independently of the
+// provider that produced it. Results without a target are never merged, so
+// two items that merely share a name stay distinct.
+func (r Result) DedupKey() string {
+	t := r.Target
+	if t == "" {
+		return ""
+	}
+	if u, err := url.Parse(t); err == nil && u.Scheme != "" && u.Host != "" {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		return strings.TrimSuffix(u.String(), "/")
+	}
+	return filepath.Clean(t)
+}
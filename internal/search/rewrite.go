@@ -0,0 +1,43 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rewrite is a rule that transforms queries before they are searched:
+// every match of Pattern is replaced with Replacement, in which $1 or
+// ${name} stand for submatches as in regexp.Regexp.ReplaceAllString.
+type Rewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// CompileRewrite compiles a rewrite rule from its pattern source.
+func CompileRewrite(pattern, replacement string) (Rewrite, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rewrite{}, fmt.Errorf("search: rewrite pattern %q: %w", pattern, err)
+	}
+	return Rewrite{Pattern: re, Replacement: replacement}, nil
+}
+
+// SetRewrites replaces the rewrite rules applied, in order, to every query
+// before it is routed and searched.
+func (e *Engine) SetRewrites(rules []Rewrite) {
+	e.mu.Lock()
+	e.rewrites = rules
+	e.mu.Unlock()
+}
+
+// rewrite applies the rewrite rules to query in order, each to the result
+// of the one before.
+func (e *Engine) rewrite(query string) string {
+	e.mu.Lock()
+	rules := e.rewrites
+	e.mu.Unlock()
+	for _, r := range rules {
+		query = r.Pattern.ReplaceAllString(query, r.Replacement)
+	}
+	return query
+}
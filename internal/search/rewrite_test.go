@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	rules := []struct{ pattern, replacement string }{
+		{`^g (.*)$`, "web $1"},
+		{`^web (.*)$`, "? $1"},
+		{`\s+`, " "},
+	}
+	var compiled []Rewrite
+	for _, r := range rules {
+		rw, err := CompileRewrite(r.pattern, r.replacement)
+		if err != nil {
+			t.Fatalf("CompileRewrite(%q): %v", r.pattern, err)
+		}
+		compiled = append(compiled, rw)
+	}
+	e := NewEngine()
+	e.SetRewrites(compiled)
+
+	tests := []struct{ query, want string }{
+		// Each rule sees the output of the one before.
+		{"g  cats", "? cats"},
+		{"web dogs", "? dogs"},
+		{"plain   query", "plain query"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := e.rewrite(tt.query); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteBeforeRouting(t *testing.T) {
+	web := &fake{id: "web"}
+	e := NewEngine(&fake{id: "apps"}, web)
+	e.SetPrefixes(map[string]string{"? ": "web"})
+	rw, err := CompileRewrite(`^g\s+`, "? ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetRewrites([]Rewrite{rw})
+	if _, err := e.Search(context.Background(), "g cats"); err != nil {
+		t.Fatal(err)
+	}
+	if got := web.asked(); !slices.Equal(got, []string{"cats"}) {
+		t.Errorf("web asked %v, want [cats]", got)
+	}
+}
+
+func TestCompileRewriteInvalid(t *testing.T) {
+	for _, pattern := range []string{`(`, `[a-`, `*x`} {
+		if _, err := CompileRewrite(pattern, ""); err == nil {
+			t.Errorf("CompileRewrite(%q) succeeded, want an error", pattern)
+		}
+	}
+}
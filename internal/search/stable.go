@@ -0,0 +1,84 @@
+package search
+
+import "math"
+
+// StableScoreDelta is how far a result's score must move before Stabilize
+// lets it change position.
+const StableScoreDelta = 24
+
+// Stabilize reorders next, the latest merged results of a streaming search,
+// so that results already shown in prev keep their relative order and stay
+// above results that arrived later. That way a slow provider finishing
+// never pushes the row under the user's cursor down. Results whose score
+// moved by more than delta, and new results, follow in next's order. Results
+// missing from next are dropped.
+func Stabilize(prev, next []Result, delta float64) []Result {
+	if len(prev) == 0 {
+		return next
+	}
+	byID := make(map[string]Result, len(next))
+	for _, r := range next {
+		byID[r.ID] = r
+	}
+	out := make([]Result, 0, len(next))
+	kept := make(map[string]bool, len(prev))
+	for _, old := range prev {
+		r, ok := byID[old.ID]
+		if !ok || math.Abs(r.Score-old.Score) > delta {
+			continue
+		}
+		out = append(out, r)
+		kept[r.ID] = true
+	}
+	for _, r := range next {
+		if !kept[r.ID] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// The kinds of change from one list of results to the next, as Change
+// reports them, so the UI can animate each its own way.
+const (
+	// ChangeAppend keeps every result already shown in place and adds
+	// results after them, as when a slower provider finishes.
+	ChangeAppend = "append"
+	// ChangeRefine keeps some of the results shown, in the same order, and
+	// adds none, as when typing narrows a query.
+	ChangeRefine = "refine"
+	// ChangeReplace is any other change.
+	ChangeReplace = "replace"
+)
+
+// Change reports how next differs from prev, comparing result IDs. Going
+// from no results to some replaces them, and a list identical to prev
+// counts as an append of nothing.
+func Change(prev, next []Result) string {
+	if len(prev) == 0 {
+		if len(next) == 0 {
+			return ChangeAppend
+		}
+		return ChangeReplace
+	}
+	if len(next) >= len(prev) {
+		for i, r := range prev {
+			if next[i].ID != r.ID {
+				return ChangeReplace
+			}
+		}
+		return ChangeAppend
+	}
+	// Every result in next must appear in prev, in the same order.
+	i := 0
+	for _, r := range next {
+		for i < len(prev) && prev[i].ID != r.ID {
+			i++
+		}
+		if i == len(prev) {
+			return ChangeReplace
+		}
+		i++
+	}
+	return ChangeRefine
+}
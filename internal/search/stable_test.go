@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStabilizeStaggered(t *testing.T) {
+	// The slow provider's results outscore what is already shown.
+	fast := &fake{id: "fast", results: []Result{result("fast", "a", 50), result("fast", "b", 40)}}
+	mid := &fake{id: "mid", results: []Result{result("mid", "c", 90)}, delay: 20 * time.Millisecond}
+	slow := &fake{id: "slow", results: []Result{result("slow", "d", 100), result("fast", "b", 41)}, delay: 40 * time.Millisecond}
+	e := NewEngine(fast, mid, slow)
+
+	var shown []Result
+	var lists [][]string
+	const selected = 1 // the user has moved to the second row
+	var selectedID string
+	err := e.Stream(context.Background(), "q", func(u Update) {
+		shown = Stabilize(shown, u.Results, StableScoreDelta)
+		lists = append(lists, ids(shown))
+		if selectedID == "" && len(shown) > selected {
+			selectedID = shown[selected].ID
+		}
+		if selectedID != "" && (len(shown) <= selected || shown[selected].ID != selectedID) {
+			t.Errorf("selected row moved: %v", ids(shown))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"fast:a", "fast:b"},
+		{"fast:a", "fast:b", "mid:c"},
+		{"fast:a", "fast:b", "mid:c", "slow:d"},
+	}
+	if !slices.EqualFunc(lists, want, slices.Equal) {
+		t.Errorf("lists = %v, want %v", lists, want)
+	}
+}
+
+func TestStabilize(t *testing.T) {
+	prev := []Result{result("p", "a", 10), result("p", "b", 5), result("p", "c", 1)}
+	tests := []struct {
+		name string
+		next []Result
+		want []string
+	}{
+		{
+			name: "new results and small score changes keep the order",
+			next: []Result{result("p", "x", 99), result("p", "c", 20), result("p", "a", 10), result("p", "b", 5)},
+			want: []string{"p:a", "p:b", "p:c", "p:x"},
+		},
+		{
+			name: "a large score change lets a result move",
+			next: []Result{result("p", "a", 100), result("p", "c", 6), result("p", "b", 5)},
+			want: []string{"p:b", "p:c", "p:a"},
+		},
+		{
+			name: "missing results are dropped",
+			next: []Result{result("p", "c", 1), result("p", "a", 10)},
+			want: []string{"p:a", "p:c"},
+		},
+	}
+	for _, tt := range tests {
+		if got := ids(Stabilize(prev, tt.next, StableScoreDelta)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Stabilize = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := ids(Stabilize(nil, prev, StableScoreDelta)); !slices.Equal(got, ids(prev)) {
+		t.Errorf("Stabilize with nothing shown = %v, want %v", got, ids(prev))
+	}
+}
+
+func TestChange(t *testing.T) {
+	list := func(keys ...string) []Result {
+		var out []Result
+		for _, k := range keys {
+			out = append(out, result("apps", k, 1))
+		}
+		return out
+	}
+	tests := []struct {
+		prev, next []Result
+		want       string
+	}{
+		{nil, nil, ChangeAppend},
+		{nil, list("a"), ChangeReplace},
+		{list("a", "b"), list("a", "b"), ChangeAppend},
+		// A slower provider adds results below those shown.
+		{list("a", "b"), list("a", "b", "c"), ChangeAppend},
+		// Typing more drops some.
+		{list("a", "b", "c"), list("a", "c"), ChangeRefine},
+		{list("a", "b"), nil, ChangeRefine},
+		{list("a", "b"), list("b", "a"), ChangeReplace},
+		{list("a", "b"), list("c", "a", "b"), ChangeReplace},
+		{list("a", "b", "c"), list("c", "a"), ChangeReplace},
+		{list("a", "b", "c"), list("a", "d"), ChangeReplace},
+	}
+	for _, tt := range tests {
+		if got := Change(tt.prev, tt.next); got != tt.want {
+			t.Errorf("Change(%v, %v) = %s, want %s", ids(tt.prev), ids(tt.next), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"context"
+	"slices"
+)
+
+// SetSticky sets the IDs of results listed at the top of every search, in
+// order, whatever the query. IDs are resolved as favorites are; those that
+// no longer resolve are left out.
+func (e *Engine) SetSticky(ids []string) {
+	e.mu.Lock()
+	e.sticky = ids
+	e.mu.Unlock()
+}
+
+// stickyResults resolves the sticky IDs for one search.
+func (e *Engine) stickyResults(ctx context.Context) []Result {
+	e.mu.Lock()
+	ids := e.sticky
+	e.mu.Unlock()
+	var results []Result
+	for _, id := range ids {
+		if r, ok := e.Resolve(ctx, id); ok {
+			r.Sticky = true
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// pin puts the sticky results ahead of results, leaving out those already
+// among them so a real match is not listed twice.
+func pin(sticky, results []Result) []Result {
+	if len(sticky) == 0 {
+		return results
+	}
+	out := make([]Result, 0, len(sticky)+len(results))
+	for _, s := range sticky {
+		if !slices.ContainsFunc(results, func(r Result) bool { return r.ID == s.ID }) {
+			out = append(out, s)
+		}
+	}
+	return append(out, results...)
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// resolving is a fake that also resolves the IDs of its results.
+type resolving struct{ *fake }
+
+func (r resolving) Resolve(ctx context.Context, id string) (Result, bool) {
+	for _, res := range r.results {
+		if res.ID == id {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+func TestSticky(t *testing.T) {
+	apps := resolving{&fake{id: "apps", results: []Result{result("apps", "mail", 2), result("apps", "notes", 1)}}}
+	actions := resolving{&fake{id: "actions", results: []Result{result("actions", "new-meeting", 0)}, prefixOnly: true}}
+	e := NewEngine(apps, actions)
+	e.SetEmptyState(func(ctx context.Context) []Result {
+		return []Result{result("apps", "notes", 1)}
+	})
+	e.SetSticky([]string{"actions:new-meeting", "actions:gone", "apps:mail"})
+
+	tests := []struct {
+		query  string
+		want   []string
+		sticky int // how many lead the results as sticky
+	}{
+		// apps:mail is a real match, so it keeps its ranked place.
+		{"anything at all", []string{"actions:new-meeting", "apps:mail", "apps:notes"}, 1},
+		{"", []string{"actions:new-meeting", "apps:mail", "apps:notes"}, 2},
+	}
+	for _, tt := range tests {
+		got, err := e.Search(context.Background(), tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, ids(got), tt.want)
+		}
+		for i, r := range got {
+			if r.Sticky != (i < tt.sticky) {
+				t.Errorf("Search(%q): %s sticky = %v, want %v", tt.query, r.ID, r.Sticky, i < tt.sticky)
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is how long a provider may take by default before its
+// results are dropped for the query.
+const DefaultTimeout = 800 * time.Millisecond
+
+// Update is a snapshot of a search in progress.
+type Update struct {
+	// Results is the merged list of everything received so far.
+	Results []Result
+	// Done is set on the last update, once every provider has finished or
+	// timed out.
+	Done bool
+	// Total is how many results matched, before the truncate transformer
+	// cut them down to Results.
+	Total int
+}
+
+type outcome struct {
+	provider Provider
+	results  []Result
+	err      error
+}
+
+// Stream runs the providers selected for query concurrently and calls update
+// with the merged results each time one of them finishes, so fast providers
+// are shown without waiting for slow ones. Fallback stages, see
+// SetFallbackChain, run afterwards only while nothing has matched. A provider
+// that exceeds its timeout is dropped for this query. update is called from a
+// single goroutine and always ends with a Done update, unless ctx is
+// cancelled or a newer search starts, in which case updates stop. A blank
+// query yields the empty state in one update. The query is rewritten first;
+// see SetRewrites. With an InstantFilter set, an unrouted query first gets an
+// update of the matching empty state results, before any provider runs.
+// Sticky results, see SetSticky, head every update.
+//
+// Provider errors do not stop the search; they are joined into the returned
+// error.
+func (e *Engine) Stream(ctx context.Context, query string, update func(Update)) error {
+	query = e.rewrite(query)
+	e.mu.Lock()
+	e.gen++
+	gen := e.gen
+	empty := e.empty
+	e.mu.Unlock()
+
+	sticky := e.stickyResults(ctx)
+	if empty != nil && strings.TrimSpace(query) == "" {
+		results := slices.Clone(empty(ctx))
+		e.keepEmptyState(results)
+		results = pin(sticky, results)
+		e.label(results)
+		if e.remember(gen, results, nil) {
+			e.prewarm(results)
+			update(Update{Results: results, Done: true, Total: len(results)})
+		}
+		return nil
+	}
+
+	providers, query, routed := e.route(query)
+	ctx = context.WithValue(ctx, routedKey{}, routed)
+	stages := [][]Provider{providers}
+	var seed []Result
+	if !routed {
+		stages = e.stages(providers)
+		if seed = e.instantMatches(query); len(seed) > 0 {
+			first := pin(sticky, slices.Clone(seed))
+			e.label(first)
+			if !e.remember(gen, first, nil) {
+				return nil
+			}
+			update(Update{Results: first, Total: len(first)})
+		}
+	}
+	var errs []error
+	for i, stage := range stages {
+		if i > 0 {
+			seed = nil
+		}
+		found, ok := e.runStage(ctx, gen, query, stage, seed, sticky, i == len(stages)-1, update, &errs)
+		if !ok {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		if found {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runStage runs one stage of Stream: its providers concurrently, with an
+// update as each finishes. The results in seed are merged into every update
+// unless a provider returns them itself, and the sticky results are put ahead
+// of everything. The final update is marked Done when the stage found results
+// or is the last; otherwise it is withheld so the next stage can follow.
+// Provider errors are added to errs. ok is false once ctx is cancelled or a
+// newer search starts.
+func (e *Engine) runStage(ctx context.Context, gen uint64, query string, providers []Provider, seed, sticky []Result, last bool, update func(Update), errs *[]error) (found, ok bool) {
+	ch := make(chan outcome, len(providers))
+	for _, p := range providers {
+		go e.run(ctx, p, query, ch)
+	}
+
+	var results []Result
+	for remaining := len(providers); remaining > 0; remaining-- {
+		var o outcome
+		select {
+		case o = <-ch:
+		case <-ctx.Done():
+			return false, false
+		}
+		switch {
+		case errors.Is(o.err, context.DeadlineExceeded):
+			log.Printf("search: %s timed out for %q", o.provider.ID(), query)
+		case o.err != nil:
+			*errs = append(*errs, fmt.Errorf("%s: %w", o.provider.ID(), o.err))
+		default:
+			results = append(results, o.results...)
+		}
+		done := remaining == 1
+		if o.err != nil && !done {
+			continue
+		}
+		merged, absorbed, total := e.merge(query, withSeed(seed, results))
+		if done && len(merged) == 0 && !last {
+			return false, true
+		}
+		found := len(merged) > 0
+		shown := len(merged)
+		merged = pin(sticky, merged)
+		total += len(merged) - shown
+		e.label(merged)
+		if !e.remember(gen, merged, absorbed) {
+			return false, false
+		}
+		e.prewarm(merged)
+		update(Update{Results: merged, Done: done, Total: total})
+		if done {
+			return found, true
+		}
+	}
+	if len(providers) == 0 {
+		merged, absorbed, total := e.merge(query, seed)
+		found := len(merged) > 0
+		shown := len(merged)
+		merged = pin(sticky, merged)
+		total += len(merged) - shown
+		e.label(merged)
+		if e.remember(gen, merged, absorbed) {
+			update(Update{Results: merged, Done: true, Total: total})
+		}
+		return found, true
+	}
+	return false, true
+}
+
+// run searches a single provider under its timeout and sends the outcome to
+// ch. A provider that ignores its context is abandoned once the timeout
+// passes.
+func (e *Engine) run(ctx context.Context, p Provider, query string, ch chan<- outcome) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeoutFor(p.ID()))
+	defer cancel()
+
+	done := make(chan outcome, 1)
+	go func() {
+		// A provider that panics, such as one running a broken plugin,
+		// fails only its own part of the search.
+		defer func() {
+			if v := recover(); v != nil {
+				done <- outcome{provider: p, err: fmt.Errorf("search: provider %q panicked: %v", p.ID(), v)}
+			}
+		}()
+		rs, err := p.Search(ctx, query)
+		for i := range rs {
+			rs[i].Provider = p.ID()
+		}
+		done <- outcome{provider: p, results: rs, err: err}
+	}()
+	select {
+	case o := <-done:
+		ch <- o
+	case <-ctx.Done():
+		ch <- outcome{provider: p, err: ctx.Err()}
+	}
+}
+
+// merge orders results by score, breaking ties as Compare does, and runs the
+// transformer pipeline over them, counting how many there were before
+// truncation; see transform.
+func (e *Engine) merge(query string, results []Result) ([]Result, map[string]map[string]Result, int) {
+	sorted := append([]Result(nil), results...)
+	e.mu.Lock()
+	tiebreak := e.tiebreak
+	e.mu.Unlock()
+	Sort(sorted, tiebreak)
+	return e.transform(query, sorted)
+}
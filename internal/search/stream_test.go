@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamSlowProvider(t *testing.T) {
+	fast := &fake{id: "fast", results: []Result{result("fast", "a", 1)}}
+	slow := &fake{id: "slow", results: []Result{result("slow", "b", 2)}, delay: 200 * time.Millisecond}
+	e := NewEngine(slow, fast)
+
+	start := time.Now()
+	var first time.Duration
+	var updates [][]string
+	err := e.Stream(context.Background(), "q", func(u Update) {
+		if updates == nil {
+			first = time.Since(start)
+		}
+		updates = append(updates, ids(u.Results))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first >= 100*time.Millisecond {
+		t.Errorf("first update after %v, waiting on the slow provider", first)
+	}
+	want := [][]string{{"fast:a"}, {"slow:b", "fast:a"}}
+	if !slices.EqualFunc(updates, want, slices.Equal) {
+		t.Errorf("updates = %v, want %v", updates, want)
+	}
+}
+
+func TestStreamTimeout(t *testing.T) {
+	fast := &fake{id: "fast", results: []Result{result("fast", "a", 1)}}
+	hung := &fake{id: "hung", results: []Result{result("hung", "b", 2)}, delay: time.Hour}
+	e := NewEngine(hung, fast)
+	e.SetTimeouts(time.Second, map[string]time.Duration{"hung": 20 * time.Millisecond})
+
+	start := time.Now()
+	var last Update
+	if err := e.Stream(context.Background(), "q", func(u Update) { last = u }); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("search took %v, past the hung provider's timeout", elapsed)
+	}
+	if got := ids(last.Results); !last.Done || !slices.Equal(got, []string{"fast:a"}) {
+		t.Errorf("last update = %v (done %v), want [fast:a] done", got, last.Done)
+	}
+}
+
+// panicker is a provider whose Search panics, like a broken plugin.
+type panicker struct{ fake }
+
+func (p *panicker) Search(ctx context.Context, query string) ([]Result, error) {
+	panic("plugin broke")
+}
+
+func TestStreamProviderPanic(t *testing.T) {
+	fast := &fake{id: "fast", results: []Result{result("fast", "a", 1)}}
+	e := NewEngine(&panicker{fake{id: "plugin"}}, fast)
+	var last Update
+	err := e.Stream(context.Background(), "q", func(u Update) { last = u })
+	if err == nil || !strings.Contains(err.Error(), "plugin") {
+		t.Errorf("Stream error = %v, want the plugin's panic", err)
+	}
+	if got := ids(last.Results); !last.Done || !slices.Equal(got, []string{"fast:a"}) {
+		t.Errorf("last update = %v (done %v), want [fast:a] done", got, last.Done)
+	}
+}
@@ -0,0 +1,127 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the built-in transformers.
+const (
+	TransformDedup    = "dedup"
+	TransformTruncate = "truncate"
+)
+
+// DefaultLimit is the number of results the built-in truncate transformer
+// keeps.
+const DefaultLimit = 50
+
+// DefaultPipeline is the transformer order used unless one is configured.
+var DefaultPipeline = []string{TransformDedup, TransformTruncate}
+
+// TransformContext carries per-query state through the pipeline.
+type TransformContext struct {
+	// Query is the query after prefix routing.
+	Query string
+
+	absorbed map[string]map[string]Result
+	// total is how many results there were before truncating them.
+	total int
+	// tiebreak orders results with equal scores; see Sort.
+	tiebreak string
+}
+
+// Transformer post-processes the merged results of a query, which arrive
+// ordered as Compare orders them, and returns the list to show. It may drop,
+// reorder or add results and rewrite their fields and actions. Transformers
+// run in pipeline order each time results are merged, so they should be cheap
+// and must not modify the results slice they are given in place if they
+// reorder it.
+type Transformer func(tc *TransformContext, results []Result) []Result
+
+// Dedup collapses results that share a DedupKey; see dedup. Actions merged
+// in from a duplicate still run on the duplicate's provider.
+func Dedup(tc *TransformContext, results []Result) []Result {
+	out, absorbed := dedup(results)
+	if tc.absorbed == nil {
+		tc.absorbed = absorbed
+		return out
+	}
+	for id, dups := range absorbed {
+		tc.absorbed[id] = dups
+	}
+	return out
+}
+
+// Truncate returns a transformer that keeps the first n results. Before
+// that, results past the first perProvider[id] from provider id are dropped,
+// so a provider with many candidates cannot crowd out the rest. Zero or
+// missing limits keep everything.
+func Truncate(n int, perProvider map[string]int) Transformer {
+	return func(tc *TransformContext, results []Result) []Result {
+		tc.total = max(tc.total, len(results))
+		if len(perProvider) > 0 {
+			counts := make(map[string]int)
+			kept := make([]Result, 0, len(results))
+			for _, r := range results {
+				if limit := perProvider[r.Provider]; limit > 0 && counts[r.Provider] >= limit {
+					continue
+				}
+				counts[r.Provider]++
+				kept = append(kept, r)
+			}
+			results = kept
+		}
+		if n > 0 && len(results) > n {
+			return results[:n]
+		}
+		return results
+	}
+}
+
+// RegisterTransformer makes t available to pipelines under name, replacing
+// any transformer registered under it before.
+func (e *Engine) RegisterTransformer(name string, t Transformer) {
+	e.mu.Lock()
+	e.transformers[name] = t
+	e.mu.Unlock()
+}
+
+// SetPipeline sets the transformers applied to merged results, in order. The
+// names must already be registered; unknown names are left out of the
+// pipeline and reported in the error.
+func (e *Engine) SetPipeline(names []string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var unknown []string
+	pipeline := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := e.transformers[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		pipeline = append(pipeline, name)
+	}
+	e.pipeline = pipeline
+	if len(unknown) > 0 {
+		return fmt.Errorf("search: unknown transformers %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// transform runs the pipeline over results. total is how many results
+// there were before truncation, or after the pipeline if that is more.
+func (e *Engine) transform(query string, results []Result) (out []Result, absorbed map[string]map[string]Result, total int) {
+	e.mu.Lock()
+	pipeline := make([]Transformer, 0, len(e.pipeline))
+	for _, name := range e.pipeline {
+		pipeline = append(pipeline, e.transformers[name])
+	}
+	tiebreak := e.tiebreak
+	e.mu.Unlock()
+
+	tc := &TransformContext{Query: query, tiebreak: tiebreak}
+	for _, t := range pipeline {
+		results = t(tc, results)
+	}
+	return results, tc.absorbed, max(tc.total, len(results))
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	p := &fake{id: "apps", results: []Result{
+		result("apps", "mail", 3),
+		result("apps", "notes", 2),
+		result("apps", "maps", 1),
+	}}
+	e := NewEngine(p)
+	// upper titles results; boostMaps then lifts Maps to the top, seeing
+	// the title upper already rewrote.
+	e.RegisterTransformer("upper", func(tc *TransformContext, results []Result) []Result {
+		out := slices.Clone(results)
+		for i := range out {
+			out[i].Title = strings.ToUpper(out[i].Title)
+		}
+		return out
+	})
+	e.RegisterTransformer("boostMaps", func(tc *TransformContext, results []Result) []Result {
+		out := slices.Clone(results)
+		for i := range out {
+			if out[i].Title == "MAPS" {
+				out[i].Score += 10
+			}
+		}
+		tc.Sort(out)
+		return out
+	})
+	if err := e.SetPipeline([]string{"upper", "boostMaps", TransformTruncate}); err != nil {
+		t.Fatal(err)
+	}
+	results, err := e.Search(context.Background(), "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, r := range results {
+		titles = append(titles, r.Title)
+	}
+	if want := []string{"MAPS", "MAIL", "NOTES"}; !slices.Equal(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+
+	// In the other order boostMaps sees the original titles and does
+	// nothing.
+	if err := e.SetPipeline([]string{"boostMaps", "upper"}); err != nil {
+		t.Fatal(err)
+	}
+	results, err = e.Search(context.Background(), "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles = titles[:0]
+	for _, r := range results {
+		titles = append(titles, r.Title)
+	}
+	if want := []string{"MAIL", "NOTES", "MAPS"}; !slices.Equal(titles, want) {
+		t.Errorf("reversed pipeline: titles = %v, want %v", titles, want)
+	}
+}
+
+func TestSetPipelineUnknown(t *testing.T) {
+	e := NewEngine()
+	err := e.SetPipeline([]string{TransformDedup, "nope", TransformTruncate})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("SetPipeline error = %v, want one naming nope", err)
+	}
+	if want := []string{TransformDedup, TransformTruncate}; !slices.Equal(e.pipeline, want) {
+		t.Errorf("pipeline = %v, want %v", e.pipeline, want)
+	}
+}
+
+func TestTruncatePerProvider(t *testing.T) {
+	var results []Result
+	for _, key := range []string{"a", "b", "c"} {
+		results = append(results, Result{ID: "files:" + key, Provider: "files"})
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		results = append(results, Result{ID: "apps:" + key, Provider: "apps"})
+	}
+	// Without the files limit, the cap would keep only files.
+	if got, want := ids(Truncate(3, nil)(&TransformContext{}, results)), []string{"files:a", "files:b", "files:c"}; !slices.Equal(got, want) {
+		t.Errorf("Truncate without limits = %v, want %v", got, want)
+	}
+	tc := &TransformContext{}
+	got := Truncate(3, map[string]int{"files": 1})(tc, results)
+	if want := []string{"files:a", "apps:a", "apps:b"}; !slices.Equal(ids(got), want) {
+		t.Errorf("Truncate = %v, want %v", ids(got), want)
+	}
+	if tc.total != len(results) {
+		t.Errorf("total = %d, want %d", tc.total, len(results))
+	}
+}
+
+func TestStreamTotal(t *testing.T) {
+	var apps, files []Result
+	for i := range 4 {
+		apps = append(apps, result("apps", fmt.Sprint("app", i), float64(10+i)))
+	}
+	for i := range 6 {
+		files = append(files, result("files", fmt.Sprint("file", i), float64(i)))
+	}
+	e := NewEngine(&fake{id: "apps", results: apps}, &fake{id: "files", results: files, delay: 20 * time.Millisecond})
+	e.RegisterTransformer(TransformTruncate, Truncate(3, nil))
+
+	var shown, totals []int
+	err := e.Stream(context.Background(), "x", func(u Update) {
+		shown = append(shown, len(u.Results))
+		totals = append(totals, u.Total)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The total grows as the slower provider finishes; the list stays cut.
+	if want := []int{3, 3}; !slices.Equal(shown, want) {
+		t.Errorf("results shown = %v, want %v", shown, want)
+	}
+	if want := []int{4, 10}; !slices.Equal(totals, want) {
+		t.Errorf("totals = %v, want %v", totals, want)
+	}
+
+	// Sticky results count towards both.
+	sticky := resolving{&fake{id: "actions", results: []Result{result("actions", "new", 0)}, prefixOnly: true}}
+	e = NewEngine(&fake{id: "apps", results: apps}, sticky)
+	e.RegisterTransformer(TransformTruncate, Truncate(3, nil))
+	e.SetSticky([]string{"actions:new"})
+	var last Update
+	if err := e.Stream(context.Background(), "x", func(u Update) { last = u }); err != nil {
+		t.Fatal(err)
+	}
+	if len(last.Results) != 4 || last.Total != 5 {
+		t.Errorf("with a sticky result: %d shown of %d, want 4 of 5", len(last.Results), last.Total)
+	}
+}
@@ -0,0 +1,28 @@
+package spotlight
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	last := time.Date(2025, 5, 30, 9, 15, 2, 0, time.UTC)
+	tests := []struct {
+		out  string
+		want Usage
+		ok   bool
+	}{
+		{"2025-05-30 09:15:02 +0000\x0042", Usage{Count: 42, Last: last}, true},
+		{"(null)\x00(null)", Usage{}, true},
+		{"2025-05-30 09:15:02 +0000\x00(null)", Usage{Last: last}, true},
+		{"(null)", Usage{}, false},
+		{"yesterday\x003", Usage{}, false},
+		{"(null)\x00many", Usage{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parse([]byte(tt.out))
+		if (err == nil) != tt.ok || !got.Last.Equal(tt.want.Last) || got.Count != tt.want.Count {
+			t.Errorf("parse(%q) = %+v, %v, want %+v", tt.out, got, err, tt.want)
+		}
+	}
+}
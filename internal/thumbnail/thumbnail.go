@@ -0,0 +1,174 @@
+// Package thumbnail makes small previews of image files, as data URIs the
+// frontend can show in place of a file's icon. Images are decoded and
+// scaled down in Go, so only the formats the standard library reads are
+// supported: PNG, JPEG and GIF.
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"changeme/internal/lru"
+)
+
+const (
+	// MaxSize is the longest side of a thumbnail, in pixels. Smaller images
+	// keep their size.
+	MaxSize = 96
+	// maxPixels refuses images too large to decode quickly and cheaply.
+	maxPixels = 50_000_000
+	// maxFileSize refuses files too large to read for a preview.
+	maxFileSize = 50 << 20
+	// samples is how many source pixels are averaged across each side of
+	// a thumbnail pixel.
+	samples = 4
+	// jpegQuality keeps photo thumbnails small without visible blocking.
+	jpegQuality = 80
+)
+
+// ErrUnsupported is returned for files that are not images in a supported
+// format.
+var ErrUnsupported = errors.New("thumbnail: unsupported image format")
+
+// extensions are those of images in a supported format.
+var extensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+
+// IsImage reports whether path names an image in a supported format, going
+// by its extension.
+func IsImage(path string) bool {
+	return extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Generate returns a thumbnail of the image at path no larger than maxSize
+// on either side, as a data URI. Photos are encoded as JPEG and everything
+// else as PNG, which keeps transparency.
+func Generate(path string, maxSize int) (string, error) {
+	if !IsImage(path) {
+		return "", ErrUnsupported
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxFileSize {
+		return "", fmt.Errorf("thumbnail: %s is too large", path)
+	}
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", fmt.Errorf("thumbnail: %s: %w", path, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return "", fmt.Errorf("thumbnail: %s is too large", path)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("thumbnail: %s: %w", path, err)
+	}
+	small := Scale(img, maxSize)
+	var buf bytes.Buffer
+	mime := "image/png"
+	if format == "jpeg" {
+		mime = "image/jpeg"
+		err = jpeg.Encode(&buf, small, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, small)
+	}
+	if err != nil {
+		return "", fmt.Errorf("thumbnail: %s: %w", path, err)
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Scale shrinks img so neither side is longer than maxSize, averaging a few
+// samples per pixel. Images already small enough are returned as they are.
+func Scale(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSize && h <= maxSize || w == 0 || h == 0 {
+		return img
+	}
+	tw, th := maxSize, h*maxSize/w
+	if h > w {
+		tw, th = w*maxSize/h, maxSize
+	}
+	tw, th = max(tw, 1), max(th, 1)
+	out := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			var r, g, bl, a uint32
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := b.Min.X + (x*samples+sx)*w/(tw*samples)
+					py := b.Min.Y + (y*samples+sy)*h/(th*samples)
+					c := color.NRGBA64Model.Convert(img.At(px, py)).(color.NRGBA64)
+					r, g, bl, a = r+uint32(c.R), g+uint32(c.G), bl+uint32(c.B), a+uint32(c.A)
+				}
+			}
+			n := uint32(samples * samples * 257)
+			out.SetNRGBA(x, y, color.NRGBA{uint8(r / n), uint8(g / n), uint8(bl / n), uint8(a / n)})
+		}
+	}
+	return out
+}
+
+// key identifies one version of a file: a thumbnail is made again once the
+// file changes.
+type key struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// Cache keeps the most recently used thumbnails, so moving back to a result
+// does not decode its image again.
+type Cache struct {
+	entries *lru.Cache[key, string]
+}
+
+// NewCache returns an empty cache whose thumbnails are charged to budget.
+func NewCache(budget *lru.Budget) *Cache {
+	return &Cache{entries: lru.New[key](budget, func(uri string) int64 { return int64(len(uri)) })}
+}
+
+// Get returns the thumbnail of the image at path, making it with Generate
+// unless the file is unchanged since it was last made. Failures are not
+// cached, so a file fixed since is tried again.
+func (c *Cache) Get(path string) (string, error) {
+	if !IsImage(path) {
+		return "", ErrUnsupported
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	k := key{path, info.ModTime(), info.Size()}
+	if uri, ok := c.entries.Get(k); ok {
+		return uri, nil
+	}
+	uri, err := Generate(path, MaxSize)
+	if err != nil {
+		return "", err
+	}
+	c.entries.Put(k, uri)
+	return uri, nil
+}
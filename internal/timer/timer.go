@@ -0,0 +1,93 @@
+// Package timer describes countdown timers and stopwatches and parses the
+// durations users type for them.
+package timer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timer is a running countdown, or a stopwatch when Deadline is zero.
+type Timer struct {
+	ID       string    `json:"id"`
+	Label    string    `json:"label"`
+	Started  time.Time `json:"started"`
+	Deadline time.Time `json:"deadline,omitempty"`
+}
+
+// Stopwatch reports whether t counts up rather than down.
+func (t Timer) Stopwatch() bool {
+	return t.Deadline.IsZero()
+}
+
+// Remaining returns the time left at now, never less than zero.
+func (t Timer) Remaining(now time.Time) time.Duration {
+	return max(t.Deadline.Sub(now), 0)
+}
+
+// Elapsed returns how long t has been running at now.
+func (t Timer) Elapsed(now time.Time) time.Duration {
+	return now.Sub(t.Started)
+}
+
+// ErrInvalidDuration is returned by ParseDuration for input it cannot read.
+var ErrInvalidDuration = errors.New("timer: invalid duration")
+
+var naturalPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(h|hrs?|hours?|m|mins?|minutes?|s|secs?|seconds?)\b`)
+
+// ParseDuration reads Go-style durations ("1h30m", "90s") and natural ones
+// ("1 hour 30 minutes", "5 min and 10 sec"). A bare number is minutes.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, ErrInvalidDuration
+	}
+	if d, err := time.ParseDuration(strings.ReplaceAll(s, " ", "")); err == nil {
+		return positive(d)
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return positive(time.Duration(n * float64(time.Minute)))
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		m := naturalPart.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		n, _ := strconv.ParseFloat(m[1], 64)
+		unit := time.Second
+		switch m[2][0] {
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		total += time.Duration(n * float64(unit))
+		rest = strings.TrimSpace(rest[len(m[0]):])
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(rest, ","), "and "))
+	}
+	return positive(total)
+}
+
+func positive(d time.Duration) (time.Duration, error) {
+	if d <= 0 {
+		return 0, ErrInvalidDuration
+	}
+	return d, nil
+}
+
+// Format renders d as "1:02:03" or "2:03", rounded down to the second.
+func Format(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
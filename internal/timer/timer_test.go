@@ -0,0 +1,52 @@
+package timer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"5m", 5 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"90s", 90 * time.Second},
+		{"1h 30m", 90 * time.Minute},
+		{"5", 5 * time.Minute},
+		{"1.5", 90 * time.Second},
+		{"1 hour 30 minutes", 90 * time.Minute},
+		{"5 min and 10 sec", 5*time.Minute + 10*time.Second},
+		{"2 hours, 5 mins", 2*time.Hour + 5*time.Minute},
+		{"  10 Seconds ", 10 * time.Second},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "0", "-5m", "soon", "5 parsecs", "0s"} {
+		if d, err := ParseDuration(in); !errors.Is(err, ErrInvalidDuration) {
+			t.Errorf("ParseDuration(%q) = %v, %v; want ErrInvalidDuration", in, d, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{65*time.Second + 900*time.Millisecond, "1:05"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package update reads a release feed to tell whether a newer version of
+// Prism has been published. It only informs; nothing is downloaded.
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Release is the latest release described by a feed.
+type Release struct {
+	Version string
+	// URL is the page the release can be downloaded from.
+	URL string
+}
+
+// feed is the subset of a GitHub "latest release" response Prism reads.
+// Feeds of other hosts may use the plain version and url fields instead.
+type feed struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// Parse reads the latest release from a feed body.
+func Parse(body []byte) (Release, error) {
+	var f feed
+	if err := json.Unmarshal(body, &f); err != nil {
+		return Release{}, fmt.Errorf("update: %w", err)
+	}
+	r := Release{Version: f.TagName, URL: f.HTMLURL}
+	if r.Version == "" {
+		r.Version = f.Version
+	}
+	if r.URL == "" {
+		r.URL = f.URL
+	}
+	if r.Version == "" {
+		return Release{}, fmt.Errorf("update: feed has no version")
+	}
+	return r, nil
+}
+
+// Newer reports whether version latest comes after current. Versions are
+// dot-separated numbers with an optional leading "v"; a pre-release or
+// build suffix after "-" or "+" is ignored, and missing parts count as
+// zero, so "1.2" equals "v1.2.0".
+func Newer(latest, current string) bool {
+	l, c := parts(latest), parts(current)
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var a, b int
+		if i < len(l) {
+			a = l[i]
+		}
+		if i < len(c) {
+			b = c[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+func parts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var out []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package update
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		body    string
+		want    Release
+		wantErr bool
+	}{
+		{`{"tag_name": "v1.3.0", "html_url": "https://github.com/prism/releases/v1.3.0"}`, Release{"v1.3.0", "https://github.com/prism/releases/v1.3.0"}, false},
+		{`{"version": "1.3", "url": "https://example.com/prism"}`, Release{"1.3", "https://example.com/prism"}, false},
+		{`{"url": "https://example.com/prism"}`, Release{}, true},
+		{`not json`, Release{}, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]byte(tt.body))
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%s) = %+v, %v; want %+v, error %v", tt.body, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.3.0", "1.2.9", true},
+		{"1.10", "1.9", true},
+		{"1.2", "v1.2.0", false},
+		{"1.2.0", "1.2.1", false},
+		{"v2.0.0-beta.1", "1.9.0", true},
+		{"1.2.0+build.5", "1.2.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
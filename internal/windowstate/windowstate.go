@@ -0,0 +1,190 @@
+// Package windowstate remembers where the user last moved the launcher
+// window, and the size it last had, so it can open the same way again.
+package windowstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+// settleDelay is how long the window must stay put before a move counts as
+// finished. Moves are reported continuously while the window is dragged,
+// and only where it ends up is saved.
+const settleDelay = 500 * time.Millisecond
+
+// Bounds for a remembered size, in logical pixels. A size outside them,
+// such as from a file edited by hand or a window squashed to nothing, is
+// ignored.
+const (
+	MinWidth  = 200
+	MinHeight = 40
+	MaxWidth  = 8192
+	MaxHeight = 8192
+)
+
+// Position is a window's top-left corner in screen coordinates.
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Size is a window's width and height in logical pixels.
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Valid reports whether s lies within the bounds a window is restored to.
+func (s Size) Valid() bool {
+	return s.Width >= MinWidth && s.Width <= MaxWidth && s.Height >= MinHeight && s.Height <= MaxHeight
+}
+
+// state is the file's contents. Files from before sizes were remembered
+// hold a bare Position.
+type state struct {
+	Position *Position `json:"position,omitempty"`
+	Size     *Size     `json:"size,omitempty"`
+}
+
+// Store keeps the last window position and size and persists them to a
+// JSON file.
+type Store struct {
+	path string
+
+	mu        sync.Mutex
+	pos       *Position
+	size      *Size
+	timer     *time.Timer
+	sizeTimer *time.Timer
+}
+
+// Open loads the store at path. A missing file yields a store with no
+// position or size, as does a corrupt one, which is quarantined and
+// reported in the error. A saved size out of bounds is dropped.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return s, fmt.Errorf("windowstate: %w", quarantine.Move(path, err))
+	}
+	if st.Position == nil && st.Size == nil {
+		var p Position
+		if err := json.Unmarshal(data, &p); err == nil {
+			st.Position = &p
+		}
+	}
+	s.pos = st.Position
+	if st.Size != nil && st.Size.Valid() {
+		s.size = st.Size
+	}
+	return s, nil
+}
+
+// Position returns the last saved position, reporting false when the window
+// has never been moved.
+func (s *Store) Position() (Position, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pos == nil {
+		return Position{}, false
+	}
+	return *s.pos, true
+}
+
+// Moved records that the window is now at p. The position is saved once
+// no further move arrives for settleDelay, which is when a drag has ended.
+func (s *Store) Moved(p Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(settleDelay, func() {
+		if err := s.Save(p); err != nil {
+			log.Println("windowstate:", err)
+		}
+	})
+}
+
+// Size returns the last saved size, reporting false when none within
+// bounds has been saved.
+func (s *Store) Size() (Size, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size == nil {
+		return Size{}, false
+	}
+	return *s.size, true
+}
+
+// Resized records that the window now has size sz, saving it once no
+// further resize arrives for settleDelay. Sizes out of bounds are ignored.
+func (s *Store) Resized(sz Size) {
+	if !sz.Valid() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sizeTimer != nil {
+		s.sizeTimer.Stop()
+	}
+	s.sizeTimer = time.AfterFunc(settleDelay, func() {
+		if err := s.SaveSize(sz); err != nil {
+			log.Println("windowstate:", err)
+		}
+	})
+}
+
+// Save makes p the last position and writes it to disk.
+func (s *Store) Save(p Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pos = &p
+	return s.write()
+}
+
+// SaveSize makes sz the last size and writes it to disk.
+func (s *Store) SaveSize(sz Size) error {
+	if !sz.Valid() {
+		return fmt.Errorf("windowstate: size %dx%d out of bounds", sz.Width, sz.Height)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size = &sz
+	return s.write()
+}
+
+// write saves the position and size to disk. The caller holds s.mu.
+func (s *Store) write() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(state{Position: s.pos, Size: s.size})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
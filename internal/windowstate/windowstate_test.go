@@ -0,0 +1,60 @@
+package windowstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"changeme/internal/quarantine"
+)
+
+func TestMovedSavesWhereDragEnds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window-state.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Position(); ok {
+		t.Fatal("new store has a position")
+	}
+
+	// A drag reports a stream of moves; only where it ends is kept.
+	for x := 100; x <= 300; x += 50 {
+		s.Moved(Position{X: x, Y: x / 2})
+	}
+	if _, ok := s.Position(); ok {
+		t.Error("position saved while the drag goes on")
+	}
+	time.Sleep(settleDelay + 200*time.Millisecond)
+
+	want := Position{X: 300, Y: 150}
+	if got, ok := s.Position(); !ok || got != want {
+		t.Errorf("Position = %+v, %v; want %+v", got, ok, want)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := reopened.Position(); !ok || got != want {
+		t.Errorf("saved Position = %+v, %v; want %+v", got, ok, want)
+	}
+}
+
+func TestOpenCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window-state.json")
+	data := `{"position": {"x": 10, "y":`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(path)
+	if err == nil {
+		t.Error("Open of a corrupt file succeeded")
+	}
+	if _, ok := s.Position(); ok {
+		t.Error("corrupt state has a position")
+	}
+	if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+		t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+	}
+}
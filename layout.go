@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// eventLayoutChanged carries the Layout after the density changes.
+const eventLayoutChanged = "layout:changed"
+
+// Row heights for each density, in logical pixels: a single line of text,
+// or a title and subtitle beside an icon.
+const (
+	compactRowHeight     = 32
+	comfortableRowHeight = 48
+)
+
+// Layout is how the frontend lays out result rows.
+type Layout struct {
+	Density string `json:"density"`
+	// RowHeight is the height of one result row, which the window's
+	// height grows by per row shown.
+	RowHeight int `json:"rowHeight"`
+	// Icons is set when rows show icons and color swatches.
+	Icons bool `json:"icons"`
+}
+
+func layoutFor(c config.Config) Layout {
+	if c.LayoutDensity == config.LayoutCompact {
+		return Layout{Density: config.LayoutCompact, RowHeight: compactRowHeight}
+	}
+	return Layout{Density: config.LayoutComfortable, RowHeight: comfortableRowHeight, Icons: true}
+}
+
+// Layout returns the configured layout, for the frontend to apply on load.
+func (g *GreetService) Layout() Layout {
+	return layoutFor(g.config.Get())
+}
+
+// SetLayoutDensity switches between the compact and comfortable layouts,
+// saving the choice to the config and announcing it as eventLayoutChanged.
+func (g *GreetService) SetLayoutDensity(density string) error {
+	if density != config.LayoutCompact && density != config.LayoutComfortable {
+		return fmt.Errorf("layout: unknown density %q", density)
+	}
+	if err := g.config.Update(func(c *config.Config) { c.LayoutDensity = density }); err != nil {
+		return err
+	}
+	g.events.EmitEvent(eventLayoutChanged, g.Layout())
+	g.refreshResults()
+	return nil
+}
+
+// stripIcons drops the visual data compact rows do not show, so it is not
+// sent to the frontend at all.
+func stripIcons(results []search.Result, c config.Config) {
+	if layoutFor(c).Icons {
+		return
+	}
+	for i := range results {
+		results[i].Swatch = ""
+		results[i].Thumbnail = ""
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestCompactOmitsIcons(t *testing.T) {
+	g, events := newTestService(t, nil)
+	swatch := func() []search.Result {
+		return []search.Result{{ID: "color:#3498db", Title: "#3498db", Swatch: "#3498db", Thumbnail: "data:image/png;base64,AAAA"}}
+	}
+
+	results := swatch()
+	g.decorate(results, g.config.Get())
+	if results[0].Swatch == "" || results[0].Thumbnail == "" {
+		t.Errorf("comfortable result = %+v, want its swatch and thumbnail", results[0])
+	}
+
+	if err := g.SetLayoutDensity(config.LayoutCompact); err != nil {
+		t.Fatal(err)
+	}
+	results = swatch()
+	g.decorate(results, g.config.Get())
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); strings.Contains(s, "swatch") || strings.Contains(s, "thumbnail") {
+		t.Errorf("compact payload %s has icon data", s)
+	}
+
+	if got := g.Layout(); got != (Layout{Density: config.LayoutCompact, RowHeight: compactRowHeight}) {
+		t.Errorf("Layout = %+v", got)
+	}
+	if names := events.emitted(); len(names) == 0 || names[0] != eventLayoutChanged {
+		t.Errorf("events %v, want %s", names, eventLayoutChanged)
+	}
+	// The choice is saved.
+	reopened, err := config.Open(g.config.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := reopened.Get().LayoutDensity; d != config.LayoutCompact {
+		t.Errorf("saved density = %q", d)
+	}
+	if err := g.SetLayoutDensity("roomy"); err == nil {
+		t.Error("SetLayoutDensity(roomy) succeeded")
+	}
+}
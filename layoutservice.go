@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+)
+
+// defaultLayoutDelay is the pause between the items of a layout without
+// its own DelayMs.
+const defaultLayoutDelay = 300 * time.Millisecond
+
+// LayoutService opens launch layouts: named sets of apps, files, URLs and
+// results configured as LaunchLayouts.
+type LayoutService struct {
+	config *config.Store
+	engine *search.Engine
+	apps   *apps.Provider
+	plat   platform.Platform
+}
+
+func NewLayoutService(cfg *config.Store, engine *search.Engine, appsProvider *apps.Provider, plat platform.Platform) *LayoutService {
+	return &LayoutService{config: cfg, engine: engine, apps: appsProvider, plat: plat}
+}
+
+// ApplyLayout opens every item of the layout called name, in order, pausing
+// between them. An item that fails does not stop the rest; the error lists
+// each one that failed.
+func (l *LayoutService) ApplyLayout(name string) error {
+	return l.apply(context.Background(), name)
+}
+
+func (l *LayoutService) apply(ctx context.Context, name string) error {
+	var layout config.LaunchLayout
+	found := false
+	for _, c := range l.config.Get().LaunchLayouts {
+		if strings.EqualFold(c.Name, name) {
+			layout, found = c, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("layout: no layout called %q", name)
+	}
+	delay := time.Duration(layout.DelayMs) * time.Millisecond
+	if delay <= 0 {
+		delay = defaultLayoutDelay
+	}
+	var errs []error
+	for i, item := range layout.Items {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		if err := l.open(ctx, item); err != nil {
+			errs = append(errs, fmt.Errorf("layout: %s: %w", item, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// open opens one layout item: a URL, a path, the result with that ID, or
+// else the installed app of that name.
+func (l *LayoutService) open(ctx context.Context, item string) error {
+	item = strings.TrimSpace(item)
+	if u, err := url.Parse(item); err == nil && u.Scheme != "" && u.Host != "" {
+		return l.plat.Open(ctx, item)
+	}
+	if rest, ok := strings.CutPrefix(item, "~"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			item = home + rest
+		}
+	}
+	if filepath.IsAbs(item) {
+		if _, err := os.Stat(item); err != nil {
+			return err
+		}
+		return l.plat.Open(ctx, item)
+	}
+	if providerID, _, ok := strings.Cut(item, ":"); ok {
+		if p, ok := l.engine.Provider(providerID); ok {
+			r, ok := l.engine.Resolve(ctx, item)
+			if !ok {
+				return search.ErrUnknownResult
+			}
+			return p.Activate(ctx, r, r.DefaultAction())
+		}
+	}
+	app, ok := l.apps.Find(item)
+	if !ok {
+		return fmt.Errorf("no app called %q is installed", item)
+	}
+	return l.plat.Launch(ctx, app)
+}
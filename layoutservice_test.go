@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+)
+
+// launchPlatform is a platform with a few installed apps that records what
+// it opens and launches.
+type launchPlatform struct {
+	platform.Platform
+	apps   []platform.App
+	opened []string
+}
+
+func (p *launchPlatform) Applications() ([]platform.App, error) { return p.apps, nil }
+
+func (p *launchPlatform) Launch(ctx context.Context, app platform.App) error {
+	p.opened = append(p.opened, "launch "+app.Name)
+	return nil
+}
+
+func (p *launchPlatform) Open(ctx context.Context, target string) error {
+	p.opened = append(p.opened, "open "+target)
+	return nil
+}
+
+func TestApplyLayout(t *testing.T) {
+	notes := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(notes, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plat := &launchPlatform{apps: []platform.App{
+		{Name: "Slack", Path: "/Applications/Slack.app"},
+		{Name: "Mail", Path: "/Applications/Mail.app"},
+	}}
+	settings := search.Result{ID: "commands:settings", Title: "Settings", Actions: []search.Action{{ID: "run", Title: "Run"}}}
+	commands := &testProvider{id: "commands", results: []search.Result{settings}}
+	engine := search.NewEngine(commands)
+	g, _ := newTestService(t, func(c *config.Config) {
+		c.LaunchLayouts = []config.LaunchLayout{{
+			Name:    "Work",
+			Items:   []string{"Slack", "https://example.com/standup", notes, "commands:settings", "Missing App", "/no/such/file", "mail"},
+			DelayMs: 1,
+		}}
+	})
+	l := NewLayoutService(g.config, engine, apps.New(plat, fuzzy.Default()), plat)
+
+	err := l.ApplyLayout("work")
+	// The items that fail do not stop the rest.
+	want := []string{"launch Slack", "open https://example.com/standup", "open " + notes, "launch Mail"}
+	if !slices.Equal(plat.opened, want) {
+		t.Errorf("opened %q, want %q", plat.opened, want)
+	}
+	if got := commands.ran(); !slices.Equal(got, []string{"commands:settings run"}) {
+		t.Errorf("ran %v, want the settings command", got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Missing App") || !strings.Contains(err.Error(), "/no/such/file") {
+		t.Errorf("ApplyLayout = %v, want both failures", err)
+	}
+
+	if err := l.ApplyLayout("gaming"); err == nil {
+		t.Error("ApplyLayout of an unknown layout succeeded")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"changeme/internal/keys"
+	"changeme/internal/providers/commands"
+)
+
+// LeaderService runs Prism commands from key sequences typed in the
+// launcher, such as "g s" for settings, configured as LeaderSequences.
+// Sequences only apply while the query is empty so they never get in the
+// way of typing a search.
+type LeaderService struct {
+	sequencer *keys.Sequencer
+	commands  *commands.Provider
+}
+
+func NewLeaderService(cmds *commands.Provider) *LeaderService {
+	return &LeaderService{sequencer: keys.NewSequencer(), commands: cmds}
+}
+
+// SetSequences replaces the sequences and the time allowed between their
+// keys.
+func (l *LeaderService) SetSequences(sequences map[string]string, timeout time.Duration) {
+	l.sequencer.Set(sequences, timeout)
+}
+
+// KeyPressed is called by the frontend for each key pressed with input
+// holding the current query. When the returned Press is consumed the
+// frontend leaves the key alone; keys it lists to replay are typed into
+// the query first. A key that completes a sequence runs its command.
+func (l *LeaderService) KeyPressed(key, input string) (keys.Press, error) {
+	if input != "" {
+		return keys.Press{Replay: l.sequencer.Reset()}, nil
+	}
+	p := l.sequencer.Press(key, time.Now())
+	if p.Command == "" {
+		return p, nil
+	}
+	return p, l.commands.Run(context.Background(), p.Command)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/fuzzy"
+	"changeme/internal/providers/commands"
+)
+
+func TestLeaderEmptyInput(t *testing.T) {
+	var ran []string
+	cmds := commands.New(fuzzy.Default())
+	cmds.Register(commands.Command{ID: "open-settings", Title: "Open Settings", Run: func(ctx context.Context) error {
+		ran = append(ran, "open-settings")
+		return nil
+	}})
+	l := NewLeaderService(cmds)
+	l.SetSequences(map[string]string{"g s": "open-settings"}, time.Second)
+
+	// Typed into a query, the keys are left alone.
+	for _, key := range []string{"g", "s"} {
+		p, err := l.KeyPressed(key, "mail")
+		if err != nil || p.Consumed {
+			t.Errorf("KeyPressed(%q) with a query = %+v, %v; want it not consumed", key, p, err)
+		}
+	}
+	// A query typed mid-sequence ends it and gives back the keys held.
+	l.KeyPressed("g", "")
+	if p, _ := l.KeyPressed("x", "x"); !slices.Equal(p.Replay, []string{"g"}) {
+		t.Errorf("KeyPressed with a query mid-sequence = %+v, want g replayed", p)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("ran %v with a query", ran)
+	}
+
+	l.KeyPressed("g", "")
+	if p, err := l.KeyPressed("s", ""); err != nil || p.Command != "open-settings" {
+		t.Errorf("KeyPressed(s) = %+v, %v; want open-settings", p, err)
+	}
+	if !slices.Equal(ran, []string{"open-settings"}) {
+		t.Errorf("ran %v, want [open-settings]", ran)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"changeme/internal/interactions"
+	"changeme/internal/search"
+)
+
+// transformLearned names the transformer that boosts the results picked
+// before for the same query.
+const transformLearned = "interactions"
+
+// learnedWeight scales the learned boost like FrecencyWeight scales
+// frecency's: weight × log2(1 + picks). Three picks of a result for a query
+// add 32 points, enough to lift it over the prefix boost of a result that
+// was passed over each time.
+const learnedWeight = 16
+
+// learnedTransformer adds the boost for results picked before for queries
+// like the current one, see interactions.Store.Counts, and re-sorts. It
+// does nothing while learning is off.
+func learnedTransformer(store *interactions.Store, enabled func() bool) search.Transformer {
+	return func(tc *search.TransformContext, results []search.Result) []search.Result {
+		if !enabled() {
+			return results
+		}
+		counts := store.Counts(tc.Query)
+		if len(counts) == 0 {
+			return results
+		}
+		out := append([]search.Result(nil), results...)
+		for i := range out {
+			if n := counts[out[i].ID]; n > 0 {
+				out[i].Score += learnedWeight * math.Log2(1+n)
+			}
+		}
+		tc.Sort(out)
+		return out
+	}
+}
+
+// recordChoice learns that resultID was picked for the current query, at
+// the position it was listed in, unless learning is off.
+func (g *GreetService) recordChoice(resultID string) {
+	if !g.config.Get().LearnRanking {
+		return
+	}
+	g.mu.Lock()
+	query := g.lastQuery
+	position := slices.IndexFunc(g.lastResults, func(r search.Result) bool { return r.ID == resultID })
+	g.mu.Unlock()
+	if position < 0 {
+		return
+	}
+	if err := g.learned.Record(g.engine.RoutedQuery(query), resultID, position, time.Now()); err != nil {
+		log.Println(err)
+	}
+}
+
+// ClearLearnedRanking forgets every pick the ranking has learned from.
+func (g *GreetService) ClearLearnedRanking() error {
+	return g.learned.Clear()
+}
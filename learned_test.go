@@ -0,0 +1,45 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestLearnedBoost(t *testing.T) {
+	for _, learn := range []bool{true, false} {
+		a := search.Result{ID: "apps:a", Title: "X Alpha", Score: 40, Actions: []search.Action{{ID: "open", Title: "Open"}}}
+		b := search.Result{ID: "apps:b", Title: "X Beta", Score: 10, Actions: []search.Action{{ID: "open", Title: "Open"}}}
+		g, _ := newTestService(t, func(c *config.Config) {
+			c.ActivationDebounceMs = 0
+			c.LearnRanking = learn
+		}, &testProvider{id: "apps", results: []search.Result{a, b}})
+		g.engine.RegisterTransformer(transformLearned, learnedTransformer(g.learned, func() bool { return g.config.Get().LearnRanking }))
+		if err := g.engine.SetPipeline([]string{transformLearned}); err != nil {
+			t.Fatal(err)
+		}
+
+		// One pick of B is not enough to pass A; three are.
+		want := [][]string{{"apps:a", "apps:b"}, {"apps:a", "apps:b"}, {"apps:a", "apps:b"}, {"apps:b", "apps:a"}}
+		if !learn {
+			want[3] = want[0]
+		}
+		for i, w := range want {
+			if got := resultIDs(g.Search("X")); !slices.Equal(got, w) {
+				t.Errorf("learn %v, after %d picks of B: Search(X) = %v, want %v", learn, i, got, w)
+			}
+			if err := g.RunAction(b.ID, "", false); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := g.ClearLearnedRanking(); err != nil {
+			t.Fatal(err)
+		}
+		if got := resultIDs(g.Search("x")); !slices.Equal(got, []string{"apps:a", "apps:b"}) {
+			t.Errorf("learn %v, after clearing: Search(x) = %v, want A first", learn, got)
+		}
+	}
+}
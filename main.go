@@ -1,10 +1,67 @@
 package main
 
 import (
+	"context"
 	"embed"
 	_ "embed"
+	"errors"
+	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"changeme/internal/audit"
+	"changeme/internal/config"
+	"changeme/internal/convert"
+	"changeme/internal/frecency"
+	"changeme/internal/fuzzy"
+	"changeme/internal/history"
+	"changeme/internal/interactions"
+	"changeme/internal/locale"
+	"changeme/internal/lru"
+	"changeme/internal/network"
+	"changeme/internal/paste"
+	"changeme/internal/platform"
+	"changeme/internal/providers/apps"
+	"changeme/internal/providers/archives"
+	"changeme/internal/providers/calc"
+	"changeme/internal/providers/clipboard"
+	"changeme/internal/providers/color"
+	"changeme/internal/providers/commands"
+	"changeme/internal/providers/currency"
+	"changeme/internal/providers/docker"
+	"changeme/internal/providers/downloads"
+	"changeme/internal/providers/encode"
+	"changeme/internal/providers/env"
+	"changeme/internal/providers/files"
+	"changeme/internal/providers/finder"
+	"changeme/internal/providers/generate"
+	"changeme/internal/providers/grep"
+	"changeme/internal/providers/layouts"
+	"changeme/internal/providers/levels"
+	"changeme/internal/providers/mail"
+	"changeme/internal/providers/menus"
+	"changeme/internal/providers/notes"
+	"changeme/internal/providers/plugins"
+	"changeme/internal/providers/ports"
+	"changeme/internal/providers/quicklinks"
+	"changeme/internal/providers/relaunch"
+	"changeme/internal/providers/reminders"
+	"changeme/internal/providers/repos"
+	"changeme/internal/providers/services"
+	"changeme/internal/providers/smartactions"
+	"changeme/internal/providers/ssh"
+	"changeme/internal/providers/switcher"
+	"changeme/internal/providers/timers"
+	"changeme/internal/providers/todo"
+	"changeme/internal/providers/wifi"
+	"changeme/internal/search"
+	"changeme/internal/windowstate"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/events"
@@ -22,12 +79,141 @@ var assets embed.FS
 
 var (
 	window *application.WebviewWindow
+	// windowState remembers where the launcher was dragged to, and the
+	// size it last had.
+	windowState *windowstate.Store
+	// placed is where placeWindow last left the window, so the move it
+	// makes is not saved as one the user dragged.
+	placed atomic.Pointer[windowstate.Position]
 )
 
 // main function serves as the application's entry point. It initializes the application, creates a window,
 // and starts a goroutine that emits a time-based event every second. It subsequently runs the application and
 // logs any error that might occur.
 func main() {
+	waitForRestart(os.Args[1:])
+
+	cfg, err := openConfig()
+	if err != nil {
+		log.Println(err)
+	}
+	fr, err := openFrecency()
+	if err != nil {
+		log.Println(err)
+	}
+	learned, err := openInteractions()
+	if err != nil {
+		log.Println(err)
+	}
+
+	// greetService is created once its providers exist, but the paste output
+	// that some providers need reads the frontmost app from it.
+	var greetService *GreetService
+
+	plat := platform.Current()
+	notificationService := NewNotificationService(plat, cfg)
+	timerService, err := openTimers(notificationService)
+	if err != nil {
+		log.Println(err)
+	}
+
+	matcher := fuzzy.New(cfg.Get().Fuzzy)
+	appsProvider := apps.New(plat, matcher)
+	// caches is the memory budget the thumbnail cache keeps within.
+	caches := lru.NewBudget(int64(cfg.Get().CacheMemoryBudgetMB) << 20)
+	filesProvider := files.New(plat, matcher, caches)
+	// Compact rows show no icons, so no thumbnails are made for them.
+	filesProvider.SetThumbnails(func() bool { return layoutFor(cfg.Get()).Icons })
+	output := &paste.Output{
+		Clipboard: appClipboard{},
+		Target:    plat,
+		Frontmost: func() platform.App { return greetService.FrontmostApp() },
+		Notify:    notificationService.Notify,
+		Mode:      func() string { return cfg.Get().DefaultClipboardAction },
+		PlainApps: func() []string { return cfg.Get().PlainPasteApps },
+	}
+	clip := clipboard.New(appClipboard{}, output, matcher)
+	grepProvider := grep.New(plat)
+	todoProvider := todo.New(plat)
+	commandsProvider := commands.New(matcher)
+	relaunchProvider := relaunch.New(plat, matcher)
+	sshProvider := ssh.New(plat, matcher)
+	reposProvider := repos.New(plat, matcher)
+	downloadsProvider := downloads.New(plat, matcher)
+	generateProvider := generate.New(output)
+	windowState, err = openWindowState()
+	if err != nil {
+		log.Println(err)
+	}
+	netClient, err := openNetwork()
+	if err != nil {
+		log.Println(err)
+	}
+	rates := convert.NewHTTPRates(netClient)
+	pluginsProvider, err := openPlugins(plat, output)
+	if err != nil {
+		log.Println(err)
+	}
+	currencyProvider := currency.New(rates, output)
+	calcProvider := calc.New(output)
+	quicklinksProvider := quicklinks.New(plat)
+	mailProvider := mail.New(plat)
+	dockerProvider := docker.New(plat, matcher)
+	smartProvider := smartactions.New(appClipboard{}, output, plat, netClient)
+	// The layouts provider opens layouts through layoutService, which is
+	// created once the engine it resolves result IDs with exists.
+	var layoutService *LayoutService
+	layoutsProvider := layouts.New(matcher, func(ctx context.Context, name string) error {
+		return layoutService.apply(ctx, name)
+	})
+	providers := []search.Provider{appsProvider, filesProvider, clip, smartProvider, grepProvider, todoProvider, reposProvider, downloadsProvider, timers.New(timerService), commandsProvider, relaunchProvider, sshProvider, pluginsProvider, color.New(output), encode.New(appClipboard{}, output), env.New(matcher, plat, output), generateProvider, currencyProvider, calcProvider, quicklinksProvider, mailProvider, dockerProvider, layoutsProvider}
+	if runtime.GOOS != "windows" {
+		providers = append(providers, ports.New())
+	}
+	archivesProvider := archives.New(plat, matcher)
+	if runtime.GOOS == "darwin" {
+		providers = append(providers,
+			archivesProvider,
+			levels.New(),
+			notes.New(plat, matcher),
+			reminders.New(plat, matcher),
+			finder.New(matcher),
+			switcher.New(matcher),
+			wifi.New(matcher),
+			menus.New(matcher, func() platform.App { return greetService.FrontmostApp() }),
+			services.New(matcher, func() platform.App { return greetService.FrontmostApp() }),
+		)
+	}
+	engine := search.NewEngine(providers...)
+	engine.RegisterTransformer(transformFrecency, frecencyTransformer(fr, func() float64 { return cfg.Get().FrecencyWeight }))
+	engine.RegisterTransformer(transformLearned, learnedTransformer(learned, func() bool { return cfg.Get().LearnRanking }))
+	leaderService := NewLeaderService(commandsProvider)
+	layoutService = NewLayoutService(cfg, engine, appsProvider, plat)
+	bg := &background{}
+	settings := configurable{bg: bg, timers: timerService, engine: engine, leader: leaderService, apps: appsProvider, files: filesProvider, grep: grepProvider, todo: todoProvider, repos: reposProvider, download: downloadsProvider, archive: archivesProvider, mail: mailProvider, docker: dockerProvider, smart: smartProvider, ssh: sshProvider, generate: generateProvider, network: netClient, rates: rates, currency: currencyProvider, calc: calcProvider, matcher: matcher, links: quicklinksProvider, layouts: layoutsProvider, power: &powerState{plat: plat}, caches: caches}
+	settings.apply(cfg.Get())
+	auditLog, err := openAudit(cfg.Get().Audit)
+	if err != nil {
+		log.Println(err)
+	}
+	hist, err := openHistory()
+	if err != nil {
+		log.Println(err)
+	}
+	greetService = NewGreetService(engine, cfg, fr, learned, appEvents{}, auditLog, hist, platformFeedback{plat: plat, config: cfg}, newIdleHider(func() { window.Hide() }, func() { showWindow(appEvents{}) }), noResults{plat: plat}, output, appClipboard{}, func() { applyDisplayAppearance(cfg.Get()) })
+
+	registerCommands(commandsProvider, cfg, plat, settings)
+	registerRestartCommand(commandsProvider, greetService)
+	// updateItem is the tray item shown once an update is found.
+	var updateItem *application.MenuItem
+	updateService := NewUpdateService(netClient, cfg, func(info UpdateInfo) {
+		if updateItem != nil {
+			updateItem.SetLabel("Update Available: " + info.Latest)
+			updateItem.SetHidden(false)
+		}
+	})
+	registerUpdateCommand(commandsProvider, updateService, plat, notificationService)
+	settingsService := NewSettingsService(cfg, fr, hist, clip, settings.apply)
 
 	// Create a new Wails application by providing the necessary options.
 	// Variables 'Name' and 'Description' are for application metadata.
@@ -38,7 +224,14 @@ func main() {
 		Name:        "prism-go",
 		Description: "A demo of using raw HTML & CSS",
 		Services: []application.Service{
-			application.NewService(&GreetService{}),
+			application.NewService(greetService),
+			application.NewService(timerService),
+			application.NewService(notificationService),
+			application.NewService(settingsService),
+			application.NewService(leaderService),
+			application.NewService(updateService),
+			application.NewService(layoutService),
+			application.NewService(NewDiagnosticsService(caches, func() []DisplayDiagnostics { return displayDiagnostics(cfg.Get()) })),
 		},
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
@@ -51,7 +244,8 @@ func main() {
 
 	// Create a new window with the necessary options.
 	// 'Title' is the title of the window.
-	// 'Mac' options tailor the window when running on macOS.
+	// 'Mac' options tailor the window when running on macOS and are ignored on
+	// other platforms; 'Linux' options do the same for Linux.
 	// 'BackgroundColour' is the background colour of the window.
 	// 'URL' is the URL that will be loaded into the webview.
 	window = app.NewWebviewWindowWithOptions(application.WebviewWindowOptions{
@@ -62,12 +256,17 @@ func main() {
 			TitleBar:                application.MacTitleBarHiddenInset,
 			WindowLevel:             application.MacWindowLevelFloating,
 			InvisibleTitleBarHeight: 50,
+			DisableShadow:           !cfg.Get().WindowShadow,
+		},
+		Linux: application.LinuxWindow{
+			WindowIsTranslucent: true,
 		},
 		URL:              "/",
 		BackgroundColour: application.NewRGBA(0, 0, 0, 0),
 		// BackgroundType:   application.BackgroundTypeTransparent,
-		Width:         600,
-		Height:        50,
+		Hidden:        true,
+		Width:         cfg.Get().WindowWidth,
+		Height:        cfg.Get().WindowHeight,
 		DisableResize: true,
 		KeyBindings: map[string]func(window *application.WebviewWindow){
 			"escape": func(window *application.WebviewWindow) {
@@ -87,21 +286,121 @@ func main() {
 	}
 
 	myMenu := app.NewMenu()
-	myMenu.Add("Hello World!").OnClick(func(_ *application.Context) {
-		app.NewWebviewWindowWithOptions((application.WebviewWindowOptions{
-			Title: "Hello",
-			URL:   "/#/page1",
-		}))
+	// The show item keeps the launcher reachable when the global hotkey
+	// could not be registered.
+	trayItems := checkTrayMenu(cfg.Get().TrayMenu, commandsProvider.Has)
+	var pauseItem *application.MenuItem
+	updateItem, pauseItem = buildTrayMenu(myMenu, trayItems, bg.Paused(), trayHandlers{
+		show: func() { toggleWindow(plat, cfg, greetService) },
+		pause: func() {
+			paused := !bg.Paused()
+			if err := cfg.Update(func(c *config.Config) { c.Paused = paused }); err != nil {
+				log.Println(err)
+				return
+			}
+			settings.applyPause(cfg.Get())
+		},
+		update: func() {
+			if err := plat.Open(context.Background(), updateService.LastCheck().URL); err != nil {
+				log.Println(err)
+			}
+		},
+		command: commandsProvider.Run,
+		open:    plat.Open,
 	})
+	if pauseItem != nil {
+		bg.onChange = func(paused bool) { pauseItem.SetLabel(pauseLabel(paused)) }
+	}
 	systemTray.SetMenu(myMenu)
+	// Linux tray hosts treat the icon as a menu and never report a left
+	// click, so only macOS and Windows can be told what one does. Windows
+	// does nothing on a left click unless told.
+	if runtime.GOOS != "linux" {
+		systemTray.OnClick(func() {
+			onTrayLeftClick(cfg.Get().TrayLeftClick, func() { toggleWindow(plat, cfg, greetService) }, systemTray.OpenMenu)
+		})
+	}
+
+	window.OnWindowEvent(events.Common.WindowShow, func(e *application.WindowEvent) {
+		greetService.BeginSession()
+		applyDisplayAppearance(cfg.Get())
+	})
+
+	window.OnWindowEvent(events.Common.WindowHide, func(e *application.WindowEvent) {
+		greetService.EndSession()
+	})
+
+	window.OnWindowEvent(events.Common.WindowFocus, func(e *application.WindowEvent) {
+		go notificationService.RefreshFocus()
+	})
 
 	window.OnWindowEvent(events.Common.WindowLostFocus, func(e *application.WindowEvent) {
-		window.Hide()
+		if !greetService.HoldsOpen() {
+			window.Hide()
+		}
+	})
+
+	// A window moved to a display with a different scale factor keeps the
+	// pixel size it had, so it is resized and restyled for its new display.
+	resize := func(e *application.WindowEvent) {
+		if window.IsVisible() {
+			placeWindow(plat, cfg)
+			applyDisplayAppearance(cfg.Get())
+		}
+	}
+	window.OnWindowEvent(events.Common.WindowDPIChanged, resize)
+	// The window has no title bar; the frontend marks the handle at the
+	// start of the search bar with --wails-draggable, and wherever a drag
+	// leaves the window is saved for the last-position placement. Moves
+	// placeWindow makes arrive here too, and are told apart by where they
+	// leave the window.
+	window.OnWindowEvent(events.Common.WindowDidMove, func(e *application.WindowEvent) {
+		if !window.IsVisible() {
+			return
+		}
+		x, y := window.Position()
+		p := windowstate.Position{X: x, Y: y}
+		if last := placed.Load(); last != nil && *last == p {
+			return
+		}
+		windowState.Moved(p)
+	})
+	window.OnWindowEvent(events.Mac.WindowDidChangeScreen, resize)
+	window.OnWindowEvent(events.Common.WindowDidResize, func(e *application.WindowEvent) {
+		if window.IsVisible() && cfg.Get().RememberWindowSize {
+			windowState.Resized(logicalSize(window.Size()))
+		}
+	})
+
+	// Rearranging displays or changing one's resolution can change the
+	// scale factor of the one the window is on without moving it.
+	app.OnApplicationEvent(events.Mac.ApplicationDidChangeScreenParameters, func(e *application.ApplicationEvent) {
+		applyDisplayAppearance(cfg.Get())
+	})
+
+	app.OnApplicationEvent(events.Common.ApplicationStarted, func(e *application.ApplicationEvent) {
+		applyDisplayAppearance(cfg.Get())
+		bg.Add("clipboard", func(ctx context.Context) error { clip.Run(ctx); return nil })
+		bg.Add("relaunch", func(ctx context.Context) error { relaunchProvider.Run(ctx); return nil })
+		bg.Add("apps watcher", func(ctx context.Context) error { appsProvider.Watch(ctx); return nil })
+		bg.Add("files watcher", func(ctx context.Context) error { filesProvider.Watch(ctx); return nil })
+		bg.Add("downloads watcher", func(ctx context.Context) error { downloadsProvider.Watch(ctx); return nil })
+		bg.Add("update check", func(ctx context.Context) error { updateService.run(ctx); return nil })
+		bg.Start()
+		if showOnStartup(cfg.Get(), os.Args[1:]) {
+			toggleWindow(plat, cfg, greetService)
+		}
+	})
+
+	app.OnShutdown(func() {
+		archivesProvider.Close()
+		auditLog.Close()
 	})
 
-	go handleHotkey()
+	go handleHotkey(plat, cfg, greetService, notificationService)
+	go handleModeHotkeys(plat, cfg, engine, greetService)
 	// Run the application. This blocks until the application has been exited.
-	err := app.Run()
+	err = app.Run()
 
 	// If an error occurred while running the application, log it and exit.
 	if err != nil {
@@ -109,24 +408,488 @@ func main() {
 	}
 }
 
-func handleHotkey() {
-	showHideHotkey := hotkey.New([]hotkey.Modifier{hotkey.ModOption}, hotkey.KeySpace)
+// handleHotkey toggles the launcher on the global hotkey. When the hotkey
+// cannot be registered, as under Wayland or without a display server, the
+// user is told once to use the tray menu instead.
+func handleHotkey(plat platform.Platform, cfg *config.Store, greetService *GreetService, notifier Notifier) {
+	showHideHotkey := hotkey.New(showHideModifiers, hotkey.KeySpace)
 	if err := showHideHotkey.Register(); err != nil {
 		log.Println(err)
+		if err := notifier.Notify("Prism Hotkey Unavailable", "The global shortcut could not be registered. Use Show Prism in the tray menu instead."); err != nil {
+			log.Println(err)
+		}
 		return
 	}
 
-	go func() {
+	supervise("show/hide hotkey", func(ctx context.Context) error {
 		for range showHideHotkey.Keydown() {
-			// log.Println("Pressed")
-			if window.IsVisible() {
-				window.Hide()
-				log.Println(window.IsFocused())
-			} else {
-				window.Show()
-				window.Focus()
-				log.Println(window.IsFocused())
+			toggleWindow(plat, cfg, greetService)
+		}
+		return nil
+	})
+	handleRepeatHotkey(plat, cfg, greetService)
+}
+
+// handleRepeatHotkey repeats the last launch on the configured RepeatHotkey.
+// When it cannot be repeated, the launcher is shown with the reason.
+func handleRepeatHotkey(plat platform.Platform, cfg *config.Store, greetService *GreetService) {
+	shortcut := cfg.Get().RepeatHotkey
+	if shortcut == "" {
+		return
+	}
+	mods, key, err := parseHotkey(shortcut)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	repeatHotkey := hotkey.New(mods, key)
+	if err := repeatHotkey.Register(); err != nil {
+		log.Println(err)
+		return
+	}
+	supervise("repeat hotkey", func(ctx context.Context) error {
+		for range repeatHotkey.Keydown() {
+			// Pasting results go to the app in front now.
+			greetService.captureFrontmostApp(plat)
+			if err := greetService.RepeatLast(ctx); err != nil {
+				log.Println(err)
+				if !window.IsVisible() {
+					placeWindow(plat, cfg)
+					showWindow(greetService.events)
+				}
+				greetService.showRepeatFailed(err)
+			}
+		}
+		return nil
+	})
+}
+
+// onTrayLeftClick runs the configured left-click behavior of the tray icon:
+// toggling the launcher or opening the menu.
+func onTrayLeftClick(mode string, toggle, openMenu func()) {
+	if mode == config.TrayLeftClickToggleWindow {
+		toggle()
+		return
+	}
+	openMenu()
+}
+
+// hiddenFlag starts Prism without showing the launcher even when
+// ShowOnStartup is set, so a login item does not open it on every login.
+const hiddenFlag = "--hidden"
+
+// showOnStartup reports whether the launcher is shown once the app has
+// started.
+func showOnStartup(c config.Config, args []string) bool {
+	return c.ShowOnStartup && !slices.Contains(args, hiddenFlag)
+}
+
+// toggleWindow hides the launcher if it is visible and otherwise shows it
+// in front of the current application.
+func toggleWindow(plat platform.Platform, cfg *config.Store, greetService *GreetService) {
+	toggle(window.IsVisible(), func() { window.Hide() }, func() {
+		greetService.captureFrontmostApp(plat)
+		placeWindow(plat, cfg)
+		showWindow(greetService.events)
+	})
+}
+
+// toggle calls hide when the launcher is visible and show otherwise.
+func toggle(visible bool, hide, show func()) {
+	if visible {
+		hide()
+		return
+	}
+	show()
+}
+
+// placeWindow sizes the launcher for the display it is about to be shown
+// on and centers it there, or moves it to where it was last dragged when
+// that placement is configured and the spot is still on a screen.
+func placeWindow(plat platform.Platform, cfg *config.Store) {
+	c := cfg.Get()
+	width, height := windowSize(c, windowState)
+	plat.PlaceWindow(launcherWindow{window}, width, height)
+	if c.WindowPlacement == config.WindowPlacementLastPosition {
+		if p, ok := windowState.Position(); ok && onScreen(p) {
+			window.SetPosition(p.X, p.Y)
+		}
+	}
+	x, y := window.Position()
+	placed.Store(&windowstate.Position{X: x, Y: y})
+}
+
+// windowSize returns the size the launcher opens at: the one it last had
+// when RememberWindowSize is set and one was saved, and otherwise the
+// configured size.
+func windowSize(c config.Config, state *windowstate.Store) (width, height int) {
+	if c.RememberWindowSize {
+		if sz, ok := state.Size(); ok {
+			return sz.Width, sz.Height
+		}
+	}
+	return c.WindowWidth, c.WindowHeight
+}
+
+// logicalSize converts the window size Wails reports to logical pixels.
+// Windows reports physical pixels, scaled by the display's DPI; the other
+// platforms report logical ones already.
+func logicalSize(width, height int) windowstate.Size {
+	if runtime.GOOS == "windows" {
+		if s, err := window.GetScreen(); err == nil && s.Scale > 0 {
+			width, height = int(float32(width)/s.Scale+0.5), int(float32(height)/s.Scale+0.5)
+		}
+	}
+	return windowstate.Size{Width: width, Height: height}
+}
+
+// onScreen reports whether p lies within a connected display, so a window
+// last left on a display that has since been unplugged opens centered.
+func onScreen(p windowstate.Position) bool {
+	screens, err := application.Get().GetScreens()
+	if err != nil {
+		return false
+	}
+	for _, s := range screens {
+		b := s.Bounds
+		if p.X >= b.X && p.X < b.X+b.Width && p.Y >= b.Y && p.Y < b.Y+b.Height {
+			return true
+		}
+	}
+	return false
+}
+
+func openWindowState() (*windowstate.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		s, _ := windowstate.Open("")
+		return s, err
+	}
+	return windowstate.Open(filepath.Join(dir, "window-state.json"))
+}
+
+// launcherWindow adapts the Wails window, whose SetSize returns the window
+// for chaining, to platform.Window.
+type launcherWindow struct {
+	*application.WebviewWindow
+}
+
+func (w launcherWindow) SetSize(width, height int) {
+	w.WebviewWindow.SetSize(width, height)
+}
+
+// configurable holds the components whose settings are applied at startup
+// and again when the config is reloaded. The fuzzy matcher and the window
+// keep the settings they started with.
+type configurable struct {
+	bg       *background
+	timers   *TimerService
+	engine   *search.Engine
+	leader   *LeaderService
+	apps     *apps.Provider
+	files    *files.Provider
+	grep     *grep.Provider
+	todo     *todo.Provider
+	repos    *repos.Provider
+	download *downloads.Provider
+	archive  *archives.Provider
+	mail     *mail.Provider
+	docker   *docker.Provider
+	smart    *smartactions.Provider
+	ssh      *ssh.Provider
+	generate *generate.Provider
+	network  *network.Client
+	rates    *convert.HTTPRates
+	currency *currency.Provider
+	calc     *calc.Provider
+	matcher  *fuzzy.Matcher
+	links    *quicklinks.Provider
+	layouts  *layouts.Provider
+	power    *powerState
+	caches   *lru.Budget
+}
+
+// configLocale returns the locale set in cfg, or the system's.
+func configLocale(cfg config.Config) locale.Locale {
+	if cfg.Locale != "" {
+		return locale.Lookup(cfg.Locale)
+	}
+	return locale.System()
+}
+
+// compileRewrites compiles the configured query rewrites, leaving out and
+// logging those whose pattern does not compile.
+func compileRewrites(rules []config.QueryRewrite) []search.Rewrite {
+	var out []search.Rewrite
+	for i, r := range rules {
+		rw, err := search.CompileRewrite(r.Pattern, r.Replacement)
+		if err != nil {
+			log.Printf("config: queryRewrites[%d]: %v", i, err)
+			continue
+		}
+		out = append(out, rw)
+	}
+	return out
+}
+
+// todoRoots returns the folders the todo provider scans: the project
+// folders or, when there are none, the repository folders.
+func todoRoots(cfg config.Config) []string {
+	if len(cfg.ProjectDirs) > 0 {
+		return cfg.ProjectDirs
+	}
+	roots := make([]string, 0, len(cfg.RepoDirs))
+	for _, r := range cfg.RepoDirs {
+		roots = append(roots, r.Path)
+	}
+	return roots
+}
+
+// applyPause pauses or resumes background activity, network requests and,
+// when configured, timer notifications.
+func (c configurable) applyPause(cfg config.Config) {
+	c.network.SetPaused(cfg.Paused)
+	c.timers.SetPaused(cfg.Paused && cfg.PauseTimers)
+	c.bg.SetPaused(cfg.Paused)
+}
+
+func (c configurable) apply(cfg config.Config) {
+	checkDefaultActions(cfg.DefaultActions)
+	c.matcher.SetMinScore(cfg.MinMatchScore)
+	c.apps.SetRoots(cfg.AppDirs, cfg.IndexIgnore)
+	c.apps.SetKeywords(cfg.AppKeywords)
+	c.apps.SetPreferSystem(cfg.AppDuplicates != config.AppDuplicatesShowAll)
+	c.files.SetRoots(cfg.FileDirs, cfg.IndexIgnore)
+	c.files.SetShowHidden(cfg.ShowHiddenFiles)
+	c.grep.SetRoots(cfg.ProjectDirs)
+	c.grep.SetFilter(cfg.ShowHiddenFiles, cfg.IndexIgnore)
+	c.grep.SetEditor(cfg.Editor)
+	c.todo.SetRoots(todoRoots(cfg))
+	c.todo.SetMarkers(cfg.TodoMarkers)
+	c.todo.SetEditor(cfg.Editor)
+	c.repos.SetRoots(cfg.RepoDirs, cfg.IndexIgnore)
+	c.repos.SetEditor(cfg.Editor)
+	c.repos.SetTerminal(cfg.Terminal, strings.Fields(cfg.TerminalCommand))
+	c.download.SetDir(cfg.DownloadsDir)
+	c.download.SetFilter(cfg.ShowHiddenFiles, cfg.IndexIgnore)
+	c.archive.SetDir(cfg.DownloadsDir)
+	c.archive.SetEnabled(cfg.IndexArchives)
+	c.mail.SetSearch(cfg.MailSearch)
+	c.caches.SetLimit(int64(cfg.CacheMemoryBudgetMB) << 20)
+	c.smart.SetEnabled(cfg.SuggestClipboard)
+	c.smart.SetDisabled(cfg.SmartActions)
+	c.smart.SetShortener(cfg.URLShortener)
+	c.ssh.SetTerminal(cfg.Terminal, cfg.SSHKnownHosts)
+	c.docker.SetEnabled(cfg.Docker)
+	c.docker.SetTerminal(cfg.Terminal)
+	c.generate.SetPasswords(cfg.PasswordLength, cfg.PasswordPreset, cfg.PasswordPresets)
+	c.network.SetTimeout(time.Duration(cfg.NetworkTimeoutMs) * time.Millisecond)
+	c.applyPause(cfg)
+	c.rates.SetSource(cfg.CurrencyEndpoint, cfg.CurrencyBase)
+	loc := configLocale(cfg)
+	c.currency.SetLocale(loc)
+	c.calc.SetLocale(loc)
+	c.calc.SetAutoEval(cfg.CalcAutoEval)
+	c.links.SetLinks(cfg.Quicklinks)
+	c.layouts.SetLayouts(cfg.LaunchLayouts)
+	c.engine.SetPrefixes(cfg.Prefixes)
+	c.engine.SetFallbackChain(fallbackProviders(cfg.FallbackChain))
+	c.engine.SetRewrites(compileRewrites(cfg.QueryRewrites))
+	c.engine.SetInstantFilter(instantFilter(cfg))
+	c.engine.SetSticky(cfg.StickyResults)
+	c.engine.SetTerseLabels(cfg.TerseAccessibilityLabels)
+	c.engine.SetTiebreak(cfg.Tiebreak)
+	c.leader.SetSequences(cfg.LeaderSequences, time.Duration(cfg.LeaderTimeoutMs)*time.Millisecond)
+	c.engine.SetTimeouts(providerTimeouts(cfg))
+	c.engine.SetPrewarmPolicy(search.PrewarmPolicy{
+		Disabled:       !cfg.Performance.Prewarm,
+		MaxConcurrent:  cfg.Performance.MaxConcurrentIO,
+		PauseOnBattery: cfg.Performance.PauseOnBattery,
+		Power:          c.power,
+	})
+	c.engine.RegisterTransformer(search.TransformTruncate, search.Truncate(cfg.MaxResults, cfg.ProviderLimits))
+	if err := c.engine.SetPipeline(cfg.Pipeline); err != nil {
+		log.Println(err)
+	}
+}
+
+// registerCommands adds Prism's own commands to the catalog.
+func registerCommands(p *commands.Provider, cfg *config.Store, plat platform.Platform, settings configurable) {
+	p.Register(commands.Command{
+		ID:       "settings",
+		Title:    "Open Settings",
+		Subtitle: "Edit Prism's config file",
+		Keywords: []string{"preferences", "config"},
+		Run: func(ctx context.Context) error {
+			if _, err := os.Stat(cfg.Path()); errors.Is(err, fs.ErrNotExist) {
+				// Write the defaults so there is a file to edit.
+				if err := cfg.Update(func(*config.Config) {}); err != nil {
+					return err
+				}
 			}
+			return plat.Open(ctx, cfg.Path())
+		},
+	})
+	p.Register(commands.Command{
+		ID:       "reload-config",
+		Title:    "Reload Config",
+		Subtitle: "Apply changes made to the config file",
+		Run: func(ctx context.Context) error {
+			if err := cfg.Reload(); err != nil {
+				return err
+			}
+			settings.apply(cfg.Get())
+			appEvents{}.EmitEvent(eventLayoutChanged, layoutFor(cfg.Get()))
+			return nil
+		},
+	})
+	p.Register(commands.Command{
+		ID:       "rebuild-index",
+		Title:    "Rebuild Index",
+		Subtitle: "Rescan applications, indexed folders and Git repositories",
+		Keywords: []string{"reindex", "refresh"},
+		Run: func(ctx context.Context) error {
+			settings.apps.Rebuild()
+			settings.files.Rebuild()
+			settings.repos.Rebuild()
+			return nil
+		},
+	})
+	p.Register(commands.Command{
+		ID:       "quit",
+		Title:    "Quit Prism",
+		Subtitle: "Stop Prism and its global shortcut",
+		Keywords: []string{"exit"},
+		Run: func(ctx context.Context) error {
+			application.Get().Quit()
+			return nil
+		},
+	})
+}
+
+// applyWindowAppearance restyles the launcher window, logging failures other
+// than the platform lacking support.
+func applyWindowAppearance(radius float64, shadow bool) {
+	if err := setWindowAppearance(window, radius, shadow); err != nil && !errors.Is(err, platform.ErrUnsupported) {
+		log.Println(err)
+	}
+}
+
+// openConfig opens the user's settings, falling back to defaults when the
+// file is missing or unreadable.
+func openConfig() (*config.Store, error) {
+	path, err := config.Path()
+	if err != nil {
+		return config.Open("")
+	}
+	return config.Open(path)
+}
+
+// openFrecency opens the launch history used to rank results.
+func openFrecency() (*frecency.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return frecency.Open("")
+	}
+	return frecency.Open(filepath.Join(dir, "frecency.json"))
+}
+
+// openInteractions opens the record of picks the ranking learns from.
+func openInteractions() (*interactions.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return interactions.Open("")
+	}
+	return interactions.Open(filepath.Join(dir, "interactions.json"))
+}
+
+// openHistory opens the query history shown by the recent-queries empty
+// state.
+func openHistory() (*history.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return history.Open("")
+	}
+	return history.Open(filepath.Join(dir, "history.json"))
+}
+
+// openTimers restores the timers that were running when Prism last quit.
+func openTimers(notifier Notifier) (*TimerService, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return NewTimerService("", notifier)
+	}
+	return NewTimerService(filepath.Join(dir, "timers.json"), notifier)
+}
+
+// openNetwork returns the client network providers share, with the
+// responses cached when Prism last ran.
+func openNetwork() (*network.Client, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		cache, _ := network.OpenCache("")
+		return network.New(cache), err
+	}
+	cache, err := network.OpenCache(filepath.Join(dir, "network-cache.json"))
+	return network.New(cache), err
+}
+
+// openPlugins loads the approvals for the plugins in the plugins folder.
+func openPlugins(plat platform.Platform, output *paste.Output) (*plugins.Provider, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		grants, _ := plugins.OpenGrants("")
+		return plugins.New("", plat, output, grants), err
+	}
+	grants, err := plugins.OpenGrants(filepath.Join(dir, "plugin-grants.json"))
+	return plugins.New(filepath.Join(dir, "plugins"), plat, output, grants), err
+}
+
+// openAudit starts the audit log when it is enabled; otherwise it returns a
+// nil log, which discards records.
+func openAudit(c config.Audit) (*audit.Log, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	path := c.Path
+	if path == "" {
+		dir, err := config.Dir()
+		if err != nil {
+			return nil, err
 		}
-	}()
+		path = filepath.Join(dir, "audit.log")
+	}
+	return audit.Open(path, int64(c.MaxSizeMB)<<20, c.Keep)
+}
+
+// providerTimeouts converts the configured provider timeouts for the engine.
+func providerTimeouts(c config.Config) (time.Duration, map[string]time.Duration) {
+	per := make(map[string]time.Duration, len(c.ProviderTimeouts))
+	for id, ms := range c.ProviderTimeouts {
+		per[id] = time.Duration(ms) * time.Millisecond
+	}
+	def := time.Duration(c.ProviderTimeoutMs) * time.Millisecond
+	if def <= 0 {
+		def = search.DefaultTimeout
+	}
+	return def, per
+}
+
+// appEvents emits events through the running application.
+type appEvents struct{}
+
+func (appEvents) EmitEvent(name string, data ...any) {
+	application.Get().EmitEvent(name, data...)
+}
+
+// appClipboard is the running application's clipboard. It is resolved on each
+// call because the clipboard is only available once the app is running.
+type appClipboard struct{}
+
+func (appClipboard) Text() (string, bool) {
+	return application.Get().Clipboard().Text()
+}
+
+func (appClipboard) SetText(text string) bool {
+	return application.Get().Clipboard().SetText(text)
 }
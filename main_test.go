@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"changeme/internal/config"
+)
+
+func TestShowOnStartup(t *testing.T) {
+	tests := []struct {
+		show bool
+		args []string
+		want bool
+	}{
+		{false, nil, false},
+		{true, nil, true},
+		{true, []string{"--verbose"}, true},
+		{true, []string{hiddenFlag}, false},
+		{false, []string{hiddenFlag}, false},
+	}
+	for _, tt := range tests {
+		c := config.Default()
+		c.ShowOnStartup = tt.show
+		if got := showOnStartup(c, tt.args); got != tt.want {
+			t.Errorf("showOnStartup(ShowOnStartup: %v, %q) = %v, want %v", tt.show, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestOnTrayLeftClick(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{config.TrayLeftClickToggleWindow, "toggle"},
+		{config.TrayLeftClickMenu, "menu"},
+		// An unknown setting keeps the default, the menu.
+		{"", "menu"},
+		{"bogus", "menu"},
+	}
+	for _, tt := range tests {
+		var got []string
+		onTrayLeftClick(tt.mode, func() { got = append(got, "toggle") }, func() { got = append(got, "menu") })
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("onTrayLeftClick(%q) ran %v, want [%s]", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// actionCopyMarkdown copies a result as a Markdown link. It is offered on
+// results whose target is a URL or a file, and handled by GreetService.
+const actionCopyMarkdown = "prism.copy-markdown"
+
+// markdownEscaper escapes the characters that would end or nest a link's
+// text.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// urlEscaper escapes what would end a link's destination early.
+var urlEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+// markdownLink returns r as "[Title](url)", with files linked by file://
+// URL. It reports false for results without a URL or path target.
+func markdownLink(r search.Result) (string, bool) {
+	u, ok := targetURL(r.Target)
+	if !ok {
+		return "", false
+	}
+	return "[" + markdownEscaper.Replace(r.Title) + "](" + urlEscaper.Replace(u) + ")", true
+}
+
+// targetURL returns target as a URL: unchanged when it already is one, or
+// as a file:// URL when it is an absolute path.
+func targetURL(target string) (string, bool) {
+	if u, err := url.Parse(target); err == nil && u.Scheme != "" && u.Host != "" {
+		return target, true
+	}
+	if !filepath.IsAbs(target) {
+		return "", false
+	}
+	p := filepath.ToSlash(target)
+	if !strings.HasPrefix(p, "/") {
+		// A Windows drive path such as C:/Users.
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String(), true
+}
+
+// CopyMarkdownLink copies a result from the last search to the clipboard
+// as a Markdown link built from its title and target.
+func (g *GreetService) CopyMarkdownLink(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	link, ok := markdownLink(r)
+	if !ok {
+		return fmt.Errorf("markdown: %q has no URL or file to link to", resultID)
+	}
+	return g.out.Deliver(context.Background(), link, config.ClipboardActionCopy)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+func TestMarkdownLink(t *testing.T) {
+	tests := []struct {
+		title, target string
+		want          string
+	}{
+		{"Go", "https://go.dev/doc", "[Go](https://go.dev/doc)"},
+		{"[draft] notes [v2]", "https://example.com/a", `[\[draft\] notes \[v2\]](https://example.com/a)`},
+		{`back\slash`, "https://example.com", `[back\\slash](https://example.com)`},
+		{"Wiki", "https://en.wikipedia.org/wiki/Go_(language)", "[Wiki](https://en.wikipedia.org/wiki/Go_%28language%29)"},
+		{"Report", "/Users/me/My Docs/report (final).pdf", "[Report](file:///Users/me/My%20Docs/report%20%28final%29.pdf)"},
+		{"Hash", "/tmp/a#b?c.txt", "[Hash](file:///tmp/a%23b%3Fc.txt)"},
+	}
+	for _, tt := range tests {
+		got, ok := markdownLink(search.Result{Title: tt.title, Target: tt.target})
+		if !ok || got != tt.want {
+			t.Errorf("markdownLink(%q, %q) = %q, %v; want %q", tt.title, tt.target, got, ok, tt.want)
+		}
+	}
+	for _, target := range []string{"", "relative/path", "com.apple.Safari"} {
+		if got, ok := markdownLink(search.Result{Title: "x", Target: target}); ok {
+			t.Errorf("markdownLink with target %q = %q, want none", target, got)
+		}
+	}
+}
+
+func TestCopyMarkdownOffered(t *testing.T) {
+	g, _ := newTestService(t, nil)
+	results := []search.Result{
+		{ID: "files:/tmp/a.txt", Title: "a.txt", Target: "/tmp/a.txt"},
+		{ID: "calc:4", Title: "4"},
+	}
+	g.decorate(results, g.config.Get())
+	has := func(r search.Result) bool {
+		return slices.ContainsFunc(r.Actions, func(a search.Action) bool { return a.ID == actionCopyMarkdown })
+	}
+	if !has(results[0]) || has(results[1]) {
+		t.Errorf("copy-markdown offered on %v, %v; want only the file", has(results[0]), has(results[1]))
+	}
+}
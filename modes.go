@@ -0,0 +1,81 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+
+	"golang.design/x/hotkey"
+)
+
+// modePrefix returns the query prefix that scopes a search to the provider
+// with ID mode. When several prefixes lead to it, the shortest wins, then
+// the first in sort order.
+func modePrefix(c config.Config, engine *search.Engine, mode string) (string, error) {
+	if _, ok := engine.Provider(mode); !ok {
+		return "", fmt.Errorf("hotkey: unknown mode %q", mode)
+	}
+	var prefixes []string
+	for prefix, id := range c.Prefixes {
+		if id == mode && prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return "", fmt.Errorf("hotkey: mode %q has no prefix", mode)
+	}
+	slices.SortFunc(prefixes, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+	return prefixes[0], nil
+}
+
+// handleModeHotkeys registers the configured ModeHotkeys. Each opens the
+// launcher, if it is hidden, with its mode's prefix as the query. Shortcuts
+// for unknown modes, or ones that cannot be registered, are logged and
+// skipped.
+func handleModeHotkeys(plat platform.Platform, cfg *config.Store, engine *search.Engine, greetService *GreetService) {
+	c := cfg.Get()
+	for shortcut, mode := range c.ModeHotkeys {
+		prefix, err := modePrefix(c, engine, mode)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		mods, key, err := parseHotkey(shortcut)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		hk := hotkey.New(mods, key)
+		if err := hk.Register(); err != nil {
+			log.Println(err)
+			continue
+		}
+		supervise(mode+" hotkey", func(ctx context.Context) error {
+			for range hk.Keydown() {
+				openMode(greetService.events, prefix, func() {
+					if !window.IsVisible() {
+						greetService.captureFrontmostApp(plat)
+						placeWindow(plat, cfg)
+					}
+					showWindow(greetService.events)
+				})
+			}
+			return nil
+		})
+	}
+}
+
+// openMode calls show to bring up the launcher and then has the frontend
+// type prefix in as the query.
+func openMode(events Emitter, prefix string, show func()) {
+	show()
+	events.EmitEvent(eventQuerySet, prefix)
+}
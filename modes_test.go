@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+func TestModePrefix(t *testing.T) {
+	engine := search.NewEngine(&testProvider{id: "files"}, &testProvider{id: "emoji"}, &testProvider{id: "calc"})
+	c := config.Default()
+	c.Prefixes = map[string]string{"f ": "files", "file ": "files", "e ": "emoji", ":": "emoji", "x ": "missing"}
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{"files", "f ", false},
+		// Of prefixes as short, the first in sort order.
+		{"emoji", ":", false},
+		{"calc", "", true},
+		{"missing", "", true},
+	}
+	for _, tt := range tests {
+		got, err := modePrefix(c, engine, tt.mode)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("modePrefix(%q) = %q, %v; want %q, error %v", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestOpenMode(t *testing.T) {
+	events := &recorder{}
+	shown := false
+	openMode(events, "f ", func() {
+		shown = true
+		events.EmitEvent(eventFocusInput)
+	})
+	if !shown {
+		t.Error("the launcher was not shown")
+	}
+	// The prefix is typed in once the window is up.
+	if got, want := events.emitted(), []string{eventFocusInput, eventQuerySet}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if got := events.data[1]; !reflect.DeepEqual(got, []any{"f "}) {
+		t.Errorf("query set to %v, want [f ]", got)
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+// Motion tells the frontend how to animate changes to the results; see
+// ResultsChange for what each change is.
+type Motion struct {
+	// Animate is set when results should move and fade as they change.
+	Animate bool `json:"animate"`
+	// ReduceMotion is set when the system asks apps to reduce motion, as
+	// macOS's Reduce Motion accessibility setting does. Animate is then
+	// off as well.
+	ReduceMotion bool `json:"reduceMotion"`
+}
+
+// Motion returns how result changes should be animated, following the
+// AnimateResults setting and the system's Reduce Motion setting, which is
+// read on every call so a change to it applies the next time the launcher
+// opens.
+func (g *GreetService) Motion() Motion {
+	reduce := systemReduceMotion()
+	return Motion{Animate: g.config.Get().AnimateResults && !reduce, ReduceMotion: reduce}
+}
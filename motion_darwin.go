@@ -0,0 +1,18 @@
+package main
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+#import <Cocoa/Cocoa.h>
+
+static bool prismReduceMotion(void) {
+	return [[NSWorkspace sharedWorkspace] accessibilityDisplayShouldReduceMotion];
+}
+*/
+import "C"
+
+// systemReduceMotion reports whether Reduce Motion is on in the
+// Accessibility settings.
+func systemReduceMotion() bool {
+	return bool(C.prismReduceMotion())
+}
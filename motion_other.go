@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package main
+
+// systemReduceMotion is only read on macOS; elsewhere results animate as
+// AnimateResults says.
+func systemReduceMotion() bool {
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// eventNoResults carries a ResultsUpdate with NoResults set once a search
+// has finished without any provider matching, so the frontend can tell it
+// apart from results still loading.
+const eventNoResults = "results:none"
+
+// Results shown when nothing matches have IDs under noResultsPrefix and are
+// handled by GreetService rather than a provider.
+const (
+	noResultsPrefix    = "prism.no-results:"
+	noResultsWebSearch = noResultsPrefix + "web-search"
+	noResultsCreate    = noResultsPrefix + "create"
+	noResultsMessage   = noResultsPrefix + "message"
+)
+
+// noResults builds and runs the results configured for queries that match
+// nothing.
+type noResults struct {
+	plat platform.Platform
+}
+
+// fallbackProviders returns the provider stages of chain, those before
+// config.FallbackWeb, which always has a result and so ends the cascade.
+func fallbackProviders(chain []string) []string {
+	if i := slices.Index(chain, config.FallbackWeb); i >= 0 {
+		return chain[:i]
+	}
+	return chain
+}
+
+// noResultsMode returns what to show when nothing matches: NoResults, or
+// the web search when a fallback chain is set that ends with it.
+func noResultsMode(cfg config.Config) string {
+	if len(cfg.FallbackChain) == 0 {
+		return cfg.NoResults
+	}
+	if slices.Contains(cfg.FallbackChain, config.FallbackWeb) {
+		return config.NoResultsWebSearch
+	}
+	return config.NoResultsNothing
+}
+
+// results returns what cfg.NoResults, or the fallback chain, shows for
+// query.
+func (n noResults) results(query string, cfg config.Config) []search.Result {
+	query = strings.TrimSpace(query)
+	switch noResultsMode(cfg) {
+	case config.NoResultsWebSearch:
+		return []search.Result{{
+			ID:       noResultsWebSearch,
+			Provider: "prism",
+			Type:     "query",
+			Title:    fmt.Sprintf("Search the Web for “%s”", query),
+			Subtitle: webSearchHost(cfg.WebSearchURL),
+			Target:   webSearchURL(cfg.WebSearchURL, query),
+			Actions:  []search.Action{{ID: "open", Title: "Search"}},
+		}}
+	case config.NoResultsCreate:
+		if strings.ContainsAny(query, `/\`) {
+			return nil
+		}
+		dir := createDir(cfg)
+		return []search.Result{{
+			ID:       noResultsCreate,
+			Provider: "prism",
+			Type:     "file",
+			Title:    fmt.Sprintf("Create “%s”", query),
+			Subtitle: dir,
+			Target:   filepath.Join(dir, query),
+			Actions:  []search.Action{{ID: "create", Title: "Create File"}},
+		}}
+	case config.NoResultsMessage:
+		text := cfg.NoResultsText
+		if text == "" {
+			text = "No Results"
+		}
+		return []search.Result{{ID: noResultsMessage, Provider: "prism", Type: "notice", Title: text}}
+	}
+	return nil
+}
+
+// activate runs the fallback result r.
+func (n noResults) activate(ctx context.Context, r search.Result) error {
+	switch r.ID {
+	case noResultsWebSearch:
+		return n.plat.Open(ctx, r.Target)
+	case noResultsCreate:
+		f, err := os.OpenFile(r.Target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		if f != nil {
+			f.Close()
+		}
+		return n.plat.Open(ctx, r.Target)
+	}
+	// The message has no actions.
+	return nil
+}
+
+func webSearchURL(template, query string) string {
+	if template == "" {
+		template = config.Default().WebSearchURL
+	}
+	return strings.ReplaceAll(template, "{query}", url.QueryEscape(query))
+}
+
+func webSearchHost(template string) string {
+	if u, err := url.Parse(webSearchURL(template, "")); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Host, "www.")
+	}
+	return "Web Search"
+}
+
+func createDir(cfg config.Config) string {
+	if cfg.CreateDir != "" {
+		return cfg.CreateDir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, "Documents")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// lastNoResults returns the latest eventNoResults update emitted.
+func lastNoResults(r *recorder) (ResultsUpdate, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i] == eventNoResults {
+			return r.data[i][0].(ResultsUpdate), true
+		}
+	}
+	return ResultsUpdate{}, false
+}
+
+func TestNoResultsFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(*config.Config)
+		want []string
+	}{
+		{"web search", func(c *config.Config) {
+			c.NoResults = config.NoResultsWebSearch
+			c.WebSearchURL = "https://example.com/search?q={query}"
+		}, []string{noResultsWebSearch}},
+		{"create", func(c *config.Config) {
+			c.NoResults = config.NoResultsCreate
+			c.CreateDir = "/tmp/notes"
+		}, []string{noResultsCreate}},
+		{"message", func(c *config.Config) {
+			c.NoResults = config.NoResultsMessage
+			c.NoResultsText = "Nothing here"
+		}, []string{noResultsMessage}},
+		{"nothing", func(c *config.Config) { c.NoResults = config.NoResultsNothing }, nil},
+		// A fallback chain ending in the web search shows it last.
+		{"chain", func(c *config.Config) {
+			c.NoResults = config.NoResultsMessage
+			c.FallbackChain = []string{"files", config.FallbackWeb}
+		}, []string{noResultsWebSearch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, events := newTestService(t, tt.edit, &testProvider{id: "apps", results: []search.Result{appResult("Mail")}})
+			results := g.Search("zebra crossing")
+			if got := resultIDs(results); !slices.Equal(got, tt.want) {
+				t.Errorf("Search = %v, want %v", got, tt.want)
+			}
+			u, ok := lastNoResults(events)
+			if !ok || !u.NoResults || !u.Done || u.Query != "zebra crossing" {
+				t.Fatalf("no-results event = %+v, %v", u, ok)
+			}
+			if got := resultIDs(u.Results); !slices.Equal(got, tt.want) {
+				t.Errorf("no-results event results = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A search that matches sends no no-results signal.
+	g, events := newTestService(t, nil, &testProvider{id: "apps", results: []search.Result{appResult("Mail")}})
+	g.Search("mail")
+	if u, ok := lastNoResults(events); ok {
+		t.Errorf("no-results event for a match: %+v", u)
+	}
+}
+
+func TestNoResultsContent(t *testing.T) {
+	c := config.Default()
+	c.NoResults = config.NoResultsWebSearch
+	c.WebSearchURL = "https://www.example.com/search?q={query}"
+	var n noResults
+	r := n.results(" cats & dogs ", c)
+	if len(r) != 1 || r[0].Target != "https://www.example.com/search?q=cats+%26+dogs" || r[0].Subtitle != "example.com" || r[0].Title != "Search the Web for “cats & dogs”" {
+		t.Errorf("web search result = %+v", r)
+	}
+
+	c.NoResults = config.NoResultsCreate
+	c.CreateDir = "/tmp/notes"
+	if r := n.results("todo.txt", c); len(r) != 1 || r[0].Target != "/tmp/notes/todo.txt" {
+		t.Errorf("create result = %+v", r)
+	}
+	// A query with a path separator is not a file name.
+	if r := n.results("a/b", c); len(r) != 0 {
+		t.Errorf("create result for a/b = %+v", r)
+	}
+
+	c.NoResults = config.NoResultsMessage
+	if r := n.results("x", c); len(r) != 1 || r[0].Title != "No Results" || r[0].Actions != nil {
+		t.Errorf("default message = %+v", r)
+	}
+}
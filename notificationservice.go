@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+)
+
+// focusCheckInterval is how long a reading of the Focus state is trusted.
+// It is also refreshed whenever the launcher gains focus.
+const focusCheckInterval = time.Minute
+
+// NotificationService shows Prism's own system notifications. While a Focus
+// or Do Not Disturb mode is on they are held back unless the config says
+// otherwise.
+type NotificationService struct {
+	plat   platform.Platform
+	config *config.Store
+
+	mu        sync.Mutex
+	focus     bool
+	checkedAt time.Time
+}
+
+func NewNotificationService(plat platform.Platform, cfg *config.Store) *NotificationService {
+	return &NotificationService{plat: plat, config: cfg}
+}
+
+// Notify shows a notification with the given title and body.
+func (n *NotificationService) Notify(title, body string) error {
+	if n.config.Get().RespectFocusMode && n.focusActive() {
+		return nil
+	}
+	return n.plat.Notify(context.Background(), title, body)
+}
+
+// NotifyCritical shows a notification that gets through an active Focus
+// mode when the config marks timers as critical.
+func (n *NotificationService) NotifyCritical(title, body string) error {
+	if n.config.Get().CriticalTimers {
+		return n.plat.Notify(context.Background(), title, body)
+	}
+	return n.Notify(title, body)
+}
+
+// RefreshFocus reads the Focus state again. It is called when the launcher
+// gains focus, since the user may have changed it while Prism was hidden.
+func (n *NotificationService) RefreshFocus() {
+	active, err := n.plat.FocusActive(context.Background())
+	if err != nil && !errors.Is(err, platform.ErrUnsupported) {
+		log.Println(err)
+	}
+	n.mu.Lock()
+	n.focus = active
+	n.checkedAt = time.Now()
+	n.mu.Unlock()
+}
+
+// focusActive returns the last reading of the Focus state, reading it again
+// when it is older than focusCheckInterval. A state that cannot be read
+// counts as off, so notifications are never lost to an error.
+func (n *NotificationService) focusActive() bool {
+	n.mu.Lock()
+	stale := time.Since(n.checkedAt) > focusCheckInterval
+	n.mu.Unlock()
+	if stale {
+		n.RefreshFocus()
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.focus
+}
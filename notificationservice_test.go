@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+)
+
+// focusPlatform is a platform with a Focus mode that counts the
+// notifications shown; it has no other methods.
+type focusPlatform struct {
+	platform.Platform
+	focus    bool
+	notified int
+}
+
+func (p *focusPlatform) FocusActive(ctx context.Context) (bool, error) { return p.focus, nil }
+
+func (p *focusPlatform) Notify(ctx context.Context, title, body string) error {
+	p.notified++
+	return nil
+}
+
+func TestNotifyFocus(t *testing.T) {
+	tests := []struct {
+		name     string
+		focus    bool
+		respect  bool
+		critical bool
+		want     int // notifications shown by Notify and NotifyCritical
+	}{
+		{"no focus", false, true, false, 2},
+		{"focus", true, true, false, 0},
+		{"focus ignored", true, false, false, 2},
+		{"critical timers", true, true, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := config.Open(filepath.Join(t.TempDir(), "config.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Update(func(c *config.Config) {
+				c.RespectFocusMode = tt.respect
+				c.CriticalTimers = tt.critical
+			}); err != nil {
+				t.Fatal(err)
+			}
+			plat := &focusPlatform{focus: tt.focus}
+			n := NewNotificationService(plat, store)
+			if err := n.Notify("Prism", "Copied"); err != nil {
+				t.Fatal(err)
+			}
+			if err := n.NotifyCritical("Timer", "Done"); err != nil {
+				t.Fatal(err)
+			}
+			if plat.notified != tt.want {
+				t.Errorf("%d notifications shown, want %d", plat.notified, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshFocus(t *testing.T) {
+	store, err := config.Open(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plat := &focusPlatform{focus: true}
+	n := NewNotificationService(plat, store)
+	n.Notify("Prism", "held back")
+	// Focus is turned off while Prism is hidden; the cached reading is
+	// still fresh, so only a refresh notices.
+	plat.focus = false
+	n.Notify("Prism", "still held back")
+	n.RefreshFocus()
+	n.Notify("Prism", "shown")
+	if plat.notified != 1 {
+		t.Errorf("%d notifications shown, want 1", plat.notified)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// backgroundTask is a long-running task, such as the clipboard poller,
+// that pausing stops.
+type backgroundTask struct {
+	name string
+	run  func(context.Context) error
+}
+
+// background runs the tasks that make up Prism's background activity, each
+// supervised, and stops and restarts them together as Prism is paused and
+// resumed. Searching keeps working with whatever they last gathered.
+type background struct {
+	mu       sync.Mutex
+	tasks    []backgroundTask
+	started  bool
+	paused   bool
+	ctx      context.Context // shared by the running tasks
+	cancel   context.CancelFunc
+	onChange func(paused bool)
+}
+
+// Add registers a task, which runs from Start on while not paused.
+func (b *background) Add(name string, run func(context.Context) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := backgroundTask{name, run}
+	b.tasks = append(b.tasks, t)
+	if b.started && !b.paused {
+		superviseContext(b.ctx, t.name, t.run)
+	}
+}
+
+// Start runs the registered tasks, unless Prism starts paused.
+func (b *background) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.started = true
+	if !b.paused {
+		b.startLocked()
+	}
+}
+
+// Paused reports whether background activity is paused.
+func (b *background) Paused() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.paused
+}
+
+// SetPaused stops every task, or restarts them all, and reports the change
+// to onChange.
+func (b *background) SetPaused(paused bool) {
+	b.mu.Lock()
+	if paused == b.paused {
+		b.mu.Unlock()
+		return
+	}
+	b.paused = paused
+	if b.started {
+		if paused {
+			b.cancel()
+			b.cancel = nil
+		} else {
+			b.startLocked()
+		}
+	}
+	onChange := b.onChange
+	b.mu.Unlock()
+	if onChange != nil {
+		onChange(paused)
+	}
+}
+
+// startLocked supervises every task under a new context. The caller holds
+// b.mu.
+func (b *background) startLocked() {
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	for _, t := range b.tasks {
+		superviseContext(b.ctx, t.name, t.run)
+	}
+}
+
+// pauseLabel is the tray item that pauses and resumes background activity.
+func pauseLabel(paused bool) string {
+	if paused {
+		return "Resume Background Activity"
+	}
+	return "Pause Background Activity"
+}
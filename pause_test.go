@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// poller is a background task that reports each start and stop.
+type poller struct {
+	started chan struct{}
+	stopped chan struct{}
+}
+
+func newPoller() *poller {
+	return &poller{started: make(chan struct{}, 4), stopped: make(chan struct{}, 4)}
+}
+
+func (p *poller) run(ctx context.Context) error {
+	p.started <- struct{}{}
+	<-ctx.Done()
+	p.stopped <- struct{}{}
+	return nil
+}
+
+// expectSignal fails t unless c receives within a second.
+func expectSignal(t *testing.T, c chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatalf("the poller never %s", what)
+	}
+}
+
+// expectNone fails t if c receives within a short while.
+func expectNone(t *testing.T, c chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-c:
+		t.Fatalf("the poller %s", what)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestBackgroundPause(t *testing.T) {
+	var changes []bool
+	b := &background{onChange: func(paused bool) { changes = append(changes, paused) }}
+	clip, watcher := newPoller(), newPoller()
+	b.Add("clipboard", clip.run)
+	expectNone(t, clip.started, "started before Start")
+	b.Start()
+	expectSignal(t, clip.started, "started")
+	b.Add("files watcher", watcher.run)
+	expectSignal(t, watcher.started, "added after Start started")
+
+	b.SetPaused(true)
+	expectSignal(t, clip.stopped, "stopped on pause")
+	expectSignal(t, watcher.stopped, "added after Start stopped on pause")
+	if !b.Paused() {
+		t.Error("Paused() = false after pausing")
+	}
+	expectNone(t, clip.started, "restarted while paused")
+
+	b.SetPaused(false)
+	expectSignal(t, clip.started, "restarted on resume")
+	expectSignal(t, watcher.started, "added after Start restarted on resume")
+	// Resuming again changes nothing.
+	b.SetPaused(false)
+	expectNone(t, clip.started, "started twice")
+
+	b.SetPaused(true)
+	expectSignal(t, clip.stopped, "stopped on the second pause")
+	if want := []bool{true, false, true}; !slices.Equal(changes, want) {
+		t.Errorf("onChange got %v, want %v", changes, want)
+	}
+}
+
+func TestBackgroundStartPaused(t *testing.T) {
+	b := &background{}
+	clip := newPoller()
+	b.SetPaused(true)
+	b.Add("clipboard", clip.run)
+	b.Start()
+	expectNone(t, clip.started, "started while paused")
+	b.SetPaused(false)
+	expectSignal(t, clip.started, "started on resume")
+	b.SetPaused(true)
+	expectSignal(t, clip.stopped, "stopped on pause")
+}
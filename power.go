@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"changeme/internal/platform"
+)
+
+// powerCheckInterval is how long a power source reading is trusted before
+// it is read again.
+const powerCheckInterval = 30 * time.Second
+
+// powerState is the search.PowerState the prewarm policy consults. Reading
+// the power source can take a while, so OnBattery reports the last reading
+// and refreshes it in the background once it is older than
+// powerCheckInterval. Until the first reading it reports mains power.
+type powerState struct {
+	plat platform.Platform
+
+	mu        sync.Mutex
+	onBattery bool
+	checked   time.Time
+	checking  bool
+}
+
+func (p *powerState) OnBattery() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.checking && time.Since(p.checked) > powerCheckInterval {
+		p.checking = true
+		go p.check()
+	}
+	return p.onBattery
+}
+
+func (p *powerState) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	onBattery, err := p.plat.OnBattery(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checking, p.checked = false, time.Now()
+	if err != nil {
+		if !errors.Is(err, platform.ErrUnsupported) {
+			log.Println("power:", err)
+		}
+		return
+	}
+	p.onBattery = onBattery
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"changeme/internal/platform"
+)
+
+// batteryPlatform is a platform that reports a fixed power source.
+type batteryPlatform struct {
+	platform.Platform
+	onBattery bool
+	err       error
+	reads     chan struct{}
+}
+
+func (p *batteryPlatform) OnBattery(ctx context.Context) (bool, error) {
+	defer func() { p.reads <- struct{}{} }()
+	return p.onBattery, p.err
+}
+
+func TestPowerState(t *testing.T) {
+	tests := []struct {
+		name string
+		plat *batteryPlatform
+		want bool
+	}{
+		{"battery", &batteryPlatform{onBattery: true}, true},
+		{"mains", &batteryPlatform{}, false},
+		{"unsupported", &batteryPlatform{onBattery: true, err: platform.ErrUnsupported}, false},
+	}
+	for _, tt := range tests {
+		tt.plat.reads = make(chan struct{}, 2)
+		p := &powerState{plat: tt.plat}
+		// Until the first reading is in, mains power is assumed.
+		if p.OnBattery() {
+			t.Errorf("%s: OnBattery before the first reading = true", tt.name)
+		}
+		select {
+		case <-tt.plat.reads:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: the power source was never read", tt.name)
+		}
+		// Wait for the reading to be stored.
+		for deadline := time.Now().Add(time.Second); ; {
+			p.mu.Lock()
+			done := !p.checking
+			p.mu.Unlock()
+			if done || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if got := p.OnBattery(); got != tt.want {
+			t.Errorf("%s: OnBattery = %v, want %v", tt.name, got, tt.want)
+		}
+		// The reading is trusted for powerCheckInterval.
+		select {
+		case <-tt.plat.reads:
+			t.Errorf("%s: the power source was read again at once", tt.name)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
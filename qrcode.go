@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+
+	"changeme/internal/search"
+)
+
+// actionShowQR shows a URL or text result as a QR code, for getting it onto
+// a phone. It is handled by GreetService, which emits the image as
+// eventResultQR and keeps the launcher open to show it.
+const actionShowQR = "prism.show-qr"
+
+// eventResultQR carries a ResultQR after actionShowQR.
+const eventResultQR = "result:qr"
+
+const (
+	// maxQRBytes is the longest text encoded, well within what a QR code
+	// holds at medium error correction and what a phone reads reliably.
+	maxQRBytes = 2048
+	// qrSize is the width and height of the image, in pixels, large enough
+	// for the UI to show it filling the window.
+	qrSize = 512
+)
+
+var (
+	errQRTooLong = fmt.Errorf("qr: text over %d bytes cannot be shown as a QR code", maxQRBytes)
+	errQREmpty   = errors.New("qr: there is no text to show as a QR code")
+)
+
+// ResultQR is the payload of eventResultQR.
+type ResultQR struct {
+	ResultID string `json:"resultId"`
+	Title    string `json:"title"`
+	// Image is a data URI of a PNG of the QR code.
+	Image string `json:"image"`
+}
+
+// qrText returns what a QR code of r encodes: the URL of a URL result, or
+// the text of a text result.
+func qrText(r search.Result) (string, bool) {
+	var text string
+	switch r.Type {
+	case "url":
+		u, ok := targetURL(r.Target)
+		if !ok {
+			return "", false
+		}
+		text = u
+	case "text":
+		text = r.Title
+	}
+	return text, text != "" && len(text) <= maxQRBytes
+}
+
+// encodeQR returns a data URI of a PNG of a QR code encoding text, which
+// must be valid UTF-8 of at most maxQRBytes.
+func encodeQR(text string) (string, error) {
+	switch {
+	case text == "":
+		return "", errQREmpty
+	case len(text) > maxQRBytes:
+		return "", errQRTooLong
+	case !utf8.ValidString(text):
+		return "", errors.New("qr: text is not valid UTF-8")
+	}
+	png, err := qrcode.Encode(text, qrcode.Medium, qrSize)
+	if err != nil {
+		return "", fmt.Errorf("qr: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
+
+// GenerateQR returns a data URI of a PNG of a QR code encoding text, for
+// the frontend to show.
+func (g *GreetService) GenerateQR(text string) (string, error) {
+	return encodeQR(text)
+}
+
+// showQR emits the QR code of the result with the given ID.
+func (g *GreetService) showQR(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	text, ok := qrText(r)
+	if !ok {
+		return errQREmpty
+	}
+	image, err := encodeQR(text)
+	if err != nil {
+		return err
+	}
+	g.events.EmitEvent(eventResultQR, ResultQR{ResultID: resultID, Title: r.Title, Image: image})
+	return nil
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// decodeQR reads the modules of the QR code in a data URI from encodeQR,
+// sampling the middle of each, as a row of strings of '#' and ' '.
+func decodeQR(t *testing.T, uri string, modules int) []string {
+	t.Helper()
+	data, ok := strings.CutPrefix(uri, "data:image/png;base64,")
+	if !ok {
+		t.Fatalf("%.40s… is not a PNG data URI", uri)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("decoding the PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != qrSize || b.Dy() != qrSize {
+		t.Fatalf("image is %v, want %dx%d", b, qrSize, qrSize)
+	}
+	rows := make([]string, modules)
+	for y := range modules {
+		var row strings.Builder
+		for x := range modules {
+			px := (2*x + 1) * qrSize / (2 * modules)
+			py := (2*y + 1) * qrSize / (2 * modules)
+			if r, _, _, _ := img.At(px, py).RGBA(); r < 0x8000 {
+				row.WriteByte('#')
+			} else {
+				row.WriteByte(' ')
+			}
+		}
+		rows[y] = row.String()
+	}
+	return rows
+}
+
+// qrMasks are the eight data masks of a QR code, by row and column; a
+// module is flipped where its mask is true.
+var qrMasks = [8]func(i, j int) bool{
+	func(i, j int) bool { return (i+j)%2 == 0 },
+	func(i, j int) bool { return i%2 == 0 },
+	func(i, j int) bool { return j%3 == 0 },
+	func(i, j int) bool { return (i+j)%3 == 0 },
+	func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+	func(i, j int) bool { return i*j%2+i*j%3 == 0 },
+	func(i, j int) bool { return (i*j%2+i*j%3)%2 == 0 },
+	func(i, j int) bool { return ((i+j)%2+i*j%3)%2 == 0 },
+}
+
+// qrFormat returns the format information of a code at error correction
+// level M with the given mask: five bits extended with their BCH(15,5)
+// check bits and masked so they are never all light.
+func qrFormat(mask int) int {
+	rem := mask << 10
+	for b := 14; b >= 10; b-- {
+		if rem&(1<<b) != 0 {
+			rem ^= 0x537 << (b - 10)
+		}
+	}
+	return (mask<<10 | rem) ^ 0x5412
+}
+
+// gfMul multiplies a and b in GF(256) as QR codes define it, modulo
+// x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(a, b byte) byte {
+	var p byte
+	for ; b > 0; b >>= 1 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a&0x80 != 0
+		a <<= 1
+		if carry {
+			a ^= 0x1d
+		}
+	}
+	return p
+}
+
+// rsCheck returns the n Reed-Solomon error correction codewords of data.
+func rsCheck(data []byte, n int) []byte {
+	gen := []byte{1}
+	for i, root := 0, byte(1); i < n; i, root = i+1, gfMul(root, 2) {
+		next := make([]byte, len(gen)+1)
+		for k, c := range gen {
+			next[k] ^= c
+			next[k+1] ^= gfMul(c, root)
+		}
+		gen = next
+	}
+	msg := append(append([]byte(nil), data...), make([]byte, n)...)
+	for k := range data {
+		if c := msg[k]; c != 0 {
+			for l, g := range gen {
+				msg[k+l] ^= gfMul(g, c)
+			}
+		}
+	}
+	return msg[len(data):]
+}
+
+// readQR decodes the byte-mode text of a version 1 QR code at error
+// correction level M from its 21 rows of modules, checking its format
+// information and error correction codewords along the way. It follows the
+// specification rather than the encoder, so the two cannot share a mistake.
+func readQR(t *testing.T, rows []string) string {
+	t.Helper()
+	const n = 21
+	dark := func(i, j int) bool { return rows[i][j] == '#' }
+
+	// The format information runs along row 8 and up column 8 beside the
+	// top-left finder, skipping the timing patterns.
+	var format int
+	read := func(i, j int) {
+		format <<= 1
+		if dark(i, j) {
+			format |= 1
+		}
+	}
+	for j := range 6 {
+		read(8, j)
+	}
+	read(8, 7)
+	read(8, 8)
+	read(7, 8)
+	for i := 5; i >= 0; i-- {
+		read(i, 8)
+	}
+	mask := -1
+	for m := range qrMasks {
+		if qrFormat(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %015b is not level M with a mask", format)
+	}
+
+	// Data fills the rest in two-column strips from the bottom right,
+	// alternately upwards and downwards, stepping over the timing column.
+	function := func(i, j int) bool {
+		return i == 6 || j == 6 || i <= 8 && (j <= 8 || j >= n-8) || i >= n-8 && j <= 8
+	}
+	var codewords []byte
+	var cur, bits int
+	up := true
+	for j := n - 1; j > 0; j -= 2 {
+		if j == 6 {
+			j--
+		}
+		for k := range n {
+			i := k
+			if up {
+				i = n - 1 - k
+			}
+			for _, c := range []int{j, j - 1} {
+				if function(i, c) {
+					continue
+				}
+				cur <<= 1
+				if dark(i, c) != qrMasks[mask](i, c) {
+					cur |= 1
+				}
+				if bits++; bits == 8 {
+					codewords = append(codewords, byte(cur))
+					cur, bits = 0, 0
+				}
+			}
+		}
+		up = !up
+	}
+	// Version 1 at level M is one block of 16 data and 10 check codewords.
+	if len(codewords) != 26 {
+		t.Fatalf("read %d codewords, want 26", len(codewords))
+	}
+	data := codewords[:16]
+	if want := rsCheck(data, 10); !bytes.Equal(codewords[16:], want) {
+		t.Fatalf("error correction codewords % x, want % x", codewords[16:], want)
+	}
+
+	// A 4-bit mode, 0100 for bytes, an 8-bit count, then the bytes.
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("mode %04b, want byte mode", data[0]>>4)
+	}
+	count := int(data[0]&0x0f)<<4 | int(data[1]>>4)
+	if count > len(data)-2 {
+		t.Fatalf("count %d overruns the data", count)
+	}
+	text := make([]byte, count)
+	for k := range text {
+		text[k] = data[1+k]<<4 | data[2+k]>>4
+	}
+	return string(text)
+}
+
+func TestGenerateQR(t *testing.T) {
+	// Short enough for a version 1 code: 21 modules and a quiet zone of 4
+	// on each side.
+	const text = "https://go.dev"
+	g, _ := newTestService(t, nil)
+	uri, err := g.GenerateQR(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := decodeQR(t, uri, 29)
+	// The finder pattern of the top-left corner, inside the quiet zone.
+	if got[4][4:11] != "#######" || got[5][4:11] != "#     #" || got[6][4:11] != "# ### #" {
+		t.Fatalf("no finder pattern at the top left:\n%s", strings.Join(got[:12], "\n"))
+	}
+	var modules []string
+	for _, row := range got[4:25] {
+		modules = append(modules, row[4:25])
+	}
+	if decoded := readQR(t, modules); decoded != text {
+		t.Errorf("the code reads %q, want %q", decoded, text)
+	}
+
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"", errQREmpty},
+		{strings.Repeat("a", maxQRBytes+1), errQRTooLong},
+	}
+	for _, tt := range tests {
+		if _, err := g.GenerateQR(tt.text); !errors.Is(err, tt.want) {
+			t.Errorf("GenerateQR(%.10q…) = %v, want %v", tt.text, err, tt.want)
+		}
+	}
+	if _, err := g.GenerateQR("caf\xe9"); err == nil {
+		t.Error("GenerateQR of text that is not UTF-8 succeeded")
+	}
+	if _, err := g.GenerateQR(strings.Repeat("é", maxQRBytes/2)); err != nil {
+		t.Errorf("GenerateQR at the limit: %v", err)
+	}
+}
+
+func TestShowQR(t *testing.T) {
+	results := []search.Result{
+		{ID: "web:docs", Type: "url", Title: "Docs", Target: "https://example.com/docs"},
+		{ID: "calc:1", Type: "text", Title: "42"},
+		{ID: "calc:2", Type: "text", Title: strings.Repeat("9", maxQRBytes+1)},
+		{ID: "files:notes", Type: "file", Title: "notes.md", Target: "notes.md"},
+	}
+	for _, r := range results {
+		_, ok := qrText(r)
+		if want := r.ID == "web:docs" || r.ID == "calc:1"; ok != want {
+			t.Errorf("qrText(%s) offered %v, want %v", r.ID, ok, want)
+		}
+	}
+
+	g, events := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 }, &testProvider{id: "web", results: results})
+	if _, err := g.engine.Search(context.Background(), "docs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Activate("web:docs", actionShowQR); err != nil {
+		t.Fatal(err)
+	}
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	var qr ResultQR
+	for i, name := range events.events {
+		if name == eventResultQR {
+			qr = events.data[i][0].(ResultQR)
+		}
+	}
+	if qr.ResultID != "web:docs" || qr.Title != "Docs" || !strings.HasPrefix(qr.Image, "data:image/png;base64,") {
+		t.Errorf("%s = %+v, want the code of web:docs", eventResultQR, qr)
+	}
+}
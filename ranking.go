@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"changeme/internal/frecency"
+	"changeme/internal/search"
+)
+
+// transformFrecency names the transformer that blends usage into scores.
+const transformFrecency = "frecency"
+
+// frecencyTransformer adds weight × log2(1 + frecency) to each result's
+// match score and re-sorts. The logarithm keeps heavy use from swamping the
+// match: with the default weight of 16, about ten recent launches add 55
+// points, more than the fuzzy prefix boost of 40, while a couple of launches
+// add 25 and never lift a poor match over an exact prefix.
+func frecencyTransformer(fr *frecency.Store, weight func() float64) search.Transformer {
+	return func(tc *search.TransformContext, results []search.Result) []search.Result {
+		w := weight()
+		if w == 0 {
+			return results
+		}
+		now := time.Now()
+		out := append([]search.Result(nil), results...)
+		for i := range out {
+			if f := fr.Score(out[i].ID, now); f > 0 {
+				out[i].Score += w * math.Log2(1+f)
+			}
+		}
+		tc.Sort(out)
+		return out
+	}
+}
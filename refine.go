@@ -0,0 +1,55 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/search"
+)
+
+// RefineResults narrows the results of the last search to those whose title
+// or subtitle fuzzy-matches filter, keeping their order. It works on the
+// results already in memory without asking any provider, so a long list can
+// be filtered as fast as the user types. The last search is left as it was,
+// so shortening the filter brings results back and an empty filter shows
+// them all; searching again ends the refinement.
+func (g *GreetService) RefineResults(filter string) []search.Result {
+	g.idle.Touch()
+	g.mu.Lock()
+	results := slices.Clone(g.lastResults)
+	g.mu.Unlock()
+	cfg := g.config.Get()
+	if filter = strings.TrimSpace(filter); filter != "" {
+		m := fuzzy.New(cfg.Fuzzy)
+		m.SetMinScore(cfg.MinMatchScore)
+		results = slices.DeleteFunc(results, func(r search.Result) bool {
+			return !refines(m, filter, r)
+		})
+	}
+	g.decorate(results, cfg)
+	return results
+}
+
+// refines reports whether r's title or subtitle fuzzy-matches filter.
+func refines(m *fuzzy.Matcher, filter string, r search.Result) bool {
+	_, title := m.Match(filter, r.Title)
+	_, subtitle := m.Match(filter, r.Subtitle)
+	return title || subtitle
+}
+
+// instantFilter returns the filter the engine narrows the empty state with
+// as a query is typed, matching it the way RefineResults does, or nil when
+// cfg turns this off.
+func instantFilter(cfg config.Config) search.InstantFilter {
+	if !cfg.InstantFilter {
+		return nil
+	}
+	m := fuzzy.New(cfg.Fuzzy)
+	m.SetMinScore(cfg.MinMatchScore)
+	return func(query string, r search.Result) bool {
+		query = strings.TrimSpace(query)
+		return query != "" && refines(m, query, r)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+func TestRefineResults(t *testing.T) {
+	apps := &searchCounter{id: "apps"}
+	g, _ := newTestService(t, nil, apps)
+	mail, maps, calendar := appResult("Mail"), appResult("Maps"), appResult("Calendar")
+	g.lastResults = []search.Result{calendar, mail, maps}
+
+	tests := []struct {
+		filter string
+		want   []search.Result
+	}{
+		{"map", []search.Result{maps}},
+		// Shortening the filter brings results back.
+		{"ma", []search.Result{mail, maps}},
+		{"", []search.Result{calendar, mail, maps}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		got := resultIDs(g.RefineResults(tt.filter))
+		if want := resultIDs(tt.want); !slices.Equal(got, want) {
+			t.Errorf("RefineResults(%q) = %v, want %v", tt.filter, got, want)
+		}
+	}
+	if n := apps.searched.Load(); n != 0 {
+		t.Errorf("providers searched %d times, want none", n)
+	}
+	if got := resultIDs(g.lastResults); len(got) != 3 {
+		t.Errorf("last results = %v, want them unchanged", got)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// repeatFailedID is the ID of the notice shown when the last launch cannot
+// be repeated.
+const repeatFailedID = "prism.repeat.failed"
+
+// errNothingToRepeat is returned by RepeatLast before anything has been
+// launched.
+var errNothingToRepeat = errors.New("repeat: nothing has been launched yet")
+
+// launch is an activated result and the action it was activated with.
+type launch struct {
+	result search.Result
+	action string
+}
+
+// remember makes r, activated with actionID, the launch RepeatLast repeats.
+func (g *GreetService) remember(r search.Result, actionID string) {
+	g.mu.Lock()
+	g.lastLaunch = &launch{result: r, action: actionID}
+	g.mu.Unlock()
+}
+
+// RepeatLast runs the action of the most recently activated result again,
+// without showing the launcher. The result is rebuilt by its provider when
+// it can be, so one that no longer exists, such as an uninstalled app or a
+// deleted file, is reported as an error rather than run.
+func (g *GreetService) RepeatLast(ctx context.Context) error {
+	g.mu.Lock()
+	last := g.lastLaunch
+	g.mu.Unlock()
+	if last == nil {
+		return errNothingToRepeat
+	}
+	r, err := g.current(ctx, last.result)
+	if err != nil {
+		return err
+	}
+	p, ok := g.engine.Provider(r.Provider)
+	if !ok {
+		return fmt.Errorf("repeat: provider %q not registered", r.Provider)
+	}
+	err = p.Activate(ctx, r, last.action)
+	g.auditAction(r, last.action, err)
+	if err != nil {
+		return err
+	}
+	g.feedback.Activated()
+	if err := g.frecency.Record(r.ID, time.Now()); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+// current returns r as it is now, or an error when it no longer exists.
+func (g *GreetService) current(ctx context.Context, r search.Result) (search.Result, error) {
+	if p, ok := g.engine.Provider(r.Provider); ok {
+		if _, ok := p.(search.Resolver); ok {
+			fresh, ok := g.engine.Resolve(ctx, r.ID)
+			if !ok {
+				return search.Result{}, fmt.Errorf("repeat: %s no longer exists", r.Title)
+			}
+			return fresh, nil
+		}
+	}
+	if filepath.IsAbs(r.Target) {
+		if _, err := os.Stat(r.Target); err != nil {
+			return search.Result{}, fmt.Errorf("repeat: %s no longer exists", r.Title)
+		}
+	}
+	return r, nil
+}
+
+// showRepeatFailed shows why the last launch could not be repeated in place
+// of results.
+func (g *GreetService) showRepeatFailed(err error) {
+	g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{
+		Results: []search.Result{{
+			ID:       repeatFailedID,
+			Type:     "notice",
+			Title:    "Cannot Repeat the Last Launch",
+			Subtitle: err.Error(),
+		}},
+		Done: true,
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"changeme/internal/search"
+)
+
+func TestRepeatLast(t *testing.T) {
+	mail := appResult("Mail")
+	apps := &testProvider{id: "apps", results: []search.Result{mail}}
+	g, events := newTestService(t, nil, apps)
+	if err := g.RepeatLast(context.Background()); !errors.Is(err, errNothingToRepeat) {
+		t.Errorf("RepeatLast before a launch = %v, want errNothingToRepeat", err)
+	}
+
+	if _, err := g.engine.Search(context.Background(), "mail"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RunAction(mail.ID, "reveal", false); err != nil {
+		t.Fatal(err)
+	}
+	// A new search does not change what is repeated.
+	if _, err := g.engine.Search(context.Background(), "zzz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RepeatLast(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{mail.ID + " reveal", mail.ID + " reveal"}
+	if got := apps.ran(); !slices.Equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+
+	// Once the app is uninstalled, repeating it is an error.
+	apps.results = nil
+	err := g.RepeatLast(context.Background())
+	if err == nil {
+		t.Fatal("RepeatLast of an uninstalled app succeeded")
+	}
+	if got := apps.ran(); len(got) != 2 {
+		t.Errorf("ran %v after the app was uninstalled", got)
+	}
+	g.showRepeatFailed(err)
+	events.mu.Lock()
+	u := events.data[len(events.data)-1][0].(ResultsUpdate)
+	events.mu.Unlock()
+	if len(u.Results) != 1 || u.Results[0].ID != repeatFailedID {
+		t.Errorf("shown %+v, want the repeat-failed notice", u.Results)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"changeme/internal/providers/commands"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// restartFlag carries the process ID of the Prism that started this one
+// while restarting, which this one waits for to exit before starting up.
+const restartFlag = "--restart-of="
+
+// restartWait bounds waiting for the previous Prism to exit, in case it
+// hangs while shutting down.
+const restartWait = 10 * time.Second
+
+// restarter relaunches Prism. Its functions are those of the process and
+// the application, and are fields so they can be replaced.
+type restarter struct {
+	// executable returns the path of the running binary.
+	executable func() (string, error)
+	// start launches path with args without waiting for it.
+	start func(path string, args []string) error
+	// quit shuts the application down, running its shutdown hooks.
+	quit func()
+}
+
+// appRestarter restarts the running Prism.
+var appRestarter = restarter{
+	executable: os.Executable,
+	start: func(path string, args []string) error {
+		cmd := exec.Command(path, args...)
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		return cmd.Process.Release()
+	},
+	quit: func() { application.Get().Quit() },
+}
+
+// restart launches a new copy of the binary with args, told to wait for
+// this process, then quits. Nothing is quit when the new copy cannot be
+// started.
+func (r restarter) restart(args []string, pid int) error {
+	path, err := r.executable()
+	if err != nil {
+		return fmt.Errorf("restart: finding Prism: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if err := r.start(path, restartArgs(args, pid)); err != nil {
+		return fmt.Errorf("restart: starting %s: %w", path, err)
+	}
+	r.quit()
+	return nil
+}
+
+// restartArgs returns args for the new copy: the same as this one's, with
+// restartFlag naming this process in place of any earlier one.
+func restartArgs(args []string, pid int) []string {
+	out := make([]string, 0, len(args)+1)
+	for _, a := range args {
+		if !strings.HasPrefix(a, restartFlag) {
+			out = append(out, a)
+		}
+	}
+	return append(out, restartFlag+strconv.Itoa(pid))
+}
+
+// Restart relaunches Prism, for changes that only apply on startup. The
+// new copy waits for this one to finish shutting down, so the hotkeys and
+// files it holds are free by the time the new copy starts.
+func (g *GreetService) Restart() error {
+	return appRestarter.restart(os.Args[1:], os.Getpid())
+}
+
+// registerRestartCommand adds the command that restarts Prism.
+func registerRestartCommand(p *commands.Provider, greetService *GreetService) {
+	p.Register(commands.Command{
+		ID:       "restart",
+		Title:    "Restart Prism",
+		Subtitle: "Quit and start again, applying changes that need a restart",
+		Keywords: []string{"relaunch"},
+		Run:      func(ctx context.Context) error { return greetService.Restart() },
+	})
+}
+
+// waitForRestart waits, when this Prism was started by Restart, for the
+// one that started it to exit.
+func waitForRestart(args []string) {
+	var pid int
+	for _, a := range args {
+		if v, ok := strings.CutPrefix(a, restartFlag); ok {
+			pid, _ = strconv.Atoi(v)
+		}
+	}
+	if pid <= 0 {
+		return
+	}
+	for deadline := time.Now().Add(restartWait); time.Now().Before(deadline) && processRunning(pid); {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRestart(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "Prism")
+	if err := os.WriteFile(binary, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "prism")
+	if err := os.Symlink(binary, link); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := filepath.EvalSymlinks(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var started, startArgs []string
+	quits := 0
+	var startErr error
+	r := restarter{
+		executable: func() (string, error) { return link, nil },
+		start: func(path string, args []string) error {
+			started = append(started, path)
+			startArgs = args
+			return startErr
+		},
+		quit: func() { quits++ },
+	}
+	if err := r.restart([]string{"--hidden", restartFlag + "7"}, 42); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{resolved}; !slices.Equal(started, want) {
+		t.Errorf("started %v, want %v", started, want)
+	}
+	if want := []string{"--hidden", restartFlag + "42"}; !slices.Equal(startArgs, want) {
+		t.Errorf("started with %q, want %q", startArgs, want)
+	}
+	if quits != 1 {
+		t.Errorf("quit %d times, want once", quits)
+	}
+
+	// When the new copy cannot start, this one keeps running.
+	startErr = errors.New("exec format error")
+	if err := r.restart(nil, 42); err == nil {
+		t.Error("restart succeeded without starting the new copy")
+	}
+	r.executable = func() (string, error) { return "", errors.New("no executable") }
+	if err := r.restart(nil, 42); err == nil {
+		t.Error("restart succeeded without finding the binary")
+	}
+	if quits != 1 {
+		t.Errorf("quit %d times after failing, want once", quits)
+	}
+}
+
+func TestWaitForRestart(t *testing.T) {
+	// The process of an exited command is not waited for.
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip(err)
+	}
+	start := time.Now()
+	waitForRestart([]string{"--hidden", restartFlag + strconv.Itoa(cmd.Process.Pid)})
+	waitForRestart([]string{"--hidden"})
+	if elapsed := time.Since(start); elapsed > restartWait/2 {
+		t.Errorf("waited %v for processes that are not running", elapsed)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// eventResultPreview carries a result from the last search with its
+// Preview filled in, after OnSelectionChanged.
+const eventResultPreview = "result:preview"
+
+// selectionDebounce is how long the selection must rest on a result before
+// its preview is fetched, so arrowing quickly through the list fetches only
+// the row it stops on.
+const selectionDebounce = 80 * time.Millisecond
+
+// OnSelectionChanged is called by the frontend when the highlighted result
+// changes. Once the selection settles, the result's preview is fetched from
+// its provider and emitted as eventResultPreview. A fetch still running for
+// an earlier selection is cancelled.
+func (g *GreetService) OnSelectionChanged(resultID string) {
+	cfg := g.config.Get()
+	if !cfg.SelectionPreview {
+		return
+	}
+	g.mu.Lock()
+	if g.selectionTimer != nil {
+		g.selectionTimer.Stop()
+	}
+	if g.cancelPreview != nil {
+		g.cancelPreview()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g.cancelPreview = cancel
+	g.selectionTimer = time.AfterFunc(selectionDebounce, func() {
+		g.preview(ctx, resultID)
+	})
+	g.mu.Unlock()
+}
+
+// preview fetches and emits the preview of resultID unless ctx has been
+// cancelled by a newer selection.
+func (g *GreetService) preview(ctx context.Context, resultID string) {
+	r, ok, err := g.engine.Preview(ctx, resultID)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if !ok {
+		return
+	}
+	results := []search.Result{r}
+	g.decorate(results, g.config.Get())
+	g.events.EmitEvent(eventResultPreview, results[0])
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// previewer is a testProvider that previews results, recording which.
+type previewer struct {
+	testProvider
+
+	mu        sync.Mutex
+	previewed []string
+}
+
+func (p *previewer) Preview(ctx context.Context, r search.Result) (search.Result, error) {
+	p.mu.Lock()
+	p.previewed = append(p.previewed, r.ID)
+	p.mu.Unlock()
+	r.Preview = "preview of " + r.Title
+	return r, nil
+}
+
+func TestSelectionPreviewDebounced(t *testing.T) {
+	names := []string{"Mail", "Maps", "Music", "Messages"}
+	var results []search.Result
+	for _, name := range names {
+		results = append(results, appResult(name))
+	}
+	p := &previewer{testProvider: testProvider{id: "apps", results: results}}
+	g, events := newTestService(t, nil, p)
+	if _, err := g.engine.Search(context.Background(), "m"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Arrowing down through the list quickly.
+	for _, r := range results {
+		g.OnSelectionChanged(r.ID)
+		time.Sleep(selectionDebounce / 4)
+	}
+	time.Sleep(4 * selectionDebounce)
+
+	last := results[len(results)-1]
+	p.mu.Lock()
+	previewed := slices.Clone(p.previewed)
+	p.mu.Unlock()
+	if !slices.Equal(previewed, []string{last.ID}) {
+		t.Errorf("previewed %v, want only %s", previewed, last.ID)
+	}
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	var shown []string
+	for i, name := range events.events {
+		if name == eventResultPreview {
+			r := events.data[i][0].(search.Result)
+			shown = append(shown, r.Preview)
+		}
+	}
+	if want := []string{"preview of Messages"}; !slices.Equal(shown, want) {
+		t.Errorf("preview events %q, want %q", shown, want)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/frecency"
+	"changeme/internal/history"
+	"changeme/internal/providers/clipboard"
+)
+
+// bundleVersion is the current settings bundle format. Import accepts
+// bundles up to this version.
+const bundleVersion = 1
+
+// Bundle is everything Export writes: the config, including favorites and
+// keybindings, and the usage data Prism learns over time.
+type Bundle struct {
+	Version  int       `json:"version"`
+	Exported time.Time `json:"exported"`
+	// Config is kept as written, so that fields it lacks keep their
+	// defaults on import.
+	Config   json.RawMessage           `json:"config"`
+	Frecency map[string]frecency.Entry `json:"frecency"`
+	History  []string                  `json:"history"`
+	// Clipboard is present only when the export included it.
+	Clipboard []clipboard.ClipItem `json:"clipboard,omitempty"`
+}
+
+// SettingsService moves settings and data between machines.
+type SettingsService struct {
+	config    *config.Store
+	frecency  *frecency.Store
+	history   *history.Store
+	clipboard *clipboard.Provider
+	// apply makes a restored config take effect.
+	apply func(config.Config)
+}
+
+func NewSettingsService(cfg *config.Store, fr *frecency.Store, hist *history.Store, clip *clipboard.Provider, apply func(config.Config)) *SettingsService {
+	return &SettingsService{config: cfg, frecency: fr, history: hist, clipboard: clip, apply: apply}
+}
+
+// Export returns a JSON bundle of the settings and data. Clipboard history
+// is left out unless includeClipboard is set, since it often holds
+// passwords and other private text.
+func (s *SettingsService) Export(includeClipboard bool) ([]byte, error) {
+	cfg, err := json.Marshal(s.config.Get())
+	if err != nil {
+		return nil, err
+	}
+	b := Bundle{
+		Version:  bundleVersion,
+		Exported: time.Now(),
+		Config:   cfg,
+		Frecency: s.frecency.Entries(),
+		History:  s.history.Recent(history.MaxQueries),
+	}
+	if includeClipboard {
+		b.Clipboard = s.clipboard.Items()
+	}
+	return json.MarshalIndent(b, "", "  ")
+}
+
+// Import restores a bundle made by Export. The current state, clipboard
+// included, is first saved to a backup file in the settings directory, so a
+// mistaken import can be undone by importing that file. Clipboard history
+// is replaced only when the bundle contains one.
+func (s *SettingsService) Import(data []byte) error {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return fmt.Errorf("settings: not a Prism settings bundle: %w", err)
+	}
+	if b.Version < 1 || b.Version > bundleVersion {
+		return fmt.Errorf("settings: unsupported bundle version %d", b.Version)
+	}
+	if err := s.backup(); err != nil {
+		return fmt.Errorf("settings: backing up current settings: %w", err)
+	}
+
+	// Fields the bundle lacks keep their defaults, as when loading a config
+	// file written by an older version.
+	cfg := config.Default()
+	if len(b.Config) > 0 {
+		var err error
+		if cfg, err = config.Parse(b.Config); err != nil {
+			return fmt.Errorf("settings: reading the bundle's config: %w", err)
+		}
+	}
+	if err := s.config.Replace(cfg); err != nil {
+		return err
+	}
+	s.apply(s.config.Get())
+	if err := s.frecency.Replace(b.Frecency); err != nil {
+		return err
+	}
+	if err := s.history.Replace(b.History); err != nil {
+		return err
+	}
+	if b.Clipboard != nil {
+		s.clipboard.Restore(b.Clipboard)
+	}
+	return nil
+}
+
+// backup writes the current state to backups/prism-<time>.json in the
+// settings directory.
+func (s *SettingsService) backup() error {
+	data, err := s.Export(true)
+	if err != nil {
+		return err
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, "backups")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	name := "prism-" + time.Now().Format("20060102-150405") + ".json"
+	return os.WriteFile(filepath.Join(dir, name), data, 0o600)
+}
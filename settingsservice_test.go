@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/frecency"
+	"changeme/internal/fuzzy"
+	"changeme/internal/history"
+	"changeme/internal/providers/clipboard"
+)
+
+// newTestSettings returns a settings service with its stores in dir.
+func newTestSettings(t *testing.T, dir string) *SettingsService {
+	t.Helper()
+	cfg, err := config.Open(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := frecency.Open(filepath.Join(dir, "frecency.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist, err := history.Open(filepath.Join(dir, "history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clip := clipboard.New(nil, nil, fuzzy.Default())
+	return NewSettingsService(cfg, fr, hist, clip, func(config.Config) {})
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	// Import backs up to the settings directory; keep it out of the real
+	// one.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("AppData", home)
+
+	src := newTestSettings(t, t.TempDir())
+	if err := src.config.Update(func(c *config.Config) {
+		c.Favorites = []string{"apps:/Applications/Mail.app"}
+		c.Keybindings["activate"] = "ctrl+o"
+		c.EmptyState = config.EmptyStateRecent
+	}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"apps:/Applications/Mail.app", "apps:/Applications/Mail.app", "apps:/Applications/Notes.app"} {
+		if err := src.frecency.Record(id, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, q := range []string{"old", "new"} {
+		if err := src.history.Add(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src.clipboard.Restore([]clipboard.ClipItem{{Text: "secret", Copied: now}})
+
+	for _, withClipboard := range []bool{false, true} {
+		data, err := src.Export(withClipboard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := newTestSettings(t, t.TempDir())
+		dst.clipboard.Restore([]clipboard.ClipItem{{Text: "kept", Copied: now}})
+		if err := dst.Import(data); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := dst.config.Get(), src.config.Get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("imported config = %+v, want %+v", got, want)
+		}
+		if got, want := dst.frecency.Entries(), src.frecency.Entries(); !reflect.DeepEqual(got, want) {
+			t.Errorf("imported frecency = %v, want %v", got, want)
+		}
+		if got, want := dst.history.Recent(history.MaxQueries), src.history.Recent(history.MaxQueries); !reflect.DeepEqual(got, want) {
+			t.Errorf("imported history = %v, want %v", got, want)
+		}
+		wantClip := "kept"
+		if withClipboard {
+			wantClip = "secret"
+		}
+		if items := dst.clipboard.Items(); len(items) != 1 || items[0].Text != wantClip {
+			t.Errorf("with clipboard %v: clipboard = %+v, want %q", withClipboard, items, wantClip)
+		}
+	}
+
+	backups, err := filepath.Glob(filepath.Join(home, "*", "Prism", "backups", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	more, _ := filepath.Glob(filepath.Join(home, "Prism", "backups", "*.json"))
+	if len(backups)+len(more) == 0 {
+		t.Error("Import made no backup")
+	}
+}
+
+func TestImportRejects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := newTestSettings(t, t.TempDir())
+	for _, data := range []string{"not json", `{"version": 99}`, `{"version": 0}`} {
+		if err := s.Import([]byte(data)); err == nil {
+			t.Errorf("Import(%s) succeeded", data)
+		}
+	}
+	if _, err := os.Stat(s.config.Path()); err == nil {
+		t.Error("a rejected import changed the config")
+	}
+}
+
+func TestImportPartialBundle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := newTestSettings(t, t.TempDir())
+	if err := s.Import([]byte(`{"version": 1, "config": {"emptyState": "recent", "trayMenu": [{"action": "quit"}]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	got, want := s.config.Get(), config.Default()
+	if got.EmptyState != config.EmptyStateRecent {
+		t.Errorf("EmptyState = %q, want the bundle's", got.EmptyState)
+	}
+	// Everything else keeps its default.
+	want.EmptyState = config.EmptyStateRecent
+	want.TrayMenu = []config.TrayItem{{Action: "quit"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imported config = %+v, want the defaults", got)
+	}
+
+	// A bundle without a config restores the defaults.
+	if err := s.Import([]byte(`{"version": 1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.config.Get(); !reflect.DeepEqual(got, config.Default()) {
+		t.Errorf("config from a bundle without one = %+v, want the defaults", got)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// templateField matches a {field} in a subtitle template.
+var templateField = regexp.MustCompile(`\{(\w+)\}`)
+
+// unknownFields remembers the "provider.field" pairs already warned about,
+// so a template is not reported on every keystroke.
+var unknownFields sync.Map
+
+// renderSubtitle fills in template with fields. A field that is not among
+// them renders empty, with a warning logged once per provider.
+func renderSubtitle(template, provider string, fields map[string]string) string {
+	out := templateField.ReplaceAllStringFunc(template, func(m string) string {
+		name := m[1 : len(m)-1]
+		v, ok := fields[name]
+		if !ok {
+			if _, warned := unknownFields.LoadOrStore(provider+"."+name, true); !warned {
+				log.Printf("config: subtitleTemplates[%q]: %s results have no field %q", provider, provider, name)
+			}
+		}
+		return v
+	})
+	return strings.TrimSpace(out)
+}
+
+// applySubtitleTemplates replaces the subtitles of results whose provider
+// has a template in cfg.
+func (g *GreetService) applySubtitleTemplates(results []search.Result, cfg config.Config) {
+	if len(cfg.SubtitleTemplates) == 0 {
+		return
+	}
+	for i, r := range results {
+		if t, ok := cfg.SubtitleTemplates[r.Provider]; ok {
+			results[i].Subtitle = renderSubtitle(t, r.Provider, g.engine.Fields(r))
+		}
+	}
+}
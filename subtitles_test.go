@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/fuzzy"
+	"changeme/internal/platform"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+)
+
+func TestSubtitleTemplates(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	path := filepath.Join(t.TempDir(), "Slack.app")
+	if err := os.MkdirAll(filepath.Join(path, "Contents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plist := `<plist><dict>
+	<key>CFBundleIdentifier</key><string>com.tinyspeck.slackmacgap</string>
+	<key>CFBundleShortVersionString</key><string>4.38.125</string>
+</dict></plist>`
+	if err := os.WriteFile(filepath.Join(path, "Contents", "Info.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plat := &launchPlatform{apps: []platform.App{{Name: "Slack", Path: path}}}
+	g, _ := newTestService(t, nil, apps.New(plat, fuzzy.Default()))
+	found, err := g.engine.Search(context.Background(), "slack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) == 0 {
+		t.Fatal("Slack not found")
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{bundleID} · {version}", "com.tinyspeck.slackmacgap · 4.38.125"},
+		{"{path}", path},
+		{"{name} ({type})", "Slack (app)"},
+		// Slack's Info.plist has no minimum OS, so the field is present
+		// but empty.
+		{"{version} {minimumOS}", "4.38.125"},
+		// An unknown field renders empty.
+		{"{version} {build}", "4.38.125"},
+		{"{build}", ""},
+		{"no fields", "no fields"},
+	}
+	for _, tt := range tests {
+		results := []search.Result{found[0]}
+		cfg := config.Config{SubtitleTemplates: map[string]string{"apps": tt.template, "files": "{title}"}}
+		g.applySubtitleTemplates(results, cfg)
+		if results[0].Subtitle != tt.want {
+			t.Errorf("template %q = %q, want %q", tt.template, results[0].Subtitle, tt.want)
+		}
+	}
+	if n := strings.Count(logs.String(), `no field "build"`); n != 1 {
+		t.Errorf("unknown field warned about %d times, want once:\n%s", n, logs.String())
+	}
+
+	// Without a template for its provider, a subtitle is left alone.
+	results := []search.Result{found[0]}
+	g.applySubtitleTemplates(results, config.Config{SubtitleTemplates: map[string]string{"files": "{title}"}})
+	if results[0].Subtitle != found[0].Subtitle {
+		t.Errorf("subtitle without a template = %q, want %q", results[0].Subtitle, found[0].Subtitle)
+	}
+}
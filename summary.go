@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+
+	"changeme/internal/config"
+	"changeme/internal/search"
+)
+
+// actionCopySummary copies a description of a result, for reporting an
+// issue with it or sharing it. It is offered on every result and handled by
+// GreetService.
+const actionCopySummary = "prism.copy-summary"
+
+// redacted stands in for a value left out of a summary as a likely secret,
+// and redactedURL for one left out of a URL, where brackets would be
+// escaped.
+const (
+	redacted    = "[redacted]"
+	redactedURL = "REDACTED"
+)
+
+// secretProviders are those whose results show what they hold, such as
+// clipboard contents or generated passwords, which a summary leaves out.
+var secretProviders = []string{"clipboard", "generate", "encode"}
+
+// secretWords mark a name, such as an environment variable or a URL query
+// parameter, as likely holding a secret.
+var secretWords = []string{"TOKEN", "SECRET", "KEY", "PASSWORD", "PASSWD", "CREDENTIAL", "AUTH", "SESSION", "SIG"}
+
+// ResultSummary describes a result to someone who cannot see it.
+type ResultSummary struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Provider string   `json:"provider"`
+	ID       string   `json:"id"`
+	Subtitle string   `json:"subtitle,omitempty"`
+	Target   string   `json:"target,omitempty"`
+	Actions  []string `json:"actions,omitempty"`
+}
+
+// summarize describes r, with likely secrets redacted: everything shown by
+// the secretProviders, the details of a result named like a secret, and
+// passwords and secret query parameters in URLs.
+func summarize(r search.Result) ResultSummary {
+	s := ResultSummary{
+		Name:     r.Title,
+		Type:     r.Type,
+		Provider: r.Provider,
+		ID:       r.ID,
+		Subtitle: redactURLs(r.Subtitle),
+		Target:   redactURLs(r.Target),
+	}
+	for _, a := range r.Actions {
+		s.Actions = append(s.Actions, a.Title)
+	}
+	switch {
+	case slices.Contains(secretProviders, r.Provider):
+		s.Name, s.ID = redacted, r.Provider+":"+redacted
+		s.Subtitle, s.Target = redactNonEmpty(s.Subtitle), redactNonEmpty(s.Target)
+	case secretName(r.Title):
+		s.Subtitle, s.Target = redactNonEmpty(s.Subtitle), redactNonEmpty(s.Target)
+	}
+	return s
+}
+
+// Text renders s a field to a line.
+func (s ResultSummary) Text() string {
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			b.WriteString(label + ": " + value + "\n")
+		}
+	}
+	line("Name", s.Name)
+	line("Type", s.Type)
+	line("Provider", s.Provider)
+	line("ID", s.ID)
+	line("Subtitle", s.Subtitle)
+	line("Target", s.Target)
+	line("Actions", strings.Join(s.Actions, ", "))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// secretName reports whether name looks like it holds a secret.
+func secretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, word := range secretWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactNonEmpty(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURLs redacts the password and the secret-named query parameters of
+// the URLs among the words of s.
+func redactURLs(s string) string {
+	words := strings.Fields(s)
+	changed := false
+	for i, w := range words {
+		u, err := url.Parse(w)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redactedURL)
+		}
+		q := u.Query()
+		for name := range q {
+			if secretName(name) {
+				q.Set(name, redactedURL)
+			}
+		}
+		if len(q) > 0 {
+			u.RawQuery = q.Encode()
+		}
+		if r := u.String(); r != w {
+			words[i], changed = r, true
+		}
+	}
+	if !changed {
+		return s
+	}
+	return strings.Join(words, " ")
+}
+
+// CopyResultSummary copies a description of a result from the last search
+// to the clipboard: its name, type, path or URL and actions, with likely
+// secrets redacted. asJSON copies it as a JSON object rather than as text.
+func (g *GreetService) CopyResultSummary(resultID string, asJSON bool) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	s := summarize(r)
+	text := s.Text()
+	if asJSON {
+		var b strings.Builder
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		text = strings.TrimSuffix(b.String(), "\n")
+	}
+	return g.out.Deliver(context.Background(), text, config.ClipboardActionCopy)
+}
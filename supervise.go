@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Restart delays of supervised goroutines. The delay doubles with each
+// failure up to superviseMaxBackoff and starts over once a run has lasted
+// superviseHealthy.
+const (
+	superviseBackoff    = time.Second
+	superviseMaxBackoff = time.Minute
+	superviseHealthy    = time.Minute
+)
+
+// supervise runs fn in a goroutine for the life of the app, restarting it
+// with backoff when it returns an error or panics, so a failing background
+// task such as the clipboard poller cannot take Prism down with it. Panics
+// are logged with their stack. A nil return ends supervision.
+func supervise(name string, fn func(context.Context) error) {
+	superviseContext(context.Background(), name, fn)
+}
+
+// superviseContext is supervise until ctx is cancelled, which fn is passed
+// and must stop on; it is not restarted after that.
+func superviseContext(ctx context.Context, name string, fn func(context.Context) error) {
+	go func() {
+		backoff := superviseBackoff
+		for {
+			start := time.Now()
+			err := runSupervised(ctx, fn)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			if time.Since(start) >= superviseHealthy {
+				backoff = superviseBackoff
+			}
+			log.Printf("supervise: %s failed, restarting in %s: %v", name, backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, superviseMaxBackoff)
+		}
+	}()
+}
+
+// runSupervised calls fn, turning a panic into an error.
+func runSupervised(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v\n%s", v, debug.Stack())
+		}
+	}()
+	return fn(ctx)
+}
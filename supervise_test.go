@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a buffer that is safe to log to from several goroutines.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSuperviseRestartsAfterPanic(t *testing.T) {
+	var logs lockedBuffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runs := make(chan int, 3)
+	n := 0
+	superviseContext(ctx, "poller", func(context.Context) error {
+		n++
+		runs <- n
+		if n == 1 {
+			panic("poller broke")
+		}
+		return nil
+	})
+	for want := 1; want <= 2; want++ {
+		select {
+		case got := <-runs:
+			if got != want {
+				t.Fatalf("run %d, want %d", got, want)
+			}
+		case <-time.After(superviseBackoff + 2*time.Second):
+			t.Fatalf("run %d never started", want)
+		}
+	}
+	out := logs.String()
+	if !strings.Contains(out, "poller failed") || !strings.Contains(out, "poller broke") || !strings.Contains(out, "goroutine") {
+		t.Errorf("log = %q, want the panic and its stack", out)
+	}
+	// A nil return ends supervision.
+	select {
+	case got := <-runs:
+		t.Errorf("run %d after a nil return", got)
+	case <-time.After(superviseBackoff + 100*time.Millisecond):
+	}
+}
+
+func TestSuperviseStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 2)
+	superviseContext(ctx, "watcher", func(ctx context.Context) error {
+		runs <- struct{}{}
+		<-ctx.Done()
+		return errors.New("stopped")
+	})
+	<-runs
+	cancel()
+	select {
+	case <-runs:
+		t.Error("restarted after the context was cancelled")
+	case <-time.After(superviseBackoff + 100*time.Millisecond):
+	}
+}
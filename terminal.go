@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"changeme/internal/search"
+)
+
+// actionOpenTerminal opens the configured terminal in the folder holding a
+// file or app result. It is handled by GreetService.
+const actionOpenTerminal = "prism.open-terminal"
+
+// terminalProviders are the providers whose results are files or apps on
+// disk.
+var terminalProviders = []string{"files", "finder", "grep", "apps"}
+
+// opensInTerminal reports whether r is a file or app whose folder Open in
+// Terminal can start in.
+func opensInTerminal(r search.Result) bool {
+	return slices.Contains(terminalProviders, r.Provider) && filepath.IsAbs(r.Target)
+}
+
+// terminalDir returns the folder a terminal opened on target starts in:
+// target itself when it is a folder, and otherwise the folder holding it.
+// App bundles are folders but count as files.
+func terminalDir(target string) string {
+	if info, err := os.Stat(target); err == nil && info.IsDir() && !isAppBundle(target) {
+		return target
+	}
+	return filepath.Dir(target)
+}
+
+// OpenInTerminal opens the configured terminal in the folder of a file or
+// app result from the last search, running the configured command there or
+// the user's shell.
+func (g *GreetService) OpenInTerminal(resultID string) error {
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	cfg := g.config.Get()
+	return g.fallback.plat.OpenTerminal(context.Background(), cfg.Terminal, terminalDir(r.Target), strings.Fields(cfg.TerminalCommand))
+}
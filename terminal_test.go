@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// terminalPlatform is a platform that records the terminals it opens.
+type terminalPlatform struct {
+	platform.Platform
+	opened []string
+}
+
+func (p *terminalPlatform) OpenTerminal(ctx context.Context, terminal, dir string, args []string) error {
+	p.opened = append(p.opened, fmt.Sprintf("%s in %s: %q", terminal, dir, args))
+	return nil
+}
+
+func TestOpenInTerminal(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "project")
+	app := filepath.Join(dir, "Code.app")
+	for _, d := range []string{project, app} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(project, "main.go")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []search.Result{
+		{ID: "files:" + file, Title: "main.go", Target: file},
+		{ID: "files:" + project, Title: "project", Target: project},
+		{ID: "apps:" + app, Title: "Code", Target: app},
+	}
+	files := &testProvider{id: "files", results: results[:2]}
+	apps := &testProvider{id: "apps", results: results[2:]}
+	g, _ := newTestService(t, func(c *config.Config) {
+		c.Terminal = "iTerm"
+		c.TerminalCommand = "nvim ."
+	}, files, apps)
+	plat := &terminalPlatform{}
+	g.fallback = noResults{plat: plat}
+	if _, err := g.engine.Search(context.Background(), "o"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range results {
+		if err := g.OpenInTerminal(r.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file opens in its folder, a folder in itself, and an app bundle,
+	// though a folder, in the one holding it.
+	want := []string{
+		fmt.Sprintf("iTerm in %s: %q", project, []string{"nvim", "."}),
+		fmt.Sprintf("iTerm in %s: %q", project, []string{"nvim", "."}),
+		fmt.Sprintf("iTerm in %s: %q", dir, []string{"nvim", "."}),
+	}
+	if !slices.Equal(plat.opened, want) {
+		t.Errorf("opened\n%q\nwant\n%q", plat.opened, want)
+	}
+	if err := g.OpenInTerminal("files:/gone"); !errors.Is(err, search.ErrUnknownResult) {
+		t.Errorf("OpenInTerminal of an unknown result = %v, want ErrUnknownResult", err)
+	}
+}
+
+func TestOpensInTerminal(t *testing.T) {
+	tests := []struct {
+		r    search.Result
+		want bool
+	}{
+		{search.Result{Provider: "files", Target: "/Users/me/notes.txt"}, true},
+		{search.Result{Provider: "apps", Target: "/Applications/Mail.app"}, true},
+		{search.Result{Provider: "grep", Target: "/Users/me/main.go"}, true},
+		{search.Result{Provider: "files", Target: "notes.txt"}, false},
+		{search.Result{Provider: "commands", Target: "/usr/bin/true"}, false},
+	}
+	for _, tt := range tests {
+		if got := opensInTerminal(tt.r); got != tt.want {
+			t.Errorf("opensInTerminal(%s %s) = %v, want %v", tt.r.Provider, tt.r.Target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"changeme/internal/quarantine"
+	"changeme/internal/timer"
+)
+
+// Notifier delivers a notification to the user.
+type Notifier interface {
+	Notify(title, body string) error
+	// NotifyCritical delivers a notification that may get through a Focus
+	// or Do Not Disturb mode.
+	NotifyCritical(title, body string) error
+}
+
+// TimerService runs countdown timers and stopwatches. Running timers are
+// saved to disk so they survive a restart; a timer that finished while Prism
+// was not running fires as soon as it is restored.
+type TimerService struct {
+	path     string
+	notifier Notifier
+
+	mu     sync.Mutex
+	timers map[string]timer.Timer
+	stops  map[string]*time.Timer
+	paused bool
+}
+
+// NewTimerService restores the timers saved at path and schedules them.
+func NewTimerService(path string, notifier Notifier) (*TimerService, error) {
+	s := &TimerService{
+		path:     path,
+		notifier: notifier,
+		timers:   make(map[string]timer.Timer),
+		stops:    make(map[string]*time.Timer),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	var saved []timer.Timer
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return s, fmt.Errorf("timers: %w", quarantine.Move(path, err))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range saved {
+		s.add(t)
+	}
+	return s, nil
+}
+
+// Start begins a countdown of d.
+func (s *TimerService) Start(d time.Duration) (timer.Timer, error) {
+	if d <= 0 {
+		return timer.Timer{}, timer.ErrInvalidDuration
+	}
+	now := time.Now()
+	t := timer.Timer{
+		ID:       newTimerID(now),
+		Label:    timer.Format(d) + " Timer",
+		Started:  now,
+		Deadline: now.Add(d),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(t)
+	return t, s.save()
+}
+
+// StartStopwatch begins a stopwatch.
+func (s *TimerService) StartStopwatch() timer.Timer {
+	now := time.Now()
+	t := timer.Timer{ID: newTimerID(now), Label: "Stopwatch", Started: now}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(t)
+	if err := s.save(); err != nil {
+		log.Println(err)
+	}
+	return t
+}
+
+// List returns the running timers, oldest first.
+func (s *TimerService) List() []timer.Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]timer.Timer, 0, len(s.timers))
+	for _, t := range s.timers {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Started.Before(list[j].Started) })
+	return list
+}
+
+// Cancel stops the timer or stopwatch with the given ID.
+func (s *TimerService) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.timers[id]; !ok {
+		return errors.New("timer: no such timer")
+	}
+	s.remove(id)
+	return s.save()
+}
+
+// SetPaused holds back or releases timer notifications. Countdowns keep
+// counting while paused; those that finish meanwhile notify on resuming.
+func (s *TimerService) SetPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if paused == s.paused {
+		return
+	}
+	s.paused = paused
+	for id, stop := range s.stops {
+		stop.Stop()
+		delete(s.stops, id)
+	}
+	if !paused {
+		for _, t := range s.timers {
+			s.schedule(t)
+		}
+	}
+}
+
+// add registers t and, for countdowns, schedules its completion. The caller
+// holds s.mu.
+func (s *TimerService) add(t timer.Timer) {
+	s.timers[t.ID] = t
+	if !s.paused {
+		s.schedule(t)
+	}
+}
+
+// schedule arranges for countdown t to finish. The caller holds s.mu.
+func (s *TimerService) schedule(t timer.Timer) {
+	if t.Stopwatch() {
+		return
+	}
+	s.stops[t.ID] = time.AfterFunc(t.Remaining(time.Now()), func() { s.finish(t.ID) })
+}
+
+// remove forgets a timer. The caller holds s.mu.
+func (s *TimerService) remove(id string) {
+	if stop, ok := s.stops[id]; ok {
+		stop.Stop()
+		delete(s.stops, id)
+	}
+	delete(s.timers, id)
+}
+
+func (s *TimerService) finish(id string) {
+	s.mu.Lock()
+	t, ok := s.timers[id]
+	if ok {
+		s.remove(id)
+		if err := s.save(); err != nil {
+			log.Println(err)
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	if err := s.notifier.NotifyCritical("Timer Finished", t.Label+" is done."); err != nil {
+		log.Println(err)
+	}
+}
+
+// save writes the running timers to disk. The caller holds s.mu.
+func (s *TimerService) save() error {
+	list := make([]timer.Timer, 0, len(s.timers))
+	for _, t := range s.timers {
+		list = append(list, t)
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func newTimerID(now time.Time) string {
+	return strconv.FormatInt(now.UnixNano(), 36)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"changeme/internal/quarantine"
+	"changeme/internal/timer"
+)
+
+// notified is a Notifier that reports each notification's title and body
+// on a channel.
+type notified chan [2]string
+
+func (n notified) Notify(title, body string) error {
+	n <- [2]string{title, body}
+	return nil
+}
+
+func (n notified) NotifyCritical(title, body string) error {
+	return n.Notify(title, body)
+}
+
+func (n notified) wait(t *testing.T) [2]string {
+	t.Helper()
+	select {
+	case got := <-n:
+		return got
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification")
+		return [2]string{}
+	}
+}
+
+func TestTimerFires(t *testing.T) {
+	n := make(notified, 1)
+	s, err := NewTimerService(filepath.Join(t.TempDir(), "timers.json"), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm, err := s.Start(20 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.wait(t); got[1] != tm.Label+" is done." {
+		t.Errorf("notification = %q, want one for %q", got, tm.Label)
+	}
+	if list := s.List(); len(list) != 0 {
+		t.Errorf("finished timer still listed: %v", list)
+	}
+}
+
+func TestTimerCancel(t *testing.T) {
+	n := make(notified, 1)
+	s, err := NewTimerService(filepath.Join(t.TempDir(), "timers.json"), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm, err := s.Start(20 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(tm.ID); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-n:
+		t.Errorf("cancelled timer notified %q", got)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestTimerRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timers.json")
+	now := time.Now()
+	saved := []timer.Timer{
+		{ID: "done", Label: "Tea", Started: now.Add(-time.Hour), Deadline: now.Add(-time.Minute)},
+		{ID: "later", Label: "Pasta", Started: now, Deadline: now.Add(time.Hour)},
+		{ID: "watch", Label: "Stopwatch", Started: now},
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	n := make(notified, 3)
+	s, err := NewTimerService(path, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A timer that finished while Prism was not running fires at once.
+	if got := n.wait(t); got[1] != "Tea is done." {
+		t.Errorf("notification = %q, want Tea's", got)
+	}
+	var ids []string
+	for _, tm := range s.List() {
+		ids = append(ids, tm.ID)
+	}
+	slices.Sort(ids)
+	if want := []string{"later", "watch"}; !slices.Equal(ids, want) {
+		t.Errorf("running timers = %v, want %v", ids, want)
+	}
+}
+
+func TestTimersCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timers.json")
+	data := `[{"id": "t1", "ends":`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewTimerService(path, make(notified, 1))
+	if err == nil {
+		t.Error("NewTimerService with a corrupt file succeeded")
+	}
+	if got := s.List(); len(got) != 0 {
+		t.Errorf("timers = %+v, want none", got)
+	}
+	if got, err := os.ReadFile(path + quarantine.Suffix); err != nil || string(got) != data {
+		t.Errorf("quarantined file = %q, %v; want %q", got, err, data)
+	}
+}
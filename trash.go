@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// actionTrash moves a file result to the Trash after confirmation. It is
+// offered on results of the files provider and handled by GreetService.
+const actionTrash = "prism.trash"
+
+// The confirmations shown in place of results before trashing. Their IDs
+// are the prefix followed by the path; an application bundle needs the
+// second confirmation as well as the first.
+const (
+	trashConfirmPrefix    = "prism.trash.confirm:"
+	trashConfirmAppPrefix = "prism.trash.confirm-app:"
+)
+
+// errTrashApp is returned by MoveToTrash for an application bundle, which
+// is only trashed after its own confirmation.
+var errTrashApp = errors.New("trash: applications need an extra confirmation")
+
+// protectedPaths are never trashed, nor is anything inside them.
+var protectedPaths = map[string][]string{
+	"darwin":  {"/System", "/Library", "/usr", "/bin", "/sbin", "/etc", "/private", "/var", "/dev", "/cores"},
+	"linux":   {"/usr", "/bin", "/sbin", "/lib", "/lib64", "/etc", "/boot", "/var", "/dev", "/proc", "/sys", "/run", "/snap"},
+	"windows": {`C:\Windows`, `C:\Program Files`, `C:\Program Files (x86)`, `C:\ProgramData`},
+}
+
+// protectedRoots are never trashed themselves, though what they contain may
+// be.
+var protectedRoots = map[string][]string{
+	"darwin":  {"/", "/Applications", "/Users", "/Volumes"},
+	"linux":   {"/", "/home", "/opt", "/mnt", "/media"},
+	"windows": {`C:\`, `C:\Users`},
+}
+
+// trashable reports whether path may be moved to the Trash: it must be an
+// absolute path outside the system's own folders, and not the home folder
+// or a folder holding the top of a disk or the users' homes.
+func trashable(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("trash: %q is not an absolute path", path)
+	}
+	path = filepath.Clean(path)
+	roots := protectedRoots[runtime.GOOS]
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, home)
+	}
+	if slices.ContainsFunc(roots, func(root string) bool { return strings.EqualFold(path, root) }) {
+		return fmt.Errorf("trash: %s is protected", path)
+	}
+	for _, dir := range protectedPaths[runtime.GOOS] {
+		if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("trash: %s is a system path", path)
+		}
+	}
+	return nil
+}
+
+// isAppBundle reports whether path is a macOS application bundle.
+func isAppBundle(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".app")
+}
+
+// MoveToTrash moves path to the Trash, from where it can be restored, and
+// drops it from the shown results. System paths are refused, as are
+// application bundles, which the Move to Trash action confirms separately.
+func (g *GreetService) MoveToTrash(path string) error {
+	if isAppBundle(path) {
+		return errTrashApp
+	}
+	return g.trash(path)
+}
+
+// trash moves path to the Trash as the shown result with that target, or
+// on its own when none is shown, and records it in the audit log.
+func (g *GreetService) trash(path string) error {
+	r := search.Result{Target: path}
+	g.mu.Lock()
+	if i := slices.IndexFunc(g.lastResults, func(r search.Result) bool { return r.Target == path }); i >= 0 {
+		r = g.lastResults[i]
+	}
+	g.mu.Unlock()
+	return g.trashResult(r)
+}
+
+// trashResult moves r's target to the Trash and records it in the audit
+// log, whether or not that succeeded.
+func (g *GreetService) trashResult(r search.Result) error {
+	err := g.moveToTrash(r.Target)
+	g.auditAction(r, actionTrash, err)
+	return err
+}
+
+func (g *GreetService) moveToTrash(path string) error {
+	if err := trashable(path); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+	if err := g.fallback.plat.Trash(context.Background(), path); err != nil {
+		return err
+	}
+	if g.fallback.plat.CanRestoreFromTrash() {
+		g.recordUndo(undoable{actionID: actionTrash, target: path, at: time.Now()})
+	}
+	// The files index follows the change through its folder watcher.
+	g.mu.Lock()
+	g.lastResults = slices.DeleteFunc(g.lastResults, func(r search.Result) bool {
+		return r.Target == path
+	})
+	g.mu.Unlock()
+	g.refreshResults()
+	return nil
+}
+
+// confirmTrash shows the confirmation for trashing path in place of the
+// results, or the extra one for an application once the first is accepted.
+func (g *GreetService) confirmTrash(path string, app bool) {
+	name := filepath.Base(path)
+	r := search.Result{
+		ID:       trashConfirmPrefix + path,
+		Provider: "prism",
+		Type:     "confirmation",
+		Title:    fmt.Sprintf("Move “%s” to the Trash?", name),
+		Subtitle: path,
+		Actions:  []search.Action{{ID: actionTrash, Title: "Move to Trash"}},
+	}
+	if app {
+		r.ID = trashConfirmAppPrefix + path
+		r.Title = fmt.Sprintf("“%s” is an application. Move it to the Trash anyway?", name)
+		r.Actions = []search.Action{{ID: actionTrash, Title: "Move Application to Trash"}}
+	}
+	g.mu.Lock()
+	query := g.lastQuery
+	g.mu.Unlock()
+	g.events.EmitEvent(eventResultsUpdated, ResultsUpdate{Query: query, Results: []search.Result{r}, Done: true})
+}
+
+// isTrashConfirmation reports whether resultID is one of confirmTrash's.
+func isTrashConfirmation(resultID string) bool {
+	return strings.HasPrefix(resultID, trashConfirmPrefix) || strings.HasPrefix(resultID, trashConfirmAppPrefix)
+}
+
+// runTrash handles actionTrash on a file result and on its confirmations.
+// Each step asks for the next until the path is trashed.
+func (g *GreetService) runTrash(resultID string) error {
+	if path, ok := strings.CutPrefix(resultID, trashConfirmAppPrefix); ok {
+		return g.trash(path)
+	}
+	if path, ok := strings.CutPrefix(resultID, trashConfirmPrefix); ok {
+		if isAppBundle(path) {
+			g.confirmTrash(path, true)
+			return nil
+		}
+		return g.trash(path)
+	}
+	r, ok := g.engine.Result(resultID)
+	if !ok {
+		return search.ErrUnknownResult
+	}
+	if err := trashable(r.Target); err != nil {
+		return err
+	}
+	g.confirmTrash(r.Target, false)
+	return nil
+}
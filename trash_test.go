@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"changeme/internal/config"
+	"changeme/internal/platform"
+	"changeme/internal/search"
+)
+
+// trashPlatform is a platform whose Trash is a folder.
+type trashPlatform struct {
+	platform.Platform
+	dir     string
+	trashed []string
+}
+
+func (p *trashPlatform) Trash(ctx context.Context, path string) error {
+	p.trashed = append(p.trashed, path)
+	return os.Rename(path, filepath.Join(p.dir, filepath.Base(path)))
+}
+
+func (p *trashPlatform) CanRestoreFromTrash() bool { return false }
+
+func TestMoveToTrash(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	plat := &trashPlatform{dir: t.TempDir()}
+	g, _ := newTestService(t, nil)
+	g.fallback = noResults{plat: plat}
+
+	dir := t.TempDir()
+	if err := trashable(dir); err != nil {
+		// On macOS temporary folders are under the protected /var.
+		t.Skip(err)
+	}
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	other := appResult("Mail")
+	g.lastResults = []search.Result{{ID: "files:" + path, Target: path}, other}
+	if err := g.MoveToTrash(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s is still there: %v", path, err)
+	}
+	if _, err := os.Stat(filepath.Join(plat.dir, "notes.txt")); err != nil {
+		t.Errorf("notes.txt is not in the Trash: %v", err)
+	}
+	if got := resultIDs(g.lastResults); len(got) != 1 || got[0] != other.ID {
+		t.Errorf("last results = %v, want only %s", got, other.ID)
+	}
+
+	for _, path := range []string{"/usr/bin/ls", "/etc/hosts", "/", home, "notes.txt"} {
+		if err := g.MoveToTrash(path); err == nil {
+			t.Errorf("MoveToTrash(%q) succeeded", path)
+		}
+	}
+	if err := g.MoveToTrash("/Applications/Mail.app"); !errors.Is(err, errTrashApp) {
+		t.Errorf("MoveToTrash of an app = %v, want errTrashApp", err)
+	}
+	if len(plat.trashed) != 1 {
+		t.Errorf("trashed %v, want only %s", plat.trashed, path)
+	}
+}
+
+// restorablePlatform is a trashPlatform that can put items back.
+type restorablePlatform struct{ *trashPlatform }
+
+func (p restorablePlatform) RestoreFromTrash(ctx context.Context, path string) error {
+	return os.Rename(filepath.Join(p.dir, filepath.Base(path)), path)
+}
+
+func (p restorablePlatform) CanRestoreFromTrash() bool { return true }
+
+func TestUndoTrash(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := trashable(dir); err != nil {
+		t.Skip(err)
+	}
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mail := appResult("Mail")
+	g, _ := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 }, &testProvider{id: "apps", results: []search.Result{mail}})
+	g.fallback = noResults{plat: restorablePlatform{&trashPlatform{dir: t.TempDir()}}}
+
+	if err := g.MoveToTrash(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := resultIDs(g.Search("mail")); len(got) != 2 || got[0] != undoID {
+		t.Errorf("Search after trashing = %v, want the undo result first", got)
+	}
+	if err := g.Activate(undoID, actionUndo); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "notes" {
+		t.Errorf("after undoing, %s = %q, %v", path, data, err)
+	}
+	if got := resultIDs(g.Search("mail")); len(got) != 1 || got[0] != mail.ID {
+		t.Errorf("Search after undoing = %v, want no undo result", got)
+	}
+	if err := g.UndoLastAction(); !errors.Is(err, errCannotUndo) {
+		t.Errorf("undoing twice = %v, want errCannotUndo", err)
+	}
+
+	// Any other action cannot be undone, and ends the chance to undo the
+	// trashing before it.
+	if err := g.MoveToTrash(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Activate(mail.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UndoLastAction(); !errors.Is(err, errCannotUndo) {
+		t.Errorf("undoing after opening Mail = %v, want errCannotUndo", err)
+	}
+}
+
+func TestUndoTrashUnsupported(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := trashable(dir); err != nil {
+		t.Skip(err)
+	}
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, _ := newTestService(t, nil)
+	g.fallback = noResults{plat: &trashPlatform{dir: t.TempDir()}}
+	if err := g.MoveToTrash(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UndoLastAction(); !errors.Is(err, errCannotUndo) {
+		t.Errorf("undo without restoring from the Trash = %v, want errCannotUndo", err)
+	}
+}
+
+func TestTrashAudit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := trashable(dir); err != nil {
+		t.Skip(err)
+	}
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := &testProvider{id: "files", results: []search.Result{{ID: "files:" + path, Type: "file", Title: "notes.txt", Target: path}}}
+	g, _ := newTestService(t, func(c *config.Config) { c.ActivationDebounceMs = 0 }, files)
+	g.fallback = noResults{plat: &trashPlatform{dir: t.TempDir()}}
+	records := audited(t, g)
+	g.Search("notes")
+
+	// Trashing through the action and its confirmation is recorded once,
+	// as the result trashed.
+	if err := g.Activate("files:"+path, actionTrash); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Activate(trashConfirmPrefix+path, actionTrash); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.MoveToTrash("/etc/hosts"); err == nil {
+		t.Fatal("MoveToTrash(/etc/hosts) succeeded")
+	}
+	got := records()
+	if len(got) != 2 {
+		t.Fatalf("audit records = %+v, want the trashing and the refusal", got)
+	}
+	if r := got[0]; r.Provider != "files" || r.ResultID != "files:"+path || r.Action != actionTrash || r.Detail != path || r.Error != "" {
+		t.Errorf("record of trashing = %+v", r)
+	}
+	if r := got[1]; r.Action != actionTrash || r.Detail != "/etc/hosts" || r.Error == "" {
+		t.Errorf("record of the refused trashing = %+v", r)
+	}
+}
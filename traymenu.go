@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+
+	"changeme/internal/config"
+)
+
+// trayHandlers run the actions of tray menu items.
+type trayHandlers struct {
+	show  func()
+	pause func()
+	// update opens the page of the newer version found.
+	update func()
+	// command runs one of Prism's commands by ID.
+	command func(ctx context.Context, id string) error
+	open    func(ctx context.Context, target string) error
+}
+
+// showItem is added to the top of a tray menu without a show item. The
+// tray is the way to the launcher when the hotkey cannot be registered.
+var showItem = config.TrayItem{Label: "Show Prism", Action: config.TrayActionShow}
+
+// checkTrayMenu returns the items of the configured tray menu whose action
+// is known, logging those left out, with showItem added when none shows
+// the launcher. commands reports whether a command ID is registered.
+func checkTrayMenu(items []config.TrayItem, commands func(id string) bool) []config.TrayItem {
+	var valid []config.TrayItem
+	for i, it := range items {
+		if err := checkTrayItem(it, commands); err != nil {
+			log.Printf("config: trayMenu[%d]: %v", i, err)
+			continue
+		}
+		valid = append(valid, it)
+	}
+	if !slices.ContainsFunc(valid, func(it config.TrayItem) bool { return it.Action == config.TrayActionShow }) {
+		log.Printf("config: trayMenu has no %q item; adding one", config.TrayActionShow)
+		valid = append([]config.TrayItem{showItem}, valid...)
+	}
+	return valid
+}
+
+func checkTrayItem(it config.TrayItem, commands func(id string) bool) error {
+	switch {
+	case it.Action == config.TrayActionSeparator:
+		return nil
+	case it.Label == "":
+		return fmt.Errorf("item with action %q has no label", it.Action)
+	case it.Action == config.TrayActionShow, it.Action == config.TrayActionPause, it.Action == config.TrayActionUpdate:
+		return nil
+	case strings.HasPrefix(it.Action, config.TrayActionOpen):
+		if strings.TrimPrefix(it.Action, config.TrayActionOpen) == "" {
+			return fmt.Errorf("%q has nothing to open", it.Label)
+		}
+		return nil
+	case commands(it.Action):
+		return nil
+	}
+	return fmt.Errorf("unknown action %q", it.Action)
+}
+
+// trayAction returns what clicking it, an item checked with checkTrayMenu,
+// does, or nil for a separator.
+func trayAction(it config.TrayItem, h trayHandlers) func() {
+	switch action := it.Action; {
+	case action == config.TrayActionSeparator:
+		return nil
+	case action == config.TrayActionShow:
+		return h.show
+	case action == config.TrayActionUpdate:
+		return h.update
+	case action == config.TrayActionPause:
+		return h.pause
+	case strings.HasPrefix(action, config.TrayActionOpen):
+		target := strings.TrimPrefix(action, config.TrayActionOpen)
+		return func() {
+			if err := h.open(context.Background(), target); err != nil {
+				log.Println(err)
+			}
+		}
+	default:
+		return func() {
+			if err := h.command(context.Background(), action); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
+
+// buildTrayMenu adds items, checked with checkTrayMenu, to menu. It returns
+// the items whose labels change: the update item, hidden until an update
+// is found, and the pause item, either nil when not in the menu.
+func buildTrayMenu(menu *application.Menu, items []config.TrayItem, paused bool, h trayHandlers) (updateItem, pauseItem *application.MenuItem) {
+	for _, it := range items {
+		run := trayAction(it, h)
+		if run == nil {
+			menu.AddSeparator()
+			continue
+		}
+		label := it.Label
+		if it.Action == config.TrayActionPause {
+			label = pauseLabel(paused)
+		}
+		item := menu.Add(label).OnClick(func(_ *application.Context) { run() })
+		switch it.Action {
+		case config.TrayActionUpdate:
+			updateItem = item
+			updateItem.SetHidden(true)
+		case config.TrayActionPause:
+			pauseItem = item
+		}
+	}
+	return updateItem, pauseItem
+}
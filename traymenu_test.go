@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"changeme/internal/config"
+)
+
+func TestCheckTrayMenu(t *testing.T) {
+	commands := func(id string) bool { return id == "settings" || id == "quit" }
+	tests := []struct {
+		name  string
+		items []config.TrayItem
+		want  []config.TrayItem
+	}{
+		{
+			name:  "default",
+			items: []config.TrayItem{{Label: "Show", Action: "show"}, {Action: "separator"}, {Label: "Quit", Action: "quit"}},
+			want:  []config.TrayItem{{Label: "Show", Action: "show"}, {Action: "separator"}, {Label: "Quit", Action: "quit"}},
+		},
+		{
+			name: "unknown actions are left out",
+			items: []config.TrayItem{
+				{Label: "Show", Action: "show"},
+				{Label: "Nope", Action: "no-such-command"},
+				{Label: "", Action: "settings"},
+				{Label: "Nothing", Action: "open:"},
+				{Label: "Site", Action: "open:https://example.com"},
+			},
+			want: []config.TrayItem{{Label: "Show", Action: "show"}, {Label: "Site", Action: "open:https://example.com"}},
+		},
+		{
+			name:  "a show item is added",
+			items: []config.TrayItem{{Label: "Quit", Action: "quit"}},
+			want:  []config.TrayItem{showItem, {Label: "Quit", Action: "quit"}},
+		},
+		{
+			name:  "empty",
+			items: nil,
+			want:  []config.TrayItem{showItem},
+		},
+	}
+	for _, tt := range tests {
+		if got := checkTrayMenu(tt.items, commands); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: checkTrayMenu = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrayShowToggles(t *testing.T) {
+	// Without a hotkey, a config whose tray menu lists only Quit still
+	// gets a show item, and clicking it toggles the launcher.
+	items := checkTrayMenu([]config.TrayItem{{Label: "Quit", Action: "quit"}}, func(id string) bool { return id == "quit" })
+	visible := false
+	h := trayHandlers{
+		show: func() { toggle(visible, func() { visible = false }, func() { visible = true }) },
+		command: func(ctx context.Context, id string) error {
+			t.Errorf("show item ran command %q", id)
+			return nil
+		},
+	}
+	click := trayAction(items[0], h)
+	if items[0].Action != config.TrayActionShow || click == nil {
+		t.Fatalf("first item = %+v, want a show item", items[0])
+	}
+	for i, want := range []bool{true, false, true} {
+		click()
+		if visible != want {
+			t.Errorf("after click %d: visible = %v, want %v", i+1, visible, want)
+		}
+	}
+}
+
+func TestTrayMenuFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	fixture := `{"trayMenu": [
+	{"label": "Show", "action": "show"},
+	{"action": "separator"},
+	{"label": "Docs", "action": "open:https://example.com/docs"},
+	{"label": "Rebuild Index", "action": "rebuild-index"},
+	{"label": "Broken", "action": "no-such-command"},
+	{"label": "Pause", "action": "pause"},
+	{"label": "Quit", "action": "quit"}
+]}`
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := checkTrayMenu(cfg.TrayMenu, func(id string) bool { return id == "rebuild-index" || id == "quit" })
+	var labels []string
+	for _, it := range items {
+		labels = append(labels, it.Label)
+	}
+	if want := []string{"Show", "", "Docs", "Rebuild Index", "Pause", "Quit"}; !slices.Equal(labels, want) {
+		t.Fatalf("tray menu = %q, want %q", labels, want)
+	}
+
+	var ran []string
+	h := trayHandlers{
+		show:  func() { ran = append(ran, "show") },
+		pause: func() { ran = append(ran, "pause") },
+		command: func(ctx context.Context, id string) error {
+			ran = append(ran, "command "+id)
+			return nil
+		},
+		open: func(ctx context.Context, target string) error {
+			ran = append(ran, "open "+target)
+			return nil
+		},
+	}
+	for _, it := range items {
+		click := trayAction(it, h)
+		if (click == nil) != (it.Action == config.TrayActionSeparator) {
+			t.Fatalf("trayAction(%+v) returned a click handler %v", it, click != nil)
+		}
+		if click != nil {
+			click()
+		}
+	}
+	want := []string{"show", "open https://example.com/docs", "command rebuild-index", "pause", "command quit"}
+	if !slices.Equal(ran, want) {
+		t.Errorf("clicking every item ran %q, want %q", ran, want)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"changeme/internal/search"
+)
+
+// undoID is the ID of the result offering to undo the last action, shown
+// at the top of the results for a while after an action that can be
+// undone; actionUndo is its action.
+const (
+	undoID     = "prism.undo"
+	actionUndo = "prism.undo.run"
+)
+
+// undoWindow is how long the undo result is offered after the action.
+const undoWindow = 30 * time.Second
+
+// errCannotUndo is returned by UndoLastAction when the last action cannot
+// be undone, or there was none.
+var errCannotUndo = errors.New("undo: the last action cannot be undone")
+
+// undoable is an action that can be reversed. Only moving a file to the
+// Trash is, by putting it back, on platforms that can.
+type undoable struct {
+	// actionID is the action that was run, such as actionTrash.
+	actionID string
+	// target is what it was run on, such as the trashed path.
+	target string
+	at     time.Time
+}
+
+// recordUndo makes u the action UndoLastAction reverses.
+func (g *GreetService) recordUndo(u undoable) {
+	g.mu.Lock()
+	g.undo = u
+	g.mu.Unlock()
+}
+
+// forgetUndo records that an action that cannot be undone ran, so undoing
+// no longer reaches back past it.
+func (g *GreetService) forgetUndo() {
+	g.recordUndo(undoable{})
+}
+
+// UndoLastAction reverses the last action run from Prism when it can be:
+// a file moved to the Trash is put back. Any other action makes it fail
+// with errCannotUndo.
+func (g *GreetService) UndoLastAction() error {
+	g.mu.Lock()
+	u := g.undo
+	g.undo = undoable{}
+	g.mu.Unlock()
+	switch u.actionID {
+	case actionTrash:
+		if err := g.fallback.plat.RestoreFromTrash(context.Background(), u.target); err != nil {
+			return err
+		}
+	default:
+		return errCannotUndo
+	}
+	// Search again so the file shows among the results once more.
+	g.searchAgain()
+	return nil
+}
+
+// withUndo puts the undo result at the top of results while the last
+// action can be undone, for undoWindow after it ran.
+func (g *GreetService) withUndo(results []search.Result) []search.Result {
+	g.mu.Lock()
+	u := g.undo
+	g.mu.Unlock()
+	if u.actionID == "" || time.Since(u.at) > undoWindow {
+		return results
+	}
+	r := search.Result{
+		ID:       undoID,
+		Provider: "prism",
+		Type:     "command",
+		Sticky:   true,
+		Actions:  []search.Action{{ID: actionUndo, Title: "Undo"}},
+	}
+	switch u.actionID {
+	case actionTrash:
+		r.Title = "Undo Move to Trash"
+		r.Subtitle = fmt.Sprintf("Put “%s” back in %s", filepath.Base(u.target), filepath.Dir(u.target))
+	}
+	return append([]search.Result{r}, results...)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/network"
+	"changeme/internal/platform"
+	"changeme/internal/providers/commands"
+	"changeme/internal/update"
+)
+
+// version is Prism's version, compared with the release feed. Release
+// builds set it with -ldflags "-X main.version=1.2.3".
+var version = "0.1.0"
+
+// updateFeedKey is the network cache key of the release feed.
+const updateFeedKey = "update:feed"
+
+// errUpdatesOff is returned by CheckForUpdate unless CheckForUpdates is set.
+var errUpdatesOff = errors.New("update: checking for updates is turned off")
+
+// UpdateInfo is the outcome of the latest update check.
+type UpdateInfo struct {
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+	// URL is where the latest release can be downloaded.
+	URL       string    `json:"url"`
+	Available bool      `json:"available"`
+	Checked   time.Time `json:"checked"`
+}
+
+// UpdateService checks the release feed for a newer version, on a schedule
+// and on demand, and keeps the latest answer. It only informs: onAvailable
+// is called when an update is found, to point the user at the download.
+type UpdateService struct {
+	client      *network.Client
+	config      *config.Store
+	onAvailable func(UpdateInfo)
+
+	mu   sync.Mutex
+	info UpdateInfo
+}
+
+func NewUpdateService(client *network.Client, cfg *config.Store, onAvailable func(UpdateInfo)) *UpdateService {
+	return &UpdateService{client: client, config: cfg, onAvailable: onAvailable, info: UpdateInfo{Current: version}}
+}
+
+// CheckForUpdate reads the release feed now and returns what it says, or
+// errUpdatesOff when update checks are not turned on.
+func (u *UpdateService) CheckForUpdate() (UpdateInfo, error) {
+	cfg := u.config.Get()
+	if !cfg.CheckForUpdates {
+		return UpdateInfo{}, errUpdatesOff
+	}
+	resp, err := u.client.Get(context.Background(), updateFeedKey, cfg.UpdateFeedURL)
+	if err != nil {
+		return UpdateInfo{}, err
+	}
+	r, err := update.Parse(resp.Body)
+	if err != nil {
+		return UpdateInfo{}, err
+	}
+	info := UpdateInfo{
+		Current:   version,
+		Latest:    r.Version,
+		URL:       r.URL,
+		Available: update.Newer(r.Version, version),
+		Checked:   resp.Fetched,
+	}
+	u.mu.Lock()
+	announce := info.Available && info.Latest != u.info.Latest
+	u.info = info
+	u.mu.Unlock()
+	if announce && u.onAvailable != nil {
+		u.onAvailable(info)
+	}
+	return info, nil
+}
+
+// LastCheck returns the result of the latest successful check, without
+// checking again.
+func (u *UpdateService) LastCheck() UpdateInfo {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.info
+}
+
+// run checks for updates every UpdateCheckHours while checks are turned on,
+// until ctx is cancelled. The setting is read before each check, so turning
+// it on takes effect by the next one.
+func (u *UpdateService) run(ctx context.Context) {
+	for {
+		if _, err := u.CheckForUpdate(); err != nil && !errors.Is(err, errUpdatesOff) {
+			log.Println(err)
+		}
+		hours := u.config.Get().UpdateCheckHours
+		if hours <= 0 {
+			hours = config.Default().UpdateCheckHours
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(hours) * time.Hour):
+		}
+	}
+}
+
+// registerUpdateCommand adds the command that checks for an update and
+// opens the download page when there is one.
+func registerUpdateCommand(p *commands.Provider, updates *UpdateService, plat platform.Platform, notifier Notifier) {
+	p.Register(commands.Command{
+		ID:       "update",
+		Title:    "Check for Updates",
+		Subtitle: "Open the download page if a newer Prism is out",
+		Keywords: []string{"prism update", "upgrade", "version"},
+		Run: func(ctx context.Context) error {
+			info, err := updates.CheckForUpdate()
+			if err != nil {
+				return err
+			}
+			if info.Available {
+				return plat.Open(ctx, info.URL)
+			}
+			return notifier.Notify("Prism Is Up to Date", "Version "+info.Current+" is the latest.")
+		},
+	})
+}
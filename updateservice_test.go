@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/network"
+)
+
+func TestCheckForUpdate(t *testing.T) {
+	tests := []struct {
+		feed      string
+		want      UpdateInfo
+		announced int
+	}{
+		{`{"tag_name": "v99.0.0", "html_url": "https://example.com/v99"}`, UpdateInfo{Current: version, Latest: "v99.0.0", URL: "https://example.com/v99", Available: true}, 1},
+		{`{"tag_name": "v` + version + `", "html_url": "https://example.com/current"}`, UpdateInfo{Current: version, Latest: "v" + version, URL: "https://example.com/current"}, 0},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.feed))
+		}))
+		defer srv.Close()
+		dir := t.TempDir()
+		cfg, err := config.Open(filepath.Join(dir, "config.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache, err := network.OpenCache(filepath.Join(dir, "cache.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		announced := 0
+		u := NewUpdateService(network.New(cache), cfg, func(UpdateInfo) { announced++ })
+
+		// Nothing is fetched until checks are turned on.
+		if _, err := u.CheckForUpdate(); !errors.Is(err, errUpdatesOff) {
+			t.Errorf("CheckForUpdate with checks off = %v, want errUpdatesOff", err)
+		}
+		if err := cfg.Update(func(c *config.Config) {
+			c.CheckForUpdates = true
+			c.UpdateFeedURL = srv.URL
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// A second check of the same release announces it only once.
+		for range 2 {
+			got, err := u.CheckForUpdate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Checked.IsZero() {
+				t.Errorf("feed %s: Checked not set", tt.feed)
+			}
+			got.Checked = time.Time{}
+			if got != tt.want {
+				t.Errorf("feed %s: CheckForUpdate = %+v, want %+v", tt.feed, got, tt.want)
+			}
+		}
+		if announced != tt.announced {
+			t.Errorf("feed %s: announced %d times, want %d", tt.feed, announced, tt.announced)
+		}
+		if got := u.LastCheck(); got.Latest != tt.want.Latest {
+			t.Errorf("LastCheck = %+v, want %s", got, tt.want.Latest)
+		}
+	}
+}
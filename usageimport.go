@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime"
+	"time"
+
+	"changeme/internal/config"
+	"changeme/internal/frecency"
+	"changeme/internal/providers/apps"
+	"changeme/internal/search"
+	"changeme/internal/spotlight"
+)
+
+// usageImportID is the ID of the one-time offer, shown before anything is
+// typed, to seed frecency from the app usage Spotlight recorded.
+const usageImportID = "prism.usage-import"
+
+// The actions of the usage import offer. Either answer is saved to the
+// config, so the offer is not shown again.
+const (
+	actionImportUsage  = "prism.usage-import.accept"
+	actionDeclineUsage = "prism.usage-import.decline"
+)
+
+// usageImportTimeout bounds reading the usage of every indexed app.
+const usageImportTimeout = time.Minute
+
+// maxSeedWeight caps the frecency an imported app starts with, so a
+// handful of launches from Prism soon outweighs years of history.
+const maxSeedWeight = 5
+
+// usageImportOffer returns the offer to import app usage while it has not
+// been answered, on macOS only.
+func usageImportOffer(cfg config.Config) []search.Result {
+	if runtime.GOOS != "darwin" || cfg.UsageImport != "" {
+		return nil
+	}
+	return []search.Result{{
+		ID:       usageImportID,
+		Provider: "prism",
+		Type:     "confirmation",
+		Title:    "Import app usage from Spotlight?",
+		Subtitle: "Ranks the apps you use most first, from the start",
+		Actions: []search.Action{
+			{ID: actionImportUsage, Title: "Import"},
+			{ID: actionDeclineUsage, Title: "Don’t Import"},
+		},
+	}}
+}
+
+// runUsageImport answers the usage import offer. Importing carries on in
+// the background; if Spotlight cannot be read it is skipped without a word,
+// since nothing is lost by starting from no usage.
+func (g *GreetService) runUsageImport(actionID string) error {
+	answer := config.UsageImportDeclined
+	if actionID != actionDeclineUsage {
+		answer = config.UsageImportDone
+	}
+	if err := g.config.Update(func(c *config.Config) { c.UsageImport = answer }); err != nil {
+		return err
+	}
+	g.searchAgain()
+	if answer == config.UsageImportDone {
+		go g.importUsage()
+	}
+	return nil
+}
+
+// importUsage seeds frecency for the indexed apps from Spotlight, keeping
+// the entries of apps already launched from Prism.
+func (g *GreetService) importUsage() {
+	p, _ := g.engine.Provider("apps")
+	appsProvider, ok := p.(*apps.Provider)
+	if !ok {
+		return
+	}
+	paths, err := appsProvider.Paths()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), usageImportTimeout)
+	defer cancel()
+	usage, err := spotlight.AppUsage(ctx, paths)
+	if errors.Is(err, spotlight.ErrUnavailable) {
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	added, err := g.frecency.Seed(seedEntries(usage))
+	if err != nil {
+		log.Println(err)
+	}
+	if added > 0 {
+		g.searchAgain()
+	}
+}
+
+// searchAgain runs the last query again, so the empty state is rebuilt
+// without the offer and with the imported usage.
+func (g *GreetService) searchAgain() {
+	g.mu.Lock()
+	query := g.lastQuery
+	g.mu.Unlock()
+	g.events.EmitEvent(eventQuerySet, query)
+}
+
+// seedEntries turns app usage into frecency entries, weighting each by its
+// use count up to maxSeedWeight as of when it was last used.
+func seedEntries(usage map[string]spotlight.Usage) map[string]frecency.Entry {
+	entries := make(map[string]frecency.Entry, len(usage))
+	for id, u := range usage {
+		entries[id] = frecency.Entry{
+			Count:  u.Count,
+			Last:   u.Last,
+			Weight: float64(min(u.Count, maxSeedWeight)),
+		}
+	}
+	return entries
+}
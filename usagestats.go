@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UsageStat is how often one result has been launched.
+type UsageStat struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	// Count is the raw number of launches, unaffected by frecency decay.
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// UsageStats lists every launched result, most launched first. Names come
+// from resolving the result; results that no longer resolve, such as
+// uninstalled apps, are named by their ID.
+func (g *GreetService) UsageStats() []UsageStat {
+	ctx := context.Background()
+	entries := g.frecency.Entries()
+	stats := make([]UsageStat, 0, len(entries))
+	for id, e := range entries {
+		stat := UsageStat{ID: id, Name: id, Count: e.Count, LastUsed: e.Last}
+		if r, ok := g.engine.Resolve(ctx, id); ok {
+			stat.Provider, stat.Name = r.Provider, r.Title
+		} else if provider, _, ok := strings.Cut(id, ":"); ok {
+			stat.Provider = provider
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].LastUsed.After(stats[j].LastUsed)
+	})
+	return stats
+}
+
+// ClearUsageStats forgets every launch. Frecency ranking starts over too, as
+// it is derived from the same data.
+func (g *GreetService) ClearUsageStats() error {
+	return g.frecency.Clear()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"changeme/internal/search"
+)
+
+func TestUsageStats(t *testing.T) {
+	mail, notes := appResult("Mail"), appResult("Notes")
+	g, _ := newTestService(t, nil, &testProvider{id: "apps", results: []search.Result{mail, notes}})
+
+	// Launches months apart have decayed to almost nothing, but still count.
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	launches := []struct {
+		id string
+		at time.Time
+	}{
+		{notes.ID, start},
+		{notes.ID, start.AddDate(0, 3, 0)},
+		{notes.ID, start.AddDate(0, 6, 0)},
+		{mail.ID, start.AddDate(0, 6, 1)},
+		{"apps:/Applications/Gone.app", start.AddDate(0, 6, 2)},
+		{mail.ID, start.AddDate(0, 6, 3)},
+	}
+	for _, l := range launches {
+		if err := g.frecency.Record(l.id, l.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []UsageStat{
+		{ID: notes.ID, Provider: "apps", Name: "Notes", Count: 3, LastUsed: start.AddDate(0, 6, 0)},
+		{ID: mail.ID, Provider: "apps", Name: "Mail", Count: 2, LastUsed: start.AddDate(0, 6, 3)},
+		{ID: "apps:/Applications/Gone.app", Provider: "apps", Name: "apps:/Applications/Gone.app", Count: 1, LastUsed: start.AddDate(0, 6, 2)},
+	}
+	got := g.UsageStats()
+	if len(got) != len(want) {
+		t.Fatalf("UsageStats = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].Provider != want[i].Provider || got[i].Name != want[i].Name ||
+			got[i].Count != want[i].Count || !got[i].LastUsed.Equal(want[i].LastUsed) {
+			t.Errorf("stat %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := g.ClearUsageStats(); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.UsageStats(); len(got) != 0 {
+		t.Errorf("after clearing, UsageStats = %+v", got)
+	}
+}